@@ -1,29 +1,394 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ngmaloney/marine-terminal/internal/database"
+	"github.com/ngmaloney/marine-terminal/internal/debuglog"
+	"github.com/ngmaloney/marine-terminal/internal/geocoding"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/noaa"
+	"github.com/ngmaloney/marine-terminal/internal/ports"
+	"github.com/ngmaloney/marine-terminal/internal/stations"
 	"github.com/ngmaloney/marine-terminal/internal/ui"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
 )
 
+// version is the build version, set with -ldflags "-X main.version=v1.2.3"
+var version = "dev"
+
 func main() {
 	stationCode := flag.String("station", "", "Specify a marine station code to load directly (requires --location) (e.g., ANZ251)")
 	location := flag.String("location", "", "Specify location for station lookup (zipcode or city, state)")
 	portName := flag.String("port", "", "Name of a saved port to load directly")
+	here := flag.Bool("here", false, "Start with marine zones near your approximate location. Unless --home is set, this sends your IP address to ipapi.co to look up the location")
+	home := flag.String("home", "", "Fixed home coordinate used by --here instead of IP lookup, as 'lat,lon' (e.g., 41.68,-69.95)")
+	reprovision := flag.Bool("reprovision", false, "Rebuild the marine zones and tide stations tables from the latest NOAA data, then exit")
+	whereAmI := flag.String("whereami", "", "Print the marine zone and nearest tide station for a location (zipcode or city, state), then exit")
+	dumpForecast := flag.String("dump-forecast", "", "Print the parsed forecast for a marine zone (e.g., ANZ254) as JSON, then exit")
+	importGPX := flag.String("import-gpx", "", "Save a port for each waypoint in a GPX file, at the marine zone and nearest tide station to its coordinates, then exit")
+	checkPorts := flag.Bool("check-ports", false, "Check that each saved port's marine zone and tide station still resolve and its forecast and tides can be fetched, then exit")
+	shapefile := flag.String("shapefile", "", "NOAA marine zones shapefile edition to provision from (e.g., mz18mr25). Defaults to the latest published edition")
+	scaWind := flag.Float64("sca-wind", models.DefaultSmallCraftThresholds.WindKnots, "Sustained wind in knots at which forecast periods are highlighted as small craft conditions (0 disables)")
+	scaSeas := flag.Float64("sca-seas", models.DefaultSmallCraftThresholds.SeasFeet, "Sea height in feet at which forecast periods are highlighted as small craft conditions (0 disables)")
+	geocoder := flag.String("geocoder", geocoding.BackendLocal, "Geocoder for searches: 'local' (offline zipcode database) or 'census' (US Census geocoder, for street addresses)")
+	geocodeCacheTTL := flag.Duration("geocode-cache-ttl", geocoding.DefaultCacheTTL, "How long results from the census geocoder are cached before being looked up again (0 disables the cache)")
+	httpTimeout := flag.Duration("http-timeout", noaa.DefaultHTTPTimeout, "How long to wait for a response from the NOAA forecast, alert, tide and station services")
+	geocodeTimeout := flag.Duration("geocode-timeout", 0, "How long to wait for a search to be geocoded (default 10s, 15s for the census geocoder)")
+	asciiChart := flag.Bool("ascii-chart", false, "Draw the tide chart with plain ASCII characters instead of braille (automatic when the locale isn't UTF-8)")
+	ascii := flag.Bool("ascii", false, "Draw plain ASCII (e.g. [!], >>) in place of emoji, symbols, box borders and the braille tide chart (automatic when the locale isn't UTF-8)")
+	periods := flag.Int("periods", ui.DefaultForecastPeriodLimit, "Number of upcoming forecast periods to list (0 lists all)")
+	zoneAutoSelect := flag.Float64("zone-auto-select", ui.DefaultZoneAutoSelectMiles, "Skip the zone list when a search finds a single zone, or only one within this many miles (0 always shows the list)")
+	tideDays := flag.Int("tide-days", ui.DefaultTideWindowDays, fmt.Sprintf("Days of tide predictions to fetch, up to %d", ui.MaxTideWindowDays))
+	dbPath := flag.String("db-path", database.DBPath(), "Path to the SQLite database holding saved ports, marine zones, tide stations and zipcodes (created if missing)")
+	pressureUnits := flag.String("pressure-units", models.PressureMillibars, "Unit for barometric pressure: 'mb' (millibars) or 'inHg' (inches of mercury)")
+	alertBell := flag.Bool("alert-bell", false, "Ring the terminal bell when a new severe or extreme marine alert is issued for the zone on display")
+	alertCommand := flag.String("alert-command", "", "Shell command to run when a new severe or extreme marine alert is issued, with MARINE_ALERT_ZONE, MARINE_ALERT_EVENT and MARINE_ALERT_HEADLINE set (e.g., 'notify-send \"$MARINE_ALERT_EVENT\" \"$MARINE_ALERT_HEADLINE\"')")
+	debug := flag.Bool("debug", false, "Log details such as the URLs forecasts were fetched from, to debug.log next to the database (to stderr with --dump-forecast)")
+	showVersion := flag.Bool("version", false, "Print the version and where the provisioned marine zones, tide stations and zipcodes came from, then exit")
+	themeName := flag.String("theme", ui.DefaultThemeName, "Color theme: 'default', 'high-contrast', 'monochrome-green', or the path to a .toml theme file")
 	flag.Parse()
 
+	database.SetDBPath(*dbPath)
+	debuglog.SetEnabled(*debug)
+
+	if *showVersion {
+		if err := runVersion(database.DBPath()); err != nil {
+			fmt.Printf("Error: --version: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *shapefile != "" {
+		if err := zonelookup.SetShapefileEdition(*shapefile); err != nil {
+			fmt.Printf("Error: --shapefile: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *reprovision {
+		// Ctrl+C stops the download and removes the partial file
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		err := runReprovision(ctx, database.DBPath())
+		stop()
+		if err != nil {
+			fmt.Printf("Error re-provisioning: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *httpTimeout <= 0 {
+		fmt.Println("Error: --http-timeout must be positive.")
+		os.Exit(1)
+	}
+	if *geocodeTimeout < 0 {
+		fmt.Println("Error: --geocode-timeout can't be negative.")
+		os.Exit(1)
+	}
+	if *geocodeTimeout == 0 {
+		*geocodeTimeout = geocoding.DefaultTimeoutFor(*geocoder)
+	}
+
+	if *whereAmI != "" {
+		if err := runWhereAmI(*whereAmI, *geocoder, *geocodeCacheTTL, *geocodeTimeout); err != nil {
+			fmt.Printf("Error: --whereami: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *dumpForecast != "" {
+		if err := runDumpForecast(*dumpForecast, *httpTimeout); err != nil {
+			fmt.Printf("Error: --dump-forecast: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *importGPX != "" {
+		if err := runImportGPX(*importGPX); err != nil {
+			fmt.Printf("Error: --import-gpx: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *checkPorts {
+		failed, err := runCheckPorts(*httpTimeout)
+		if err != nil {
+			fmt.Printf("Error checking ports: %v\n", err)
+			os.Exit(1)
+		}
+		if failed > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Validation logic: if station is provided, location must be too
 	if *stationCode != "" && *location == "" {
 		fmt.Println("Error: --station requires --location to determine the nearest tide station.")
 		os.Exit(1)
 	}
 
-	p := tea.NewProgram(ui.NewModel(*stationCode, *location, *portName), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	if *home != "" && !*here {
+		fmt.Println("Error: --home is only used together with --here.")
+		os.Exit(1)
+	}
+
+	if *scaWind < 0 || *scaSeas < 0 {
+		fmt.Println("Error: --sca-wind and --sca-seas can't be negative.")
+		os.Exit(1)
+	}
+
+	if *geocodeCacheTTL < 0 {
+		fmt.Println("Error: --geocode-cache-ttl can't be negative.")
+		os.Exit(1)
+	}
+
+	geo, err := geocoding.NewGeocoderByName(*geocoder, *geocodeCacheTTL, *geocodeTimeout)
+	if err != nil {
+		fmt.Printf("Error: --geocoder: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *periods < 0 {
+		fmt.Println("Error: --periods can't be negative.")
+		os.Exit(1)
+	}
+
+	if *zoneAutoSelect < 0 {
+		fmt.Println("Error: --zone-auto-select can't be negative.")
+		os.Exit(1)
+	}
+
+	if err := ui.ValidateTideWindowDays(*tideDays); err != nil {
+		fmt.Printf("Error: --tide-days: %v\n", err)
+		os.Exit(1)
+	}
+
+	pressureUnit, err := models.ParsePressureUnit(*pressureUnits)
+	if err != nil {
+		fmt.Printf("Error: --pressure-units: %v\n", err)
+		os.Exit(1)
+	}
+
+	theme, err := ui.LoadTheme(*themeName)
+	if err != nil {
+		fmt.Printf("Error: --theme: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Quitting stops any provisioning in progress so it doesn't leave a partial download behind
+	provisionCtx, stopProvisioning := context.WithCancel(context.Background())
+	defer stopProvisioning()
+
+	model := ui.NewModel(*stationCode, *location, *portName).
+		WithProvisionContext(provisionCtx).
+		WithSmallCraftThresholds(models.SmallCraftThresholds{WindKnots: *scaWind, SeasFeet: *scaSeas}).
+		WithForecastPeriodLimit(*periods).
+		WithTideWindowDays(*tideDays).
+		WithZoneAutoSelect(*zoneAutoSelect).
+		WithPressureUnit(pressureUnit).
+		WithAlertNotifications(*alertBell, *alertCommand).
+		WithASCIIChart(*asciiChart || !ui.BrailleSupported()).
+		WithASCII(*ascii || !ui.BrailleSupported()).
+		WithTheme(theme).
+		WithGeocoder(geo).
+		WithHTTPTimeout(*httpTimeout).
+		WithGeocodeTimeout(*geocodeTimeout).
+		WithObservationHistory(database.NewObservationRepository(database.DBPath())).
+		WithSettings(database.NewSettingsRepository(database.DBPath()))
+	if *here {
+		if *home != "" {
+			lat, lon, err := geocoding.ParseCoordinates(*home)
+			if err != nil {
+				fmt.Printf("Error: --home: %v\n", err)
+				os.Exit(1)
+			}
+			model = model.WithLocator(geocoding.NewFixedLocator(lat, lon))
+		} else {
+			model = model.WithLocator(geocoding.NewIPLocator())
+		}
+	}
+
+	if *debug {
+		// Logging to the terminal would garble the UI
+		logDir := filepath.Dir(database.DBPath())
+		if err := os.MkdirAll(logDir, 0755); err != nil {
+			fmt.Printf("Error: --debug: %v\n", err)
+			os.Exit(1)
+		}
+		logFile, err := tea.LogToFile(filepath.Join(logDir, "debug.log"), "debug")
+		if err != nil {
+			fmt.Printf("Error: --debug: %v\n", err)
+			os.Exit(1)
+		}
+		defer logFile.Close()
+	}
+
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	_, err = p.Run()
+	stopProvisioning()
+	ui.WaitForProvisioning(5 * time.Second)
+	if err != nil {
 		fmt.Printf("Error running application: %v\n", err)
 		os.Exit(1)
 	}
-}
\ No newline at end of file
+}
+
+// runCheckPorts checks every saved port and prints a pass/fail report, returning
+// the number of ports that failed
+func runCheckPorts(httpTimeout time.Duration) (int, error) {
+	saved, err := ports.NewService().ListPorts()
+	if err != nil {
+		return 0, fmt.Errorf("listing saved ports: %w", err)
+	}
+	if len(saved) == 0 {
+		fmt.Println("No saved ports to check.")
+		return 0, nil
+	}
+
+	httpClient := &http.Client{Timeout: httpTimeout}
+	checker := ports.NewChecker(noaa.NewWeatherClientWithHTTPClient(httpClient), noaa.NewTideClientWithHTTPClient(httpClient))
+	results := checker.Check(context.Background(), saved)
+	return ports.WriteCheckReport(os.Stdout, results), nil
+}
+
+// runImportGPX saves a port for each waypoint in a GPX file and prints what was added
+// and skipped. It fails if the file has waypoints but none of them could be saved.
+func runImportGPX(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	result, err := ports.ImportGPX(context.Background(), ports.NewService(), f)
+	if err != nil {
+		return err
+	}
+	ports.WriteGPXImportReport(os.Stdout, result)
+	if len(result.Created) == 0 && len(result.Skipped) > 0 {
+		return fmt.Errorf("no waypoints could be imported")
+	}
+	return nil
+}
+
+// runDumpForecast prints a zone's parsed forecast as JSON without starting the UI
+func runDumpForecast(zone string, httpTimeout time.Duration) error {
+	client := noaa.NewWeatherClientWithHTTPClient(&http.Client{Timeout: httpTimeout})
+	return noaa.WriteForecastJSON(context.Background(), os.Stdout, client, strings.ToUpper(strings.TrimSpace(zone)))
+}
+
+// runWhereAmI prints the marine zone and tide station for a location without saving
+// anything or starting the UI
+func runWhereAmI(query, geocoderName string, cacheTTL, timeout time.Duration) error {
+	geo, err := geocoding.NewGeocoderByName(geocoderName, cacheTTL, timeout)
+	if err != nil {
+		return err
+	}
+	result, err := ports.LookupWhereAmI(context.Background(), geo, database.DBPath(), query)
+	if err != nil {
+		return err
+	}
+	ports.WriteWhereAmIReport(os.Stdout, result)
+	return nil
+}
+
+// runReprovision rebuilds the marine zones and tide stations tables without starting the UI.
+// Each table is replaced in a single transaction, so a failed rebuild keeps the previous data.
+func runReprovision(ctx context.Context, dbPath string) error {
+	if err := zonelookup.ReprovisionDatabaseWithProgress(ctx, dbPath, nil); err != nil {
+		return fmt.Errorf("marine zones: %w", err)
+	}
+	zones, err := zonelookup.Count(dbPath)
+	if err != nil {
+		return err
+	}
+
+	if err := stations.ReprovisionStationsDatabase(dbPath, nil); err != nil {
+		return fmt.Errorf("tide stations: %w", err)
+	}
+	tideStations, err := stations.Count(dbPath)
+	if err != nil {
+		return err
+	}
+
+	edition, err := zonelookup.InstalledEdition(dbPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Re-provisioned %s: %d marine zones (edition %s), %d tide stations\n", dbPath, zones, edition, tideStations)
+	return nil
+}
+
+// runVersion prints the build version and where the reference data in dbPath came from:
+// the marine zones shapefile edition, and the row counts and provisioning dates of each
+// table, so reports about stale data can be checked against them
+func runVersion(dbPath string) error {
+	fmt.Printf("marine-terminal %s\n", version)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		fmt.Printf("Database: %s (not provisioned yet)\n", dbPath)
+		return nil
+	}
+	fmt.Printf("Database: %s\n", dbPath)
+
+	meta := database.NewMetadataRepository(dbPath)
+	tables := []struct {
+		label string
+		key   string
+		empty func(string) (bool, error)
+		count func(string) (int, error)
+	}{
+		{"Marine zones", database.MetadataZonesProvisionedAt, zonelookup.IsEmpty, zonelookup.Count},
+		{"Tide stations", database.MetadataStationsProvisionedAt, stations.IsEmpty, stations.Count},
+		{"Zipcodes", database.MetadataZipcodesProvisionedAt, geocoding.NeedsProvisioning, geocoding.Count},
+	}
+	for _, table := range tables {
+		empty, err := table.empty(dbPath)
+		if err != nil {
+			return err
+		}
+		if empty {
+			fmt.Printf("%-14s not provisioned\n", table.label+":")
+			continue
+		}
+		count, err := table.count(dbPath)
+		if err != nil {
+			return err
+		}
+
+		var details []string
+		if table.key == database.MetadataZonesProvisionedAt {
+			edition, err := zonelookup.InstalledEdition(dbPath)
+			if err != nil {
+				return err
+			}
+			details = append(details, "edition "+edition)
+		}
+		at, err := meta.ProvisionedAt(table.key)
+		if err != nil {
+			return err
+		}
+		if at.IsZero() {
+			details = append(details, "provisioning date unknown")
+		} else {
+			details = append(details, "provisioned "+at.Format("2006-01-02 15:04 MST"))
+		}
+		fmt.Printf("%-14s %d (%s)\n", table.label+":", count, strings.Join(details, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// Conn shares one open connection to a database between callers. It opens the
+// connection on first use and reopens it when asked for a different path, so
+// an overridden database path or a test's temporary database takes effect
+// instead of whichever path happened to be opened first.
+type Conn struct {
+	open func(dbPath string) (*sql.DB, error)
+
+	mu   sync.Mutex
+	path string
+	db   *sql.DB
+}
+
+// NewConn creates a connection manager that opens databases with open
+func NewConn(open func(dbPath string) (*sql.DB, error)) *Conn {
+	return &Conn{open: open}
+}
+
+// Get returns the connection to the database at dbPath, opening it if needed.
+// A failed open isn't remembered, so the next call tries again.
+func (c *Conn) Get(dbPath string) (*sql.DB, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.db != nil && c.path == dbPath {
+		return c.db, nil
+	}
+	if err := c.closeLocked(); err != nil {
+		return nil, err
+	}
+
+	db, err := c.open(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	c.path, c.db = dbPath, db
+	return db, nil
+}
+
+// Set makes db the connection returned for dbPath, e.g. an in-memory database in tests.
+// Any previous connection is closed.
+func (c *Conn) Set(dbPath string, db *sql.DB) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.closeLocked(); err != nil {
+		return err
+	}
+	c.path, c.db = dbPath, db
+	return nil
+}
+
+// Reset closes the current connection, if any, so the next Get opens a fresh one
+func (c *Conn) Reset() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeLocked()
+}
+
+func (c *Conn) closeLocked() error {
+	if c.db == nil {
+		return nil
+	}
+	err := c.db.Close()
+	c.path, c.db = "", nil
+	if err != nil {
+		return fmt.Errorf("closing database: %w", err)
+	}
+	return nil
+}
+
+// OpenTuned opens the SQLite database at dbPath with the pragmas used for the
+// shared lookup connections
+func OpenTuned(dbPath string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil, err
+	}
+	// Set pragmas for performance
+	if _, err := db.Exec(`
+		PRAGMA journal_mode=WAL;
+		PRAGMA synchronous=NORMAL;
+		PRAGMA cache_size=10000;
+	`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("setting pragmas: %w", err)
+	}
+	return db, nil
+}
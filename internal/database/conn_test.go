@@ -0,0 +1,133 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// openLabeled opens a database at path containing a label naming it
+func openLabeled(t *testing.T, path, label string) {
+	t.Helper()
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE label (name TEXT); INSERT INTO label VALUES (?)`, label); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readLabel(t *testing.T, db *sql.DB) string {
+	t.Helper()
+	var name string
+	if err := db.QueryRow("SELECT name FROM label").Scan(&name); err != nil {
+		t.Fatalf("reading label: %v", err)
+	}
+	return name
+}
+
+func TestConn_SwitchesPaths(t *testing.T) {
+	dir := t.TempDir()
+	pathA, pathB := filepath.Join(dir, "a.db"), filepath.Join(dir, "b.db")
+	openLabeled(t, pathA, "A")
+	openLabeled(t, pathB, "B")
+
+	opens := 0
+	conn := NewConn(func(dbPath string) (*sql.DB, error) {
+		opens++
+		return OpenTuned(dbPath)
+	})
+	t.Cleanup(func() { conn.Reset() })
+
+	dbA, err := conn.Get(pathA)
+	if err != nil {
+		t.Fatalf("Get(a) error = %v", err)
+	}
+	if again, _ := conn.Get(pathA); again != dbA || opens != 1 {
+		t.Errorf("second Get(a) opened %d times, want the cached connection", opens)
+	}
+
+	// Asking for another path must not return the first database
+	dbB, err := conn.Get(pathB)
+	if err != nil {
+		t.Fatalf("Get(b) error = %v", err)
+	}
+	if got := readLabel(t, dbB); got != "B" {
+		t.Errorf("Get(b) connected to database %s, want B", got)
+	}
+	if err := dbA.Ping(); err == nil {
+		t.Error("connection to a should be closed after switching to b")
+	}
+}
+
+func TestConn_ResetAndSet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.db")
+	openLabeled(t, path, "A")
+
+	conn := NewConn(OpenTuned)
+	first, err := conn.Get(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Reset(); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	second, err := conn.Get(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second == first {
+		t.Error("Get after Reset should open a new connection")
+	}
+
+	injected, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := injected.Exec(`CREATE TABLE label (name TEXT); INSERT INTO label VALUES ('memory')`); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Set(path, injected); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Reset() })
+	db, _ := conn.Get(path)
+	if got := readLabel(t, db); got != "memory" {
+		t.Errorf("Get after Set connected to %s, want the injected database", got)
+	}
+}
+
+func TestConn_FailedOpenIsRetried(t *testing.T) {
+	fail := true
+	conn := NewConn(func(dbPath string) (*sql.DB, error) {
+		if fail {
+			return nil, errors.New("provisioning failed")
+		}
+		return sql.Open("sqlite", ":memory:")
+	})
+	t.Cleanup(func() { conn.Reset() })
+
+	if _, err := conn.Get("test.db"); err == nil {
+		t.Fatal("Get() succeeded, want the open error")
+	}
+	fail = false
+	if _, err := conn.Get("test.db"); err != nil {
+		t.Errorf("Get() after a failed open error = %v, want a retry", err)
+	}
+}
+
+func TestSetDBPath(t *testing.T) {
+	t.Cleanup(func() { SetDBPath("") })
+
+	SetDBPath("/tmp/other.db")
+	if got := DBPath(); got != "/tmp/other.db" {
+		t.Errorf("DBPath() = %s, want the override", got)
+	}
+	SetDBPath("")
+	if got := DBPath(); got != filepath.Join("data", "marine-terminal.db") {
+		t.Errorf("DBPath() = %s, want the default after clearing the override", got)
+	}
+}
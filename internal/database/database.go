@@ -8,9 +8,24 @@ import (
 	_ "modernc.org/sqlite"
 )
 
+// defaultDBPath is where the database lives unless SetDBPath overrides it
+var defaultDBPath = filepath.Join("data", "marine-terminal.db")
+
+// currentDBPath is the database used by the rest of the application
+var currentDBPath = defaultDBPath
+
 // DBPath returns the path to the single shared database
 func DBPath() string {
-	return filepath.Join("data", "marine-terminal.db")
+	return currentDBPath
+}
+
+// SetDBPath overrides the path returned by DBPath (e.g. from --db-path).
+// An empty path restores the default.
+func SetDBPath(path string) {
+	if path == "" {
+		path = defaultDBPath
+	}
+	currentDBPath = path
 }
 
 // EnsureUserSchema ensures that the user-specific tables (like user_ports) exist.
@@ -32,7 +47,10 @@ func EnsureUserSchema(dbPath string) error {
 			tide_station_id TEXT NOT NULL,
 			latitude REAL NOT NULL,
 			longitude REAL NOT NULL,
-			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			preferred_datum TEXT NOT NULL DEFAULT 'MLLW',
+			preferred_zone_type TEXT NOT NULL DEFAULT '',
+			is_home INTEGER NOT NULL DEFAULT 0
 		);
 		CREATE UNIQUE INDEX IF NOT EXISTS idx_user_ports_name ON user_ports(name);
 	`)
@@ -40,5 +58,53 @@ func EnsureUserSchema(dbPath string) error {
 		return fmt.Errorf("creating user_ports table: %w", err)
 	}
 
+	if err := migrateUserPorts(db); err != nil {
+		return fmt.Errorf("migrating user_ports table: %w", err)
+	}
+
+	return nil
+}
+
+// userPortColumns lists columns added to user_ports after its initial release,
+// with the definitions used to add them to existing databases. Databases from
+// versions that stored a preferred_units column keep it; nothing reads it.
+var userPortColumns = []struct {
+	name       string
+	definition string
+}{
+	{"preferred_datum", "TEXT NOT NULL DEFAULT 'MLLW'"},
+	{"preferred_zone_type", "TEXT NOT NULL DEFAULT ''"},
+	{"is_home", "INTEGER NOT NULL DEFAULT 0"},
+}
+
+// migrateUserPorts adds any missing columns to a user_ports table created by an older version
+func migrateUserPorts(db *sql.DB) error {
+	rows, err := db.Query("PRAGMA table_info(user_ports)")
+	if err != nil {
+		return fmt.Errorf("reading table info: %w", err)
+	}
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning table info: %w", err)
+		}
+		existing[name] = true
+	}
+	rows.Close()
+
+	for _, col := range userPortColumns {
+		if existing[col.name] {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE user_ports ADD COLUMN %s %s", col.name, col.definition)); err != nil {
+			return fmt.Errorf("adding column %s: %w", col.name, err)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,131 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// Keys of the metadata table recording when each reference table was provisioned
+const (
+	MetadataZonesProvisionedAt    = "marine_zones_provisioned_at"
+	MetadataStationsProvisionedAt = "tide_stations_provisioned_at"
+	MetadataZipcodesProvisionedAt = "zipcodes_provisioned_at"
+)
+
+// Execer runs a statement, e.g. a *sql.DB or a *sql.Tx
+type Execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// WriteMetadata stores value as the metadata key, creating the metadata table if
+// needed. Passing the transaction that fills a reference table records both together.
+func WriteMetadata(db Execer, key, value string) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS metadata (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("creating metadata table: %w", err)
+	}
+
+	if _, err := db.Exec("INSERT OR REPLACE INTO metadata (key, value) VALUES (?, ?)", key, value); err != nil {
+		return fmt.Errorf("writing metadata %s: %w", key, err)
+	}
+	return nil
+}
+
+// RecordProvisioned stores at as when the reference table behind key was provisioned
+func RecordProvisioned(db Execer, key string, at time.Time) error {
+	return WriteMetadata(db, key, at.UTC().Format(time.RFC3339))
+}
+
+// MetadataRepository reads and writes the metadata table describing where the
+// provisioned reference data came from, e.g. the marine zones shapefile edition
+type MetadataRepository struct {
+	dbPath string
+
+	once sync.Once
+	db   *sql.DB
+	err  error
+}
+
+// NewMetadataRepository creates a repository for the database at dbPath.
+// The database is opened on first use.
+func NewMetadataRepository(dbPath string) *MetadataRepository {
+	return &MetadataRepository{dbPath: dbPath}
+}
+
+// newMetadataRepositoryFromDB creates a repository using the provided database connection
+func newMetadataRepositoryFromDB(db *sql.DB) *MetadataRepository {
+	r := &MetadataRepository{db: db}
+	r.once.Do(func() {})
+	return r
+}
+
+// open returns the repository's database connection
+func (r *MetadataRepository) open() (*sql.DB, error) {
+	r.once.Do(func() {
+		r.db, r.err = sql.Open("sqlite", r.dbPath)
+		if r.err != nil {
+			r.err = fmt.Errorf("opening metadata database: %w", r.err)
+		}
+	})
+	return r.db, r.err
+}
+
+// Get returns the value stored as key, or "" if it, or the metadata table, is missing
+func (r *MetadataRepository) Get(key string) (string, error) {
+	db, err := r.open()
+	if err != nil {
+		return "", err
+	}
+
+	var tables int
+	err = db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name='metadata'").Scan(&tables)
+	if err != nil {
+		return "", fmt.Errorf("checking for metadata table: %w", err)
+	}
+	if tables == 0 {
+		return "", nil
+	}
+
+	var value string
+	err = db.QueryRow("SELECT value FROM metadata WHERE key = ?", key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading metadata %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// Set stores value as key, replacing any earlier value
+func (r *MetadataRepository) Set(key, value string) error {
+	db, err := r.open()
+	if err != nil {
+		return err
+	}
+	return WriteMetadata(db, key, value)
+}
+
+// ProvisionedAt returns when the reference table behind key was provisioned, or the
+// zero time if that wasn't recorded, e.g. for tables provisioned by older versions
+func (r *MetadataRepository) ProvisionedAt(key string) (time.Time, error) {
+	value, err := r.Get(key)
+	if err != nil || value == "" {
+		return time.Time{}, err
+	}
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing metadata %s: %w", key, err)
+	}
+	return at, nil
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func newTestMetadataRepository(t *testing.T) (*MetadataRepository, *sql.DB) {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open in-memory database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return newMetadataRepositoryFromDB(db), db
+}
+
+func TestMetadataRepository_GetAndSet(t *testing.T) {
+	repo, _ := newTestMetadataRepository(t)
+
+	// Nothing is recorded before the metadata table exists
+	got, err := repo.Get("marine_zones_edition")
+	if err != nil || got != "" {
+		t.Errorf("Get() without a metadata table = %q, %v; want empty", got, err)
+	}
+
+	for _, value := range []string{"mz05mr24", "mz18mr25"} {
+		if err := repo.Set("marine_zones_edition", value); err != nil {
+			t.Fatalf("Set(%q) failed: %v", value, err)
+		}
+		got, err := repo.Get("marine_zones_edition")
+		if err != nil || got != value {
+			t.Errorf("Get() = %q, %v; want %q", got, err, value)
+		}
+	}
+
+	if got, err := repo.Get("missing"); err != nil || got != "" {
+		t.Errorf("Get() for a missing key = %q, %v; want empty", got, err)
+	}
+}
+
+func TestMetadataRepository_ProvisionedAt(t *testing.T) {
+	repo, db := newTestMetadataRepository(t)
+
+	at, err := repo.ProvisionedAt(MetadataStationsProvisionedAt)
+	if err != nil || !at.IsZero() {
+		t.Errorf("ProvisionedAt() before provisioning = %v, %v; want the zero time", at, err)
+	}
+
+	// Recorded in the transaction that fills the table, as provisioning does
+	provisioned := time.Date(2025, 11, 26, 9, 3, 0, 0, time.FixedZone("EST", -5*60*60))
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordProvisioned(tx, MetadataStationsProvisionedAt, provisioned); err != nil {
+		t.Fatalf("RecordProvisioned failed: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	at, err = repo.ProvisionedAt(MetadataStationsProvisionedAt)
+	if err != nil || !at.Equal(provisioned) || at.Location() != time.UTC {
+		t.Errorf("ProvisionedAt() = %v, %v; want %v in UTC", at, err, provisioned)
+	}
+	if at, err := repo.ProvisionedAt(MetadataZipcodesProvisionedAt); err != nil || !at.IsZero() {
+		t.Errorf("ProvisionedAt() for another table = %v, %v; want the zero time", at, err)
+	}
+
+	if err := repo.Set(MetadataZipcodesProvisionedAt, "last tuesday"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.ProvisionedAt(MetadataZipcodesProvisionedAt); err == nil {
+		t.Error("ProvisionedAt() should fail for an unparseable date")
+	}
+}
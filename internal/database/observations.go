@@ -0,0 +1,184 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	_ "modernc.org/sqlite"
+)
+
+// ObservationRetention is how long stored observations are kept. Older rows are
+// pruned whenever new observations are recorded.
+const ObservationRetention = 48 * time.Hour
+
+// Kinds of station observation stored in the observations table
+const (
+	ObservationAirTemp   = "air_temp"
+	ObservationWaterTemp = "water_temp"
+)
+
+// ObservationRepository persists station observations in the observations table so
+// temperature trends survive a restart
+type ObservationRepository struct {
+	dbPath string
+	clock  models.Clock
+
+	once sync.Once
+	db   *sql.DB
+	err  error
+}
+
+// NewObservationRepository creates a repository stored in the database at dbPath.
+// The database is opened on first use.
+func NewObservationRepository(dbPath string) *ObservationRepository {
+	return &ObservationRepository{dbPath: dbPath, clock: models.SystemClock}
+}
+
+// newObservationRepositoryFromDB creates a repository using the provided database connection
+func newObservationRepositoryFromDB(db *sql.DB, clock models.Clock) *ObservationRepository {
+	r := &ObservationRepository{clock: clock, db: db}
+	r.once.Do(func() { r.err = ensureObservationSchema(db) })
+	return r
+}
+
+// ensureObservationSchema creates the observations table if it doesn't exist
+func ensureObservationSchema(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS observations (
+			station_id TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			observed_at DATETIME NOT NULL,
+			value REAL NOT NULL,
+			PRIMARY KEY (station_id, kind, observed_at)
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("creating observations table: %w", err)
+	}
+	return nil
+}
+
+// open returns the repository's database connection, creating the table on first use
+func (r *ObservationRepository) open() (*sql.DB, error) {
+	r.once.Do(func() {
+		r.db, r.err = sql.Open("sqlite", r.dbPath)
+		if r.err != nil {
+			r.err = fmt.Errorf("opening observations database: %w", r.err)
+			return
+		}
+		r.err = ensureObservationSchema(r.db)
+	})
+	return r.db, r.err
+}
+
+// InsertObservation stores one observation of kind for a station, replacing any
+// existing value at the same time
+func (r *ObservationRepository) InsertObservation(stationID, kind string, obs models.Observation) error {
+	db, err := r.open()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(
+		`INSERT OR REPLACE INTO observations (station_id, kind, observed_at, value) VALUES (?, ?, ?, ?)`,
+		stationID, kind, obs.Time.UTC(), obs.Value,
+	)
+	if err != nil {
+		return fmt.Errorf("writing observation: %w", err)
+	}
+	return nil
+}
+
+// RecordObservations stores a station's histories, keyed by kind, and prunes anything
+// older than ObservationRetention. It's all done in one transaction, so a fetch's
+// observations are stored together or not at all.
+func (r *ObservationRepository) RecordObservations(stationID string, series map[string][]models.Observation) error {
+	db, err := r.open()
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT OR REPLACE INTO observations (station_id, kind, observed_at, value) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("preparing insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for kind, history := range series {
+		for _, obs := range history {
+			if _, err := stmt.Exec(stationID, kind, obs.Time.UTC(), obs.Value); err != nil {
+				return fmt.Errorf("writing observation: %w", err)
+			}
+		}
+	}
+	if err := r.prune(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing observations: %w", err)
+	}
+	return nil
+}
+
+// RecentObservations returns a station's observations of kind from the last
+// ObservationRetention, oldest first
+func (r *ObservationRepository) RecentObservations(stationID, kind string) ([]models.Observation, error) {
+	db, err := r.open()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(
+		`SELECT observed_at, value FROM observations
+		WHERE station_id = ? AND kind = ? AND observed_at >= ?
+		ORDER BY observed_at`,
+		stationID, kind, r.clock.Now().Add(-ObservationRetention).UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying observations: %w", err)
+	}
+	defer rows.Close()
+
+	var history []models.Observation
+	for rows.Next() {
+		var obs models.Observation
+		if err := rows.Scan(&obs.Time, &obs.Value); err != nil {
+			return nil, fmt.Errorf("scanning observation: %w", err)
+		}
+		history = append(history, obs)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("querying observations: %w", err)
+	}
+	return history, nil
+}
+
+// Prune deletes observations older than ObservationRetention
+func (r *ObservationRepository) Prune() error {
+	db, err := r.open()
+	if err != nil {
+		return err
+	}
+	return r.prune(db)
+}
+
+// prune deletes observations older than ObservationRetention with db, e.g. a transaction
+func (r *ObservationRepository) prune(db Execer) error {
+	_, err := db.Exec(
+		`DELETE FROM observations WHERE observed_at < ?`,
+		r.clock.Now().Add(-ObservationRetention).UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("pruning observations: %w", err)
+	}
+	return nil
+}
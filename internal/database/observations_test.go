@@ -0,0 +1,135 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+func newTestObservationRepository(t *testing.T, now time.Time) *ObservationRepository {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open in-memory database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	repo := newObservationRepositoryFromDB(db, models.FixedClock(now))
+	if repo.err != nil {
+		t.Fatalf("Failed to create observations table: %v", repo.err)
+	}
+	return repo
+}
+
+func TestObservationRepository_InsertAndRecent(t *testing.T) {
+	now := time.Date(2025, 11, 26, 12, 0, 0, 0, time.UTC)
+	repo := newTestObservationRepository(t, now)
+
+	// Inserted out of order, with one value replaced and one for another station
+	inserts := []struct {
+		station string
+		kind    string
+		obs     models.Observation
+	}{
+		{"8447435", ObservationAirTemp, models.Observation{Time: now.Add(-time.Hour), Value: 48}},
+		{"8447435", ObservationAirTemp, models.Observation{Time: now.Add(-3 * time.Hour), Value: 44}},
+		{"8447435", ObservationAirTemp, models.Observation{Time: now.Add(-time.Hour), Value: 47}},
+		{"8447435", ObservationWaterTemp, models.Observation{Time: now.Add(-time.Hour), Value: 51}},
+		{"8443970", ObservationAirTemp, models.Observation{Time: now.Add(-time.Hour), Value: 40}},
+	}
+	for _, in := range inserts {
+		if err := repo.InsertObservation(in.station, in.kind, in.obs); err != nil {
+			t.Fatalf("InsertObservation failed: %v", err)
+		}
+	}
+
+	got, err := repo.RecentObservations("8447435", ObservationAirTemp)
+	if err != nil {
+		t.Fatalf("RecentObservations failed: %v", err)
+	}
+	want := []models.Observation{
+		{Time: now.Add(-3 * time.Hour), Value: 44},
+		{Time: now.Add(-time.Hour), Value: 47},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("RecentObservations returned %d observations, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !got[i].Time.Equal(want[i].Time) || got[i].Value != want[i].Value {
+			t.Errorf("observation %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	none, err := repo.RecentObservations("9999999", ObservationAirTemp)
+	if err != nil {
+		t.Fatalf("RecentObservations failed: %v", err)
+	}
+	if len(none) != 0 {
+		t.Errorf("Expected no observations for an unknown station, got %v", none)
+	}
+}
+
+func TestObservationRepository_Retention(t *testing.T) {
+	now := time.Date(2025, 11, 26, 12, 0, 0, 0, time.UTC)
+	repo := newTestObservationRepository(t, now)
+
+	history := []models.Observation{
+		{Time: now.Add(-72 * time.Hour), Value: 40},
+		{Time: now.Add(-ObservationRetention - time.Minute), Value: 41},
+		{Time: now.Add(-24 * time.Hour), Value: 42},
+		{Time: now, Value: 43},
+	}
+	for _, obs := range history {
+		if err := repo.InsertObservation("8447435", ObservationWaterTemp, obs); err != nil {
+			t.Fatalf("InsertObservation failed: %v", err)
+		}
+	}
+
+	got, err := repo.RecentObservations("8447435", ObservationWaterTemp)
+	if err != nil {
+		t.Fatalf("RecentObservations failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Value != 42 || got[1].Value != 43 {
+		t.Errorf("RecentObservations should skip observations older than %v, got %v", ObservationRetention, got)
+	}
+
+	if err := repo.RecordObservations("8447435", nil); err != nil {
+		t.Fatalf("RecordObservations failed: %v", err)
+	}
+	var rows int
+	if err := repo.db.QueryRow("SELECT COUNT(*) FROM observations").Scan(&rows); err != nil {
+		t.Fatalf("Failed to count observations: %v", err)
+	}
+	if rows != 2 {
+		t.Errorf("Expected pruning to leave 2 rows, got %d", rows)
+	}
+}
+
+func TestObservationRepository_RecordObservations(t *testing.T) {
+	now := time.Date(2025, 11, 26, 12, 0, 0, 0, time.UTC)
+	repo := newTestObservationRepository(t, now)
+
+	err := repo.RecordObservations("8447435", map[string][]models.Observation{
+		ObservationAirTemp:   {{Time: now.Add(-time.Hour), Value: 50}, {Time: now, Value: 51}},
+		ObservationWaterTemp: {{Time: now.Add(-72 * time.Hour), Value: 44}, {Time: now, Value: 45}},
+	})
+	if err != nil {
+		t.Fatalf("RecordObservations failed: %v", err)
+	}
+
+	air, err := repo.RecentObservations("8447435", ObservationAirTemp)
+	if err != nil || len(air) != 2 {
+		t.Errorf("air temperatures = %v, %v; want both stored", air, err)
+	}
+	// The old water temperature is pruned in the same transaction
+	var rows int
+	if err := repo.db.QueryRow("SELECT COUNT(*) FROM observations WHERE kind = ?", ObservationWaterTemp).Scan(&rows); err != nil {
+		t.Fatalf("Failed to count observations: %v", err)
+	}
+	if rows != 1 {
+		t.Errorf("stored %d water temperatures, want the old one pruned", rows)
+	}
+}
@@ -55,3 +55,55 @@ func TestEnsureUserSchema_Persistence(t *testing.T) {
 		t.Errorf("Expected 1 record, got %d. Data was likely lost due to table drop.", count)
 	}
 }
+
+func TestEnsureUserSchema_MigratesPreferenceColumns(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	// Create a user_ports table as written by older versions (no preference columns)
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	_, err = db.Exec(`
+		CREATE TABLE user_ports (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			state TEXT,
+			city TEXT,
+			zipcode TEXT,
+			marine_zone_id TEXT NOT NULL,
+			tide_station_id TEXT NOT NULL,
+			latitude REAL NOT NULL,
+			longitude REAL NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+		INSERT INTO user_ports (name, marine_zone_id, tide_station_id, latitude, longitude) VALUES ('Old Port', 'Z1', 'S1', 0.0, 0.0);
+	`)
+	db.Close()
+	if err != nil {
+		t.Fatalf("Failed to create legacy table: %v", err)
+	}
+
+	if err := EnsureUserSchema(dbPath); err != nil {
+		t.Fatalf("EnsureUserSchema failed: %v", err)
+	}
+	// Running again must not try to re-add the columns
+	if err := EnsureUserSchema(dbPath); err != nil {
+		t.Fatalf("Second EnsureUserSchema failed: %v", err)
+	}
+
+	db, err = sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	var datum, zoneType string
+	err = db.QueryRow("SELECT preferred_datum, preferred_zone_type FROM user_ports WHERE name = 'Old Port'").Scan(&datum, &zoneType)
+	if err != nil {
+		t.Fatalf("Failed to query preferences: %v", err)
+	}
+	if datum != "MLLW" || zoneType != "" {
+		t.Errorf("existing row preferences = %s/%q, want MLLW with no zone type", datum, zoneType)
+	}
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	_ "modernc.org/sqlite"
+)
+
+// Keys of the preferences stored in the settings table
+const (
+	SettingTimeFormat = "time_format" // "12h" or "24h"
+)
+
+// SettingsRepository persists UI preferences chosen while the app is running in the
+// settings table, so they survive a restart
+type SettingsRepository struct {
+	dbPath string
+
+	once sync.Once
+	db   *sql.DB
+	err  error
+}
+
+// NewSettingsRepository creates a repository stored in the database at dbPath.
+// The database is opened on first use.
+func NewSettingsRepository(dbPath string) *SettingsRepository {
+	return &SettingsRepository{dbPath: dbPath}
+}
+
+// newSettingsRepositoryFromDB creates a repository using the provided database connection
+func newSettingsRepositoryFromDB(db *sql.DB) *SettingsRepository {
+	r := &SettingsRepository{db: db}
+	r.once.Do(func() { r.err = ensureSettingsSchema(db) })
+	return r
+}
+
+// ensureSettingsSchema creates the settings table if it doesn't exist
+func ensureSettingsSchema(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("creating settings table: %w", err)
+	}
+	return nil
+}
+
+// open returns the repository's database connection, creating the table on first use
+func (r *SettingsRepository) open() (*sql.DB, error) {
+	r.once.Do(func() {
+		r.db, r.err = sql.Open("sqlite", r.dbPath)
+		if r.err != nil {
+			r.err = fmt.Errorf("opening settings database: %w", r.err)
+			return
+		}
+		r.err = ensureSettingsSchema(r.db)
+	})
+	return r.db, r.err
+}
+
+// Get returns the stored value of the setting key, or "" if it has never been set
+func (r *SettingsRepository) Get(key string) (string, error) {
+	db, err := r.open()
+	if err != nil {
+		return "", err
+	}
+
+	var value string
+	err = db.QueryRow("SELECT value FROM settings WHERE key = ?", key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading setting %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// Set stores value as the setting key, replacing any earlier value
+func (r *SettingsRepository) Set(key, value string) error {
+	db, err := r.open()
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.Exec("INSERT OR REPLACE INTO settings (key, value) VALUES (?, ?)", key, value); err != nil {
+		return fmt.Errorf("writing setting %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestSettingsRepository_GetAndSet(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open in-memory database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	repo := newSettingsRepositoryFromDB(db)
+	if repo.err != nil {
+		t.Fatalf("Failed to create settings table: %v", repo.err)
+	}
+
+	got, err := repo.Get(SettingTimeFormat)
+	if err != nil || got != "" {
+		t.Errorf("Get() before Set = %q, %v; want empty", got, err)
+	}
+
+	for _, value := range []string{"24h", "12h"} {
+		if err := repo.Set(SettingTimeFormat, value); err != nil {
+			t.Fatalf("Set(%q) failed: %v", value, err)
+		}
+		got, err := repo.Get(SettingTimeFormat)
+		if err != nil || got != value {
+			t.Errorf("Get() = %q, %v; want %q", got, err, value)
+		}
+	}
+}
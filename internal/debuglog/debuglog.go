@@ -0,0 +1,20 @@
+// Package debuglog logs details that are only wanted with --debug, such as which URL a
+// forecast was fetched from, for every package that has them
+package debuglog
+
+import "log"
+
+// enabled turns on logging, see SetEnabled
+var enabled bool
+
+// SetEnabled turns debug logging on or off. Messages go to the standard logger.
+func SetEnabled(on bool) {
+	enabled = on
+}
+
+// Printf logs a message when debug logging is on
+func Printf(format string, args ...any) {
+	if enabled {
+		log.Printf(format, args...)
+	}
+}
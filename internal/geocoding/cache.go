@@ -0,0 +1,124 @@
+package geocoding
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	_ "modernc.org/sqlite"
+)
+
+// DefaultCacheTTL is how long a geocoded query is reused before it is looked up
+// again. Places rarely move, so results are kept for a long time.
+const DefaultCacheTTL = 30 * 24 * time.Hour
+
+// GeocodeCache persists network geocoding results in the geocode_cache table so
+// repeated searches for the same place don't query the remote service
+type GeocodeCache struct {
+	dbPath string
+	ttl    time.Duration
+	clock  models.Clock
+
+	once sync.Once
+	db   *sql.DB
+	err  error
+}
+
+// NewGeocodeCache creates a cache stored in the database at dbPath. The database is
+// opened on first use. A ttl of zero or less disables the cache.
+func NewGeocodeCache(dbPath string, ttl time.Duration) *GeocodeCache {
+	return &GeocodeCache{dbPath: dbPath, ttl: ttl, clock: models.SystemClock}
+}
+
+// newGeocodeCacheFromDB creates a cache using the provided database connection
+func newGeocodeCacheFromDB(db *sql.DB, ttl time.Duration, clock models.Clock) *GeocodeCache {
+	c := &GeocodeCache{ttl: ttl, clock: clock, db: db}
+	c.once.Do(func() { c.err = ensureCacheSchema(db) })
+	return c
+}
+
+// ensureCacheSchema creates the geocode_cache table if it doesn't exist
+func ensureCacheSchema(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS geocode_cache (
+			query TEXT PRIMARY KEY,
+			latitude REAL NOT NULL,
+			longitude REAL NOT NULL,
+			name TEXT NOT NULL,
+			cached_at DATETIME NOT NULL
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("creating geocode_cache table: %w", err)
+	}
+	return nil
+}
+
+// open returns the cache's database connection, creating the table on first use
+func (c *GeocodeCache) open() (*sql.DB, error) {
+	c.once.Do(func() {
+		c.db, c.err = sql.Open("sqlite", c.dbPath)
+		if c.err != nil {
+			c.err = fmt.Errorf("opening geocode cache: %w", c.err)
+			return
+		}
+		c.err = ensureCacheSchema(c.db)
+	})
+	return c.db, c.err
+}
+
+// cacheKey normalizes a query so differences in case and spacing share an entry
+func cacheKey(query string) string {
+	return strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
+
+// Get returns the cached location for query, or nil if there is no entry younger than the TTL
+func (c *GeocodeCache) Get(query string) (*Location, error) {
+	if c == nil || c.ttl <= 0 {
+		return nil, nil
+	}
+	db, err := c.open()
+	if err != nil {
+		return nil, err
+	}
+
+	var loc Location
+	var cachedAt time.Time
+	err = db.QueryRow(
+		"SELECT latitude, longitude, name, cached_at FROM geocode_cache WHERE query = ?",
+		cacheKey(query),
+	).Scan(&loc.Latitude, &loc.Longitude, &loc.Name, &cachedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("querying geocode cache: %w", err)
+	}
+	if c.clock.Now().Sub(cachedAt) >= c.ttl {
+		return nil, nil
+	}
+	return &loc, nil
+}
+
+// Put stores the location for query, replacing any existing entry
+func (c *GeocodeCache) Put(query string, loc *Location) error {
+	if c == nil || c.ttl <= 0 || loc == nil {
+		return nil
+	}
+	db, err := c.open()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(
+		`INSERT OR REPLACE INTO geocode_cache (query, latitude, longitude, name, cached_at) VALUES (?, ?, ?, ?, ?)`,
+		cacheKey(query), loc.Latitude, loc.Longitude, loc.Name, c.clock.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("writing geocode cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package geocoding
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+func newTestCache(t *testing.T, ttl time.Duration, now time.Time) *GeocodeCache {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open in-memory database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	cache := newGeocodeCacheFromDB(db, ttl, models.FixedClock(now))
+	if cache.err != nil {
+		t.Fatalf("creating cache schema: %v", cache.err)
+	}
+	return cache
+}
+
+func TestGeocodeCache_GetPut(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	cache := newTestCache(t, 30*24*time.Hour, now)
+	chatham := &Location{Latitude: 41.6885, Longitude: -69.9511, Name: "Chatham, MA"}
+
+	if loc, err := cache.Get("Chatham, MA"); err != nil || loc != nil {
+		t.Fatalf("Get() on empty cache = %v, %v; want nil, nil", loc, err)
+	}
+	if err := cache.Put("Chatham, MA", chatham); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		age     time.Duration
+		wantHit bool
+	}{
+		{"same query", "Chatham, MA", 0, true},
+		{"case and spacing differ", "  chatham,   ma ", 0, true},
+		{"different query", "Harwich, MA", 0, false},
+		{"just before TTL", "Chatham, MA", 30*24*time.Hour - time.Minute, true},
+		{"at TTL", "Chatham, MA", 30 * 24 * time.Hour, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache.clock = models.FixedClock(now.Add(tt.age))
+			loc, err := cache.Get(tt.query)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if (loc != nil) != tt.wantHit {
+				t.Fatalf("Get(%q) hit = %v, want %v", tt.query, loc != nil, tt.wantHit)
+			}
+			if loc != nil && *loc != *chatham {
+				t.Errorf("Get(%q) = %+v, want %+v", tt.query, *loc, *chatham)
+			}
+		})
+	}
+}
+
+func TestGeocodeCache_Disabled(t *testing.T) {
+	cache := newTestCache(t, 0, time.Now())
+	if err := cache.Put("Chatham, MA", &Location{Name: "Chatham, MA"}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if loc, _ := cache.Get("Chatham, MA"); loc != nil {
+		t.Error("cache with zero TTL should never hit")
+	}
+}
+
+func TestCensusGeocoder_CachedQuerySkipsNetwork(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	g, _, requests := newTestCensusGeocoder(t, "census_geocode_response.json")
+	g.cache = newTestCache(t, DefaultCacheTTL, now)
+
+	first, err := g.Geocode(context.Background(), "2 Bridge St, Chatham, MA")
+	if err != nil {
+		t.Fatalf("Geocode() error = %v", err)
+	}
+	second, err := g.Geocode(context.Background(), "2 bridge st, chatham, ma")
+	if err != nil {
+		t.Fatalf("Geocode() error = %v", err)
+	}
+	if *requests != 1 {
+		t.Errorf("Census geocoder queried %d times, want 1", *requests)
+	}
+	if *first != *second {
+		t.Errorf("cached location = %+v, want %+v", *second, *first)
+	}
+
+	// Once the entry expires the Census geocoder is queried again
+	g.cache.clock = models.FixedClock(now.Add(DefaultCacheTTL))
+	if _, err := g.Geocode(context.Background(), "2 Bridge St, Chatham, MA"); err != nil {
+		t.Fatalf("Geocode() error = %v", err)
+	}
+	if *requests != 2 {
+		t.Errorf("Census geocoder queried %d times after expiry, want 2", *requests)
+	}
+}
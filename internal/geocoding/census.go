@@ -0,0 +1,125 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ngmaloney/marine-terminal/internal/database"
+)
+
+// CensusGeocoder implements Geocoder using the US Census Bureau geocoder, which is
+// free, has no rate limit and resolves full US street addresses. Zipcodes, and
+// queries the Census geocoder can't match (e.g. a bare "City, ST"), are resolved
+// with the local zipcode database instead. Matches are cached in the database so
+// repeated searches don't query the Census geocoder again.
+type CensusGeocoder struct {
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+	fallback   Geocoder
+	cache      *GeocodeCache
+}
+
+// NewCensusGeocoder creates a geocoder backed by geocoding.geo.census.gov whose
+// matches are cached for cacheTTL (zero disables the cache)
+func NewCensusGeocoder(cacheTTL time.Duration) *CensusGeocoder {
+	return NewCensusGeocoderWithHTTPClient(cacheTTL, &http.Client{Timeout: CensusTimeout})
+}
+
+// NewCensusGeocoderWithHTTPClient is NewCensusGeocoder sending its requests with
+// httpClient, e.g. one with a different timeout or transport
+func NewCensusGeocoderWithHTTPClient(cacheTTL time.Duration, httpClient *http.Client) *CensusGeocoder {
+	return &CensusGeocoder{
+		baseURL:    "https://geocoding.geo.census.gov/geocoder",
+		httpClient: httpClient,
+		userAgent:  "MarineTerminal/1.0 (github.com/ngmaloney/marine-terminal)",
+		fallback:   NewGeocoder(),
+		cache:      NewGeocodeCache(database.DBPath(), cacheTTL),
+	}
+}
+
+// censusResponse is the subset of the Census onelineaddress JSON response we use
+type censusResponse struct {
+	Result struct {
+		AddressMatches []struct {
+			MatchedAddress string `json:"matchedAddress"`
+			Coordinates    struct {
+				X float64 `json:"x"` // Longitude
+				Y float64 `json:"y"` // Latitude
+			} `json:"coordinates"`
+		} `json:"addressMatches"`
+	} `json:"result"`
+}
+
+// Geocode converts a query (zipcode, "City, ST" or street address) to coordinates
+func (g *CensusGeocoder) Geocode(ctx context.Context, query string) (*Location, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("query cannot be empty")
+	}
+
+	if isZipcode(query) {
+		return g.fallback.Geocode(ctx, query)
+	}
+
+	// The cache only speeds up lookups, so a cache that can't be read or written
+	// falls through to the Census geocoder
+	if cached, err := g.cache.Get(query); err == nil && cached != nil {
+		return cached, nil
+	}
+
+	loc, err := g.lookup(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if loc == nil {
+		return g.fallback.Geocode(ctx, query)
+	}
+	_ = g.cache.Put(query, loc)
+	return loc, nil
+}
+
+// lookup queries the Census geocoder, returning nil if it has no match
+func (g *CensusGeocoder) lookup(ctx context.Context, query string) (*Location, error) {
+	params := url.Values{}
+	params.Add("address", query)
+	params.Add("benchmark", "Public_AR_Current")
+	params.Add("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", g.baseURL+"/locations/onelineaddress?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", g.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying Census geocoder: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Census geocoder returned status %d", resp.StatusCode)
+	}
+
+	var data censusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("decoding Census geocoder response: %w", err)
+	}
+	if len(data.Result.AddressMatches) == 0 {
+		return nil, nil
+	}
+
+	match := data.Result.AddressMatches[0]
+	return &Location{
+		Latitude:  match.Coordinates.Y,
+		Longitude: match.Coordinates.X,
+		Name:      match.MatchedAddress,
+	}, nil
+}
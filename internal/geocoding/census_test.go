@@ -0,0 +1,144 @@
+package geocoding
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// stubGeocoder records queries and resolves everything to a fixed location
+type stubGeocoder struct {
+	queries []string
+}
+
+func (s *stubGeocoder) Geocode(ctx context.Context, query string) (*Location, error) {
+	s.queries = append(s.queries, query)
+	return &Location{Latitude: 41.6885, Longitude: -69.9511, Name: "Chatham, MA"}, nil
+}
+
+func newTestCensusGeocoder(t *testing.T, fixture string) (*CensusGeocoder, *stubGeocoder, *int) {
+	t.Helper()
+	data, err := os.ReadFile("../../testdata/" + fixture)
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/locations/onelineaddress" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("format") != "json" || r.URL.Query().Get("address") == "" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+
+	fallback := &stubGeocoder{}
+	g := NewCensusGeocoder(0)
+	g.baseURL = server.URL
+	g.fallback = fallback
+	return g, fallback, &requests
+}
+
+func TestCensusGeocoder_Geocode(t *testing.T) {
+	g, fallback, _ := newTestCensusGeocoder(t, "census_geocode_response.json")
+
+	loc, err := g.Geocode(context.Background(), "2 Bridge St, Chatham, MA")
+	if err != nil {
+		t.Fatalf("Geocode() error = %v", err)
+	}
+	if loc.Latitude != 41.67437180524 || loc.Longitude != -69.95914373271 {
+		t.Errorf("coordinates = (%v, %v), want (41.67437180524, -69.95914373271)", loc.Latitude, loc.Longitude)
+	}
+	if loc.Name != "2 BRIDGE ST, CHATHAM, MA, 02633" {
+		t.Errorf("Name = %q", loc.Name)
+	}
+	if len(fallback.queries) != 0 {
+		t.Errorf("fallback used for a matched address: %v", fallback.queries)
+	}
+}
+
+func TestCensusGeocoder_NoMatchFallsBack(t *testing.T) {
+	g, fallback, requests := newTestCensusGeocoder(t, "census_geocode_nomatch.json")
+
+	loc, err := g.Geocode(context.Background(), "Chatham, MA")
+	if err != nil {
+		t.Fatalf("Geocode() error = %v", err)
+	}
+	if *requests != 1 || len(fallback.queries) != 1 || loc.Name != "Chatham, MA" {
+		t.Errorf("requests = %d, fallback queries = %v; want Census then local lookup", *requests, fallback.queries)
+	}
+}
+
+func TestCensusGeocoder_ZipcodeUsesLocal(t *testing.T) {
+	g, fallback, requests := newTestCensusGeocoder(t, "census_geocode_response.json")
+
+	if _, err := g.Geocode(context.Background(), "02633"); err != nil {
+		t.Fatalf("Geocode() error = %v", err)
+	}
+	if *requests != 0 {
+		t.Errorf("Census geocoder queried %d times for a zipcode, want 0", *requests)
+	}
+	if len(fallback.queries) != 1 || fallback.queries[0] != "02633" {
+		t.Errorf("fallback queries = %v, want [02633]", fallback.queries)
+	}
+}
+
+func TestCensusGeocoder_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	g := NewCensusGeocoder(0)
+	g.baseURL = server.URL
+	g.fallback = &stubGeocoder{}
+
+	if _, err := g.Geocode(context.Background(), "2 Bridge St, Chatham, MA"); err == nil {
+		t.Error("Expected error for bad status")
+	}
+	if _, err := g.Geocode(context.Background(), "  "); err == nil {
+		t.Error("Expected error for empty query")
+	}
+}
+
+func TestNewGeocoderByName(t *testing.T) {
+	tests := []struct {
+		name        string
+		wantType    string
+		wantTimeout time.Duration
+		wantErr     bool
+	}{
+		{"", "*geocoding.LocalGeocoder", DefaultTimeout, false},
+		{"local", "*geocoding.LocalGeocoder", DefaultTimeout, false},
+		{"Census", "*geocoding.CensusGeocoder", CensusTimeout, false},
+		{"nominatim", "", DefaultTimeout, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeout := DefaultTimeoutFor(tt.name)
+			if timeout != tt.wantTimeout {
+				t.Errorf("DefaultTimeoutFor(%q) = %v, want %v", tt.name, timeout, tt.wantTimeout)
+			}
+			g, err := NewGeocoderByName(tt.name, 0, timeout)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewGeocoderByName(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if !tt.wantErr && fmt.Sprintf("%T", g) != tt.wantType {
+				t.Errorf("NewGeocoderByName(%q) = %T, want %s", tt.name, g, tt.wantType)
+			}
+			if census, ok := g.(*CensusGeocoder); ok && census.httpClient.Timeout != CensusTimeout {
+				t.Errorf("census geocoder timeout = %v, want %v", census.httpClient.Timeout, CensusTimeout)
+			}
+		})
+	}
+}
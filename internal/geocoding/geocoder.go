@@ -3,12 +3,20 @@ package geocoding
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"regexp"
 	"strings"
+	"time"
 )
 
-// Geocoder converts addresses to coordinates using local database
-type Geocoder struct{}
+// Geocoder defines the interface for converting place names to coordinates
+type Geocoder interface {
+	// Geocode converts a query (zipcode or "City, ST") to coordinates
+	Geocode(ctx context.Context, query string) (*Location, error)
+}
+
+// LocalGeocoder implements Geocoder using the local zipcode database
+type LocalGeocoder struct{}
 
 // Location represents a geocoded location
 type Location struct {
@@ -18,12 +26,12 @@ type Location struct {
 }
 
 // NewGeocoder creates a new geocoder
-func NewGeocoder() *Geocoder {
-	return &Geocoder{}
+func NewGeocoder() *LocalGeocoder {
+	return &LocalGeocoder{}
 }
 
 // Geocode converts a query (zipcode, city/state, etc.) to coordinates
-func (g *Geocoder) Geocode(ctx context.Context, query string) (*Location, error) {
+func (g *LocalGeocoder) Geocode(ctx context.Context, query string) (*Location, error) {
 	query = strings.TrimSpace(query)
 	if query == "" {
 		return nil, fmt.Errorf("query cannot be empty")
@@ -35,8 +43,11 @@ func (g *Geocoder) Geocode(ctx context.Context, query string) (*Location, error)
 	}
 
 	// For city/state queries, parse and use local database
-	// Expected format: "City, ST" or "City, State"
+	// Expected format: "City, ST" or "City, State", or just "City" if only one state has it
 	parts := strings.Split(query, ",")
+	if len(parts) == 1 {
+		return geocodeCity(query)
+	}
 	if len(parts) != 2 {
 		return nil, fmt.Errorf("invalid format: expected 'City, State' (e.g., 'Chatham, MA')")
 	}
@@ -52,9 +63,76 @@ func (g *Geocoder) Geocode(ctx context.Context, query string) (*Location, error)
 	return lookupCityState(city, state)
 }
 
+// AmbiguousCityError is returned for a search naming a city without a state when
+// more than one state has a city of that name. Candidates holds one location per state
+// for the user to choose from.
+type AmbiguousCityError struct {
+	City       string
+	Candidates []Location
+}
+
+func (e *AmbiguousCityError) Error() string {
+	return fmt.Sprintf("'%s' matches places in %d states: add the state (e.g., '%s, MA')", e.City, len(e.Candidates), e.City)
+}
+
+// geocodeCity looks up a city without a state, returning an AmbiguousCityError if it
+// could be in more than one state
+func geocodeCity(city string) (*Location, error) {
+	candidates, err := lookupCityCandidates(city)
+	if err != nil {
+		return nil, err
+	}
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("no location found for %s: expected 'City, State' (e.g., 'Chatham, MA')", city)
+	case 1:
+		return &candidates[0], nil
+	default:
+		return nil, &AmbiguousCityError{City: city, Candidates: candidates}
+	}
+}
+
 // isZipcode checks if a string looks like a US zipcode
 func isZipcode(s string) bool {
 	// Match 5-digit or 9-digit (with hyphen) zipcodes
 	matched, _ := regexp.MatchString(`^\d{5}(-\d{4})?$`, s)
 	return matched
 }
+
+// Geocoder backends selectable by name
+const (
+	BackendLocal  = "local"  // Offline zipcode database
+	BackendCensus = "census" // US Census Bureau geocoder, for street addresses
+)
+
+// Backends lists the geocoder backend names accepted by NewGeocoderByName
+var Backends = []string{BackendLocal, BackendCensus}
+
+// How long a search may take to geocode unless configured otherwise. The census
+// geocoder is a network service, so it's given longer.
+const (
+	DefaultTimeout = 10 * time.Second
+	CensusTimeout  = 15 * time.Second
+)
+
+// DefaultTimeoutFor returns the default timeout of the geocoder backend with the given name
+func DefaultTimeoutFor(name string) time.Duration {
+	if strings.EqualFold(strings.TrimSpace(name), BackendCensus) {
+		return CensusTimeout
+	}
+	return DefaultTimeout
+}
+
+// NewGeocoderByName creates the geocoder backend with the given name.
+// An empty name selects the local backend. Network backends give up on a request
+// after timeout and cache their results for cacheTTL; zero disables caching.
+func NewGeocoderByName(name string, cacheTTL, timeout time.Duration) (Geocoder, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", BackendLocal:
+		return NewGeocoder(), nil
+	case BackendCensus:
+		return NewCensusGeocoderWithHTTPClient(cacheTTL, &http.Client{Timeout: timeout}), nil
+	default:
+		return nil, fmt.Errorf("unknown geocoder %q: expected one of %s", name, strings.Join(Backends, ", "))
+	}
+}
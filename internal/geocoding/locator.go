@@ -0,0 +1,200 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locator determines the approximate position of the machine running the app
+type Locator interface {
+	Locate(ctx context.Context) (*Location, error)
+}
+
+// IPLocator looks up an approximate position from the public IP address.
+// The request reveals the caller's IP address to the lookup service, so it
+// must only be used when the user has opted in.
+type IPLocator struct {
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+	maxRetries int           // Retries after a 429 or 503 response
+	retryDelay time.Duration // Initial backoff, doubled on each retry unless Retry-After says otherwise
+}
+
+// ErrLocationServiceBusy is returned when the IP location service keeps rejecting requests
+var ErrLocationServiceBusy = errors.New("location service busy, try again later")
+
+// maxRetryAfter caps how long a Retry-After header can make us wait
+const maxRetryAfter = 30 * time.Second
+
+// NewIPLocator creates a locator backed by the ipapi.co geolocation service
+func NewIPLocator() *IPLocator {
+	return &IPLocator{
+		baseURL: "https://ipapi.co",
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		userAgent:  "MarineTerminal/1.0 (github.com/ngmaloney/marine-terminal)",
+		maxRetries: 3,
+		retryDelay: time.Second,
+	}
+}
+
+// ipapiResponse is the subset of the ipapi.co JSON response we use
+type ipapiResponse struct {
+	City      string  `json:"city"`
+	Region    string  `json:"region_code"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Error     bool    `json:"error"`
+	Reason    string  `json:"reason"`
+}
+
+// Locate returns the approximate position of the current public IP address.
+// Rate-limited (429) and unavailable (503) responses are retried with backoff.
+func (l *IPLocator) Locate(ctx context.Context) (*Location, error) {
+	delay := l.retryDelay
+	for attempt := 0; ; attempt++ {
+		resp, err := l.fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+			wait := retryAfter(resp.Header.Get("Retry-After"), delay)
+			resp.Body.Close()
+			if attempt >= l.maxRetries {
+				return nil, fmt.Errorf("%w (status %d after %d attempts)", ErrLocationServiceBusy, resp.StatusCode, attempt+1)
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+			}
+			delay *= 2
+			continue
+		}
+
+		defer resp.Body.Close()
+		return decodeIPLocation(resp)
+	}
+}
+
+// fetch sends a single lookup request
+func (l *IPLocator) fetch(ctx context.Context) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", l.baseURL+"/json/", nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", l.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("looking up IP location: %w", err)
+	}
+	return resp, nil
+}
+
+// retryAfter returns the wait requested by a Retry-After header (seconds or HTTP date),
+// or fallback if the header is missing or invalid
+func retryAfter(header string, fallback time.Duration) time.Duration {
+	if header == "" {
+		return fallback
+	}
+	var wait time.Duration
+	if secs, err := strconv.Atoi(header); err == nil {
+		wait = time.Duration(secs) * time.Second
+	} else if at, err := http.ParseTime(header); err == nil {
+		wait = time.Until(at)
+	} else {
+		return fallback
+	}
+	if wait < 0 {
+		return 0
+	}
+	if wait > maxRetryAfter {
+		return maxRetryAfter
+	}
+	return wait
+}
+
+// decodeIPLocation converts a successful lookup response into a Location
+func decodeIPLocation(resp *http.Response) (*Location, error) {
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("IP location service returned status %d", resp.StatusCode)
+	}
+
+	var data ipapiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("decoding IP location: %w", err)
+	}
+	if data.Error {
+		return nil, fmt.Errorf("IP location service error: %s", data.Reason)
+	}
+	if data.Latitude == 0 && data.Longitude == 0 {
+		return nil, fmt.Errorf("IP location service returned no coordinates")
+	}
+
+	name := "Current location"
+	if data.City != "" && data.Region != "" {
+		name = fmt.Sprintf("%s, %s", data.City, data.Region)
+	}
+
+	return &Location{
+		Latitude:  data.Latitude,
+		Longitude: data.Longitude,
+		Name:      name,
+	}, nil
+}
+
+// FixedLocator always returns a preconfigured home coordinate
+type FixedLocator struct {
+	location Location
+}
+
+// NewFixedLocator creates a locator that always reports the given coordinate
+func NewFixedLocator(lat, lon float64) *FixedLocator {
+	return &FixedLocator{
+		location: Location{Latitude: lat, Longitude: lon, Name: "Home"},
+	}
+}
+
+// Locate returns the configured home coordinate
+func (l *FixedLocator) Locate(ctx context.Context) (*Location, error) {
+	loc := l.location
+	return &loc, nil
+}
+
+// ParseCoordinates parses a "lat,lon" string into a latitude and longitude
+func ParseCoordinates(s string) (float64, float64, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid coordinates %q: expected 'lat,lon' (e.g., '41.68,-69.95')", s)
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid latitude %q: %w", parts[0], err)
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid longitude %q: %w", parts[1], err)
+	}
+
+	if lat < -90 || lat > 90 {
+		return 0, 0, fmt.Errorf("latitude %v out of range [-90, 90]", lat)
+	}
+	if lon < -180 || lon > 180 {
+		return 0, 0, fmt.Errorf("longitude %v out of range [-180, 180]", lon)
+	}
+
+	return lat, lon, nil
+}
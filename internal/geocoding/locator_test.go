@@ -0,0 +1,181 @@
+package geocoding
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIPLocator_Locate(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		wantErr  bool
+		wantName string
+	}{
+		{
+			name:     "success",
+			status:   http.StatusOK,
+			body:     `{"city":"Chatham","region_code":"MA","latitude":41.68,"longitude":-69.95}`,
+			wantName: "Chatham, MA",
+		},
+		{
+			name:     "no city",
+			status:   http.StatusOK,
+			body:     `{"latitude":41.68,"longitude":-69.95}`,
+			wantName: "Current location",
+		},
+		{
+			name:    "service error",
+			status:  http.StatusOK,
+			body:    `{"error":true,"reason":"RateLimited"}`,
+			wantErr: true,
+		},
+		{
+			name:    "no coordinates",
+			status:  http.StatusOK,
+			body:    `{"city":"Nowhere"}`,
+			wantErr: true,
+		},
+		{
+			name:    "bad status",
+			status:  http.StatusInternalServerError,
+			body:    `{}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/json/" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			locator := NewIPLocator()
+			locator.baseURL = server.URL
+
+			loc, err := locator.Locate(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Locate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if loc.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", loc.Name, tt.wantName)
+			}
+			if loc.Latitude != 41.68 || loc.Longitude != -69.95 {
+				t.Errorf("coordinates = (%v, %v), want (41.68, -69.95)", loc.Latitude, loc.Longitude)
+			}
+		})
+	}
+}
+
+func TestIPLocator_RetriesRateLimit(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"city":"Chatham","region_code":"MA","latitude":41.68,"longitude":-69.95}`))
+	}))
+	defer server.Close()
+
+	locator := NewIPLocator()
+	locator.baseURL = server.URL
+
+	loc, err := locator.Locate(context.Background())
+	if err != nil {
+		t.Fatalf("Locate() error = %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+	if loc.Name != "Chatham, MA" {
+		t.Errorf("Name = %q, want %q", loc.Name, "Chatham, MA")
+	}
+}
+
+func TestIPLocator_GivesUpWhenBusy(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	locator := NewIPLocator()
+	locator.baseURL = server.URL
+	locator.retryDelay = time.Millisecond
+
+	_, err := locator.Locate(context.Background())
+	if !errors.Is(err, ErrLocationServiceBusy) {
+		t.Fatalf("Locate() error = %v, want ErrLocationServiceBusy", err)
+	}
+	if requests != locator.maxRetries+1 {
+		t.Errorf("requests = %d, want %d", requests, locator.maxRetries+1)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	fallback := 2 * time.Second
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+	}{
+		{"missing", "", fallback},
+		{"seconds", "5", 5 * time.Second},
+		{"capped", "3600", maxRetryAfter},
+		{"past date", "Mon, 02 Jan 2006 15:04:05 GMT", 0},
+		{"invalid", "soon", fallback},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryAfter(tt.header, fallback); got != tt.want {
+				t.Errorf("retryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCoordinates(t *testing.T) {
+	tests := []struct {
+		input   string
+		lat     float64
+		lon     float64
+		wantErr bool
+	}{
+		{"41.68,-69.95", 41.68, -69.95, false},
+		{" 41.68 , -69.95 ", 41.68, -69.95, false},
+		{"41.68", 0, 0, true},
+		{"north,west", 0, 0, true},
+		{"91,0", 0, 0, true},
+		{"0,181", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			lat, lon, err := ParseCoordinates(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCoordinates(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if lat != tt.lat || lon != tt.lon {
+				t.Errorf("ParseCoordinates(%q) = (%v, %v), want (%v, %v)", tt.input, lat, lon, tt.lat, tt.lon)
+			}
+		})
+	}
+}
@@ -5,15 +5,25 @@ import (
 	"encoding/csv"
 	"fmt"
 	"io"
-	"log"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"sync"
+	"time"
 
+	"github.com/ngmaloney/marine-terminal/internal/database"
+	"github.com/ngmaloney/marine-terminal/internal/provision"
 	_ "modernc.org/sqlite"
 )
 
+// provisionMu serializes provisioning, so the lazy provisioning on a first lookup and
+// the provisioning screen can't both build the zipcodes table at once
+var provisionMu sync.Mutex
+
+// zipcodeCSVPath locates the bundled zipcode CSV; tests point it elsewhere
+var zipcodeCSVPath = getZipcodeCSVPath
+
 // getZipcodeCSVPath returns the path to the bundled zipcode CSV file
 // It looks for testdata/uszips.csv relative to the module root
 func getZipcodeCSVPath() string {
@@ -38,7 +48,8 @@ func getZipcodeCSVPath() string {
 	return "testdata/uszips.csv"
 }
 
-// NeedsProvisioning checks if the zipcode database needs to be provisioned
+// NeedsProvisioning checks if the zipcode database needs to be provisioned: the
+// zipcodes table is missing, or empty because an earlier run failed partway
 func NeedsProvisioning(dbPath string) (bool, error) {
 	// If file doesn't exist, we need to provision
 	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
@@ -57,8 +68,30 @@ func NeedsProvisioning(dbPath string) (bool, error) {
 	if err != nil {
 		return false, fmt.Errorf("checking for zipcodes table: %w", err)
 	}
-	
-	return count == 0, nil
+	if count == 0 {
+		return true, nil
+	}
+
+	var hasRows bool
+	if err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM zipcodes)").Scan(&hasRows); err != nil {
+		return false, fmt.Errorf("counting zipcodes: %w", err)
+	}
+	return !hasRows, nil
+}
+
+// Count returns the number of rows in the zipcodes table
+func Count(dbPath string) (int, error) {
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return 0, fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM zipcodes").Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting zipcodes: %w", err)
+	}
+	return count, nil
 }
 
 // ProvisionZipcodeDatabase downloads and builds the zipcode table
@@ -66,8 +99,13 @@ func ProvisionZipcodeDatabase(dbPath string) error {
 	return ProvisionZipcodeDatabaseWithProgress(dbPath, nil)
 }
 
-// ProvisionZipcodeDatabaseWithProgress builds the zipcode table from bundled CSV data
-func ProvisionZipcodeDatabaseWithProgress(dbPath string, progressChan chan<- string) error {
+// ProvisionZipcodeDatabaseWithProgress builds the zipcode table from bundled CSV data.
+// It's safe to call again, including concurrently: a table already filled is kept, and
+// one left missing or empty by a failed run is built again.
+func ProvisionZipcodeDatabaseWithProgress(dbPath string, progressChan chan<- provision.Progress) error {
+	provisionMu.Lock()
+	defer provisionMu.Unlock()
+
 	needs, err := NeedsProvisioning(dbPath)
 	if err != nil {
 		return err
@@ -77,11 +115,7 @@ func ProvisionZipcodeDatabaseWithProgress(dbPath string, progressChan chan<- str
 	}
 
 	sendProgress := func(msg string) {
-		if progressChan != nil {
-			progressChan <- msg
-		} else {
-			log.Println(msg)
-		}
+		provision.Send(progressChan, provision.Status(msg))
 	}
 
 	sendProgress("Zipcode table not found, provisioning...")
@@ -93,7 +127,7 @@ func ProvisionZipcodeDatabaseWithProgress(dbPath string, progressChan chan<- str
 	}
 
 	// Get path to bundled CSV
-	csvPath := getZipcodeCSVPath()
+	csvPath := zipcodeCSVPath()
 
 	// Verify bundled CSV exists
 	if _, err := os.Stat(csvPath); os.IsNotExist(err) {
@@ -111,7 +145,7 @@ func ProvisionZipcodeDatabaseWithProgress(dbPath string, progressChan chan<- str
 }
 
 // buildZipcodeDatabase creates a SQLite database from the CSV file
-func buildZipcodeDatabase(csvPath, dbPath string, progressChan chan<- string) error {
+func buildZipcodeDatabase(csvPath, dbPath string, progressChan chan<- provision.Progress) error {
 	// Open database
 	db, err := sql.Open("sqlite", dbPath)
 	if err != nil {
@@ -119,8 +153,15 @@ func buildZipcodeDatabase(csvPath, dbPath string, progressChan chan<- string) er
 	}
 	defer db.Close()
 
+	// Build the table in one transaction, so a failed run leaves no partial table behind
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // Rollback on error
+
 	// Create table
-	_, err = db.Exec(`
+	_, err = tx.Exec(`
 		CREATE TABLE IF NOT EXISTS zipcodes (
 			zipcode TEXT PRIMARY KEY,
 			city TEXT NOT NULL,
@@ -134,7 +175,7 @@ func buildZipcodeDatabase(csvPath, dbPath string, progressChan chan<- string) er
 	}
 
 	// Create indexes for efficient lookups
-	_, err = db.Exec(`
+	_, err = tx.Exec(`
 		CREATE INDEX IF NOT EXISTS idx_state ON zipcodes(state);
 		CREATE INDEX IF NOT EXISTS idx_city_state ON zipcodes(city, state);
 	`)
@@ -149,6 +190,12 @@ func buildZipcodeDatabase(csvPath, dbPath string, progressChan chan<- string) er
 	}
 	defer file.Close()
 
+	// Estimate progress from bytes consumed, since the row count isn't known up front
+	var fileSize int
+	if info, err := file.Stat(); err == nil {
+		fileSize = int(info.Size())
+	}
+
 	reader := csv.NewReader(file)
 
 	// Skip header
@@ -158,18 +205,12 @@ func buildZipcodeDatabase(csvPath, dbPath string, progressChan chan<- string) er
 	}
 
 	// Prepare insert statement
-	stmt, err := db.Prepare("INSERT OR IGNORE INTO zipcodes (zipcode, city, state, latitude, longitude) VALUES (?, ?, ?, ?, ?)")
+	stmt, err := tx.Prepare("INSERT OR IGNORE INTO zipcodes (zipcode, city, state, latitude, longitude) VALUES (?, ?, ?, ?, ?)")
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	// Begin transaction for faster inserts
-	tx, err := db.Begin()
-	if err != nil {
-		return err
-	}
-
 	count := 0
 	for {
 		record, err := reader.Read()
@@ -198,32 +239,32 @@ func buildZipcodeDatabase(csvPath, dbPath string, progressChan chan<- string) er
 			continue
 		}
 
-		_, err = tx.Stmt(stmt).Exec(zipcode, city, state, lat, lon)
+		_, err = stmt.Exec(zipcode, city, state, lat, lon)
 		if err != nil {
 			continue
 		}
 
 		count++
-		if count%5000 == 0 {
+		if count%2500 == 0 {
 			msg := fmt.Sprintf("Processed %d zipcodes...", count)
-			if progressChan != nil {
-				progressChan <- msg
-			} else {
-				log.Println(msg)
-			}
+			provision.Send(progressChan, provision.Step(msg, int(reader.InputOffset()), fileSize))
 		}
 	}
 
+	if count == 0 {
+		return fmt.Errorf("no zipcodes read from %s", filepath.Base(csvPath))
+	}
+
+	if err := database.RecordProvisioned(tx, database.MetadataZipcodesProvisionedAt, time.Now()); err != nil {
+		return err
+	}
+
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
 		return err
 	}
 
 	msg := fmt.Sprintf("Successfully created database with %d zipcodes", count)
-	if progressChan != nil {
-		progressChan <- msg
-	} else {
-		log.Println(msg)
-	}
+	provision.Send(progressChan, provision.Step(msg, fileSize, fileSize))
 	return nil
 }
@@ -3,40 +3,30 @@ package geocoding
 import (
 	"database/sql"
 	"fmt"
-	"sync"
 
 	"github.com/ngmaloney/marine-terminal/internal/database"
 	_ "modernc.org/sqlite"
 )
 
-var (
-	zipDB     *sql.DB
-	zipDBOnce sync.Once
-	initErr   error
-)
+// zipConn is the shared connection used by getZipcodeDB. The zipcodes table is
+// provisioned on first use; a failed attempt isn't remembered, so a later lookup
+// provisions again.
+var zipConn = database.NewConn(func(dbPath string) (*sql.DB, error) {
+	// Provision database if it doesn't exist
+	if err := ProvisionZipcodeDatabase(dbPath); err != nil {
+		return nil, err
+	}
+	return database.OpenTuned(dbPath)
+})
 
-// getZipcodeDB returns the singleton database connection
+// getZipcodeDB returns the shared database connection for dbPath
 func getZipcodeDB(dbPath string) (*sql.DB, error) {
-	zipDBOnce.Do(func() {
-		// Provision database if it doesn't exist
-		initErr = ProvisionZipcodeDatabase(dbPath)
-		if initErr != nil {
-			return
-		}
-
-		zipDB, initErr = sql.Open("sqlite", dbPath)
-		if initErr != nil {
-			return
-		}
+	return zipConn.Get(dbPath)
+}
 
-		// Set pragmas for performance
-		_, initErr = zipDB.Exec(`
-			PRAGMA journal_mode=WAL;
-			PRAGMA synchronous=NORMAL;
-			PRAGMA cache_size=10000;
-		`)
-	})
-	return zipDB, initErr
+// ResetZipcodeDB closes the shared zipcode connection so the next lookup opens the database again
+func ResetZipcodeDB() error {
+	return zipConn.Reset()
 }
 
 // lookupZipcode looks up a zipcode in the SQLite database and returns a Location
@@ -58,6 +48,16 @@ func lookupCityState(city, state string) (*Location, error) {
 	return lookupCityStateInDB(db, city, state)
 }
 
+// lookupCityCandidates looks up a city without a state in the SQLite database and
+// returns one Location per state with a city of that name
+func lookupCityCandidates(city string) ([]Location, error) {
+	db, err := getZipcodeDB(database.DBPath())
+	if err != nil {
+		return nil, fmt.Errorf("opening zipcode database: %w", err)
+	}
+	return lookupCityCandidatesInDB(db, city)
+}
+
 // lookupZipcodeInDB looks up a zipcode in the provided database connection
 func lookupZipcodeInDB(db *sql.DB, zipcode string) (*Location, error) {
 	var city, state string
@@ -105,3 +105,36 @@ func lookupCityStateInDB(db *sql.DB, city, state string) (*Location, error) {
 		Name:      fmt.Sprintf("%s, %s %s", foundCity, foundState, zipcode),
 	}, nil
 }
+
+// lookupCityCandidatesInDB looks up a city in every state in the provided database
+// connection, ignoring case. Each state's candidate is its first zipcode, and the
+// candidates are ordered by state.
+func lookupCityCandidatesInDB(db *sql.DB, city string) ([]Location, error) {
+	// SQLite takes the bare columns from the row holding MIN(zipcode)
+	rows, err := db.Query(
+		"SELECT MIN(zipcode), city, state, latitude, longitude FROM zipcodes WHERE city = ? COLLATE NOCASE GROUP BY state ORDER BY state",
+		city,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying city: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []Location
+	for rows.Next() {
+		var zipcode, foundCity, state string
+		var lat, lon float64
+		if err := rows.Scan(&zipcode, &foundCity, &state, &lat, &lon); err != nil {
+			return nil, fmt.Errorf("scanning city: %w", err)
+		}
+		candidates = append(candidates, Location{
+			Latitude:  lat,
+			Longitude: lon,
+			Name:      fmt.Sprintf("%s, %s %s", foundCity, state, zipcode),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("querying city: %w", err)
+	}
+	return candidates, nil
+}
@@ -1,9 +1,14 @@
 package geocoding
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/ngmaloney/marine-terminal/internal/database"
 	_ "modernc.org/sqlite"
 )
 
@@ -141,3 +146,120 @@ func TestLookupCityStateInDB(t *testing.T) {
 		})
 	}
 }
+
+func TestLookupCityCandidatesInDB(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open in-memory database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`
+		CREATE TABLE zipcodes (
+			zipcode TEXT PRIMARY KEY,
+			city TEXT NOT NULL,
+			state TEXT NOT NULL,
+			latitude REAL NOT NULL,
+			longitude REAL NOT NULL
+		);
+		INSERT INTO zipcodes (zipcode, city, state, latitude, longitude)
+		VALUES
+			('12037', 'Chatham', 'NY', 42.3643, -73.5949),
+			('02650', 'Chatham', 'MA', 41.7001, -69.9600),
+			('02633', 'Chatham', 'MA', 41.6885, -69.9511),
+			('07928', 'Chatham', 'NJ', 40.7409, -74.3838),
+			('98101', 'Seattle', 'WA', 47.6062, -122.3321)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create test data: %v", err)
+	}
+
+	candidates, err := lookupCityCandidatesInDB(db, "chatham")
+	if err != nil {
+		t.Fatalf("lookupCityCandidatesInDB() error = %v", err)
+	}
+	want := []Location{
+		{Latitude: 41.6885, Longitude: -69.9511, Name: "Chatham, MA 02633"},
+		{Latitude: 40.7409, Longitude: -74.3838, Name: "Chatham, NJ 07928"},
+		{Latitude: 42.3643, Longitude: -73.5949, Name: "Chatham, NY 12037"},
+	}
+	if len(candidates) != len(want) {
+		t.Fatalf("lookupCityCandidatesInDB() = %v, want %v", candidates, want)
+	}
+	for i := range want {
+		if candidates[i] != want[i] {
+			t.Errorf("candidate %d = %v, want %v", i, candidates[i], want[i])
+		}
+	}
+
+	// Geocoding the bare city asks which one was meant; a unique city resolves directly
+	if err := zipConn.Set(database.DBPath(), db); err != nil {
+		t.Fatalf("Failed to install test database: %v", err)
+	}
+	t.Cleanup(func() { ResetZipcodeDB() })
+
+	_, err = NewGeocoder().Geocode(context.Background(), "Chatham")
+	var ambiguous *AmbiguousCityError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("Geocode(Chatham) error = %v, want AmbiguousCityError", err)
+	}
+	if len(ambiguous.Candidates) != 3 {
+		t.Errorf("AmbiguousCityError has %d candidates, want 3", len(ambiguous.Candidates))
+	}
+
+	loc, err := NewGeocoder().Geocode(context.Background(), "Seattle")
+	if err != nil || loc.Name != "Seattle, WA 98101" {
+		t.Errorf("Geocode(Seattle) = %v, %v, want Seattle, WA 98101", loc, err)
+	}
+
+	if _, err := NewGeocoder().Geocode(context.Background(), "Nowhere"); err == nil {
+		t.Error("Geocode(Nowhere) expected error, got nil")
+	}
+}
+
+func TestGetZipcodeDB_RetriesAfterFailedProvisioning(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "test.db")
+	t.Cleanup(func() { ResetZipcodeDB() })
+
+	origCSVPath := zipcodeCSVPath
+	t.Cleanup(func() { zipcodeCSVPath = origCSVPath })
+	writeCSV := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	header := `"zip","lat","lng","city","state_id","state_name"` + "\n"
+	broken := writeCSV("broken.csv", header+`"02633","not a latitude","-69.9511","Chatham","MA","Massachusetts"`+"\n")
+	good := writeCSV("good.csv", header+`"02633","41.6885","-69.9511","Chatham","MA","Massachusetts"`+"\n")
+
+	// The first lookup's provisioning reads no zipcodes and fails, leaving no empty table behind
+	zipcodeCSVPath = func() string { return broken }
+	if _, err := getZipcodeDB(dbPath); err == nil {
+		t.Fatal("getZipcodeDB() should fail when provisioning reads no zipcodes")
+	}
+	if needs, err := NeedsProvisioning(dbPath); err != nil || !needs {
+		t.Errorf("NeedsProvisioning() after a failed run = %v, %v; want true", needs, err)
+	}
+
+	// The failure isn't remembered, so the next lookup provisions again and succeeds
+	zipcodeCSVPath = func() string { return good }
+	db, err := getZipcodeDB(dbPath)
+	if err != nil {
+		t.Fatalf("getZipcodeDB() retry error = %v", err)
+	}
+	loc, err := lookupZipcodeInDB(db, "02633")
+	if err != nil || loc.Name != "Chatham, MA 02633" {
+		t.Errorf("lookupZipcodeInDB() = %v, %v; want Chatham, MA 02633", loc, err)
+	}
+
+	// Provisioning again keeps the filled table
+	if err := ProvisionZipcodeDatabase(dbPath); err != nil {
+		t.Errorf("ProvisionZipcodeDatabase() on a provisioned database = %v", err)
+	}
+	if needs, err := NeedsProvisioning(dbPath); err != nil || needs {
+		t.Errorf("NeedsProvisioning() after provisioning = %v, %v; want false", needs, err)
+	}
+}
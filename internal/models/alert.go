@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"sort"
+	"strings"
+	"time"
+)
 
 // AlertSeverity represents the severity level of an alert
 type AlertSeverity string
@@ -13,31 +17,141 @@ const (
 	SeverityUnknown  AlertSeverity = "Unknown"
 )
 
+// Rank orders severities from Unknown (0) to Extreme (4)
+func (s AlertSeverity) Rank() int {
+	switch s {
+	case SeverityExtreme:
+		return 4
+	case SeveritySevere:
+		return 3
+	case SeverityModerate:
+		return 2
+	case SeverityMinor:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// AlertUrgency is how soon responsive action should be taken, per the CAP urgency field
+type AlertUrgency string
+
+const (
+	UrgencyImmediate AlertUrgency = "Immediate"
+	UrgencyExpected  AlertUrgency = "Expected"
+	UrgencyFuture    AlertUrgency = "Future"
+	UrgencyPast      AlertUrgency = "Past"
+	UrgencyUnknown   AlertUrgency = "Unknown"
+)
+
+// ParseAlertUrgency maps NOAA's urgency text to an AlertUrgency, ignoring case.
+// Unrecognized values are UrgencyUnknown.
+func ParseAlertUrgency(s string) AlertUrgency {
+	for _, u := range []AlertUrgency{UrgencyImmediate, UrgencyExpected, UrgencyFuture, UrgencyPast} {
+		if strings.EqualFold(strings.TrimSpace(s), string(u)) {
+			return u
+		}
+	}
+	return UrgencyUnknown
+}
+
+// Rank orders urgencies from Past and Unknown (0) to Immediate (3)
+func (u AlertUrgency) Rank() int {
+	switch u {
+	case UrgencyImmediate:
+		return 3
+	case UrgencyExpected:
+		return 2
+	case UrgencyFuture:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// AlertCertainty is how likely the event is, per the CAP certainty field
+type AlertCertainty string
+
+const (
+	CertaintyObserved AlertCertainty = "Observed"
+	CertaintyLikely   AlertCertainty = "Likely"
+	CertaintyPossible AlertCertainty = "Possible"
+	CertaintyUnlikely AlertCertainty = "Unlikely"
+	CertaintyUnknown  AlertCertainty = "Unknown"
+)
+
+// ParseAlertCertainty maps NOAA's certainty text to an AlertCertainty, ignoring case.
+// Unrecognized values are CertaintyUnknown.
+func ParseAlertCertainty(s string) AlertCertainty {
+	for _, c := range []AlertCertainty{CertaintyObserved, CertaintyLikely, CertaintyPossible, CertaintyUnlikely} {
+		if strings.EqualFold(strings.TrimSpace(s), string(c)) {
+			return c
+		}
+	}
+	return CertaintyUnknown
+}
+
+// Rank orders certainties from Unlikely and Unknown (0) to Observed (3)
+func (c AlertCertainty) Rank() int {
+	switch c {
+	case CertaintyObserved:
+		return 3
+	case CertaintyLikely:
+		return 2
+	case CertaintyPossible:
+		return 1
+	default:
+		return 0
+	}
+}
+
 // Alert represents a NOAA weather or marine alert
 type Alert struct {
-	ID          string
-	Event       string        // e.g., "Small Craft Advisory", "Gale Warning"
-	Headline    string
-	Description string
-	Severity    AlertSeverity
-	Urgency     string        // e.g., "Immediate", "Expected"
-	Certainty   string        // e.g., "Likely", "Possible"
-	Onset       time.Time
-	Expires     time.Time
-	Areas       []string      // Affected areas
-	Instruction string        // What to do
+	ID          string         `json:"id"`
+	Event       string         `json:"event"` // e.g., "Small Craft Advisory", "Gale Warning"
+	Headline    string         `json:"headline"`
+	Description string         `json:"description"`
+	Severity    AlertSeverity  `json:"severity"`
+	Urgency     AlertUrgency   `json:"urgency"`
+	Certainty   AlertCertainty `json:"certainty"`
+	Onset       time.Time      `json:"onset"`
+	Expires     time.Time      `json:"expires"`
+	Areas       []string       `json:"areas"`       // Affected areas
+	Instruction string         `json:"instruction"` // What to do
 }
 
 // AlertData contains all active alerts for a location
 type AlertData struct {
-	Alerts    []Alert
-	UpdatedAt time.Time
+	Alerts    []Alert   `json:"alerts"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // IsActive checks if an alert is currently active
 func (a *Alert) IsActive() bool {
-	now := time.Now()
-	return now.After(a.Onset) && now.Before(a.Expires)
+	return a.IsActiveAt(SystemClock)
+}
+
+// IsActiveAt checks if an alert is active at the clock's current time: from its
+// onset up to, but not including, its expiry. An alert without an expiry is
+// ongoing until NOAA withdraws it.
+func (a *Alert) IsActiveAt(clock Clock) bool {
+	now := clock.Now()
+	return !now.Before(a.Onset) && (a.Expires.IsZero() || now.Before(a.Expires))
+}
+
+// Actionability scores how pressing an alert is, for ranking alerts. Severity counts
+// most, then urgency, then certainty, so a Severe alert always outranks a Moderate
+// one and among equally severe alerts an Immediate, Observed one comes first.
+func (a *Alert) Actionability() int {
+	return a.Severity.Rank()*16 + a.Urgency.Rank()*4 + a.Certainty.Rank()
+}
+
+// SortByActionability orders alerts most actionable first. Alerts with the same
+// score keep their order.
+func SortByActionability(alerts []Alert) {
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alerts[i].Actionability() > alerts[j].Actionability()
+	})
 }
 
 // IsMarine returns true if the alert is marine-related
@@ -53,3 +167,78 @@ func (a *Alert) IsMarine() bool {
 	}
 	return marineEvents[a.Event]
 }
+
+// IsStatement returns true for informational alerts, such as a Marine Weather
+// Statement, that are not a warning, watch or advisory
+func (a *Alert) IsStatement() bool {
+	for _, kind := range []string{"Warning", "Watch", "Advisory"} {
+		if strings.Contains(a.Event, kind) {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge combines the alerts from ad and other into new AlertData, de-duplicating by
+// alert ID. ad's copy of a duplicate wins, and alerts keep their order: ad's first,
+// then the new ones from other. Alerts without an ID are always kept. UpdatedAt is
+// the more recent of the two. Either side may be nil.
+func (ad *AlertData) Merge(other *AlertData) *AlertData {
+	merged := &AlertData{Alerts: make([]Alert, 0)}
+	seen := make(map[string]bool)
+	for _, source := range []*AlertData{ad, other} {
+		if source == nil {
+			continue
+		}
+		if source.UpdatedAt.After(merged.UpdatedAt) {
+			merged.UpdatedAt = source.UpdatedAt
+		}
+		for _, alert := range source.Alerts {
+			if alert.ID != "" {
+				if seen[alert.ID] {
+					continue
+				}
+				seen[alert.ID] = true
+			}
+			merged.Alerts = append(merged.Alerts, alert)
+		}
+	}
+	return merged
+}
+
+// ActiveMarineAlerts returns the currently active marine alerts, most severe first.
+// Alerts of equal severity keep their original order.
+func (ad *AlertData) ActiveMarineAlerts() []Alert {
+	return ad.ActiveMarineAlertsAt(SystemClock)
+}
+
+// ActiveMarineAlertsAt is ActiveMarineAlerts evaluated at the clock's current time
+func (ad *AlertData) ActiveMarineAlertsAt(clock Clock) []Alert {
+	if ad == nil {
+		return nil
+	}
+	var active []Alert
+	for _, a := range ad.Alerts {
+		if a.IsActiveAt(clock) && a.IsMarine() {
+			active = append(active, a)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		return active[i].Severity.Rank() > active[j].Severity.Rank()
+	})
+	return active
+}
+
+// MostSevereActive returns the highest-severity currently active marine alert
+func (ad *AlertData) MostSevereActive() (*Alert, bool) {
+	return ad.MostSevereActiveAt(SystemClock)
+}
+
+// MostSevereActiveAt is MostSevereActive evaluated at the clock's current time
+func (ad *AlertData) MostSevereActiveAt(clock Clock) (*Alert, bool) {
+	active := ad.ActiveMarineAlertsAt(clock)
+	if len(active) == 0 {
+		return nil, false
+	}
+	return &active[0], true
+}
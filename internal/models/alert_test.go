@@ -78,6 +78,27 @@ func TestAlert_IsMarine(t *testing.T) {
 	}
 }
 
+func TestAlert_IsStatement(t *testing.T) {
+	tests := []struct {
+		event string
+		want  bool
+	}{
+		{"Marine Weather Statement", true},
+		{"Small Craft Advisory", false},
+		{"Gale Warning", false},
+		{"Hurricane Force Wind Watch", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.event, func(t *testing.T) {
+			alert := Alert{Event: tt.event}
+			if got := alert.IsStatement(); got != tt.want {
+				t.Errorf("Alert.IsStatement() for %q = %v, want %v", tt.event, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestAlertSeverity_Constants(t *testing.T) {
 	tests := []struct {
 		severity AlertSeverity
@@ -98,3 +119,280 @@ func TestAlertSeverity_Constants(t *testing.T) {
 		})
 	}
 }
+
+func TestAlert_IsActiveAt_Boundaries(t *testing.T) {
+	onset := time.Date(2025, 11, 27, 6, 0, 0, 0, time.UTC)
+	expires := onset.Add(12 * time.Hour)
+	alert := Alert{Onset: onset, Expires: expires}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"just before onset", onset.Add(-time.Nanosecond), false},
+		{"at onset", onset, true},
+		{"mid alert", onset.Add(6 * time.Hour), true},
+		{"just before expiry", expires.Add(-time.Nanosecond), true},
+		{"at expiry", expires, false},
+		{"after expiry", expires.Add(time.Minute), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := alert.IsActiveAt(FixedClock(tt.now)); got != tt.want {
+				t.Errorf("IsActiveAt(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlert_IsActiveAt_MissingTimes(t *testing.T) {
+	now := time.Date(2025, 11, 27, 12, 0, 0, 0, time.UTC)
+	clock := FixedClock(now)
+
+	tests := []struct {
+		name  string
+		alert Alert
+		want  bool
+	}{
+		{"no onset or expiry", Alert{}, true},
+		{"no expiry", Alert{Onset: now.Add(-time.Hour)}, true},
+		{"no expiry, onset ahead", Alert{Onset: now.Add(time.Hour)}, false},
+		{"no onset", Alert{Expires: now.Add(time.Hour)}, true},
+		{"no onset, expired", Alert{Expires: now.Add(-time.Hour)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.alert.IsActiveAt(clock); got != tt.want {
+				t.Errorf("IsActiveAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlertData_ActiveMarineAlerts(t *testing.T) {
+	now := time.Date(2025, 11, 27, 12, 0, 0, 0, time.UTC)
+	clock := FixedClock(now)
+	active := func(id, event string, severity AlertSeverity) Alert {
+		return Alert{ID: id, Event: event, Severity: severity, Onset: now.Add(-time.Hour), Expires: now.Add(time.Hour)}
+	}
+
+	expiredGale := active("expired", "Gale Warning", SeverityExtreme)
+	expiredGale.Expires = now.Add(-time.Minute)
+
+	data := &AlertData{Alerts: []Alert{
+		active("sca", "Small Craft Advisory", SeverityMinor),
+		expiredGale,
+		active("tornado", "Tornado Warning", SeverityExtreme),
+		active("statement", "Marine Weather Statement", SeverityMinor),
+		active("storm", "Storm Warning", SeveritySevere),
+		active("seas", "Hazardous Seas Warning", SeverityModerate),
+	}}
+
+	got := data.ActiveMarineAlertsAt(clock)
+	wantIDs := []string{"storm", "seas", "sca", "statement"}
+	if len(got) != len(wantIDs) {
+		t.Fatalf("ActiveMarineAlerts() returned %d alerts, want %d", len(got), len(wantIDs))
+	}
+	for i, id := range wantIDs {
+		if got[i].ID != id {
+			t.Errorf("ActiveMarineAlerts()[%d] = %s, want %s", i, got[i].ID, id)
+		}
+	}
+
+	alert, ok := data.MostSevereActiveAt(clock)
+	if !ok || alert.ID != "storm" {
+		t.Errorf("MostSevereActive() = %v, %v; want storm, true", alert, ok)
+	}
+}
+
+func TestAlertData_MostSevereActive_None(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		data *AlertData
+	}{
+		{"nil data", nil},
+		{"no alerts", &AlertData{}},
+		{"only expired and non-marine", &AlertData{Alerts: []Alert{
+			{Event: "Gale Warning", Severity: SeveritySevere, Onset: now.Add(-2 * time.Hour), Expires: now.Add(-time.Hour)},
+			{Event: "Flood Warning", Severity: SeverityExtreme, Onset: now.Add(-time.Hour), Expires: now.Add(time.Hour)},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if alert, ok := tt.data.MostSevereActive(); ok || alert != nil {
+				t.Errorf("MostSevereActive() = %v, %v; want nil, false", alert, ok)
+			}
+		})
+	}
+}
+
+func TestAlertSeverity_Rank(t *testing.T) {
+	order := []AlertSeverity{SeverityUnknown, SeverityMinor, SeverityModerate, SeveritySevere, SeverityExtreme}
+	for i := 1; i < len(order); i++ {
+		if order[i].Rank() <= order[i-1].Rank() {
+			t.Errorf("%s should rank above %s", order[i], order[i-1])
+		}
+	}
+	if AlertSeverity("Bogus").Rank() != SeverityUnknown.Rank() {
+		t.Error("unrecognized severity should rank as Unknown")
+	}
+}
+
+func TestAlertData_ActiveMarineAlertsAt_Expiry(t *testing.T) {
+	expires := time.Date(2025, 11, 27, 18, 0, 0, 0, time.UTC)
+	data := &AlertData{Alerts: []Alert{
+		{ID: "gale", Event: "Gale Warning", Severity: SeveritySevere, Onset: expires.Add(-12 * time.Hour), Expires: expires},
+	}}
+
+	if got := data.ActiveMarineAlertsAt(FixedClock(expires.Add(-time.Second))); len(got) != 1 {
+		t.Errorf("one second before expiry: %d alerts, want 1", len(got))
+	}
+	if got := data.ActiveMarineAlertsAt(FixedClock(expires)); len(got) != 0 {
+		t.Errorf("at expiry: %d alerts, want 0", len(got))
+	}
+}
+
+func TestParseAlertUrgencyAndCertainty(t *testing.T) {
+	urgencies := map[string]AlertUrgency{
+		"Immediate": UrgencyImmediate,
+		"expected":  UrgencyExpected,
+		" Future ":  UrgencyFuture,
+		"Past":      UrgencyPast,
+		"Unknown":   UrgencyUnknown,
+		"":          UrgencyUnknown,
+		"Soon":      UrgencyUnknown,
+	}
+	for in, want := range urgencies {
+		if got := ParseAlertUrgency(in); got != want {
+			t.Errorf("ParseAlertUrgency(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	certainties := map[string]AlertCertainty{
+		"Observed": CertaintyObserved,
+		"LIKELY":   CertaintyLikely,
+		"Possible": CertaintyPossible,
+		"Unlikely": CertaintyUnlikely,
+		"":         CertaintyUnknown,
+		"Maybe":    CertaintyUnknown,
+	}
+	for in, want := range certainties {
+		if got := ParseAlertCertainty(in); got != want {
+			t.Errorf("ParseAlertCertainty(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	if !(UrgencyImmediate.Rank() > UrgencyExpected.Rank() && UrgencyExpected.Rank() > UrgencyFuture.Rank() &&
+		UrgencyFuture.Rank() > UrgencyPast.Rank() && UrgencyPast.Rank() == UrgencyUnknown.Rank()) {
+		t.Error("urgency ranks should run Immediate > Expected > Future > Past = Unknown")
+	}
+	if !(CertaintyObserved.Rank() > CertaintyLikely.Rank() && CertaintyLikely.Rank() > CertaintyPossible.Rank() &&
+		CertaintyPossible.Rank() > CertaintyUnlikely.Rank() && CertaintyUnlikely.Rank() == CertaintyUnknown.Rank()) {
+		t.Error("certainty ranks should run Observed > Likely > Possible > Unlikely = Unknown")
+	}
+}
+
+func TestSortByActionability(t *testing.T) {
+	alert := func(event string, s AlertSeverity, u AlertUrgency, c AlertCertainty) Alert {
+		return Alert{Event: event, Severity: s, Urgency: u, Certainty: c}
+	}
+	alerts := []Alert{
+		alert("Gale Watch", SeveritySevere, UrgencyFuture, CertaintyPossible),
+		alert("Small Craft Advisory", SeverityMinor, UrgencyImmediate, CertaintyObserved),
+		alert("Special Marine Warning", SeveritySevere, UrgencyImmediate, CertaintyObserved),
+		alert("Marine Weather Statement", SeverityModerate, UrgencyExpected, CertaintyLikely),
+		alert("Gale Warning", SeveritySevere, UrgencyExpected, CertaintyLikely),
+		alert("Hazardous Seas Warning", SeveritySevere, UrgencyExpected, CertaintyLikely),
+	}
+
+	SortByActionability(alerts)
+
+	want := []string{
+		"Special Marine Warning", // Most severe, immediate and observed
+		"Gale Warning",           // Equal scores keep their order
+		"Hazardous Seas Warning",
+		"Gale Watch",               // Severe still outranks any Moderate alert
+		"Marine Weather Statement", // Moderate outranks an immediate Minor alert
+		"Small Craft Advisory",
+	}
+	for i, a := range alerts {
+		if a.Event != want[i] {
+			t.Errorf("position %d = %s, want %s", i, a.Event, want[i])
+		}
+	}
+}
+
+func TestAlertData_Merge(t *testing.T) {
+	earlier := time.Date(2025, 11, 27, 12, 0, 0, 0, time.UTC)
+	later := earlier.Add(5 * time.Minute)
+
+	ids := func(ad *AlertData) []string {
+		var got []string
+		for _, a := range ad.Alerts {
+			got = append(got, a.ID+":"+a.Headline)
+		}
+		return got
+	}
+
+	tests := []struct {
+		name        string
+		a, b        *AlertData
+		want        []string
+		wantUpdated time.Time
+	}{
+		{
+			name: "overlapping",
+			a:    &AlertData{UpdatedAt: earlier, Alerts: []Alert{{ID: "gale", Headline: "zone"}, {ID: "sca", Headline: "zone"}}},
+			b:    &AlertData{UpdatedAt: later, Alerts: []Alert{{ID: "sca", Headline: "point"}, {ID: "smw", Headline: "point"}}},
+			want: []string{"gale:zone", "sca:zone", "smw:point"}, wantUpdated: later,
+		},
+		{
+			name: "disjoint",
+			a:    &AlertData{UpdatedAt: later, Alerts: []Alert{{ID: "gale", Headline: "zone"}}},
+			b:    &AlertData{UpdatedAt: earlier, Alerts: []Alert{{ID: "smw", Headline: "point"}}},
+			want: []string{"gale:zone", "smw:point"}, wantUpdated: later,
+		},
+		{
+			name: "alerts without IDs are all kept",
+			a:    &AlertData{Alerts: []Alert{{Headline: "one"}}},
+			b:    &AlertData{Alerts: []Alert{{Headline: "two"}}},
+			want: []string{":one", ":two"},
+		},
+		{
+			name: "nil side",
+			b:    &AlertData{UpdatedAt: earlier, Alerts: []Alert{{ID: "gale", Headline: "point"}}},
+			want: []string{"gale:point"}, wantUpdated: earlier,
+		},
+		{name: "both nil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := tt.a.Merge(tt.b)
+			got := ids(merged)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Merge() = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("Merge()[%d] = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+			if !merged.UpdatedAt.Equal(tt.wantUpdated) {
+				t.Errorf("UpdatedAt = %v, want %v", merged.UpdatedAt, tt.wantUpdated)
+			}
+		})
+	}
+
+	// The sources are left untouched
+	a := &AlertData{Alerts: []Alert{{ID: "gale"}}}
+	a.Merge(&AlertData{Alerts: []Alert{{ID: "smw"}}})
+	if len(a.Alerts) != 1 {
+		t.Errorf("Merge() modified its receiver: %v", a.Alerts)
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Clock reports the current time. Time-dependent logic takes a Clock so tests
+// can pin "now" instead of depending on the wall clock.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock reads the wall clock
+type systemClock struct{}
+
+// Now returns the current local time
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock is the Clock used outside of tests
+var SystemClock Clock = systemClock{}
+
+// FixedClock is a Clock that always reports the same time
+type FixedClock time.Time
+
+// Now returns the fixed time
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
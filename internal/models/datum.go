@@ -0,0 +1,40 @@
+package models
+
+import "fmt"
+
+// Further tidal datums published by CO-OPS stations, offered for comparison
+const (
+	DatumMHHW   = "MHHW"   // Mean Higher High Water
+	DatumNAVD88 = "NAVD88" // North American Vertical Datum of 1988 (land survey datum)
+)
+
+// ComparisonDatums lists the datums tide heights can be converted to for display, in cycling order
+var ComparisonDatums = []string{DatumMLLW, DatumMSL, DatumMHHW, DatumNAVD88}
+
+// DatumOffsets holds a station's datum elevations, all measured from the same
+// station datum, so heights can be converted from one datum to another
+type DatumOffsets struct {
+	StationID  string
+	Elevations map[string]float64 // feet above station datum, keyed by datum name (e.g. "MLLW")
+}
+
+// Has reports whether the station publishes the datum
+func (d *DatumOffsets) Has(datum string) bool {
+	if d == nil {
+		return false
+	}
+	_, ok := d.Elevations[datum]
+	return ok
+}
+
+// Convert re-references a height measured from one datum to another. It fails if
+// the station doesn't publish either datum.
+func (d *DatumOffsets) Convert(height float64, from, to string) (float64, error) {
+	if !d.Has(from) {
+		return 0, fmt.Errorf("datum %s not available for this station", from)
+	}
+	if !d.Has(to) {
+		return 0, fmt.Errorf("datum %s not available for this station", to)
+	}
+	return height + d.Elevations[from] - d.Elevations[to], nil
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDatumOffsets_Convert(t *testing.T) {
+	// Boston (8443970) datums in feet above station datum
+	offsets := &DatumOffsets{StationID: "8443970", Elevations: map[string]float64{
+		DatumMLLW: 0.0,
+		DatumMSL:  4.82,
+		DatumMHHW: 9.95,
+	}}
+
+	tests := []struct {
+		name     string
+		height   float64
+		from, to string
+		want     float64
+		wantErr  bool
+	}{
+		{"high tide MLLW to MSL", 10.2, DatumMLLW, DatumMSL, 5.38, false},
+		{"low tide MLLW to MSL", -0.5, DatumMLLW, DatumMSL, -5.32, false},
+		{"MSL to MLLW", 0, DatumMSL, DatumMLLW, 4.82, false},
+		{"MLLW to MHHW", 9.95, DatumMLLW, DatumMHHW, 0, false},
+		{"same datum", 3.1, DatumMSL, DatumMSL, 3.1, false},
+		{"target not published", 3.1, DatumMLLW, DatumNAVD88, 0, true},
+		{"source not published", 3.1, DatumNAVD88, DatumMLLW, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := offsets.Convert(tt.height, tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Convert(%.2f, %s, %s) = %.4f, want %.4f", tt.height, tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+
+	var missing *DatumOffsets
+	if missing.Has(DatumMLLW) {
+		t.Error("nil offsets should have no datums")
+	}
+}
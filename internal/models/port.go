@@ -1,20 +1,142 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Tide datums supported for predictions
+const (
+	DatumMLLW = "MLLW" // Mean Lower Low Water (NOAA chart datum)
+	DatumMSL  = "MSL"  // Mean Sea Level
+)
 
 // Port represents a user-configured marine location.
 // It can be a transient object from an API search or a saved user configuration.
 type Port struct {
-	ID            int64     `json:"id"`             // Database Primary Key (0 if not saved)
-	StationID     string    `json:"station_id"`     // NOAA station ID (e.g. "8447435")
-	Name          string    `json:"name"`           // User-friendly name
-	State         string    `json:"state"`          // State (e.g. "MA")
-	City          string    `json:"city"`           // City (e.g. "Chatham")
-	Zipcode       string    `json:"zipcode"`        // Zipcode (e.g. "02633")
-	MarineZoneID  string    `json:"marine_zone_id"` // NOAA marine forecast zone (e.g. "ANZ254")
-	TideStationID string    `json:"tide_station_id"`// NOAA tide station ID
-	Latitude      float64   `json:"latitude"`
-	Longitude     float64   `json:"longitude"`
-	Type          string    `json:"type"`           // e.g., "buoy", "coastal"
-	CreatedAt     time.Time `json:"created_at"`
-}
\ No newline at end of file
+	ID                int64     `json:"id"`              // Database Primary Key (0 if not saved)
+	StationID         string    `json:"station_id"`      // NOAA station ID (e.g. "8447435")
+	Name              string    `json:"name"`            // User-friendly name
+	State             string    `json:"state"`           // State (e.g. "MA")
+	City              string    `json:"city"`            // City (e.g. "Chatham")
+	Zipcode           string    `json:"zipcode"`         // Zipcode (e.g. "02633")
+	MarineZoneID      string    `json:"marine_zone_id"`  // NOAA marine forecast zone (e.g. "ANZ254")
+	TideStationID     string    `json:"tide_station_id"` // NOAA tide station ID
+	Latitude          float64   `json:"latitude"`
+	Longitude         float64   `json:"longitude"`
+	Type              string    `json:"type"`                          // e.g., "buoy", "coastal"
+	PreferredDatum    string    `json:"preferred_datum"`               // Tide datum (e.g. "MLLW", "MSL")
+	PreferredZoneType string    `json:"preferred_zone_type,omitempty"` // Zone type ("coastal" or "offshore") listed first
+	Home              bool      `json:"home,omitempty"`                // The home port, jumped to with a hotkey; at most one port is home
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// PortFromStation builds an unsaved port (ID 0) for a NOAA tide station, as listed by
+// station searches. The city is the station name up to the first comma (e.g. "Boston"
+// for "Boston, MA"). Use it wherever a station becomes a port so the ID fields agree.
+func PortFromStation(id, name, state string, lat, lon float64) Port {
+	city := name
+	if idx := strings.Index(city, ","); idx > 0 {
+		city = city[:idx]
+	}
+
+	port := Port{
+		Name:      name,
+		City:      city,
+		State:     state,
+		Latitude:  lat,
+		Longitude: lon,
+		Type:      "coastal",
+	}
+	port.SetStation(id)
+	return port
+}
+
+// SetStation makes id the port's NOAA tide station. StationID and TideStationID
+// always hold the same station; set them through here.
+func (p *Port) SetStation(id string) {
+	p.StationID = id
+	p.TideStationID = id
+}
+
+// TideDatum returns the port's preferred tide datum, defaulting to MLLW
+func (p *Port) TideDatum() string {
+	if p.PreferredDatum == "" {
+		return DatumMLLW
+	}
+	return p.PreferredDatum
+}
+
+// DisplayName returns a label for the port's location: "City, State", or whichever
+// of the two is set, then the zipcode, then the port's name
+func (p *Port) DisplayName() string {
+	city, state := strings.TrimSpace(p.City), strings.TrimSpace(p.State)
+	switch {
+	case city != "" && state != "":
+		return city + ", " + state
+	case city != "":
+		return city
+	case state != "":
+		return state
+	case strings.TrimSpace(p.Zipcode) != "":
+		return strings.TrimSpace(p.Zipcode)
+	default:
+		return p.Name
+	}
+}
+
+// Validate checks that the port can be saved and later loaded: it needs a name,
+// coordinates within range, and a marine zone or coordinates to find one from
+func (p *Port) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("port name cannot be empty")
+	}
+	if p.Latitude < -90 || p.Latitude > 90 {
+		return fmt.Errorf("latitude %.4f is out of range (-90 to 90)", p.Latitude)
+	}
+	if p.Longitude < -180 || p.Longitude > 180 {
+		return fmt.Errorf("longitude %.4f is out of range (-180 to 180)", p.Longitude)
+	}
+	if strings.TrimSpace(p.MarineZoneID) == "" && p.Latitude == 0 && p.Longitude == 0 {
+		return fmt.Errorf("port %q needs a marine zone or coordinates", p.Name)
+	}
+	return nil
+}
+
+// portJSON is the wire form of Port with CreatedAt as an RFC3339 string
+type portJSON struct {
+	portFields
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+// portFields aliases Port without its methods so encoding doesn't recurse
+type portFields Port
+
+// MarshalJSON encodes the port with CreatedAt formatted as RFC3339
+func (p Port) MarshalJSON() ([]byte, error) {
+	out := portJSON{portFields: portFields(p)}
+	if !p.CreatedAt.IsZero() {
+		out.CreatedAt = p.CreatedAt.Format(time.RFC3339)
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a port whose CreatedAt is an RFC3339 string
+func (p *Port) UnmarshalJSON(data []byte) error {
+	var in portJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*p = Port(in.portFields)
+	p.CreatedAt = time.Time{}
+	if in.CreatedAt != "" {
+		t, err := time.Parse(time.RFC3339, in.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("parsing created_at: %w", err)
+		}
+		p.CreatedAt = t
+	}
+	return nil
+}
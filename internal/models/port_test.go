@@ -0,0 +1,166 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPort_JSONRoundTrip(t *testing.T) {
+	created := time.Date(2025, 6, 14, 9, 30, 15, 0, time.FixedZone("EDT", -4*60*60))
+	port := Port{
+		ID:             42,
+		StationID:      "8447435",
+		Name:           "Stage Harbor",
+		State:          "MA",
+		City:           "Chatham",
+		Zipcode:        "02633",
+		MarineZoneID:   "ANZ254",
+		TideStationID:  "8447435",
+		Latitude:       41.6885,
+		Longitude:      -69.9511,
+		Type:           "coastal",
+		PreferredDatum: DatumMSL,
+		CreatedAt:      created,
+	}
+
+	data, err := json.Marshal(port)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"created_at":"2025-06-14T09:30:15-04:00"`) {
+		t.Errorf("created_at not RFC3339 in %s", data)
+	}
+	if !strings.Contains(string(data), `"marine_zone_id":"ANZ254"`) {
+		t.Errorf("marine_zone_id missing from %s", data)
+	}
+
+	var got Port
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !got.CreatedAt.Equal(created) {
+		t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, created)
+	}
+	got.CreatedAt = created
+	if got != port {
+		t.Errorf("round trip = %+v, want %+v", got, port)
+	}
+}
+
+func TestPort_JSONZeroCreatedAt(t *testing.T) {
+	data, err := json.Marshal(Port{Name: "Unsaved"})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if strings.Contains(string(data), "created_at") {
+		t.Errorf("zero CreatedAt should be omitted, got %s", data)
+	}
+
+	var got Port
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !got.CreatedAt.IsZero() {
+		t.Errorf("CreatedAt = %v, want zero", got.CreatedAt)
+	}
+
+	if err := json.Unmarshal([]byte(`{"created_at":"yesterday"}`), &got); err == nil {
+		t.Error("expected error for invalid created_at")
+	}
+}
+
+func TestPort_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		port    Port
+		wantErr string
+	}{
+		{"zone and coordinates", Port{Name: "Chatham", MarineZoneID: "ANZ254", Latitude: 41.68, Longitude: -69.95}, ""},
+		{"zone only", Port{Name: "Chatham", MarineZoneID: "ANZ254"}, ""},
+		{"coordinates only", Port{Name: "Chatham", Latitude: 41.68, Longitude: -69.95}, ""},
+		{"empty name", Port{MarineZoneID: "ANZ254"}, "name cannot be empty"},
+		{"blank name", Port{Name: "   ", MarineZoneID: "ANZ254"}, "name cannot be empty"},
+		{"latitude too high", Port{Name: "Chatham", MarineZoneID: "ANZ254", Latitude: 91}, "latitude"},
+		{"longitude too low", Port{Name: "Chatham", MarineZoneID: "ANZ254", Latitude: 41.68, Longitude: -181}, "longitude"},
+		{"no zone or coordinates", Port{Name: "Chatham"}, "needs a marine zone or coordinates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.port.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPort_DisplayName(t *testing.T) {
+	tests := []struct {
+		name string
+		port Port
+		want string
+	}{
+		{"city, state and zipcode", Port{Name: "Stage Harbor", City: "Chatham", State: "MA", Zipcode: "02633"}, "Chatham, MA"},
+		{"city and state", Port{Name: "Stage Harbor", City: "Chatham", State: "MA"}, "Chatham, MA"},
+		{"city only", Port{Name: "Stage Harbor", City: "Chatham"}, "Chatham"},
+		{"state only", Port{Name: "Stage Harbor", State: "MA"}, "MA"},
+		{"zipcode only", Port{Zipcode: "02633"}, "02633"},
+		{"zipcode and name", Port{Name: "Stage Harbor", Zipcode: " 02633 "}, "02633"},
+		{"name only", Port{Name: "Stage Harbor"}, "Stage Harbor"},
+		{"blank location", Port{Name: "Stage Harbor", City: " ", State: ""}, "Stage Harbor"},
+		{"empty", Port{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.port.DisplayName(); got != tt.want {
+				t.Errorf("DisplayName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPortFromStation(t *testing.T) {
+	tests := []struct {
+		name, stationName, wantCity string
+	}{
+		{"name with state", "Boston, MA", "Boston"},
+		{"name with place", "Woods Hole, Buzzards Bay", "Woods Hole"},
+		{"plain name", "Chatham", "Chatham"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port := PortFromStation("8443970", tt.stationName, "MA", 42.3548, -71.0534)
+			if port.ID != 0 {
+				t.Errorf("ID = %d, want 0 for an unsaved port", port.ID)
+			}
+			if port.StationID != "8443970" || port.TideStationID != "8443970" {
+				t.Errorf("StationID = %q, TideStationID = %q; want both 8443970", port.StationID, port.TideStationID)
+			}
+			if port.Name != tt.stationName || port.City != tt.wantCity || port.State != "MA" {
+				t.Errorf("Name, City, State = %q, %q, %q; want %q, %q, MA", port.Name, port.City, port.State, tt.stationName, tt.wantCity)
+			}
+			if port.Latitude != 42.3548 || port.Longitude != -71.0534 || port.Type != "coastal" {
+				t.Errorf("Latitude, Longitude, Type = %v, %v, %q", port.Latitude, port.Longitude, port.Type)
+			}
+		})
+	}
+}
+
+func TestPort_SetStation(t *testing.T) {
+	port := PortFromStation("8447930", "Woods Hole", "MA", 41.5236, -70.6711)
+	port.SetStation("8447387")
+	if port.StationID != "8447387" || port.TideStationID != "8447387" {
+		t.Errorf("StationID = %q, TideStationID = %q; want both 8447387", port.StationID, port.TideStationID)
+	}
+}
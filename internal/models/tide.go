@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"math"
+	"time"
+)
 
 // TideType represents whether a tide is high or low
 type TideType string
@@ -10,31 +14,151 @@ const (
 	TideLow  TideType = "L"
 )
 
+// String returns the tide type's label, "High" or "Low"
+func (t TideType) String() string {
+	switch t {
+	case TideHigh:
+		return "High"
+	case TideLow:
+		return "Low"
+	default:
+		return "Unknown"
+	}
+}
+
 // TideEvent represents a single high or low tide occurrence
 type TideEvent struct {
-	Time   time.Time
-	Type   TideType
-	Height float64 // feet relative to MLLW (Mean Lower Low Water)
+	Time   time.Time `json:"time"`
+	Type   TideType  `json:"type"`
+	Height float64   `json:"height"` // feet relative to MLLW (Mean Lower Low Water)
 }
 
 // TideData contains tide predictions for a location
 type TideData struct {
-	StationID   string
-	StationName string
-	Events      []TideEvent // Ordered by time
-	UpdatedAt   time.Time
+	StationID   string      `json:"station_id"`
+	StationName string      `json:"station_name"`
+	Datum       string      `json:"datum"`  // Datum heights are relative to (e.g. "MLLW")
+	Events      []TideEvent `json:"events"` // Ordered by time
+	UpdatedAt   time.Time   `json:"updated_at"`
 }
 
-// GetEventsForDay returns tide events for a specific date
+// GetEventsForDay returns tide events for a specific date, from midnight up to but not
+// including the next midnight in date's location
 func (td *TideData) GetEventsForDay(date time.Time) []TideEvent {
 	var events []TideEvent
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
+	endOfDay := startOfDay.AddDate(0, 0, 1)
 
 	for _, event := range td.Events {
-		if event.Time.After(startOfDay) && event.Time.Before(endOfDay) {
+		if !event.Time.Before(startOfDay) && event.Time.Before(endOfDay) {
 			events = append(events, event)
 		}
 	}
 	return events
 }
+
+// HighestHigh returns the high tide with the greatest height, or nil if there are no high tides
+func (td *TideData) HighestHigh() *TideEvent {
+	var highest *TideEvent
+	for i := range td.Events {
+		event := &td.Events[i]
+		if event.Type != TideHigh {
+			continue
+		}
+		if highest == nil || event.Height > highest.Height {
+			highest = event
+		}
+	}
+	return highest
+}
+
+// LowestLow returns the low tide with the smallest height, or nil if there are no low tides
+func (td *TideData) LowestLow() *TideEvent {
+	var lowest *TideEvent
+	for i := range td.Events {
+		event := &td.Events[i]
+		if event.Type != TideLow {
+			continue
+		}
+		if lowest == nil || event.Height < lowest.Height {
+			lowest = event
+		}
+	}
+	return lowest
+}
+
+// NextEvent returns the first tide event after t, or nil if there are none
+func (td *TideData) NextEvent(t time.Time) *TideEvent {
+	for i := range td.Events {
+		if td.Events[i].Time.After(t) {
+			return &td.Events[i]
+		}
+	}
+	return nil
+}
+
+// HeightAt estimates the tide height at t by interpolating between the high and low
+// events either side of it with a cosine curve, the usual approximation of the rise
+// and fall between extremes. It returns an error if t is outside the predictions.
+func (td *TideData) HeightAt(t time.Time) (float64, error) {
+	if len(td.Events) < 2 {
+		return 0, fmt.Errorf("need at least two tide events to interpolate")
+	}
+	first, last := td.Events[0], td.Events[len(td.Events)-1]
+	if t.Before(first.Time) || t.After(last.Time) {
+		return 0, fmt.Errorf("%s is outside the predictions (%s to %s)",
+			t.Format("Jan 2 3:04 PM"), first.Time.Format("Jan 2 3:04 PM"), last.Time.Format("Jan 2 3:04 PM"))
+	}
+
+	for i := 1; i < len(td.Events); i++ {
+		prev, next := td.Events[i-1], td.Events[i]
+		if t.After(next.Time) {
+			continue
+		}
+		span := next.Time.Sub(prev.Time)
+		if span <= 0 {
+			return next.Height, nil
+		}
+		fraction := float64(t.Sub(prev.Time)) / float64(span)
+		return prev.Height + (next.Height-prev.Height)*(1-math.Cos(math.Pi*fraction))/2, nil
+	}
+	return last.Height, nil
+}
+
+// CyclePosition returns how far t is through the tide cycle, from 0 at low tide through
+// 0.5 at high tide to 1 at the next low, from the high and low events either side of
+// it. Between two events of the same type, or outside the predictions, it returns false.
+func (td *TideData) CyclePosition(t time.Time) (float64, bool) {
+	for i := 1; i < len(td.Events); i++ {
+		prev, next := td.Events[i-1], td.Events[i]
+		if t.Before(prev.Time) || t.After(next.Time) || (t.Equal(next.Time) && i < len(td.Events)-1) {
+			continue
+		}
+		span := next.Time.Sub(prev.Time)
+		if span <= 0 || prev.Type == next.Type {
+			return 0, false
+		}
+		fraction := float64(t.Sub(prev.Time)) / float64(span)
+		if prev.Type == TideLow {
+			return fraction / 2, true
+		}
+		return 0.5 + fraction/2, true
+	}
+	return 0, false
+}
+
+// WaterLevel is a station's latest observed water level with the predicted level at
+// the same time, both relative to Datum
+type WaterLevel struct {
+	StationID string
+	Datum     string
+	Time      time.Time // Local station time of the observation
+	Observed  float64   // feet
+	Predicted float64   // feet
+}
+
+// Residual is how far the observed level is above (positive) or below the prediction,
+// e.g. storm surge or setdown
+func (w WaterLevel) Residual() float64 {
+	return w.Observed - w.Predicted
+}
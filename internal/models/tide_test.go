@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"testing"
 	"time"
 )
@@ -34,6 +35,23 @@ func TestTideData_GetEventsForDay(t *testing.T) {
 			date: time.Date(2025, 11, 27, 0, 0, 0, 0, loc),
 			want: 0,
 		},
+		{
+			name: "event at midnight starts the day",
+			events: []TideEvent{
+				{Time: time.Date(2025, 11, 27, 0, 0, 0, 0, loc), Type: TideHigh, Height: 5.0},
+				{Time: time.Date(2025, 11, 28, 0, 0, 0, 0, loc), Type: TideLow, Height: 0.5},
+			},
+			date: time.Date(2025, 11, 27, 15, 0, 0, 0, loc),
+			want: 1,
+		},
+		{
+			name: "25 hour day when daylight saving time ends",
+			events: []TideEvent{
+				{Time: time.Date(2025, 11, 2, 23, 30, 0, 0, loc), Type: TideHigh, Height: 5.0},
+			},
+			date: time.Date(2025, 11, 2, 0, 0, 0, 0, loc),
+			want: 1,
+		},
 		{
 			name: "single event",
 			events: []TideEvent{
@@ -60,9 +78,201 @@ func TestTideData_GetEventsForDay(t *testing.T) {
 
 func TestTideType_Constants(t *testing.T) {
 	if TideHigh != "H" {
-		t.Errorf("TideHigh = %v, want 'H'", TideHigh)
+		t.Errorf("TideHigh = %q, want 'H'", string(TideHigh))
 	}
 	if TideLow != "L" {
-		t.Errorf("TideLow = %v, want 'L'", TideLow)
+		t.Errorf("TideLow = %q, want 'L'", string(TideLow))
+	}
+}
+
+func TestTideType_String(t *testing.T) {
+	tests := []struct {
+		tideType TideType
+		want     string
+	}{
+		{TideHigh, "High"},
+		{TideLow, "Low"},
+		{TideType("X"), "Unknown"},
+		{TideType(""), "Unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.tideType.String(); got != tt.want {
+			t.Errorf("TideType(%q).String() = %q, want %q", string(tt.tideType), got, tt.want)
+		}
+	}
+}
+
+func TestTideData_Extremes(t *testing.T) {
+	loc, _ := time.LoadLocation("America/New_York")
+
+	threeDays := []TideEvent{
+		{Time: time.Date(2025, 11, 26, 2, 14, 0, 0, loc), Type: TideLow, Height: 0.3},
+		{Time: time.Date(2025, 11, 26, 8, 20, 0, 0, loc), Type: TideHigh, Height: 5.1},
+		{Time: time.Date(2025, 11, 26, 14, 40, 0, 0, loc), Type: TideLow, Height: 0.6},
+		{Time: time.Date(2025, 11, 26, 20, 50, 0, 0, loc), Type: TideHigh, Height: 4.8},
+		{Time: time.Date(2025, 11, 27, 3, 5, 0, 0, loc), Type: TideLow, Height: -0.2},
+		{Time: time.Date(2025, 11, 27, 8, 40, 0, 0, loc), Type: TideHigh, Height: 5.6},
+		{Time: time.Date(2025, 11, 27, 15, 25, 0, 0, loc), Type: TideLow, Height: 0.4},
+		{Time: time.Date(2025, 11, 28, 9, 30, 0, 0, loc), Type: TideHigh, Height: 5.4},
+	}
+
+	tests := []struct {
+		name        string
+		events      []TideEvent
+		wantHighest *TideEvent
+		wantLowest  *TideEvent
+	}{
+		{
+			name:        "multi-day events",
+			events:      threeDays,
+			wantHighest: &threeDays[5],
+			wantLowest:  &threeDays[4],
+		},
+		{
+			name:   "empty dataset",
+			events: nil,
+		},
+		{
+			name: "only high tides",
+			events: []TideEvent{
+				{Time: time.Date(2025, 11, 26, 8, 20, 0, 0, loc), Type: TideHigh, Height: 5.1},
+			},
+			wantHighest: &TideEvent{Time: time.Date(2025, 11, 26, 8, 20, 0, 0, loc), Type: TideHigh, Height: 5.1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := &TideData{Events: tt.events}
+
+			gotHighest := td.HighestHigh()
+			if (gotHighest == nil) != (tt.wantHighest == nil) {
+				t.Fatalf("HighestHigh() = %v, want %v", gotHighest, tt.wantHighest)
+			}
+			if gotHighest != nil && (gotHighest.Height != tt.wantHighest.Height || !gotHighest.Time.Equal(tt.wantHighest.Time)) {
+				t.Errorf("HighestHigh() = %+v, want %+v", *gotHighest, *tt.wantHighest)
+			}
+
+			gotLowest := td.LowestLow()
+			if (gotLowest == nil) != (tt.wantLowest == nil) {
+				t.Fatalf("LowestLow() = %v, want %v", gotLowest, tt.wantLowest)
+			}
+			if gotLowest != nil && (gotLowest.Height != tt.wantLowest.Height || !gotLowest.Time.Equal(tt.wantLowest.Time)) {
+				t.Errorf("LowestLow() = %+v, want %+v", *gotLowest, *tt.wantLowest)
+			}
+		})
+	}
+}
+
+func TestTideData_NextEvent(t *testing.T) {
+	base := time.Date(2025, 11, 26, 0, 0, 0, 0, time.UTC)
+	td := &TideData{Events: []TideEvent{
+		{Time: base.Add(2 * time.Hour), Type: TideLow, Height: 0.3},
+		{Time: base.Add(8 * time.Hour), Type: TideHigh, Height: 5.1},
+	}}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want *TideEvent
+	}{
+		{"before first", base, &td.Events[0]},
+		{"between events", base.Add(3 * time.Hour), &td.Events[1]},
+		{"exactly at an event", base.Add(2 * time.Hour), &td.Events[1]},
+		{"after last", base.Add(9 * time.Hour), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := td.NextEvent(tt.at); got != tt.want {
+				t.Errorf("NextEvent(%v) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTideData_HeightAt(t *testing.T) {
+	base := time.Date(2025, 11, 26, 0, 0, 0, 0, time.UTC)
+	td := &TideData{Events: []TideEvent{
+		{Time: base, Type: TideLow, Height: 1.0},
+		{Time: base.Add(6 * time.Hour), Type: TideHigh, Height: 9.0},
+		{Time: base.Add(12 * time.Hour), Type: TideLow, Height: 2.0},
+	}}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want float64
+	}{
+		{"at low", base, 1.0},
+		{"at high", base.Add(6 * time.Hour), 9.0},
+		{"midpoint rising", base.Add(3 * time.Hour), 5.0},
+		{"quarter rising", base.Add(90 * time.Minute), 1.0 + 8.0*(1-math.Cos(math.Pi/4))/2},
+		{"midpoint falling", base.Add(9 * time.Hour), 5.5},
+		{"last event", base.Add(12 * time.Hour), 2.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := td.HeightAt(tt.at)
+			if err != nil {
+				t.Fatalf("HeightAt(%v) error: %v", tt.at, err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("HeightAt(%v) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+
+	for _, at := range []time.Time{base.Add(-time.Minute), base.Add(12*time.Hour + time.Minute)} {
+		if _, err := td.HeightAt(at); err == nil {
+			t.Errorf("HeightAt(%v) should fail outside the predictions", at)
+		}
+	}
+	single := &TideData{Events: td.Events[:1]}
+	if _, err := single.HeightAt(base); err == nil {
+		t.Error("HeightAt with one event should fail")
+	}
+}
+
+func TestTideData_CyclePosition(t *testing.T) {
+	base := time.Date(2025, 11, 26, 0, 0, 0, 0, time.UTC)
+	td := &TideData{Events: []TideEvent{
+		{Time: base, Type: TideLow, Height: 0.3},
+		{Time: base.Add(6 * time.Hour), Type: TideHigh, Height: 5.1},
+		{Time: base.Add(12 * time.Hour), Type: TideLow, Height: 0.5},
+	}}
+
+	tests := []struct {
+		name   string
+		at     time.Time
+		want   float64
+		wantOK bool
+	}{
+		{"at low", base, 0, true},
+		{"mid rising", base.Add(3 * time.Hour), 0.25, true},
+		{"at high", base.Add(6 * time.Hour), 0.5, true},
+		{"mid falling", base.Add(9 * time.Hour), 0.75, true},
+		{"at the last low", base.Add(12 * time.Hour), 1, true},
+		{"before the predictions", base.Add(-time.Minute), 0, false},
+		{"after the predictions", base.Add(13 * time.Hour), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := td.CyclePosition(tt.at)
+			if ok != tt.wantOK || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("CyclePosition(%v) = %v, %v; want %v, %v", tt.at, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	// Two highs in a row, e.g. a missing low, can't be placed in the cycle
+	gap := &TideData{Events: []TideEvent{
+		{Time: base, Type: TideHigh, Height: 5},
+		{Time: base.Add(12 * time.Hour), Type: TideHigh, Height: 5},
+	}}
+	if _, ok := gap.CyclePosition(base.Add(time.Hour)); ok {
+		t.Error("CyclePosition() between two highs should fail")
 	}
 }
@@ -1,6 +1,11 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
 
 // WindData represents wind conditions in marine format
 type WindData struct {
@@ -9,14 +14,82 @@ type WindData struct {
 	SpeedMax      float64 // knots
 	GustSpeed     float64 // knots (0 if no gusts)
 	HasGust       bool
+	Squalls       bool   // The forecast mentions squalls, which can bring the gusts or worse
 	RawText       string // Original NOAA format
 }
 
+// compassPoints lists the 16 compass directions clockwise from north
+var compassPoints = []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+
+// DirectionDegrees converts the compass direction (e.g. "W", "NNE") to degrees true.
+// It returns false for variable or unrecognized directions.
+func (w WindData) DirectionDegrees() (float64, bool) {
+	for i, point := range compassPoints {
+		if strings.EqualFold(w.Direction, point) {
+			return float64(i) * 22.5, true
+		}
+	}
+	return 0, false
+}
+
+// BeaufortForce is a wind's force on the Beaufort scale
+type BeaufortForce struct {
+	Number      int    // 0 (calm) to 12 (hurricane force)
+	Description string // e.g. "Fresh Breeze"
+}
+
+// String formats the force as e.g. "Force 5 (Fresh Breeze)"
+func (f BeaufortForce) String() string {
+	return fmt.Sprintf("Force %d (%s)", f.Number, f.Description)
+}
+
+// beaufortScale lists each force with the lowest whole knot speed it starts at
+var beaufortScale = []struct {
+	minKnots    float64
+	description string
+}{
+	{0, "Calm"},
+	{1, "Light Air"},
+	{4, "Light Breeze"},
+	{7, "Gentle Breeze"},
+	{11, "Moderate Breeze"},
+	{17, "Fresh Breeze"},
+	{22, "Strong Breeze"},
+	{28, "Near Gale"},
+	{34, "Gale"},
+	{41, "Strong Gale"},
+	{48, "Storm"},
+	{56, "Violent Storm"},
+	{64, "Hurricane Force"},
+}
+
+// Beaufort returns the Beaufort force of the wind's maximum speed. The speed is rounded
+// to the nearest knot first, since the scale's ranges are given in whole knots.
+func (w WindData) Beaufort() BeaufortForce {
+	speed := math.Round(w.SpeedMax)
+	force := 0
+	for i, level := range beaufortScale {
+		if speed >= level.minKnots {
+			force = i
+		}
+	}
+	return BeaufortForce{Number: force, Description: beaufortScale[force].description}
+}
+
+// WaveKind distinguishes long-period swell from locally generated wind waves
+type WaveKind string
+
+const (
+	WaveKindSwell    WaveKind = "swell"
+	WaveKindWindWave WaveKind = "windwave"
+)
+
 // WaveComponent represents a single wave/swell component
 type WaveComponent struct {
-	Direction string  // e.g., "S", "W", "NW"
-	Height    float64 // feet
-	Period    int     // seconds
+	Kind      WaveKind // Swell or wind wave
+	Direction string   // e.g., "S", "W", "NW" (empty if not given)
+	Height    float64  // feet
+	Period    int      // seconds (0 if not given)
 }
 
 // SeaState represents overall sea conditions
@@ -27,6 +100,55 @@ type SeaState struct {
 	RawText   string          // Original NOAA format
 }
 
+// Dominant returns the highest wave component, the one that sets the feel of the
+// seas. Of components the same height, the first listed wins. It returns false if
+// there are no components.
+func (s SeaState) Dominant() (WaveComponent, bool) {
+	if len(s.Components) == 0 {
+		return WaveComponent{}, false
+	}
+	dominant := s.Components[0]
+	for _, c := range s.Components[1:] {
+		if c.Height > dominant.Height {
+			dominant = c
+		}
+	}
+	return dominant, true
+}
+
+// Units for displaying barometric pressure
+const (
+	PressureMillibars = "mb"
+	PressureInchesHg  = "inHg"
+)
+
+// MillibarsPerInchHg is the pressure of one inch of mercury in millibars
+const MillibarsPerInchHg = 33.8639
+
+// ParsePressureUnit maps "mb" or "inHg", ignoring case, to a pressure unit
+func ParsePressureUnit(s string) (string, error) {
+	for _, unit := range []string{PressureMillibars, PressureInchesHg} {
+		if strings.EqualFold(strings.TrimSpace(s), unit) {
+			return unit, nil
+		}
+	}
+	return "", fmt.Errorf("unknown pressure unit %q: expected %q or %q", s, PressureMillibars, PressureInchesHg)
+}
+
+// MillibarsToInchesHg converts a pressure in millibars to inches of mercury
+func MillibarsToInchesHg(mb float64) float64 {
+	return mb / MillibarsPerInchHg
+}
+
+// FormatPressure renders a pressure given in millibars in unit, e.g. "1013.2 mb" or
+// "29.92 inHg". Any unit other than PressureInchesHg is shown in millibars.
+func FormatPressure(mb float64, unit string) string {
+	if unit == PressureInchesHg {
+		return fmt.Sprintf("%.2f inHg", MillibarsToInchesHg(mb))
+	}
+	return fmt.Sprintf("%.1f mb", mb)
+}
+
 // MarineConditions represents current marine weather conditions
 type MarineConditions struct {
 	Location     string
@@ -35,8 +157,93 @@ type MarineConditions struct {
 	Wind         WindData
 	Seas         SeaState
 	Visibility   float64 // nautical miles
-	Pressure     float64 // millibars or inHg
+	Pressure     float64 // millibars, as reported by CO-OPS in either unit system
 	UpdatedAt    time.Time
+
+	// Recent station observations, oldest first, for trend display
+	WaterTemperature float64       // Fahrenheit
+	AirTempHistory   []Observation // Fahrenheit
+	WaterTempHistory []Observation // Fahrenheit
+	WindHistory      []WindObservation
+}
+
+// Observation is a single timestamped station measurement
+type Observation struct {
+	Time  time.Time
+	Value float64
+}
+
+// WindObservation is a single timestamped wind measurement from a station
+type WindObservation struct {
+	Time      time.Time
+	Direction string  // Compass point, e.g. "SW"
+	Speed     float64 // knots
+	Gust      float64 // knots, 0 if not reported
+}
+
+// WindSummary describes the wind observed over a recent period
+type WindSummary struct {
+	Direction string  // Prevailing 8-point compass direction, or "Variable"
+	SpeedMin  float64 // knots
+	SpeedMax  float64 // knots
+}
+
+// String formats the summary as e.g. "W 12-18 kt"
+func (s WindSummary) String() string {
+	if math.Round(s.SpeedMin) == math.Round(s.SpeedMax) {
+		return fmt.Sprintf("%s %.0f kt", s.Direction, s.SpeedMax)
+	}
+	return fmt.Sprintf("%s %.0f-%.0f kt", s.Direction, s.SpeedMin, s.SpeedMax)
+}
+
+// SummarizeWind summarizes the observations in history, oldest first, made within
+// period of the latest one. The prevailing direction is the 45° compass sector most
+// observations fall in, ties going to the sector with the stronger total wind.
+// Observations without a direction count toward the speed range only.
+// Windows end at the latest observation rather than now since station times are
+// local. It returns false if there are no observations.
+func SummarizeWind(history []WindObservation, period time.Duration) (WindSummary, bool) {
+	if len(history) == 0 {
+		return WindSummary{}, false
+	}
+	since := history[len(history)-1].Time.Add(-period)
+
+	var counts, totals [8]float64
+	summary := WindSummary{Direction: "Variable", SpeedMin: math.Inf(1)}
+	for _, obs := range history {
+		if obs.Time.Before(since) {
+			continue
+		}
+		summary.SpeedMin = math.Min(summary.SpeedMin, obs.Speed)
+		summary.SpeedMax = math.Max(summary.SpeedMax, obs.Speed)
+		deg, ok := (WindData{Direction: obs.Direction}).DirectionDegrees()
+		if !ok {
+			continue
+		}
+		// Points between two sectors, e.g. WNW, count half toward each
+		point := int(deg / 22.5)
+		sectors := []int{point / 2}
+		if point%2 == 1 {
+			sectors = append(sectors, (point/2+1)%8)
+		}
+		for _, sector := range sectors {
+			counts[sector] += 1 / float64(len(sectors))
+			totals[sector] += obs.Speed / float64(len(sectors))
+		}
+	}
+	best := -1
+	for i := range counts {
+		if counts[i] == 0 {
+			continue
+		}
+		if best < 0 || counts[i] > counts[best] || (counts[i] == counts[best] && totals[i] > totals[best]) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		summary.Direction = compassPoints[best*2]
+	}
+	return summary, true
 }
 
 // MarineForecast represents a forecast period for marine conditions
@@ -56,3 +263,268 @@ type ThreeDayForecast struct {
 	Periods   []MarineForecast
 	UpdatedAt time.Time
 }
+
+// SmallCraftThresholds are the sustained wind and sea heights at which conditions
+// are considered hazardous to small craft
+type SmallCraftThresholds struct {
+	WindKnots float64 // Sustained wind speed in knots
+	SeasFeet  float64 // Sea height in feet
+}
+
+// DefaultSmallCraftThresholds mirrors the typical NWS small craft advisory criteria
+var DefaultSmallCraftThresholds = SmallCraftThresholds{
+	WindKnots: 21,
+	SeasFeet:  5,
+}
+
+// Exceeded reports whether the upper end of the wind or sea forecast reaches a threshold.
+// A zero threshold is ignored.
+func (t SmallCraftThresholds) Exceeded(wind WindData, seas SeaState) bool {
+	if t.WindKnots > 0 && wind.SpeedMax >= t.WindKnots {
+		return true
+	}
+	if t.SeasFeet > 0 && seas.HeightMax >= t.SeasFeet {
+		return true
+	}
+	return false
+}
+
+// ConditionsRating is a quick go/no-go rating of wind and seas for small craft
+type ConditionsRating int
+
+const (
+	RatingUnknown ConditionsRating = iota // No wind or sea data to rate
+	RatingCalm
+	RatingModerate
+	RatingRough
+	RatingDangerous
+)
+
+// String returns the rating's label, e.g. "Rough"
+func (r ConditionsRating) String() string {
+	switch r {
+	case RatingCalm:
+		return "Calm"
+	case RatingModerate:
+		return "Moderate"
+	case RatingRough:
+		return "Rough"
+	case RatingDangerous:
+		return "Dangerous"
+	default:
+		return "Unknown"
+	}
+}
+
+// RatingThresholds are the wind and sea heights at which conditions are rated
+// moderate, rough and dangerous. Each level is reached when the upper end of the wind
+// or sea forecast reaches it, as with SmallCraftThresholds; a zero value is ignored.
+type RatingThresholds struct {
+	Moderate  SmallCraftThresholds
+	Rough     SmallCraftThresholds
+	Dangerous SmallCraftThresholds
+}
+
+// DefaultRatingThresholds rate conditions rough at the small craft advisory criteria
+// and dangerous at gale force winds or 10 ft seas
+var DefaultRatingThresholds = RatingThresholds{
+	Moderate:  SmallCraftThresholds{WindKnots: 11, SeasFeet: 3},
+	Rough:     DefaultSmallCraftThresholds,
+	Dangerous: SmallCraftThresholds{WindKnots: 34, SeasFeet: 10},
+}
+
+// Rating rates the wind and seas against thresholds. Conditions with neither a wind
+// speed nor a sea height are RatingUnknown.
+func (c MarineConditions) Rating(thresholds RatingThresholds) ConditionsRating {
+	switch {
+	case c.Wind.SpeedMax <= 0 && c.Seas.HeightMax <= 0:
+		return RatingUnknown
+	case thresholds.Dangerous.Exceeded(c.Wind, c.Seas):
+		return RatingDangerous
+	case thresholds.Rough.Exceeded(c.Wind, c.Seas):
+		return RatingRough
+	case thresholds.Moderate.Exceeded(c.Wind, c.Seas):
+		return RatingModerate
+	default:
+		return RatingCalm
+	}
+}
+
+// ForecastDay is the forecast periods that fall on one calendar day
+type ForecastDay struct {
+	Date    time.Time // Midnight starting the day; zero if the periods carry no dates
+	Name    string    // e.g. "Thursday", or "Today" for undated text product periods
+	Periods []MarineForecast
+}
+
+// Summary describes the day and its periods, e.g. "Thursday: afternoon / night"
+func (d ForecastDay) Summary() string {
+	parts := make([]string, len(d.Periods))
+	for i, p := range d.Periods {
+		parts[i] = p.PartOfDay()
+	}
+	return d.Name + ": " + strings.Join(parts, " / ")
+}
+
+// PartOfDay names the part of the day a period covers: "overnight", "morning",
+// "afternoon", "night" or "day"
+func (f MarineForecast) PartOfDay() string {
+	name := strings.ToUpper(f.PeriodName)
+	switch {
+	case strings.Contains(name, "OVERNIGHT"):
+		return "overnight"
+	case strings.Contains(name, "NIGHT"), strings.Contains(name, "EVENING"):
+		return "night"
+	case strings.Contains(name, "AFTERNOON"):
+		return "afternoon"
+	case strings.Contains(name, "MORNING"):
+		return "morning"
+	case !f.Date.IsZero() && f.Date.Hour() >= 12 && f.Date.Hour() < 18:
+		return "afternoon"
+	}
+	return "day"
+}
+
+// IsNight reports whether the period covers the night, e.g. "TONIGHT", "FRI NIGHT" or
+// "OVERNIGHT". Periods without a name go by their start time, from 6 PM to 6 AM.
+func (f MarineForecast) IsNight() bool {
+	if strings.TrimSpace(f.PeriodName) == "" {
+		hour := f.Date.Hour()
+		return !f.Date.IsZero() && (hour >= 18 || hour < 6)
+	}
+	part := f.PartOfDay()
+	return part == "night" || part == "overnight"
+}
+
+// GroupByDay groups the periods under the calendar day they fall on. A night
+// period stays with the day before it even when it starts after midnight, and a
+// leading overnight period joins the day that follows it. Periods parsed from the
+// marine text product have no Date, so for those the day is read from the period
+// name ("TODAY", "THU", "THU NIGHT").
+func (f *ThreeDayForecast) GroupByDay() []ForecastDay {
+	var days []ForecastDay
+	for _, p := range f.Periods {
+		if n := len(days); n > 0 && sameForecastDay(days[n-1], p) {
+			days[n-1].Periods = append(days[n-1].Periods, p)
+			continue
+		}
+		day := ForecastDay{Periods: []MarineForecast{p}}
+		if !p.Date.IsZero() {
+			day.Date = time.Date(p.Date.Year(), p.Date.Month(), p.Date.Day(), 0, 0, 0, 0, p.Date.Location())
+		}
+		days = append(days, day)
+	}
+
+	for i := range days {
+		days[i].Name = forecastDayName(days[i])
+	}
+	return days
+}
+
+// sameForecastDay reports whether period p belongs with the periods already in day
+func sameForecastDay(day ForecastDay, p MarineForecast) bool {
+	parts := make(map[string]bool)
+	for _, q := range day.Periods {
+		parts[q.PartOfDay()] = true
+	}
+	part := p.PartOfDay()
+
+	switch {
+	case part == "night" || part == "overnight":
+		// Pair a night with the day before it, but a day only has one night
+		return !parts["night"] && !parts[part]
+	case len(parts) == 1 && parts["overnight"]:
+		// The small hours before the first daytime period
+		return true
+	case parts["night"]:
+		return false
+	case !p.Date.IsZero() && !day.Date.IsZero():
+		y1, m1, d1 := day.Date.Date()
+		y2, m2, d2 := p.Date.Date()
+		return y1 == y2 && m1 == m2 && d1 == d2
+	}
+	return false
+}
+
+// forecastDayName names a day by its weekday, or from its period names when undated
+func forecastDayName(day ForecastDay) string {
+	if !day.Date.IsZero() {
+		return day.Date.Weekday().String()
+	}
+
+	name := strings.ToUpper(day.Periods[0].PeriodName)
+	for _, p := range day.Periods {
+		if p.PartOfDay() != "overnight" {
+			name = strings.ToUpper(p.PeriodName)
+			break
+		}
+	}
+	switch name {
+	case "TODAY", "TONIGHT", "REST OF TODAY", "THIS AFTERNOON", "THIS MORNING", "THIS EVENING", "OVERNIGHT":
+		return "Today"
+	}
+	name = strings.TrimSpace(strings.TrimSuffix(name, "NIGHT"))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		full := strings.ToUpper(d.String())
+		if name == full || name == full[:3] {
+			return d.String()
+		}
+	}
+	return day.Periods[0].PeriodName
+}
+
+// Summary describes the first forecast period with wind or sea data in one line,
+// e.g. "Today: W 15-20 kt, seas 5-7 ft, SCA". SCA is added when the period reaches
+// the small craft thresholds. It returns "" when no period has wind or seas.
+func (f *ThreeDayForecast) Summary(thresholds SmallCraftThresholds) string {
+	if f == nil {
+		return ""
+	}
+	for _, p := range f.Periods {
+		var parts []string
+		if p.Wind.SpeedMax > 0 {
+			parts = append(parts, summaryWind(p.Wind))
+		}
+		if p.Seas.HeightMax > 0 {
+			parts = append(parts, "seas "+summaryRange(p.Seas.HeightMin, p.Seas.HeightMax, "ft"))
+		}
+		if len(parts) == 0 {
+			continue
+		}
+		if thresholds.Exceeded(p.Wind, p.Seas) {
+			parts = append(parts, "SCA")
+		}
+		return periodTitle(p.PeriodName) + ": " + strings.Join(parts, ", ")
+	}
+	return ""
+}
+
+// summaryWind describes wind tersely, e.g. "W 15-20 kt G30"
+func summaryWind(w WindData) string {
+	s := summaryRange(w.SpeedMin, w.SpeedMax, "kt")
+	if w.Direction != "" {
+		s = w.Direction + " " + s
+	}
+	if w.HasGust && w.GustSpeed > 0 {
+		s += fmt.Sprintf(" G%.0f", w.GustSpeed)
+	}
+	return s
+}
+
+// summaryRange formats a min-max range with its unit, collapsing equal or missing minimums
+func summaryRange(lo, hi float64, unit string) string {
+	if lo <= 0 || lo == hi {
+		return fmt.Sprintf("%.0f %s", hi, unit)
+	}
+	return fmt.Sprintf("%.0f-%.0f %s", lo, hi, unit)
+}
+
+// periodTitle capitalizes each word of a period name, so the text product's "THU NIGHT"
+// reads "Thu Night"
+func periodTitle(name string) string {
+	words := strings.Fields(strings.ToLower(name))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
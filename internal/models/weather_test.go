@@ -1,7 +1,10 @@
 package models
 
 import (
+	"math"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestWindData_HasGustLogic(t *testing.T) {
@@ -46,6 +49,55 @@ func TestWindData_HasGustLogic(t *testing.T) {
 	}
 }
 
+func TestWindData_Beaufort(t *testing.T) {
+	tests := []struct {
+		knots       float64
+		wantForce   int
+		description string
+	}{
+		{0, 0, "Calm"},
+		{0.4, 0, "Calm"},
+		{0.5, 1, "Light Air"},
+		{3, 1, "Light Air"},
+		{3.4, 1, "Light Air"},
+		{3.5, 2, "Light Breeze"},
+		{6, 2, "Light Breeze"},
+		{7, 3, "Gentle Breeze"},
+		{10, 3, "Gentle Breeze"},
+		{11, 4, "Moderate Breeze"},
+		{16, 4, "Moderate Breeze"},
+		{17, 5, "Fresh Breeze"},
+		{21, 5, "Fresh Breeze"},
+		{22, 6, "Strong Breeze"},
+		{27, 6, "Strong Breeze"},
+		{28, 7, "Near Gale"},
+		{33, 7, "Near Gale"},
+		{34, 8, "Gale"},
+		{40, 8, "Gale"},
+		{41, 9, "Strong Gale"},
+		{47, 9, "Strong Gale"},
+		{48, 10, "Storm"},
+		{55, 10, "Storm"},
+		{56, 11, "Violent Storm"},
+		{63, 11, "Violent Storm"},
+		{64, 12, "Hurricane Force"},
+		{120, 12, "Hurricane Force"},
+	}
+
+	for _, tt := range tests {
+		// The force follows the top of the speed range
+		wind := WindData{Direction: "SW", SpeedMin: tt.knots / 2, SpeedMax: tt.knots}
+		got := wind.Beaufort()
+		if got.Number != tt.wantForce || got.Description != tt.description {
+			t.Errorf("Beaufort() at %v kt = %d (%s), want %d (%s)", tt.knots, got.Number, got.Description, tt.wantForce, tt.description)
+		}
+	}
+
+	if got := (WindData{SpeedMin: 15, SpeedMax: 20}).Beaufort().String(); got != "Force 5 (Fresh Breeze)" {
+		t.Errorf("String() = %q, want %q", got, "Force 5 (Fresh Breeze)")
+	}
+}
+
 func TestWaveComponent_Structure(t *testing.T) {
 	// Test that WaveComponent can represent the NOAA format:
 	// "S 5 ft at 8 seconds"
@@ -97,3 +149,455 @@ func TestSeaState_MultipleComponents(t *testing.T) {
 		t.Errorf("Second component = %+v, want {W 4.0 5}", c)
 	}
 }
+
+func TestSmallCraftThresholds_Exceeded(t *testing.T) {
+	tests := []struct {
+		name       string
+		thresholds SmallCraftThresholds
+		wind       WindData
+		seas       SeaState
+		want       bool
+	}{
+		{
+			name:       "calm",
+			thresholds: DefaultSmallCraftThresholds,
+			wind:       WindData{SpeedMin: 10, SpeedMax: 15},
+			seas:       SeaState{HeightMin: 2, HeightMax: 3},
+			want:       false,
+		},
+		{
+			name:       "just below both thresholds",
+			thresholds: DefaultSmallCraftThresholds,
+			wind:       WindData{SpeedMin: 15, SpeedMax: 20, GustSpeed: 30, HasGust: true},
+			seas:       SeaState{HeightMin: 3, HeightMax: 4},
+			want:       false,
+		},
+		{
+			name:       "wind at threshold",
+			thresholds: DefaultSmallCraftThresholds,
+			wind:       WindData{SpeedMin: 15, SpeedMax: 21},
+			seas:       SeaState{HeightMin: 2, HeightMax: 3},
+			want:       true,
+		},
+		{
+			name:       "seas above threshold",
+			thresholds: DefaultSmallCraftThresholds,
+			wind:       WindData{SpeedMin: 10, SpeedMax: 15},
+			seas:       SeaState{HeightMin: 4, HeightMax: 6},
+			want:       true,
+		},
+		{
+			name:       "custom wind threshold",
+			thresholds: SmallCraftThresholds{WindKnots: 15, SeasFeet: 5},
+			wind:       WindData{SpeedMin: 10, SpeedMax: 15},
+			seas:       SeaState{HeightMin: 2, HeightMax: 3},
+			want:       true,
+		},
+		{
+			name:       "zero thresholds disabled",
+			thresholds: SmallCraftThresholds{},
+			wind:       WindData{SpeedMin: 30, SpeedMax: 40},
+			seas:       SeaState{HeightMin: 8, HeightMax: 10},
+			want:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.thresholds.Exceeded(tt.wind, tt.seas); got != tt.want {
+				t.Errorf("Exceeded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarineConditions_Rating(t *testing.T) {
+	tests := []struct {
+		name    string
+		windMax float64
+		seasMax float64
+		want    ConditionsRating
+	}{
+		{"no data", 0, 0, RatingUnknown},
+		{"light air, flat", 5, 1, RatingCalm},
+		{"just below moderate", 10, 2.9, RatingCalm},
+		{"wind at moderate", 11, 1, RatingModerate},
+		{"seas at moderate", 5, 3, RatingModerate},
+		{"just below rough", 20, 4.9, RatingModerate},
+		{"wind at rough", 21, 2, RatingRough},
+		{"seas at rough", 10, 5, RatingRough},
+		{"just below dangerous", 33, 9.9, RatingRough},
+		{"gale force wind", 34, 4, RatingDangerous},
+		{"seas at dangerous", 15, 10, RatingDangerous},
+		{"seas only", 0, 6, RatingRough},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := MarineConditions{Wind: WindData{SpeedMax: tt.windMax}, Seas: SeaState{HeightMax: tt.seasMax}}
+			if got := c.Rating(DefaultRatingThresholds); got != tt.want {
+				t.Errorf("Rating() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Custom thresholds, with the rough level disabled
+	custom := RatingThresholds{
+		Moderate:  SmallCraftThresholds{WindKnots: 5},
+		Dangerous: SmallCraftThresholds{WindKnots: 25},
+	}
+	c := MarineConditions{Wind: WindData{SpeedMax: 22}, Seas: SeaState{HeightMax: 8}}
+	if got := c.Rating(custom); got != RatingModerate {
+		t.Errorf("Rating() with custom thresholds = %v, want Moderate", got)
+	}
+
+	if RatingDangerous.String() != "Dangerous" || ConditionsRating(99).String() != "Unknown" {
+		t.Error("String() should label ratings")
+	}
+}
+
+func TestWindData_DirectionDegrees(t *testing.T) {
+	tests := []struct {
+		direction string
+		want      float64
+		ok        bool
+	}{
+		{"N", 0, true},
+		{"NNE", 22.5, true},
+		{"e", 90, true},
+		{"SW", 225, true},
+		{"W", 270, true},
+		{"NNW", 337.5, true},
+		{"Variable", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.direction, func(t *testing.T) {
+			got, ok := WindData{Direction: tt.direction}.DirectionDegrees()
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("DirectionDegrees() = %v, %v; want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestThreeDayForecast_GroupByDay(t *testing.T) {
+	// Gridpoint periods issued on a Wednesday afternoon: a leading partial day
+	// followed by two full days
+	loc := time.FixedZone("EST", -5*60*60)
+	at := func(day, hour int) time.Time { return time.Date(2025, 11, day, hour, 0, 0, 0, loc) }
+	dated := &ThreeDayForecast{Periods: []MarineForecast{
+		{PeriodName: "This Afternoon", Date: at(26, 14)},
+		{PeriodName: "Tonight", Date: at(26, 18)},
+		{PeriodName: "Thanksgiving Day", Date: at(27, 6)},
+		{PeriodName: "Thursday Night", Date: at(27, 18)},
+		{PeriodName: "Friday", Date: at(28, 6)},
+		{PeriodName: "Friday Night", Date: at(28, 18)},
+	}}
+
+	// The same sequence from the marine text product, which carries no dates
+	undated := &ThreeDayForecast{Periods: []MarineForecast{
+		{PeriodName: "THIS AFTERNOON"},
+		{PeriodName: "TONIGHT"},
+		{PeriodName: "THU"},
+		{PeriodName: "THU NIGHT"},
+		{PeriodName: "FRI"},
+		{PeriodName: "FRI NIGHT"},
+	}}
+
+	tests := []struct {
+		name     string
+		forecast *ThreeDayForecast
+		want     []string
+	}{
+		{"dated", dated, []string{"Wednesday: afternoon / night", "Thursday: day / night", "Friday: day / night"}},
+		{"undated", undated, []string{"Today: afternoon / night", "Thursday: day / night", "Friday: day / night"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days := tt.forecast.GroupByDay()
+			var got []string
+			for _, d := range days {
+				got = append(got, d.Summary())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("GroupByDay() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	days := dated.GroupByDay()
+	if !days[1].Date.Equal(time.Date(2025, 11, 27, 0, 0, 0, 0, loc)) {
+		t.Errorf("Thursday's Date = %v, want midnight Nov 27", days[1].Date)
+	}
+	if days[1].Periods[0].PeriodName != "Thanksgiving Day" {
+		t.Errorf("Thursday should start with its day period, got %q", days[1].Periods[0].PeriodName)
+	}
+}
+
+func TestThreeDayForecast_GroupByDay_Overnight(t *testing.T) {
+	// Issued late in the evening: the first period runs from midnight and belongs
+	// with the day that follows, and a night ending after midnight stays with its day
+	loc := time.UTC
+	at := func(day, hour int) time.Time { return time.Date(2025, 11, day, hour, 0, 0, 0, loc) }
+	f := &ThreeDayForecast{Periods: []MarineForecast{
+		{PeriodName: "Overnight", Date: at(27, 1)},
+		{PeriodName: "Thursday", Date: at(27, 6)},
+		{PeriodName: "Thursday Night", Date: at(27, 18)},
+		{PeriodName: "Friday", Date: at(28, 6)},
+	}}
+
+	days := f.GroupByDay()
+	if len(days) != 2 {
+		t.Fatalf("GroupByDay() returned %d days, want 2", len(days))
+	}
+	if got := days[0].Summary(); got != "Thursday: overnight / day / night" {
+		t.Errorf("first day = %q", got)
+	}
+	if got := days[1].Summary(); got != "Friday: day" {
+		t.Errorf("second day = %q", got)
+	}
+
+	if got := (&ThreeDayForecast{}).GroupByDay(); len(got) != 0 {
+		t.Errorf("empty forecast should have no days, got %v", got)
+	}
+}
+
+func TestThreeDayForecast_Summary(t *testing.T) {
+	tests := []struct {
+		name     string
+		forecast *ThreeDayForecast
+		want     string
+	}{
+		{
+			name: "small craft conditions",
+			forecast: &ThreeDayForecast{Periods: []MarineForecast{{
+				PeriodName: "TODAY",
+				Wind:       WindData{Direction: "W", SpeedMin: 15, SpeedMax: 20},
+				Seas:       SeaState{HeightMin: 5, HeightMax: 7},
+			}}},
+			want: "Today: W 15-20 kt, seas 5-7 ft, SCA",
+		},
+		{
+			name: "light winds with gusts",
+			forecast: &ThreeDayForecast{Periods: []MarineForecast{{
+				PeriodName: "THU NIGHT",
+				Wind:       WindData{Direction: "SW", SpeedMin: 10, SpeedMax: 10, HasGust: true, GustSpeed: 18},
+				Seas:       SeaState{HeightMin: 2, HeightMax: 3},
+			}}},
+			want: "Thu Night: SW 10 kt G18, seas 2-3 ft",
+		},
+		{
+			name: "skips periods without wind or seas",
+			forecast: &ThreeDayForecast{Periods: []MarineForecast{
+				{PeriodName: "Tonight"},
+				{PeriodName: "Friday", Seas: SeaState{HeightMax: 4}},
+			}},
+			want: "Friday: seas 4 ft",
+		},
+		{name: "no periods", forecast: &ThreeDayForecast{}, want: ""},
+		{name: "nil forecast", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.forecast.Summary(DefaultSmallCraftThresholds); got != tt.want {
+				t.Errorf("Summary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPressureUnits(t *testing.T) {
+	if got := MillibarsToInchesHg(MillibarsPerInchHg); got != 1 {
+		t.Errorf("MillibarsToInchesHg(%v) = %v, want 1", MillibarsPerInchHg, got)
+	}
+	// Standard sea level pressure
+	if got := MillibarsToInchesHg(1013.25); math.Abs(got-29.92) > 0.005 {
+		t.Errorf("MillibarsToInchesHg(1013.25) = %v, want 29.92", got)
+	}
+
+	tests := []struct {
+		unit string
+		want string
+	}{
+		{PressureMillibars, "1013.2 mb"},
+		{PressureInchesHg, "29.92 inHg"},
+		{"", "1013.2 mb"},
+	}
+	for _, tt := range tests {
+		if got := FormatPressure(1013.2, tt.unit); got != tt.want {
+			t.Errorf("FormatPressure(1013.2, %q) = %q, want %q", tt.unit, got, tt.want)
+		}
+	}
+
+	for input, want := range map[string]string{"mb": PressureMillibars, "INHG": PressureInchesHg, " inHg ": PressureInchesHg} {
+		if got, err := ParsePressureUnit(input); err != nil || got != want {
+			t.Errorf("ParsePressureUnit(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParsePressureUnit("hPa"); err == nil {
+		t.Error("ParsePressureUnit(\"hPa\") should fail")
+	}
+}
+
+func TestSeaState_Dominant(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []WaveComponent
+		want       WaveComponent
+		wantOK     bool
+	}{
+		{
+			name: "swell over wind waves",
+			components: []WaveComponent{
+				{Kind: WaveKindWindWave, Height: 2},
+				{Kind: WaveKindSwell, Direction: "W", Height: 6, Period: 10},
+				{Kind: WaveKindSwell, Direction: "S", Height: 3, Period: 12},
+			},
+			want:   WaveComponent{Kind: WaveKindSwell, Direction: "W", Height: 6, Period: 10},
+			wantOK: true,
+		},
+		{
+			name: "wind waves over swell",
+			components: []WaveComponent{
+				{Kind: WaveKindSwell, Direction: "SE", Height: 2, Period: 9},
+				{Kind: WaveKindWindWave, Direction: "NE", Height: 5, Period: 5},
+			},
+			want:   WaveComponent{Kind: WaveKindWindWave, Direction: "NE", Height: 5, Period: 5},
+			wantOK: true,
+		},
+		{
+			name: "tie keeps the first",
+			components: []WaveComponent{
+				{Kind: WaveKindSwell, Direction: "E", Height: 4, Period: 8},
+				{Kind: WaveKindSwell, Direction: "S", Height: 4, Period: 14},
+			},
+			want:   WaveComponent{Kind: WaveKindSwell, Direction: "E", Height: 4, Period: 8},
+			wantOK: true,
+		},
+		{
+			name:       "single component",
+			components: []WaveComponent{{Kind: WaveKindWindWave, Height: 1}},
+			want:       WaveComponent{Kind: WaveKindWindWave, Height: 1},
+			wantOK:     true,
+		},
+		{name: "no components"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SeaState{Components: tt.components}.Dominant()
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("Dominant() = %+v, %v; want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// windSeries builds a synthetic series of observations 30 minutes apart, each given
+// as a direction and speed
+func windSeries(start time.Time, readings ...any) []WindObservation {
+	var history []WindObservation
+	for i := 0; i+1 < len(readings); i += 2 {
+		history = append(history, WindObservation{
+			Time:      start.Add(time.Duration(i/2) * 30 * time.Minute),
+			Direction: readings[i].(string),
+			Speed:     readings[i+1].(float64),
+		})
+	}
+	return history
+}
+
+func TestSummarizeWind(t *testing.T) {
+	start := time.Date(2025, 11, 27, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		history []WindObservation
+		want    WindSummary
+		wantOK  bool
+	}{
+		{
+			name:    "points between sectors count toward both",
+			history: windSeries(start, "WSW", 12.0, "W", 15.0, "WNW", 18.0, "S", 10.0),
+			want:    WindSummary{Direction: "W", SpeedMin: 10, SpeedMax: 18},
+			wantOK:  true,
+		},
+		{
+			name:    "north sector wraps around 0°",
+			history: windSeries(start, "NNW", 8.0, "N", 9.0, "NNE", 10.0, "E", 11.0),
+			want:    WindSummary{Direction: "N", SpeedMin: 8, SpeedMax: 11},
+			wantOK:  true,
+		},
+		{
+			name:    "observations before the period are ignored",
+			history: windSeries(start, "E", 25.0, "E", 24.0, "SW", 10.0, "SW", 12.0, "SW", 11.0, "SW", 13.0, "SW", 14.0, "SW", 12.0, "SW", 11.0),
+			want:    WindSummary{Direction: "SW", SpeedMin: 10, SpeedMax: 14},
+			wantOK:  true,
+		},
+		{
+			name:    "tie goes to the stronger sector",
+			history: windSeries(start, "N", 6.0, "S", 14.0, "N", 7.0, "S", 15.0),
+			want:    WindSummary{Direction: "S", SpeedMin: 6, SpeedMax: 15},
+			wantOK:  true,
+		},
+		{
+			name:    "no direction reported",
+			history: windSeries(start, "", 3.0, "", 4.0),
+			want:    WindSummary{Direction: "Variable", SpeedMin: 3, SpeedMax: 4},
+			wantOK:  true,
+		},
+		{name: "no observations"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SummarizeWind(tt.history, 3*time.Hour)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("SummarizeWind() = %+v, %v; want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestWindSummary_String(t *testing.T) {
+	if got := (WindSummary{Direction: "W", SpeedMin: 12.2, SpeedMax: 17.8}).String(); got != "W 12-18 kt" {
+		t.Errorf("String() = %q, want %q", got, "W 12-18 kt")
+	}
+	if got := (WindSummary{Direction: "NE", SpeedMin: 9.8, SpeedMax: 10.1}).String(); got != "NE 10 kt" {
+		t.Errorf("String() = %q, want %q", got, "NE 10 kt")
+	}
+}
+
+func TestMarineForecast_IsNight(t *testing.T) {
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{name: "TODAY", want: false},
+		{name: "THIS AFTERNOON", want: false},
+		{name: "REST OF TODAY", want: false},
+		{name: "FRI", want: false},
+		{name: "Saturday", want: false},
+		{name: "TONIGHT", want: true},
+		{name: "FRI NIGHT", want: true},
+		{name: "Friday Night", want: true},
+		{name: "OVERNIGHT", want: true},
+		{name: "THIS EVENING", want: true},
+		{date: time.Date(2025, 11, 27, 18, 0, 0, 0, time.UTC), want: true},
+		{date: time.Date(2025, 11, 28, 3, 0, 0, 0, time.UTC), want: true},
+		{date: time.Date(2025, 11, 28, 6, 0, 0, 0, time.UTC), want: false},
+		{want: false},
+	}
+
+	for _, tt := range tests {
+		f := MarineForecast{PeriodName: tt.name, Date: tt.date}
+		if got := f.IsNight(); got != tt.want {
+			t.Errorf("IsNight() for %q at %v = %v, want %v", tt.name, tt.date, got, tt.want)
+		}
+	}
+}
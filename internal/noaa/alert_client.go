@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -25,17 +27,23 @@ type NOAAAlertClient struct {
 	userAgent  string
 	cache      map[string]cacheEntry
 	mu         sync.RWMutex
+	clock      models.Clock
 }
 
 // NewAlertClient creates a new NOAA alert client
 func NewAlertClient() *NOAAAlertClient {
+	return NewAlertClientWithHTTPClient(&http.Client{Timeout: DefaultHTTPTimeout})
+}
+
+// NewAlertClientWithHTTPClient creates a NOAA alert client that sends its requests with
+// httpClient, e.g. one with a different timeout or transport
+func NewAlertClientWithHTTPClient(httpClient *http.Client) *NOAAAlertClient {
 	return &NOAAAlertClient{
-		baseURL: "https://api.weather.gov",
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		userAgent: "MarineTerminal/1.0 (github.com/ngmaloney/marine-terminal)",
+		baseURL:    "https://api.weather.gov",
+		httpClient: httpClient,
+		userAgent:  "MarineTerminal/1.0 (github.com/ngmaloney/marine-terminal)",
 		cache:      make(map[string]cacheEntry),
+		clock:      models.SystemClock,
 	}
 }
 
@@ -77,17 +85,14 @@ func (c *NOAAAlertClient) GetActiveAlerts(ctx context.Context, lat, lon float64)
 		props := feature.Properties
 
 		// Parse times
-		onset, _ := time.Parse(time.RFC3339, props.Onset)
-		expires, _ := time.Parse(time.RFC3339, props.Expires)
+		onset := parseAlertTime(props.Onset, props.Effective, props.Sent)
+		expires := parseAlertTime(props.Expires)
 
 		// Map severity
 		severity := mapSeverity(props.Severity)
 
 		// Extract affected areas
-		areas := make([]string, 0)
-		if props.AreaDesc != "" {
-			areas = append(areas, props.AreaDesc)
-		}
+		areas := splitAreaDesc(props.AreaDesc)
 
 		alert := models.Alert{
 			ID:          props.ID,
@@ -95,8 +100,8 @@ func (c *NOAAAlertClient) GetActiveAlerts(ctx context.Context, lat, lon float64)
 			Headline:    props.Headline,
 			Description: props.Description,
 			Severity:    severity,
-			Urgency:     props.Urgency,
-			Certainty:   props.Certainty,
+			Urgency:     models.ParseAlertUrgency(props.Urgency),
+			Certainty:   models.ParseAlertCertainty(props.Certainty),
 			Onset:       onset,
 			Expires:     expires,
 			Areas:       areas,
@@ -114,17 +119,31 @@ func (c *NOAAAlertClient) GetActiveAlerts(ctx context.Context, lat, lon float64)
 
 // GetActiveAlertsByZone retrieves active alerts for a specific marine zone
 func (c *NOAAAlertClient) GetActiveAlertsByZone(ctx context.Context, marineZone string) (*models.AlertData, error) {
+	return c.GetActiveAlertsByZones(ctx, []string{marineZone})
+}
+
+// GetActiveAlertsByZones retrieves active alerts for several marine zones in a single
+// request, e.g. a zone and its neighbours when a port is near a boundary. An alert
+// covering more than one of the zones is only included once. Results are cached by
+// the set of zones, whatever their order.
+func (c *NOAAAlertClient) GetActiveAlertsByZones(ctx context.Context, marineZones []string) (*models.AlertData, error) {
+	zones := zoneSet(marineZones)
+	if len(zones) == 0 {
+		return nil, fmt.Errorf("no marine zones given")
+	}
+	cacheKey := strings.Join(zones, ",")
+
 	// Check cache first
 	c.mu.RLock()
-	entry, ok := c.cache[marineZone]
+	entry, ok := c.cache[cacheKey]
 	c.mu.RUnlock()
 
-	if ok && time.Since(entry.fetchedAt) < cacheDuration {
+	if ok && c.clock.Now().Sub(entry.fetchedAt) < cacheDuration {
 		return entry.data, nil
 	}
 
-	// Query alerts by zone
-	url := fmt.Sprintf("%s/alerts/active?zone=%s", c.baseURL, marineZone)
+	// Query alerts by zone; the zone parameter takes a comma-separated list
+	url := fmt.Sprintf("%s/alerts/active?zone=%s", c.baseURL, cacheKey)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -159,17 +178,14 @@ func (c *NOAAAlertClient) GetActiveAlertsByZone(ctx context.Context, marineZone
 		props := feature.Properties
 
 		// Parse times
-		onset, _ := time.Parse(time.RFC3339, props.Onset)
-		expires, _ := time.Parse(time.RFC3339, props.Expires)
+		onset := parseAlertTime(props.Onset, props.Effective, props.Sent)
+		expires := parseAlertTime(props.Expires)
 
 		// Map severity
 		severity := mapSeverity(props.Severity)
 
 		// Extract affected areas
-		areas := make([]string, 0)
-		if props.AreaDesc != "" {
-			areas = append(areas, props.AreaDesc)
-		}
+		areas := splitAreaDesc(props.AreaDesc)
 
 		alert := models.Alert{
 			ID:          props.ID,
@@ -177,26 +193,101 @@ func (c *NOAAAlertClient) GetActiveAlertsByZone(ctx context.Context, marineZone
 			Headline:    props.Headline,
 			Description: props.Description,
 			Severity:    severity,
-			Urgency:     props.Urgency,
-			Certainty:   props.Certainty,
+			Urgency:     models.ParseAlertUrgency(props.Urgency),
+			Certainty:   models.ParseAlertCertainty(props.Certainty),
 			Onset:       onset,
 			Expires:     expires,
 			Areas:       areas,
 			Instruction: props.Instruction,
 		}
 
-		// Include all alerts for this zone (they should all be marine)
+		// Include all alerts for these zones (they should all be marine)
 		alertData.Alerts = append(alertData.Alerts, alert)
 	}
+	alertData = alertData.Merge(nil)
 
 	// Store in cache
 	c.mu.Lock()
-	c.cache[marineZone] = cacheEntry{data: alertData, fetchedAt: time.Now()}
+	c.cache[cacheKey] = cacheEntry{data: alertData, fetchedAt: c.clock.Now()}
 	c.mu.Unlock()
 
 	return alertData, nil
 }
 
+// zoneSet returns the distinct, non-empty zone codes in sorted order
+func zoneSet(marineZones []string) []string {
+	seen := make(map[string]bool)
+	var zones []string
+	for _, zone := range marineZones {
+		zone = strings.ToUpper(strings.TrimSpace(zone))
+		if zone == "" || seen[zone] {
+			continue
+		}
+		seen[zone] = true
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return zones
+}
+
+// GetActiveAlertsByZonesAndPoint retrieves alerts for marine zones and merges in any
+// marine alerts issued for the given point. Some alerts (e.g. Special Marine Warnings)
+// are issued for areas that don't map cleanly to the forecast zone, so the point query
+// acts as a fallback. A failed point query does not fail the zone result.
+func (c *NOAAAlertClient) GetActiveAlertsByZonesAndPoint(ctx context.Context, marineZones []string, lat, lon float64) (*models.AlertData, error) {
+	zoneAlerts, err := c.GetActiveAlertsByZones(ctx, marineZones)
+	if err != nil {
+		return nil, err
+	}
+
+	pointAlerts, err := c.GetActiveAlerts(ctx, lat, lon)
+	if err != nil {
+		return mergeAlerts(zoneAlerts, nil), nil
+	}
+
+	return mergeAlerts(zoneAlerts, pointAlerts), nil
+}
+
+// mergeAlerts combines marine alerts from both sources, de-duplicating by alert ID.
+// Alerts from primary take precedence over those in secondary.
+func mergeAlerts(primary, secondary *models.AlertData) *models.AlertData {
+	merged := primary.Merge(secondary)
+
+	marine := merged.Alerts[:0]
+	for _, alert := range merged.Alerts {
+		if alert.IsMarine() {
+			marine = append(marine, alert)
+		}
+	}
+	merged.Alerts = marine
+
+	return merged
+}
+
+// parseAlertTime returns the first of values that parses as an RFC 3339 time, or the
+// zero time if none do. Many alerts omit onset, so it falls back to effective and then
+// sent.
+func parseAlertTime(values ...string) time.Time {
+	for _, v := range values {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// splitAreaDesc splits an alert's areaDesc, the affected areas joined with semicolons
+// (e.g. "Nantucket Sound; Vineyard Sound"), into the area names
+func splitAreaDesc(desc string) []string {
+	areas := make([]string, 0)
+	for _, area := range strings.Split(desc, ";") {
+		if area = strings.TrimSpace(area); area != "" {
+			areas = append(areas, area)
+		}
+	}
+	return areas
+}
+
 func mapSeverity(s string) models.AlertSeverity {
 	switch s {
 	case "Extreme":
@@ -225,6 +316,8 @@ type alertResponse struct {
 			Severity    string `json:"severity"`
 			Urgency     string `json:"urgency"`
 			Certainty   string `json:"certainty"`
+			Sent        string `json:"sent"`
+			Effective   string `json:"effective"`
 			Onset       string `json:"onset"`
 			Expires     string `json:"expires"`
 			AreaDesc    string `json:"areaDesc"`
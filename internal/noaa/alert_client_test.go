@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -109,6 +110,25 @@ func TestMapSeverity(t *testing.T) {
 	}
 }
 
+func TestSplitAreaDesc(t *testing.T) {
+	tests := []struct {
+		desc string
+		want []string
+	}{
+		{"Nantucket Sound", []string{"Nantucket Sound"}},
+		{"Vineyard Sound; Buzzards Bay; Nantucket Sound", []string{"Vineyard Sound", "Buzzards Bay", "Nantucket Sound"}},
+		{" Cape Cod Bay ;; Massachusetts Bay and Ipswich Bay; ", []string{"Cape Cod Bay", "Massachusetts Bay and Ipswich Bay"}},
+		{"", []string{}},
+	}
+
+	for _, tt := range tests {
+		got := splitAreaDesc(tt.desc)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitAreaDesc(%q) = %q, want %q", tt.desc, got, tt.want)
+		}
+	}
+}
+
 func TestNOAAAlertClient_NoAlerts(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -130,3 +150,210 @@ func TestNOAAAlertClient_NoAlerts(t *testing.T) {
 		t.Errorf("len(Alerts) = %d, want 0", len(alertData.Alerts))
 	}
 }
+
+func TestNOAAAlertClient_MissingOnsetAndExpires(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"features":[
+			{"properties":{"id":"effective","event":"Gale Warning","sent":"2025-11-27T05:00:00-05:00","effective":"2025-11-27T06:00:00-05:00","expires":"2025-11-27T18:00:00-05:00"}},
+			{"properties":{"id":"sent","event":"Small Craft Advisory","sent":"2025-11-27T05:00:00-05:00"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewAlertClient()
+	client.baseURL = server.URL
+
+	alertData, err := client.GetActiveAlertsByZone(context.Background(), "ANZ254")
+	if err != nil {
+		t.Fatalf("GetActiveAlertsByZone() error = %v", err)
+	}
+	if len(alertData.Alerts) != 2 {
+		t.Fatalf("len(Alerts) = %d, want 2", len(alertData.Alerts))
+	}
+
+	effective, sent := alertData.Alerts[0], alertData.Alerts[1]
+	if want := time.Date(2025, 11, 27, 11, 0, 0, 0, time.UTC); !effective.Onset.Equal(want) {
+		t.Errorf("Onset = %v, want the effective time %v", effective.Onset, want)
+	}
+	if want := time.Date(2025, 11, 27, 10, 0, 0, 0, time.UTC); !sent.Onset.Equal(want) {
+		t.Errorf("Onset = %v, want the sent time %v", sent.Onset, want)
+	}
+	if !sent.Expires.IsZero() {
+		t.Errorf("Expires = %v, want zero for an alert without an expiry", sent.Expires)
+	}
+
+	now := models.FixedClock(time.Date(2025, 11, 27, 12, 0, 0, 0, time.UTC))
+	if !effective.IsActiveAt(now) || !sent.IsActiveAt(now) {
+		t.Error("Alerts without an onset should be active from their effective or sent time")
+	}
+}
+
+func TestNOAAAlertClient_GetActiveAlertsByZonesAndPoint(t *testing.T) {
+	zoneBody := `{"features":[
+		{"properties":{"id":"alert-1","event":"Small Craft Advisory","severity":"Moderate"}},
+		{"properties":{"id":"alert-2","event":"Gale Warning","severity":"Severe"}},
+		{"properties":{"id":"alert-3","event":"Flood Warning","severity":"Severe"}}
+	]}`
+	pointBody := `{"features":[
+		{"properties":{"id":"alert-2","event":"Gale Warning","severity":"Severe"}},
+		{"properties":{"id":"alert-4","event":"Special Marine Warning","severity":"Severe"}},
+		{"properties":{"id":"alert-5","event":"Tornado Warning","severity":"Extreme"}}
+	]}`
+
+	tests := []struct {
+		name        string
+		pointStatus int
+		wantIDs     []string
+	}{
+		{"merges and de-duplicates overlapping IDs", http.StatusOK, []string{"alert-1", "alert-2", "alert-4"}},
+		{"point failure falls back to zone alerts", http.StatusInternalServerError, []string{"alert-1", "alert-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Query().Get("zone") != "" {
+					w.Write([]byte(zoneBody))
+					return
+				}
+				w.WriteHeader(tt.pointStatus)
+				w.Write([]byte(pointBody))
+			}))
+			defer server.Close()
+
+			client := NewAlertClient()
+			client.baseURL = server.URL
+
+			alertData, err := client.GetActiveAlertsByZonesAndPoint(context.Background(), []string{"ANZ254"}, 41.68, -69.95)
+			if err != nil {
+				t.Fatalf("GetActiveAlertsByZonesAndPoint() error = %v", err)
+			}
+
+			if len(alertData.Alerts) != len(tt.wantIDs) {
+				t.Fatalf("len(Alerts) = %d, want %d", len(alertData.Alerts), len(tt.wantIDs))
+			}
+			for i, id := range tt.wantIDs {
+				if alertData.Alerts[i].ID != id {
+					t.Errorf("Alerts[%d].ID = %s, want %s", i, alertData.Alerts[i].ID, id)
+				}
+			}
+		})
+	}
+}
+
+func TestNOAAAlertClient_GetActiveAlertsByZones(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("zone"))
+		w.Header().Set("Content-Type", "application/json")
+		// A gale warning issued for both zones comes back once per zone it covers
+		w.Write([]byte(`{"features":[
+			{"properties":{"id":"alert-1","event":"Gale Warning","severity":"Severe","areaDesc":"Nantucket Sound"}},
+			{"properties":{"id":"alert-2","event":"Small Craft Advisory","severity":"Moderate","areaDesc":"Cape Cod Bay; Massachusetts Bay and Ipswich Bay"}},
+			{"properties":{"id":"alert-1","event":"Gale Warning","severity":"Severe","areaDesc":"Nantucket Sound"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewAlertClient()
+	client.baseURL = server.URL
+
+	alertData, err := client.GetActiveAlertsByZones(context.Background(), []string{"ANZ254", "ANZ232", "ANZ254"})
+	if err != nil {
+		t.Fatalf("GetActiveAlertsByZones() error = %v", err)
+	}
+	if len(queries) != 1 || queries[0] != "ANZ232,ANZ254" {
+		t.Errorf("zone queries = %q, want one request for ANZ232,ANZ254", queries)
+	}
+	if len(alertData.Alerts) != 2 || alertData.Alerts[0].ID != "alert-1" || alertData.Alerts[1].ID != "alert-2" {
+		t.Errorf("Alerts = %+v, want alert-1 and alert-2 once each", alertData.Alerts)
+	} else if want := []string{"Cape Cod Bay", "Massachusetts Bay and Ipswich Bay"}; !reflect.DeepEqual(alertData.Alerts[1].Areas, want) {
+		t.Errorf("alert-2 Areas = %q, want %q", alertData.Alerts[1].Areas, want)
+	}
+
+	// The same set of zones in another order is served from the cache
+	if _, err := client.GetActiveAlertsByZones(context.Background(), []string{"ANZ232", "ANZ254"}); err != nil {
+		t.Fatalf("GetActiveAlertsByZones() error = %v", err)
+	}
+	if len(queries) != 1 {
+		t.Errorf("made %d requests, want the reordered zones cached", len(queries))
+	}
+	if _, err := client.GetActiveAlertsByZone(context.Background(), "ANZ254"); err != nil {
+		t.Fatalf("GetActiveAlertsByZone() error = %v", err)
+	}
+	if len(queries) != 2 || queries[1] != "ANZ254" {
+		t.Errorf("zone queries = %q, want a separate request for ANZ254 alone", queries)
+	}
+
+	if _, err := client.GetActiveAlertsByZones(context.Background(), nil); err == nil {
+		t.Error("GetActiveAlertsByZones(nil) should fail")
+	}
+}
+
+func TestMergeAlerts(t *testing.T) {
+	primary := &models.AlertData{Alerts: []models.Alert{
+		{ID: "a", Event: "Gale Warning", Headline: "from zone"},
+	}}
+	secondary := &models.AlertData{Alerts: []models.Alert{
+		{ID: "a", Event: "Gale Warning", Headline: "from point"},
+		{ID: "b", Event: "Storm Warning"},
+	}}
+
+	merged := mergeAlerts(primary, secondary)
+	if len(merged.Alerts) != 2 {
+		t.Fatalf("len(Alerts) = %d, want 2", len(merged.Alerts))
+	}
+	if merged.Alerts[0].Headline != "from zone" {
+		t.Errorf("duplicate alert should keep primary copy, got %q", merged.Alerts[0].Headline)
+	}
+
+	if got := mergeAlerts(nil, nil); len(got.Alerts) != 0 {
+		t.Errorf("mergeAlerts(nil, nil) returned %d alerts, want 0", len(got.Alerts))
+	}
+}
+
+// manualClock is a test clock that only moves when told to
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.now
+}
+
+func TestNOAAAlertClient_GetActiveAlertsByZone_CacheExpiry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"features":[]}`))
+	}))
+	defer server.Close()
+
+	clock := &manualClock{now: time.Date(2025, 11, 27, 12, 0, 0, 0, time.UTC)}
+	client := NewAlertClient()
+	client.baseURL = server.URL
+	client.clock = clock
+
+	fetch := func() {
+		t.Helper()
+		if _, err := client.GetActiveAlertsByZone(context.Background(), "ANZ254"); err != nil {
+			t.Fatalf("GetActiveAlertsByZone() error = %v", err)
+		}
+	}
+
+	fetch()
+	clock.now = clock.now.Add(cacheDuration - time.Second)
+	fetch()
+	if requests != 1 {
+		t.Errorf("requests within cache duration = %d, want 1", requests)
+	}
+
+	clock.now = clock.now.Add(time.Second)
+	fetch()
+	if requests != 2 {
+		t.Errorf("requests after cache expiry = %d, want 2", requests)
+	}
+}
@@ -7,6 +7,10 @@ import (
 	"github.com/ngmaloney/marine-terminal/internal/models"
 )
 
+// DefaultHTTPTimeout is how long the NOAA clients wait for a response unless they're
+// given their own http.Client
+const DefaultHTTPTimeout = 30 * time.Second
+
 // WeatherClient defines the interface for fetching weather data from NOAA
 type WeatherClient interface {
 	// GetMarineConditions retrieves current marine conditions for a location
@@ -21,11 +25,18 @@ type WeatherClient interface {
 
 // TideClient defines the interface for fetching tide data from NOAA CO-OPS
 type TideClient interface {
-	// GetTidePredictions retrieves tide predictions for the next 3 days
-	GetTidePredictions(ctx context.Context, stationID string, startDate, endDate time.Time) (*models.TideData, error)
+	// GetTidePredictions retrieves tide predictions for the next 3 days relative to the given datum (defaults to MLLW)
+	GetTidePredictions(ctx context.Context, stationID, datum string, startDate, endDate time.Time) (*models.TideData, error)
 
 	// GetMeteorologicalData retrieves meteorological data (e.g., air temperature, pressure) for a station
 	GetMeteorologicalData(ctx context.Context, stationID string, startDate, endDate time.Time) (*models.MarineConditions, error)
+
+	// GetDatums retrieves the station's tidal datum elevations, used to convert heights between datums
+	GetDatums(ctx context.Context, stationID string) (*models.DatumOffsets, error)
+
+	// GetWaterLevel retrieves the latest observed water level and the prediction at the same time.
+	// It returns ErrNoWaterLevel for stations without a water level sensor.
+	GetWaterLevel(ctx context.Context, stationID, datum string) (*models.WaterLevel, error)
 }
 
 // AlertClient defines the interface for fetching NOAA alerts
@@ -35,6 +46,12 @@ type AlertClient interface {
 
 	// GetActiveAlertsByZone retrieves active alerts for a specific marine zone
 	GetActiveAlertsByZone(ctx context.Context, marineZone string) (*models.AlertData, error)
+
+	// GetActiveAlertsByZones retrieves active alerts for several marine zones in one request
+	GetActiveAlertsByZones(ctx context.Context, marineZones []string) (*models.AlertData, error)
+
+	// GetActiveAlertsByZonesAndPoint retrieves alerts for marine zones merged with point alerts for the given coordinates
+	GetActiveAlertsByZonesAndPoint(ctx context.Context, marineZones []string, lat, lon float64) (*models.AlertData, error)
 }
 
 // PortClient defines the interface for searching ports/stations
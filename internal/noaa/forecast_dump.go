@@ -0,0 +1,34 @@
+package noaa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+// forecastDump is the JSON form of a parsed zone forecast written by WriteForecastJSON
+type forecastDump struct {
+	Zone       string                   `json:"zone"`
+	Conditions *models.MarineConditions `json:"conditions"`
+	Forecast   *models.ThreeDayForecast `json:"forecast"`
+}
+
+// WriteForecastJSON fetches a zone's marine forecast with client and writes the parsed
+// current conditions and forecast periods, raw text included, to w as indented JSON.
+// It's for checking what the text product parser made of a forecast.
+func WriteForecastJSON(ctx context.Context, w io.Writer, client WeatherClient, zone string) error {
+	conditions, forecast, err := client.GetMarineForecastByZone(ctx, zone)
+	if err != nil {
+		return fmt.Errorf("fetching forecast for %s: %w", zone, err)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(forecastDump{Zone: zone, Conditions: conditions, Forecast: forecast}); err != nil {
+		return fmt.Errorf("encoding forecast for %s: %w", zone, err)
+	}
+	return nil
+}
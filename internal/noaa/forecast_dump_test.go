@@ -0,0 +1,81 @@
+package noaa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+// anz254Product is a trimmed NOAA coastal waters forecast for Nantucket Sound
+const anz254Product = `ANZ254-261000-
+Nantucket Sound-
+406 AM EST Wed Nov 26 2025
+
+.TODAY...SW winds 15 to 20 kt with gusts up to 25 kt. Seas 3 to 5 ft.
+.TONIGHT...W winds 10 to 15 kt. Seas around 3 ft.
+.THU...NW winds 5 to 10 kt. Seas 2 ft or less.
+`
+
+// fixtureWeatherClient parses a fixed marine text product for any zone
+type fixtureWeatherClient struct {
+	text string
+	err  error
+}
+
+func (c *fixtureWeatherClient) GetMarineConditions(ctx context.Context, lat, lon float64) (*models.MarineConditions, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *fixtureWeatherClient) GetMarineForecast(ctx context.Context, lat, lon float64) (*models.ThreeDayForecast, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *fixtureWeatherClient) GetMarineForecastByZone(ctx context.Context, zone string) (*models.MarineConditions, *models.ThreeDayForecast, error) {
+	if c.err != nil {
+		return nil, nil, c.err
+	}
+	return parseMarineTextProduct(c.text, zone)
+}
+
+func TestWriteForecastJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteForecastJSON(context.Background(), &buf, &fixtureWeatherClient{text: anz254Product}, "ANZ254"); err != nil {
+		t.Fatalf("WriteForecastJSON() error = %v", err)
+	}
+
+	var got forecastDump
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if got.Zone != "ANZ254" || got.Conditions == nil || got.Forecast == nil {
+		t.Fatalf("output missing zone, conditions or forecast:\n%s", buf.String())
+	}
+	if got.Conditions.Wind.SpeedMax != 20 || got.Conditions.Seas.HeightMax != 5 {
+		t.Errorf("conditions = %+v, want SW 15-20 kt and 3-5 ft seas", got.Conditions)
+	}
+	if len(got.Forecast.Periods) != 3 {
+		t.Fatalf("len(Periods) = %d, want 3", len(got.Forecast.Periods))
+	}
+	if first := got.Forecast.Periods[0]; first.PeriodName != "TODAY" || !strings.Contains(first.RawText, "gusts up to 25 kt") {
+		t.Errorf("first period = %q with raw text %q, want TODAY with its raw text", first.PeriodName, first.RawText)
+	}
+	if !strings.Contains(buf.String(), "\n  \"conditions\": {") {
+		t.Errorf("output should be indented:\n%s", buf.String())
+	}
+}
+
+func TestWriteForecastJSON_FetchError(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteForecastJSON(context.Background(), &buf, &fixtureWeatherClient{err: errors.New("status 404")}, "ANZ999")
+	if err == nil || !strings.Contains(err.Error(), "ANZ999") {
+		t.Errorf("WriteForecastJSON() error = %v, want a fetch error naming the zone", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("nothing should be written on error, got %q", buf.String())
+	}
+}
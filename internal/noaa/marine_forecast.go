@@ -2,6 +2,7 @@ package noaa
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,7 +11,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ngmaloney/marine-terminal/internal/debuglog"
 	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
 )
 
 // GetMarineForecastByZone retrieves marine forecast for a given zone
@@ -19,54 +22,112 @@ func (c *NOAAWeatherClient) GetMarineForecastByZone(ctx context.Context, marineZ
 		return nil, nil, fmt.Errorf("marine zone is required")
 	}
 
-	// NOAA's JSON API doesn't support marine forecasts, use text products instead
-	// Format: https://tgftp.nws.noaa.gov/data/forecasts/marine/coastal/an/anz254.txt
-	zoneType := determineZoneType(marineZone)
-	url := fmt.Sprintf("https://tgftp.nws.noaa.gov/data/forecasts/marine/%s/%s/%s.txt",
-		zoneType, getZonePrefix(marineZone), strings.ToLower(marineZone))
+	// NOAA's JSON API doesn't support marine forecasts, use text products instead.
+	// Not every zone is where the naming convention puts it, so a missing product is
+	// looked for at the alternate paths before giving up.
+	var text string
+	var err error
+	for _, url := range c.textProductURLs(marineZone) {
+		text, err = c.fetchTextProduct(ctx, url)
+		if errors.Is(err, errTextProductNotFound) {
+			debuglog.Printf("No marine text product for %s at %s", marineZone, url)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		debuglog.Printf("Fetched marine text product for %s from %s", marineZone, url)
+		break
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("marine text product not found for zone %s", marineZone)
+	}
 
+	// Parse the marine text forecast
+	return parseMarineTextProduct(text, marineZone)
+}
+
+// errTextProductNotFound marks a text product URL that returned 404
+var errTextProductNotFound = errors.New("text product not found")
+
+// fetchTextProduct downloads a marine text product, failing with errTextProductNotFound
+// on a 404
+func (c *NOAAWeatherClient) fetchTextProduct(ctx context.Context, url string) (string, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, nil, fmt.Errorf("creating request: %w", err)
+		return "", fmt.Errorf("creating request: %w", err)
 	}
 
 	req.Header.Set("User-Agent", c.userAgent)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, nil, fmt.Errorf("fetching forecast: %w", err)
+		return "", fmt.Errorf("fetching forecast: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return "", errTextProductNotFound
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, nil, fmt.Errorf("marine text product returned status %d for zone %s", resp.StatusCode, marineZone)
+		return "", fmt.Errorf("marine text product returned status %d for %s", resp.StatusCode, url)
 	}
 
 	// Read the full text response
 	textBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, nil, fmt.Errorf("reading response: %w", err)
+		return "", fmt.Errorf("reading response: %w", err)
 	}
+	return string(textBytes), nil
+}
 
-	// Parse the marine text forecast
-	return parseMarineTextProduct(string(textBytes), marineZone)
+// zoneForecastLocation returns the forecast type ("coastal" or "offshore") and
+// product directory of a zone code, e.g. "coastal" and "an" for ANZ254. It returns
+// false for codes that aren't a known prefix followed by a three-digit number.
+func zoneForecastLocation(zone string) (forecastType, dir string, ok bool) {
+	zoneType, family, ok := zonelookup.ZoneCodeType(zone)
+	if !ok {
+		return "", "", false
+	}
+	return string(zoneType), family.Dir, true
 }
 
-// determineZoneType returns the forecast type based on zone prefix
-func determineZoneType(zone string) string {
-	zone = strings.ToUpper(zone)
-	if strings.HasPrefix(zone, "AN") || strings.HasPrefix(zone, "GM") {
-		return "coastal"
+// ZoneForecastPath returns the path of a zone's marine text product under NOAA's
+// forecast directory, e.g. coastal/an/anz254.txt for ANZ254. It returns false for
+// codes that aren't a known marine zone prefix followed by a three-digit number.
+func ZoneForecastPath(zone string) (string, bool) {
+	forecastType, dir, ok := zoneForecastLocation(zone)
+	if !ok {
+		return "", false
 	}
-	return "offshore"
+	return fmt.Sprintf("%s/%s/%s.txt", forecastType, dir, strings.ToLower(strings.TrimSpace(zone))), true
 }
 
-// getZonePrefix returns the two-letter prefix for the zone directory
-func getZonePrefix(zone string) string {
-	if len(zone) < 2 {
-		return "an"
+// textProductURLs returns the URLs a zone's marine text product may be found at, most
+// likely first: the conventional path, the same path under the other forecast type,
+// then both again with the file name in upper case. Zones with an unknown prefix are
+// looked for as coastal zones in the directory named for their first two letters.
+func (c *NOAAWeatherClient) textProductURLs(zone string) []string {
+	zone = strings.ToUpper(strings.TrimSpace(zone))
+	zoneType, dir, ok := zoneForecastLocation(zone)
+	if !ok {
+		zoneType, dir = "coastal", "an"
+		if len(zone) >= 2 {
+			dir = strings.ToLower(zone[:2])
+		}
+	}
+	otherType := "offshore"
+	if zoneType == "offshore" {
+		otherType = "coastal"
+	}
+
+	var urls []string
+	for _, name := range []string{strings.ToLower(zone), zone} {
+		for _, typ := range []string{zoneType, otherType} {
+			urls = append(urls, fmt.Sprintf("%s/%s/%s/%s.txt", c.textProductURL, typ, dir, name))
+		}
 	}
-	return strings.ToLower(zone[:2])
+	return urls
 }
 
 // parseMarineTextProduct parses NOAA's marine text product format
@@ -141,6 +202,69 @@ func parseMarineTextProduct(text, zone string) (*models.MarineConditions, *model
 	return conditions, forecast, nil
 }
 
+// swellPeriodSeconds is the period at or above which an unlabeled wave component is treated as swell
+const swellPeriodSeconds = 9
+
+var (
+	sentenceRegex = regexp.MustCompile(`\.\s+|\.$`)
+	waveRegex     = regexp.MustCompile(`(?i)\b([NESW]{1,3})\s+(\d+)\s*ft\s+at\s+(\d+)\s+seconds?`)
+	windWaveRegex = regexp.MustCompile(`(?i)wind\s+waves?\s+(?:around\s+)?(?:([NESW]{1,3})\s+)?(\d+)(?:\s+to\s+(\d+))?\s*ft(?:\s+or\s+less)?(?:\s+at\s+(\d+)\s+seconds?)?`)
+)
+
+// parseWaveComponents extracts swell and wind wave components from forecast text.
+// Components in a sentence mentioning "swell" or "wind waves" take that kind; unlabeled
+// components (e.g. "Wave Detail: S 5 ft at 8 seconds") are classified by period.
+func parseWaveComponents(forecastText string) []models.WaveComponent {
+	var components []models.WaveComponent
+
+	for _, sentence := range sentenceRegex.Split(forecastText, -1) {
+		lower := strings.ToLower(sentence)
+		isSwell := strings.Contains(lower, "swell")
+		isWindWave := strings.Contains(lower, "wind wave")
+
+		// "Wind waves 2 ft" has no direction/period, so it needs its own pattern
+		if isWindWave && !isSwell {
+			if match := windWaveRegex.FindStringSubmatch(sentence); match != nil {
+				height, _ := strconv.ParseFloat(match[2], 64)
+				if match[3] != "" {
+					height, _ = strconv.ParseFloat(match[3], 64)
+				}
+				period, _ := strconv.Atoi(match[4])
+				components = append(components, models.WaveComponent{
+					Kind:      models.WaveKindWindWave,
+					Direction: strings.ToUpper(match[1]),
+					Height:    height,
+					Period:    period,
+				})
+			}
+			continue
+		}
+
+		for _, match := range waveRegex.FindAllStringSubmatch(sentence, -1) {
+			height, _ := strconv.ParseFloat(match[2], 64)
+			period, _ := strconv.Atoi(match[3])
+
+			kind := models.WaveKindWindWave
+			if isSwell || (!isWindWave && period >= swellPeriodSeconds) {
+				kind = models.WaveKindSwell
+			}
+			components = append(components, models.WaveComponent{
+				Kind:      kind,
+				Direction: strings.ToUpper(match[1]),
+				Height:    height,
+				Period:    period,
+			})
+		}
+	}
+
+	return components
+}
+
+var (
+	gustRegex   = regexp.MustCompile(`(?i)gusts?\s+(?:up\s+to\s+|to\s+)?(\d+)(?:\s+to\s+(\d+))?\s*kt`)
+	squallRegex = regexp.MustCompile(`(?i)\bsqualls?\b`)
+)
+
 // parseMarineForecast parses a NOAA marine forecast text into structured data
 func parseMarineForecast(forecastText, zone string) *models.MarineConditions {
 	conditions := &models.MarineConditions{
@@ -165,22 +289,31 @@ func parseMarineForecast(forecastText, zone string) *models.MarineConditions {
 			RawText:   match[0],
 		}
 
-		// Check for gusts
-		gustRegex := regexp.MustCompile(`(?i)gusts?\s+(?:up\s+to\s+)?(\d+)\s*kt`)
+		// Check for gusts ("gusts to 30 kt", "gusts up to 30 kt", "gusts 25 to 30 kt");
+		// a range is recorded as its upper end
 		if gustMatch := gustRegex.FindStringSubmatch(forecastText); len(gustMatch) > 0 {
 			gust, _ := strconv.ParseFloat(gustMatch[1], 64)
+			if gustMatch[2] != "" {
+				gust, _ = strconv.ParseFloat(gustMatch[2], 64)
+			}
 			conditions.Wind.GustSpeed = gust
 			conditions.Wind.HasGust = true
 		}
+		// e.g. "gusts to 35 kt in squalls" or "Chance of squalls"
+		conditions.Wind.Squalls = squallRegex.MatchString(forecastText)
 	}
 
-	// Parse seas (e.g., "Seas 5 to 7 ft" or "Waves 3 to 5 ft")
-	seasRegex := regexp.MustCompile(`(?i)(?:seas|waves)\s+(\d+)(?:\s+to\s+(\d+))?\s*ft`)
+	// Parse seas (e.g., "Seas 5 to 7 ft", "Seas around 3 ft", "Seas 2 ft or less" or "Seas less than 2 ft")
+	seasRegex := regexp.MustCompile(`(?i)(?:seas|waves)\s+(around\s+|less\s+than\s+)?(\d+)(?:\s+to\s+(\d+))?\s*ft(\s+or\s+less)?`)
 	if match := seasRegex.FindStringSubmatch(forecastText); len(match) > 0 {
-		heightMin, _ := strconv.ParseFloat(match[1], 64)
+		heightMin, _ := strconv.ParseFloat(match[2], 64)
 		heightMax := heightMin
-		if len(match) > 2 && match[2] != "" {
-			heightMax, _ = strconv.ParseFloat(match[2], 64)
+		if match[3] != "" {
+			heightMax, _ = strconv.ParseFloat(match[3], 64)
+		}
+		// "or less" and "less than" give an upper bound only
+		if match[4] != "" || strings.HasPrefix(strings.ToLower(match[1]), "less") {
+			heightMin = 0
 		}
 
 		conditions.Seas = models.SeaState{
@@ -191,20 +324,8 @@ func parseMarineForecast(forecastText, zone string) *models.MarineConditions {
 		}
 	}
 
-	// Parse wave components (e.g., "S 5 ft at 8 seconds")
-	waveRegex := regexp.MustCompile(`(?i)([NESW]+)\s+(\d+)\s*ft\s+at\s+(\d+)\s+seconds?`)
-	for _, match := range waveRegex.FindAllStringSubmatch(forecastText, -1) {
-		if len(match) > 0 {
-			height, _ := strconv.ParseFloat(match[2], 64)
-			period, _ := strconv.Atoi(match[3])
-
-			conditions.Seas.Components = append(conditions.Seas.Components, models.WaveComponent{
-				Direction: match[1],
-				Height:    height,
-				Period:    period,
-			})
-		}
-	}
+	// Parse wave components (e.g., "Swell W 6 ft at 10 seconds" or "Wave Detail: S 5 ft at 8 seconds")
+	conditions.Seas.Components = append(conditions.Seas.Components, parseWaveComponents(forecastText)...)
 
 	// Store the full forecast text
 	conditions.Conditions = forecastText
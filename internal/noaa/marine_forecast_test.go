@@ -0,0 +1,344 @@
+package noaa
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ngmaloney/marine-terminal/internal/debuglog"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
+)
+
+func TestParseWaveComponents(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []models.WaveComponent
+	}{
+		{
+			name: "wind waves and swell",
+			text: "SW winds 10 to 15 kt. Wind waves 2 ft. Swell W 6 ft at 10 seconds.",
+			want: []models.WaveComponent{
+				{Kind: models.WaveKindWindWave, Height: 2},
+				{Kind: models.WaveKindSwell, Direction: "W", Height: 6, Period: 10},
+			},
+		},
+		{
+			name: "wind wave range with period",
+			text: "Wind waves SW 2 to 3 ft at 4 seconds. Mixed swell S 4 ft at 12 seconds and SE 2 ft at 7 seconds.",
+			want: []models.WaveComponent{
+				{Kind: models.WaveKindWindWave, Direction: "SW", Height: 3, Period: 4},
+				{Kind: models.WaveKindSwell, Direction: "S", Height: 4, Period: 12},
+				{Kind: models.WaveKindSwell, Direction: "SE", Height: 2, Period: 7},
+			},
+		},
+		{
+			name: "unlabeled wave detail classified by period",
+			text: "Seas 3 to 5 ft. Wave Detail: S 4 ft at 9 seconds and SW 2 ft at 5 seconds.",
+			want: []models.WaveComponent{
+				{Kind: models.WaveKindSwell, Direction: "S", Height: 4, Period: 9},
+				{Kind: models.WaveKindWindWave, Direction: "SW", Height: 2, Period: 5},
+			},
+		},
+		{
+			name: "wind waves or less",
+			text: "W winds 5 kt. Wind waves 1 ft or less.",
+			want: []models.WaveComponent{
+				{Kind: models.WaveKindWindWave, Height: 1},
+			},
+		},
+		{
+			name: "no wave detail",
+			text: "N winds 10 kt. Seas 2 ft.",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseWaveComponents(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseWaveComponents() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMarineForecast_WaveComponents(t *testing.T) {
+	text := "SW winds 15 to 20 kt. Seas 4 to 6 ft. Wind waves 3 ft. Swell S 5 ft at 11 seconds."
+
+	conditions := parseMarineForecast(text, "ANZ254")
+
+	if conditions.Seas.HeightMin != 4 || conditions.Seas.HeightMax != 6 {
+		t.Errorf("Seas = %v-%v, want 4-6", conditions.Seas.HeightMin, conditions.Seas.HeightMax)
+	}
+	if len(conditions.Seas.Components) != 2 {
+		t.Fatalf("len(Components) = %d, want 2", len(conditions.Seas.Components))
+	}
+	if c := conditions.Seas.Components[0]; c.Kind != models.WaveKindWindWave || c.Height != 3 {
+		t.Errorf("Components[0] = %+v, want 3 ft wind wave", c)
+	}
+	if c := conditions.Seas.Components[1]; c.Kind != models.WaveKindSwell || c.Direction != "S" || c.Period != 11 {
+		t.Errorf("Components[1] = %+v, want S swell at 11 seconds", c)
+	}
+}
+
+func TestParseMarineForecast_SeasPhrasings(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantMin float64
+		wantMax float64
+	}{
+		{name: "range", text: "SW winds 10 kt. Seas 3 to 5 ft.", wantMin: 3, wantMax: 5},
+		{name: "or less", text: "Variable winds less than 5 kt. Seas 2 ft or less.", wantMin: 0, wantMax: 2},
+		{name: "around", text: "S winds 10 kt. Seas around 3 ft.", wantMin: 3, wantMax: 3},
+		{name: "less than", text: "N winds 5 kt. Seas less than 2 ft.", wantMin: 0, wantMax: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditions := parseMarineForecast(tt.text, "ANZ254")
+			if conditions.Seas.HeightMin != tt.wantMin || conditions.Seas.HeightMax != tt.wantMax {
+				t.Errorf("Seas = %v-%v, want %v-%v", conditions.Seas.HeightMin, conditions.Seas.HeightMax, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestParseMarineForecast_GustPhrasings(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		wantGust    float64
+		wantSqualls bool
+	}{
+		{name: "no gusts", text: "SW winds 10 kt. Seas 2 ft.", wantGust: 0},
+		{name: "gusts", text: "SW winds 15 to 20 kt with gusts 25 kt. Seas 3 ft.", wantGust: 25},
+		{name: "gusts to", text: "SW winds 15 to 20 kt with gusts to 30 kt. Seas 3 ft.", wantGust: 30},
+		{name: "gusts up to", text: "SW winds 15 to 20 kt with gusts up to 30 kt. Seas 3 ft.", wantGust: 30},
+		{name: "gust range", text: "NW winds 20 to 25 kt with gusts 30 to 35 kt. Seas 5 ft.", wantGust: 35},
+		{name: "gusts in squalls", text: "S winds 10 to 15 kt, with gusts to 35 kt in squalls. Seas 3 ft.", wantGust: 35, wantSqualls: true},
+		{name: "squalls without gusts", text: "W winds 10 kt. Chance of showers with squalls. Seas 2 ft.", wantSqualls: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wind := parseMarineForecast(tt.text, "ANZ254").Wind
+			if wind.HasGust != (tt.wantGust > 0) || wind.GustSpeed != tt.wantGust {
+				t.Errorf("gust = %v (HasGust %v), want %v", wind.GustSpeed, wind.HasGust, tt.wantGust)
+			}
+			if wind.Squalls != tt.wantSqualls {
+				t.Errorf("Squalls = %v, want %v", wind.Squalls, tt.wantSqualls)
+			}
+		})
+	}
+}
+
+func TestParseMarineTextProduct_SeasPhrasings(t *testing.T) {
+	text := "ANZ254-151000-\n.TODAY...S winds 10 kt. Seas around 3 ft.\n.TONIGHT...W winds 5 kt. Seas 2 ft or less.\n"
+	conditions, forecast, err := parseMarineTextProduct(text, "ANZ254")
+	if err != nil {
+		t.Fatalf("parseMarineTextProduct() error = %v", err)
+	}
+	if conditions.Seas.HeightMin != 3 || conditions.Seas.HeightMax != 3 {
+		t.Errorf("conditions Seas = %v-%v, want 3-3", conditions.Seas.HeightMin, conditions.Seas.HeightMax)
+	}
+	if len(forecast.Periods) != 2 {
+		t.Fatalf("len(Periods) = %d, want 2", len(forecast.Periods))
+	}
+	if seas := forecast.Periods[1].Seas; seas.HeightMin != 0 || seas.HeightMax != 2 {
+		t.Errorf("TONIGHT Seas = %v-%v, want 0-2", seas.HeightMin, seas.HeightMax)
+	}
+}
+
+func TestGetMarineForecastByZone_ProductPaths(t *testing.T) {
+	product := "ZONE-261000-\n.TODAY...SW winds 10 kt. Seas 2 ft.\n"
+	tests := []struct {
+		name      string
+		zone      string
+		served    string // The only path with a product; "" serves nothing
+		status    int    // Status for every other path
+		wantPaths []string
+		wantErr   string
+	}{
+		{
+			name:      "conventional path",
+			zone:      "ANZ254",
+			served:    "/coastal/an/anz254.txt",
+			status:    http.StatusNotFound,
+			wantPaths: []string{"/coastal/an/anz254.txt"},
+		},
+		{
+			name:      "offshore zone",
+			zone:      "anz800",
+			served:    "/offshore/an/anz800.txt",
+			status:    http.StatusNotFound,
+			wantPaths: []string{"/offshore/an/anz800.txt"},
+		},
+		{
+			name:      "pacific coastal zone",
+			zone:      "PZZ535",
+			served:    "/coastal/pz/pzz535.txt",
+			status:    http.StatusNotFound,
+			wantPaths: []string{"/coastal/pz/pzz535.txt"},
+		},
+		{
+			name:      "upper case file name",
+			zone:      "LCZ460",
+			served:    "/coastal/lc/LCZ460.txt",
+			status:    http.StatusNotFound,
+			wantPaths: []string{"/coastal/lc/lcz460.txt", "/offshore/lc/lcz460.txt", "/coastal/lc/LCZ460.txt"},
+		},
+		{
+			name:      "not found anywhere",
+			zone:      "ANZ254",
+			status:    http.StatusNotFound,
+			wantPaths: []string{"/coastal/an/anz254.txt", "/offshore/an/anz254.txt", "/coastal/an/ANZ254.txt", "/offshore/an/ANZ254.txt"},
+			wantErr:   "not found for zone ANZ254",
+		},
+		{
+			name:      "server error isn't retried",
+			zone:      "ANZ254",
+			status:    http.StatusInternalServerError,
+			wantPaths: []string{"/coastal/an/anz254.txt"},
+			wantErr:   "status 500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.URL.Path)
+				if r.URL.Path == tt.served {
+					w.Write([]byte(product))
+					return
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := NewWeatherClient()
+			client.textProductURL = server.URL
+			conditions, _, err := client.GetMarineForecastByZone(context.Background(), tt.zone)
+
+			if !reflect.DeepEqual(paths, tt.wantPaths) {
+				t.Errorf("requested %v, want %v", paths, tt.wantPaths)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetMarineForecastByZone() error = %v", err)
+			}
+			if conditions.Wind.SpeedMax != 10 {
+				t.Errorf("Wind.SpeedMax = %v, want 10", conditions.Wind.SpeedMax)
+			}
+		})
+	}
+}
+
+func TestGetMarineForecastByZone_DebugLogsURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/coastal/lc/LCZ460.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("LCZ460-261000-\n.TODAY...W winds 5 kt.\n"))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	orig := log.Writer()
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(orig) })
+	debuglog.SetEnabled(true)
+	t.Cleanup(func() { debuglog.SetEnabled(false) })
+
+	client := NewWeatherClient()
+	client.textProductURL = server.URL
+	if _, _, err := client.GetMarineForecastByZone(context.Background(), "LCZ460"); err != nil {
+		t.Fatalf("GetMarineForecastByZone() error = %v", err)
+	}
+	if want := "Fetched marine text product for LCZ460 from " + server.URL + "/coastal/lc/LCZ460.txt"; !strings.Contains(buf.String(), want) {
+		t.Errorf("debug log should contain %q, got:\n%s", want, buf.String())
+	}
+
+	buf.Reset()
+	debuglog.SetEnabled(false)
+	if _, _, err := client.GetMarineForecastByZone(context.Background(), "LCZ460"); err != nil {
+		t.Fatalf("GetMarineForecastByZone() error = %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("nothing should be logged with debug logging off, got:\n%s", buf.String())
+	}
+}
+
+func TestZoneForecastPath(t *testing.T) {
+	tests := []struct {
+		zone   string
+		want   string
+		wantOK bool
+	}{
+		// Atlantic
+		{"ANZ254", "coastal/an/anz254.txt", true},
+		{"ANZ800", "offshore/an/anz800.txt", true},
+		{"ANZ935", "offshore/an/anz935.txt", true},
+		// Puerto Rico, the U.S. Virgin Islands and the Caribbean
+		{"AMZ710", "coastal/am/amz710.txt", true},
+		{"AMZ011", "offshore/am/amz011.txt", true},
+		// Gulf of America
+		{"GMZ250", "coastal/gm/gmz250.txt", true},
+		{"GMZ011", "offshore/gm/gmz011.txt", true},
+		// Pacific
+		{"PZZ535", "coastal/pz/pzz535.txt", true},
+		{"PZZ815", "offshore/pz/pzz815.txt", true},
+		{"PKZ120", "coastal/pk/pkz120.txt", true},
+		{"PHZ110", "coastal/ph/phz110.txt", true},
+		{"PHZ180", "offshore/ph/phz180.txt", true},
+		{"PMZ151", "coastal/pm/pmz151.txt", true},
+		{"PSZ150", "coastal/ps/psz150.txt", true},
+		// Great Lakes and the St. Lawrence
+		{"LSZ162", "coastal/ls/lsz162.txt", true},
+		{"LMZ080", "coastal/lm/lmz080.txt", true},
+		{"LHZ361", "coastal/lh/lhz361.txt", true},
+		{"LEZ061", "coastal/le/lez061.txt", true},
+		{"LOZ042", "coastal/lo/loz042.txt", true},
+		{"LCZ460", "coastal/lc/lcz460.txt", true},
+		{"SLZ022", "coastal/sl/slz022.txt", true},
+		// Codes are normalized
+		{" anz254 ", "coastal/an/anz254.txt", true},
+		// Not marine zones
+		{"XXZ123", "", false},
+		{"MAZ022", "", false},
+		{"ANZ25", "", false},
+		{"ANZ2544", "", false},
+		{"ANZABC", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.zone, func(t *testing.T) {
+			got, ok := ZoneForecastPath(tt.zone)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ZoneForecastPath(%q) = %q, %v; want %q, %v", tt.zone, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	for prefix := range zonelookup.ZoneFamilies {
+		if _, ok := ZoneForecastPath(prefix + "100"); !ok {
+			t.Errorf("ZoneForecastPath(%s100) not recognized", prefix)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package noaa
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -20,16 +21,24 @@ type NOAATideClient struct {
 
 // NewTideClient creates a new NOAA tide client
 func NewTideClient() *NOAATideClient {
+	return NewTideClientWithHTTPClient(&http.Client{Timeout: DefaultHTTPTimeout})
+}
+
+// NewTideClientWithHTTPClient creates a NOAA tide client that sends its requests with
+// httpClient, e.g. one with a different timeout or transport
+func NewTideClientWithHTTPClient(httpClient *http.Client) *NOAATideClient {
 	return &NOAATideClient{
-		baseURL: "https://api.tidesandcurrents.noaa.gov/api/prod/datagetter",
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		baseURL:    "https://api.tidesandcurrents.noaa.gov/api/prod/datagetter",
+		httpClient: httpClient,
 	}
 }
 
-// GetTidePredictions retrieves tide predictions for a date range
-func (c *NOAATideClient) GetTidePredictions(ctx context.Context, stationID string, startDate, endDate time.Time) (*models.TideData, error) {
+// GetTidePredictions retrieves tide predictions for a date range relative to the given datum
+func (c *NOAATideClient) GetTidePredictions(ctx context.Context, stationID, datum string, startDate, endDate time.Time) (*models.TideData, error) {
+	if datum == "" {
+		datum = models.DatumMLLW
+	}
+
 	// Format dates as YYYYMMDD
 	beginDate := startDate.Format("20060102")
 	endDateStr := endDate.Format("20060102")
@@ -40,7 +49,7 @@ func (c *NOAATideClient) GetTidePredictions(ctx context.Context, stationID strin
 	params.Add("end_date", endDateStr)
 	params.Add("station", stationID)
 	params.Add("product", "predictions")
-	params.Add("datum", "MLLW")      // Mean Lower Low Water
+	params.Add("datum", datum)        // e.g. MLLW (Mean Lower Low Water) or MSL
 	params.Add("time_zone", "lst_ldt") // Local standard/daylight time
 	params.Add("interval", "hilo")    // High and low tides only
 	params.Add("units", "english")    // Feet
@@ -73,6 +82,7 @@ func (c *NOAATideClient) GetTidePredictions(ctx context.Context, stationID strin
 	tideData := &models.TideData{
 		StationID:   stationID,
 		StationName: tideResp.Metadata.Name,
+		Datum:       datum,
 		Events:      make([]models.TideEvent, 0, len(tideResp.Predictions)),
 		UpdatedAt:   time.Now(),
 	}
@@ -109,7 +119,171 @@ func (c *NOAATideClient) GetTidePredictions(ctx context.Context, stationID strin
 	return tideData, nil
 }
 
-// GetMeteorologicalData retrieves meteorological data (e.g., air temperature, pressure) for a station
+// GetDatums retrieves the station's datum elevations (e.g. MLLW, MSL) in feet above station datum.
+// Datums without a published value are left out.
+func (c *NOAATideClient) GetDatums(ctx context.Context, stationID string) (*models.DatumOffsets, error) {
+	params := url.Values{}
+	params.Add("station", stationID)
+	params.Add("product", "datums")
+	params.Add("units", "english")
+	params.Add("format", "json")
+	params.Add("application", "MarineTerminal")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s?%s", c.baseURL, params.Encode()), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch datums: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	var datumResp struct {
+		Datums []struct {
+			Name  string `json:"n"`
+			Value string `json:"v"`
+		} `json:"datums"`
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&datumResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if datumResp.Error.Message != "" {
+		return nil, fmt.Errorf("no datums for station %s: %s", stationID, datumResp.Error.Message)
+	}
+
+	offsets := &models.DatumOffsets{StationID: stationID, Elevations: make(map[string]float64)}
+	for _, d := range datumResp.Datums {
+		value, err := strconv.ParseFloat(d.Value, 64)
+		if err != nil {
+			continue // Datum not established at this station
+		}
+		offsets.Elevations[d.Name] = value
+	}
+	return offsets, nil
+}
+
+// ErrNoWaterLevel is returned by GetWaterLevel for stations without a water level
+// sensor, such as subordinate stations that only have predictions
+var ErrNoWaterLevel = errors.New("no water level observations")
+
+// GetWaterLevel retrieves the station's latest observed water level (the 6-minute
+// water_level product) and the predicted level at the same time, relative to datum
+func (c *NOAATideClient) GetWaterLevel(ctx context.Context, stationID, datum string) (*models.WaterLevel, error) {
+	if datum == "" {
+		datum = models.DatumMLLW
+	}
+	params := func(product string) url.Values {
+		p := url.Values{}
+		p.Add("station", stationID)
+		p.Add("product", product)
+		p.Add("datum", datum)
+		p.Add("time_zone", "lst_ldt")
+		p.Add("units", "english")
+		p.Add("format", "json")
+		p.Add("application", "MarineTerminal")
+		return p
+	}
+
+	type level struct {
+		Time  string `json:"t"`
+		Value string `json:"v"`
+	}
+	var observed struct {
+		Data  []level `json:"data"`
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	obsParams := params("water_level")
+	obsParams.Add("date", "latest")
+	if err := c.getJSON(ctx, obsParams, &observed); err != nil {
+		return nil, fmt.Errorf("failed to fetch water level: %w", err)
+	}
+	if observed.Error.Message != "" || len(observed.Data) == 0 {
+		return nil, fmt.Errorf("%w at station %s: %s", ErrNoWaterLevel, stationID, observed.Error.Message)
+	}
+
+	latest := observed.Data[len(observed.Data)-1]
+	obsTime, err := time.Parse("2006-01-02 15:04", latest.Time)
+	if err != nil {
+		return nil, fmt.Errorf("invalid water level time %q: %w", latest.Time, err)
+	}
+	obsValue, err := strconv.ParseFloat(latest.Value, 64)
+	if err != nil {
+		// An observation without a value means the sensor isn't reporting
+		return nil, fmt.Errorf("%w at station %s: missing value at %s", ErrNoWaterLevel, stationID, latest.Time)
+	}
+
+	// Predictions at the same 6-minute interval give the level to compare against
+	var predicted struct {
+		Predictions []level `json:"predictions"`
+		Error       struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	predParams := params("predictions")
+	predParams.Add("interval", "6")
+	predParams.Add("begin_date", obsTime.Format("20060102 15:04"))
+	predParams.Add("end_date", obsTime.Format("20060102 15:04"))
+	if err := c.getJSON(ctx, predParams, &predicted); err != nil {
+		return nil, fmt.Errorf("failed to fetch predicted water level: %w", err)
+	}
+	for _, p := range predicted.Predictions {
+		if p.Time != latest.Time {
+			continue
+		}
+		predValue, err := strconv.ParseFloat(p.Value, 64)
+		if err != nil {
+			break
+		}
+		return &models.WaterLevel{
+			StationID: stationID,
+			Datum:     datum,
+			Time:      obsTime,
+			Observed:  obsValue,
+			Predicted: predValue,
+		}, nil
+	}
+	return nil, fmt.Errorf("no predicted water level for station %s at %s %s", stationID, latest.Time, predicted.Error.Message)
+}
+
+// getJSON requests the CO-OPS data API with params and decodes the response into out
+func (c *NOAATideClient) getJSON(ctx context.Context, params url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s?%s", c.baseURL, params.Encode()), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// maxObservationHistory is how many recent observations are kept per series,
+// 24 hours of the 6-minute data CO-OPS stations report
+const maxObservationHistory = 240
+
+// GetMeteorologicalData retrieves meteorological data (e.g., air and water temperature, pressure,
+// wind) for a station. Temperature and wind series are kept as history for trend display.
 func (c *NOAATideClient) GetMeteorologicalData(ctx context.Context, stationID string, startDate, endDate time.Time) (*models.MarineConditions, error) {
 	// Format dates as YYYYMMDD
 	beginDate := startDate.Format("20060102")
@@ -163,13 +337,67 @@ func (c *NOAATideClient) GetMeteorologicalData(ctx context.Context, stationID st
 	type apiResponse struct {
 		Data []observation `json:"data"`
 	}
+	type windObservation struct {
+		Time      string `json:"t"`
+		Speed     string `json:"s"`  // knots with english units
+		Direction string `json:"dr"` // Compass point, e.g. "SW"
+		Gust      string `json:"g"`
+	}
+	type windResponse struct {
+		Data []windObservation `json:"data"`
+	}
 
 	// Channels for concurrent requests
 	airTempChan := make(chan result)
+	waterTempChan := make(chan result)
 	pressureChan := make(chan result)
+	windChan := make(chan result)
 
 	go func() { airTempChan <- fetchProduct("air_temperature", &apiResponse{}) }()
+	go func() { waterTempChan <- fetchProduct("water_temperature", &apiResponse{}) }()
 	go func() { pressureChan <- fetchProduct("air_pressure", &apiResponse{}) }()
+	go func() { windChan <- fetchProduct("wind", &windResponse{}) }()
+
+	// toHistory converts observations to a series, skipping missing or invalid values
+	toHistory := func(data []observation) []models.Observation {
+		var history []models.Observation
+		for _, obs := range data {
+			t, err := time.Parse("2006-01-02 15:04", obs.Time)
+			if err != nil {
+				continue
+			}
+			val, err := strconv.ParseFloat(obs.Value, 64)
+			if err != nil {
+				continue
+			}
+			history = append(history, models.Observation{Time: t, Value: val})
+		}
+		if len(history) > maxObservationHistory {
+			history = history[len(history)-maxObservationHistory:]
+		}
+		return history
+	}
+
+	// toWindHistory converts wind observations to a series, skipping missing speeds
+	toWindHistory := func(data []windObservation) []models.WindObservation {
+		var history []models.WindObservation
+		for _, obs := range data {
+			t, err := time.Parse("2006-01-02 15:04", obs.Time)
+			if err != nil {
+				continue
+			}
+			speed, err := strconv.ParseFloat(obs.Speed, 64)
+			if err != nil {
+				continue
+			}
+			gust, _ := strconv.ParseFloat(obs.Gust, 64)
+			history = append(history, models.WindObservation{Time: t, Direction: obs.Direction, Speed: speed, Gust: gust})
+		}
+		if len(history) > maxObservationHistory {
+			history = history[len(history)-maxObservationHistory:]
+		}
+		return history
+	}
 
 	// Collect results
 	conditions := &models.MarineConditions{
@@ -186,6 +414,18 @@ func (c *NOAATideClient) GetMeteorologicalData(ctx context.Context, stationID st
 			if val, err := strconv.ParseFloat(lastObs.Value, 64); err == nil {
 				conditions.Temperature = val
 			}
+			conditions.AirTempHistory = toHistory(resp.Data)
+		}
+	}
+
+	// Wait for Water Temp (not every station has a water sensor)
+	res = <-waterTempChan
+	if res.err == nil {
+		if resp, ok := res.data.(*apiResponse); ok {
+			conditions.WaterTempHistory = toHistory(resp.Data)
+			if n := len(conditions.WaterTempHistory); n > 0 {
+				conditions.WaterTemperature = conditions.WaterTempHistory[n-1].Value
+			}
 		}
 	}
 
@@ -196,11 +436,29 @@ func (c *NOAATideClient) GetMeteorologicalData(ctx context.Context, stationID st
 			// Get most recent observation
 			lastObs := resp.Data[len(resp.Data)-1]
 			if val, err := strconv.ParseFloat(lastObs.Value, 64); err == nil {
-				conditions.Pressure = val // NOAA returns in millibars by default with "metric", check "english" units
-				// "english" units for pressure might be different? 
-				// CO-OPS API docs: "english": pressure in mb? No, usually mb. 
-				// Let's assume mb for now as standard marine unit.
+				// CO-OPS reports air_pressure in millibars with both "english" and "metric" units
+				conditions.Pressure = val
+			}
+		}
+	}
+
+	// Wait for Wind (not every station has an anemometer)
+	res = <-windChan
+	if res.err == nil {
+		if resp, ok := res.data.(*windResponse); ok && len(resp.Data) > 0 {
+			lastObs := resp.Data[len(resp.Data)-1]
+			if speed, err := strconv.ParseFloat(lastObs.Speed, 64); err == nil && lastObs.Direction != "" {
+				conditions.Wind = models.WindData{
+					Direction: lastObs.Direction,
+					SpeedMin:  speed,
+					SpeedMax:  speed,
+				}
+				if gust, err := strconv.ParseFloat(lastObs.Gust, 64); err == nil && gust > speed {
+					conditions.Wind.GustSpeed = gust
+					conditions.Wind.HasGust = true
+				}
 			}
+			conditions.WindHistory = toWindHistory(resp.Data)
 		}
 	}
 
@@ -2,6 +2,7 @@ package noaa
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -57,7 +58,7 @@ func TestNOAATideClient_GetTidePredictions(t *testing.T) {
 	startDate := time.Date(2025, 11, 27, 0, 0, 0, 0, time.UTC)
 	endDate := time.Date(2025, 11, 29, 0, 0, 0, 0, time.UTC)
 
-	tideData, err := client.GetTidePredictions(ctx, "9447130", startDate, endDate)
+	tideData, err := client.GetTidePredictions(ctx, "9447130", "", startDate, endDate)
 
 	if err != nil {
 		t.Fatalf("GetTidePredictions() error = %v", err)
@@ -116,9 +117,170 @@ func TestNOAATideClient_ErrorHandling(t *testing.T) {
 	startDate := time.Now()
 	endDate := startDate.Add(3 * 24 * time.Hour)
 
-	_, err := client.GetTidePredictions(ctx, "invalid", startDate, endDate)
+	_, err := client.GetTidePredictions(ctx, "invalid", "", startDate, endDate)
 
 	if err == nil {
 		t.Error("Expected error for invalid station, got nil")
 	}
 }
+
+func TestNOAATideClient_GetTidePredictions_Datum(t *testing.T) {
+	var gotDatum string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotDatum = r.URL.Query().Get("datum")
+		w.Header().Set("Content-Type", "application/json")
+		data, _ := os.ReadFile("../../testdata/noaa_tide_response.json")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	client := NewTideClient()
+	client.baseURL = server.URL
+
+	startDate := time.Date(2025, 11, 27, 0, 0, 0, 0, time.UTC)
+	tideData, err := client.GetTidePredictions(context.Background(), "9447130", models.DatumMSL, startDate, startDate.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("GetTidePredictions() error = %v", err)
+	}
+
+	if gotDatum != "MSL" {
+		t.Errorf("datum param = %s, want MSL", gotDatum)
+	}
+	if tideData.Datum != models.DatumMSL {
+		t.Errorf("TideData.Datum = %s, want MSL", tideData.Datum)
+	}
+}
+
+func TestNOAATideClient_GetMeteorologicalData_History(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("product") {
+		case "air_temperature":
+			// The 12:06 reading is missing, as CO-OPS reports sensor gaps
+			w.Write([]byte(`{"data":[{"t":"2025-11-27 12:00","v":"48.2"},{"t":"2025-11-27 12:06","v":""},{"t":"2025-11-27 12:12","v":"49.0"}]}`))
+		case "air_pressure":
+			w.Write([]byte(`{"data":[{"t":"2025-11-27 12:12","v":"1016.4"}]}`))
+		case "wind":
+			w.Write([]byte(`{"data":[{"t":"2025-11-27 12:06","s":"9.50","d":"220.00","dr":"SW","g":"12.10","f":"0,0"},{"t":"2025-11-27 12:12","s":"11.66","d":"225.00","dr":"SW","g":"15.55","f":"0,0"}]}`))
+		default:
+			// No water temperature sensor at this station
+			w.Write([]byte(`{"error":{"message":"No data was found."}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewTideClient()
+	client.baseURL = server.URL
+
+	now := time.Now()
+	conditions, err := client.GetMeteorologicalData(context.Background(), "8447435", now.AddDate(0, 0, -1), now)
+	if err != nil {
+		t.Fatalf("GetMeteorologicalData() error = %v", err)
+	}
+
+	if conditions.Temperature != 49.0 || conditions.Pressure != 1016.4 {
+		t.Errorf("latest readings = %v°F, %v mb; want 49°F, 1016.4 mb", conditions.Temperature, conditions.Pressure)
+	}
+	if len(conditions.AirTempHistory) != 2 {
+		t.Fatalf("AirTempHistory has %d observations, want 2 (gap skipped)", len(conditions.AirTempHistory))
+	}
+	if conditions.AirTempHistory[0].Value != 48.2 || conditions.AirTempHistory[1].Time.Minute() != 12 {
+		t.Errorf("AirTempHistory = %+v", conditions.AirTempHistory)
+	}
+	if w := conditions.Wind; w.Direction != "SW" || w.SpeedMax != 11.66 || !w.HasGust || w.GustSpeed != 15.55 {
+		t.Errorf("Wind = %+v, want latest reading SW 11.66 kt gusting 15.55", w)
+	}
+	if h := conditions.WindHistory; len(h) != 2 || h[0].Speed != 9.5 || h[0].Direction != "SW" || h[0].Gust != 12.1 {
+		t.Errorf("WindHistory = %+v, want both wind readings", h)
+	}
+	if len(conditions.WaterTempHistory) != 0 || conditions.WaterTemperature != 0 {
+		t.Errorf("expected no water temperature, got %v with %d observations", conditions.WaterTemperature, len(conditions.WaterTempHistory))
+	}
+}
+
+func TestNOAATideClient_GetDatums(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("product") != "datums" || r.URL.Query().Get("units") != "english" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("station") == "9999999" {
+			w.Write([]byte(`{"error":{"message":"No datums found for this station."}}`))
+			return
+		}
+		// NAVD88 is not established at this station
+		w.Write([]byte(`{"datums":[{"n":"MHHW","v":"9.950"},{"n":"MSL","v":"4.820"},{"n":"MLLW","v":"0.000"},{"n":"NAVD88","v":""}]}`))
+	}))
+	defer server.Close()
+
+	client := NewTideClient()
+	client.baseURL = server.URL
+
+	offsets, err := client.GetDatums(context.Background(), "8443970")
+	if err != nil {
+		t.Fatalf("GetDatums() error = %v", err)
+	}
+	if offsets.StationID != "8443970" || offsets.Elevations["MSL"] != 4.82 || offsets.Elevations["MHHW"] != 9.95 {
+		t.Errorf("offsets = %+v", offsets)
+	}
+	if offsets.Has(models.DatumNAVD88) {
+		t.Error("datum without a value should be left out")
+	}
+
+	if _, err := client.GetDatums(context.Background(), "9999999"); err == nil {
+		t.Error("expected error for a station without datums")
+	}
+}
+
+// Recorded responses from the CO-OPS API for Boston (8443970)
+const (
+	waterLevelFixture = `{"metadata":{"id":"8443970","name":"Boston","lat":"42.3539","lon":"-71.0503"},` +
+		`"data":[{"t":"2025-11-27 12:06","v":"4.123","s":"0.010","f":"0,0,0,0","q":"p"}]}`
+	waterLevelPredictionFixture = `{"predictions":[{"t":"2025-11-27 12:06","v":"3.512"}]}`
+	noWaterLevelFixture         = `{"error":{"message":"No data was found. This product may not be offered at this station at the requested time."}}`
+)
+
+func TestNOAATideClient_GetWaterLevel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("datum") != "MLLW" || q.Get("time_zone") != "lst_ldt" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case q.Get("station") == "8447435": // Prediction-only subordinate station
+			w.Write([]byte(noWaterLevelFixture))
+		case q.Get("product") == "water_level" && q.Get("date") == "latest":
+			w.Write([]byte(waterLevelFixture))
+		case q.Get("product") == "predictions" && q.Get("interval") == "6" &&
+			q.Get("begin_date") == "20251127 12:06" && q.Get("end_date") == "20251127 12:06":
+			w.Write([]byte(waterLevelPredictionFixture))
+		default:
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTideClient()
+	client.baseURL = server.URL
+
+	level, err := client.GetWaterLevel(context.Background(), "8443970", "")
+	if err != nil {
+		t.Fatalf("GetWaterLevel() error = %v", err)
+	}
+	if level.Observed != 4.123 || level.Predicted != 3.512 || level.Datum != models.DatumMLLW {
+		t.Errorf("level = %+v", level)
+	}
+	if want := time.Date(2025, 11, 27, 12, 6, 0, 0, time.UTC); !level.Time.Equal(want) {
+		t.Errorf("level.Time = %v, want %v", level.Time, want)
+	}
+	if got := level.Residual(); got < 0.610 || got > 0.612 {
+		t.Errorf("Residual() = %v, want 0.611", got)
+	}
+
+	_, err = client.GetWaterLevel(context.Background(), "8447435", models.DatumMLLW)
+	if !errors.Is(err, ErrNoWaterLevel) {
+		t.Errorf("GetWaterLevel() for a station without a sensor error = %v, want ErrNoWaterLevel", err)
+	}
+}
@@ -5,7 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ngmaloney/marine-terminal/internal/models"
@@ -13,19 +17,25 @@ import (
 
 // NOAAWeatherClient implements WeatherClient using the NOAA Weather API
 type NOAAWeatherClient struct {
-	baseURL    string
-	httpClient *http.Client
-	userAgent  string
+	baseURL        string
+	textProductURL string // Root of the marine text product directories on tgftp
+	httpClient     *http.Client
+	userAgent      string
 }
 
 // NewWeatherClient creates a new NOAA weather client
 func NewWeatherClient() *NOAAWeatherClient {
+	return NewWeatherClientWithHTTPClient(&http.Client{Timeout: DefaultHTTPTimeout})
+}
+
+// NewWeatherClientWithHTTPClient creates a NOAA weather client that sends its requests
+// with httpClient, e.g. one with a different timeout or transport
+func NewWeatherClientWithHTTPClient(httpClient *http.Client) *NOAAWeatherClient {
 	return &NOAAWeatherClient{
-		baseURL: "https://api.weather.gov",
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		userAgent: "MarineTerminal/1.0 (github.com/ngmaloney/marine-terminal)",
+		baseURL:        "https://api.weather.gov",
+		textProductURL: "https://tgftp.nws.noaa.gov/data/forecasts/marine",
+		httpClient:     httpClient,
+		userAgent:      "MarineTerminal/1.0 (github.com/ngmaloney/marine-terminal)",
 	}
 }
 
@@ -77,7 +87,7 @@ func (c *NOAAWeatherClient) GetMarineConditions(ctx context.Context, lat, lon fl
 		period := forecastResp.Properties.Periods[0]
 		conditions.Conditions = period.ShortForecast
 		conditions.Temperature = float64(period.Temperature)
-		// Note: Full wind/wave parsing would happen here
+		conditions.Wind = parseGridpointWind(period.WindSpeed, period.WindDirection, period.DetailedForecast)
 	}
 
 	return conditions, nil
@@ -138,6 +148,7 @@ func (c *NOAAWeatherClient) GetMarineForecast(ctx context.Context, lat, lon floa
 			DayOfWeek:   startTime.Weekday().String(),
 			PeriodName:  period.Name,
 			Conditions:  period.ShortForecast,
+			Wind:        parseGridpointWind(period.WindSpeed, period.WindDirection, period.DetailedForecast),
 			Temperature: float64(period.Temperature),
 			RawText:     period.DetailedForecast,
 		}
@@ -148,6 +159,47 @@ func (c *NOAAWeatherClient) GetMarineForecast(ctx context.Context, lat, lon floa
 	return forecast, nil
 }
 
+// knotsPerMph converts statute miles per hour to knots
+const knotsPerMph = 0.868976
+
+var (
+	gridpointSpeedRegex = regexp.MustCompile(`(?i)(\d+)(?:\s+to\s+(\d+))?\s*(mph|kt|knots)`)
+	gridpointGustRegex  = regexp.MustCompile(`(?i)gusts?\s+(?:up\s+to|as\s+high\s+as)\s+(\d+)\s*(mph|kt|knots)`)
+)
+
+// parseGridpointWind converts the gridpoint forecast's windSpeed ("15 to 20 mph") and
+// windDirection ("W") into WindData in knots. Gusts come from the detailed forecast text.
+func parseGridpointWind(speed, direction, detailed string) models.WindData {
+	wind := models.WindData{
+		Direction: strings.TrimSpace(direction),
+		RawText:   strings.TrimSpace(strings.TrimSpace(direction) + " " + speed),
+	}
+
+	if match := gridpointSpeedRegex.FindStringSubmatch(speed); match != nil {
+		wind.SpeedMin = toKnots(match[1], match[3])
+		wind.SpeedMax = wind.SpeedMin
+		if match[2] != "" {
+			wind.SpeedMax = toKnots(match[2], match[3])
+		}
+	}
+
+	if match := gridpointGustRegex.FindStringSubmatch(detailed); match != nil {
+		wind.GustSpeed = toKnots(match[1], match[2])
+		wind.HasGust = true
+	}
+
+	return wind
+}
+
+// toKnots parses a speed in the given unit and returns it rounded to whole knots
+func toKnots(value, unit string) float64 {
+	v, _ := strconv.ParseFloat(value, 64)
+	if strings.EqualFold(unit, "mph") {
+		v *= knotsPerMph
+	}
+	return math.Round(v)
+}
+
 // getGridPoint gets the NOAA grid point for a lat/lon
 func (c *NOAAWeatherClient) getGridPoint(ctx context.Context, lat, lon float64) (*gridPoint, error) {
 	url := fmt.Sprintf("%s/points/%.4f,%.4f", c.baseURL, lat, lon)
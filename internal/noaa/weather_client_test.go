@@ -3,6 +3,7 @@ package noaa
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -30,6 +31,51 @@ func TestNewWeatherClient(t *testing.T) {
 	}
 }
 
+// roundTripFunc lets a function stand in for an http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestNewClientsWithHTTPClient(t *testing.T) {
+	requests := 0
+	offline := errors.New("offline")
+	httpClient := &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			requests++
+			return nil, offline
+		}),
+	}
+
+	weather := NewWeatherClientWithHTTPClient(httpClient)
+	alerts := NewAlertClientWithHTTPClient(httpClient)
+	tides := NewTideClientWithHTTPClient(httpClient)
+	for name, c := range map[string]*http.Client{"weather": weather.httpClient, "alert": alerts.httpClient, "tide": tides.httpClient} {
+		if c.Timeout != 5*time.Second {
+			t.Errorf("%s client timeout = %v, want the injected client's 5s", name, c.Timeout)
+		}
+	}
+
+	// Every request goes through the injected transport instead of the network
+	ctx := context.Background()
+	calls := map[string]func() error{
+		"weather": func() error { _, _, err := weather.GetMarineForecastByZone(ctx, "ANZ254"); return err },
+		"alert":   func() error { _, err := alerts.GetActiveAlertsByZone(ctx, "ANZ254"); return err },
+		"tide":    func() error { _, err := tides.GetDatums(ctx, "8447435"); return err },
+	}
+	for name, call := range calls {
+		before := requests
+		if err := call(); !errors.Is(err, offline) {
+			t.Errorf("%s error = %v, want the transport's error", name, err)
+		}
+		if requests == before {
+			t.Errorf("%s client didn't send its request through the injected transport", name)
+		}
+	}
+}
+
 func TestNOAAWeatherClient_GetGridPoint(t *testing.T) {
 	// Create a test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -124,6 +170,15 @@ func TestNOAAWeatherClient_GetMarineConditions(t *testing.T) {
 	if conditions.Temperature != 58 {
 		t.Errorf("Temperature = %v, want 58", conditions.Temperature)
 	}
+
+	// "W", "15 to 20 mph" with gusts up to 30 mph
+	wind := conditions.Wind
+	if wind.Direction != "W" || wind.SpeedMin != 13 || wind.SpeedMax != 17 {
+		t.Errorf("Wind = %s %v-%v kt, want W 13-17 kt", wind.Direction, wind.SpeedMin, wind.SpeedMax)
+	}
+	if !wind.HasGust || wind.GustSpeed != 26 {
+		t.Errorf("Gusts = %v (%v kt), want 26 kt", wind.HasGust, wind.GustSpeed)
+	}
 }
 
 func TestNOAAWeatherClient_GetMarineForecast(t *testing.T) {
@@ -172,6 +227,46 @@ func TestNOAAWeatherClient_GetMarineForecast(t *testing.T) {
 			t.Errorf("Conditions = %s, want 'Partly Cloudy'", period.Conditions)
 		}
 	}
+
+	if len(forecast.Periods) > 1 {
+		wind := forecast.Periods[1].Wind
+		if wind.Direction != "W" || wind.SpeedMin != 9 || wind.SpeedMax != 13 || wind.HasGust {
+			t.Errorf("Tonight wind = %+v, want W 9-13 kt without gusts", wind)
+		}
+	}
+}
+
+func TestParseGridpointWind(t *testing.T) {
+	tests := []struct {
+		name      string
+		speed     string
+		direction string
+		detailed  string
+		wantMin   float64
+		wantMax   float64
+		wantGust  float64
+	}{
+		{"single speed", "10 mph", "SW", "", 9, 9, 0},
+		{"range", "15 to 20 mph", "W", "", 13, 17, 0},
+		{"gusts as high as", "20 to 25 mph", "NE", "NE wind 20 to 25 mph, with gusts as high as 40 mph.", 17, 22, 35},
+		{"knots passthrough", "10 to 15 kt", "S", "", 10, 15, 0},
+		{"unparseable", "calm", "", "", 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wind := parseGridpointWind(tt.speed, tt.direction, tt.detailed)
+			if wind.Direction != tt.direction {
+				t.Errorf("Direction = %q, want %q", wind.Direction, tt.direction)
+			}
+			if wind.SpeedMin != tt.wantMin || wind.SpeedMax != tt.wantMax {
+				t.Errorf("Speed = %v-%v, want %v-%v", wind.SpeedMin, wind.SpeedMax, tt.wantMin, tt.wantMax)
+			}
+			if wind.GustSpeed != tt.wantGust || wind.HasGust != (tt.wantGust > 0) {
+				t.Errorf("Gust = %v (%v), want %v", wind.GustSpeed, wind.HasGust, tt.wantGust)
+			}
+		})
+	}
 }
 
 func TestNOAAWeatherClient_ErrorHandling(t *testing.T) {
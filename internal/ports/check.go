@@ -0,0 +1,150 @@
+package ports
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ngmaloney/marine-terminal/internal/database"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/noaa"
+	"github.com/ngmaloney/marine-terminal/internal/stations"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
+)
+
+// Defaults for Checker; each check step gets its own timeout so one slow
+// API doesn't stall the report
+const (
+	checkConcurrency = 3
+	checkTimeout     = 10 * time.Second
+)
+
+// CheckStep is the outcome of one check on a saved port
+type CheckStep struct {
+	Name string
+	Err  error // nil if the check passed
+}
+
+// PortCheck is the outcome of checking a saved port
+type PortCheck struct {
+	Port  models.Port
+	Steps []CheckStep
+}
+
+// OK reports whether every check on the port passed
+func (c PortCheck) OK() bool {
+	for _, step := range c.Steps {
+		if step.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Checker verifies that saved ports still resolve to a marine zone and tide
+// station, and that their forecast and tides can be fetched
+type Checker struct {
+	weather     noaa.WeatherClient
+	tides       noaa.TideClient
+	concurrency int
+	timeout     time.Duration
+
+	// Lookups against the local database, replaced in tests
+	lookupZone    func(code string) error
+	lookupStation func(id string) error
+}
+
+// NewChecker creates a port checker using the given NOAA clients
+func NewChecker(weather noaa.WeatherClient, tides noaa.TideClient) *Checker {
+	return &Checker{
+		weather:     weather,
+		tides:       tides,
+		concurrency: checkConcurrency,
+		timeout:     checkTimeout,
+		lookupZone: func(code string) error {
+			_, err := zonelookup.GetZoneInfoByCode(database.DBPath(), code)
+			return err
+		},
+		lookupStation: func(id string) error {
+			_, err := stations.GetStationByID(database.DBPath(), id)
+			return err
+		},
+	}
+}
+
+// Check checks each port, a few at a time, and returns the results in the order given
+func (c *Checker) Check(ctx context.Context, ports []models.Port) []PortCheck {
+	results := make([]PortCheck, len(ports))
+	sem := make(chan struct{}, c.concurrency)
+	var wg sync.WaitGroup
+	for i, p := range ports {
+		wg.Add(1)
+		go func(i int, p models.Port) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = c.checkPort(ctx, p)
+		}(i, p)
+	}
+	wg.Wait()
+	return results
+}
+
+// checkPort runs every check on one port. Fetches are skipped when the zone or
+// station they depend on is missing.
+func (c *Checker) checkPort(ctx context.Context, p models.Port) PortCheck {
+	result := PortCheck{Port: p}
+	step := func(name string, check func(ctx context.Context) error) error {
+		stepCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+		err := check(stepCtx)
+		result.Steps = append(result.Steps, CheckStep{Name: name, Err: err})
+		return err
+	}
+
+	if err := step("marine zone", func(context.Context) error { return c.lookupZone(p.MarineZoneID) }); err == nil {
+		step("forecast", func(ctx context.Context) error {
+			_, _, err := c.weather.GetMarineForecastByZone(ctx, p.MarineZoneID)
+			return err
+		})
+	}
+
+	stationErr := step("tide station", func(context.Context) error {
+		if p.TideStationID == "" {
+			return fmt.Errorf("no tide station saved")
+		}
+		return c.lookupStation(p.TideStationID)
+	})
+	if stationErr == nil {
+		step("tides", func(ctx context.Context) error {
+			now := time.Now()
+			_, err := c.tides.GetTidePredictions(ctx, p.TideStationID, p.TideDatum(), now, now.AddDate(0, 0, 1))
+			return err
+		})
+	}
+
+	return result
+}
+
+// WriteCheckReport prints a pass/fail line per port, with the reason for each
+// failed check, and returns the number of ports that failed
+func WriteCheckReport(w io.Writer, results []PortCheck) int {
+	failed := 0
+	for _, r := range results {
+		if r.OK() {
+			fmt.Fprintf(w, "PASS  %s (zone %s, station %s)\n", r.Port.Name, r.Port.MarineZoneID, r.Port.TideStationID)
+			continue
+		}
+		failed++
+		fmt.Fprintf(w, "FAIL  %s (zone %s, station %s)\n", r.Port.Name, r.Port.MarineZoneID, r.Port.TideStationID)
+		for _, step := range r.Steps {
+			if step.Err != nil {
+				fmt.Fprintf(w, "      %s: %v\n", step.Name, step.Err)
+			}
+		}
+	}
+	fmt.Fprintf(w, "\n%d of %d saved ports passed\n", len(results)-failed, len(results))
+	return failed
+}
@@ -0,0 +1,165 @@
+package ports
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+// checkWeatherClient fails forecasts for the zones in failing and tracks how many
+// fetches run at once
+type checkWeatherClient struct {
+	failing map[string]bool
+
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (c *checkWeatherClient) GetMarineConditions(ctx context.Context, lat, lon float64) (*models.MarineConditions, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (c *checkWeatherClient) GetMarineForecast(ctx context.Context, lat, lon float64) (*models.ThreeDayForecast, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (c *checkWeatherClient) GetMarineForecastByZone(ctx context.Context, zone string) (*models.MarineConditions, *models.ThreeDayForecast, error) {
+	c.mu.Lock()
+	c.inFlight++
+	if c.inFlight > c.peak {
+		c.peak = c.inFlight
+	}
+	c.mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+
+	if c.failing[zone] {
+		return nil, nil, fmt.Errorf("forecast unavailable")
+	}
+	return &models.MarineConditions{}, &models.ThreeDayForecast{}, nil
+}
+
+// checkTideClient fails predictions for the stations in failing
+type checkTideClient struct {
+	failing map[string]bool
+}
+
+func (c *checkTideClient) GetTidePredictions(ctx context.Context, stationID, datum string, start, end time.Time) (*models.TideData, error) {
+	if c.failing[stationID] {
+		return nil, fmt.Errorf("station offline")
+	}
+	return &models.TideData{StationID: stationID}, nil
+}
+
+func (c *checkTideClient) GetMeteorologicalData(ctx context.Context, stationID string, start, end time.Time) (*models.MarineConditions, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (c *checkTideClient) GetDatums(ctx context.Context, stationID string) (*models.DatumOffsets, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (c *checkTideClient) GetWaterLevel(ctx context.Context, stationID, datum string) (*models.WaterLevel, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func newTestChecker(weather *checkWeatherClient, tides *checkTideClient) *Checker {
+	c := NewChecker(weather, tides)
+	c.lookupZone = func(code string) error {
+		if code == "ANZ999" {
+			return fmt.Errorf("zone code %s not found", code)
+		}
+		return nil
+	}
+	c.lookupStation = func(id string) error {
+		if id == "0000000" {
+			return fmt.Errorf("tide station %s not found", id)
+		}
+		return nil
+	}
+	return c
+}
+
+func TestChecker_Check(t *testing.T) {
+	weather := &checkWeatherClient{failing: map[string]bool{"ANZ255": true}}
+	tides := &checkTideClient{failing: map[string]bool{"8449130": true}}
+	c := newTestChecker(weather, tides)
+
+	ports := []models.Port{
+		{Name: "Chatham", MarineZoneID: "ANZ254", TideStationID: "8447435"},
+		{Name: "Renamed Zone", MarineZoneID: "ANZ999", TideStationID: "8447435"},
+		{Name: "Retired Station", MarineZoneID: "ANZ254", TideStationID: "0000000"},
+		{Name: "No Station", MarineZoneID: "ANZ254"},
+		{Name: "Forecast Down", MarineZoneID: "ANZ255", TideStationID: "8447435"},
+		{Name: "Tides Down", MarineZoneID: "ANZ254", TideStationID: "8449130"},
+	}
+	results := c.Check(context.Background(), ports)
+
+	tests := []struct {
+		name       string
+		wantOK     bool
+		wantFailed []string
+		wantSteps  int
+	}{
+		{"Chatham", true, nil, 4},
+		{"Renamed Zone", false, []string{"marine zone"}, 3},
+		{"Retired Station", false, []string{"tide station"}, 3},
+		{"No Station", false, []string{"tide station"}, 3},
+		{"Forecast Down", false, []string{"forecast"}, 4},
+		{"Tides Down", false, []string{"tides"}, 4},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := results[i]
+			if r.Port.Name != tt.name {
+				t.Fatalf("result %d is for %s, want %s", i, r.Port.Name, tt.name)
+			}
+			if r.OK() != tt.wantOK {
+				t.Errorf("OK() = %v, want %v", r.OK(), tt.wantOK)
+			}
+			var failed []string
+			for _, step := range r.Steps {
+				if step.Err != nil {
+					failed = append(failed, step.Name)
+				}
+			}
+			if fmt.Sprint(failed) != fmt.Sprint(tt.wantFailed) {
+				t.Errorf("failed steps = %v, want %v", failed, tt.wantFailed)
+			}
+			if len(r.Steps) != tt.wantSteps {
+				t.Errorf("ran %d steps, want %d", len(r.Steps), tt.wantSteps)
+			}
+		})
+	}
+
+	if weather.peak > checkConcurrency {
+		t.Errorf("%d forecasts fetched at once, want at most %d", weather.peak, checkConcurrency)
+	}
+}
+
+func TestWriteCheckReport(t *testing.T) {
+	results := []PortCheck{
+		{Port: models.Port{Name: "Chatham", MarineZoneID: "ANZ254", TideStationID: "8447435"}, Steps: []CheckStep{{Name: "marine zone"}}},
+		{Port: models.Port{Name: "Old Port", MarineZoneID: "ANZ999", TideStationID: "8447435"}, Steps: []CheckStep{{Name: "marine zone", Err: fmt.Errorf("zone code ANZ999 not found")}}},
+	}
+
+	var buf bytes.Buffer
+	if failed := WriteCheckReport(&buf, results); failed != 1 {
+		t.Errorf("WriteCheckReport() = %d failed, want 1", failed)
+	}
+	out := buf.String()
+	for _, want := range []string{"PASS  Chatham", "FAIL  Old Port", "marine zone: zone code ANZ999 not found", "1 of 2 saved ports passed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+}
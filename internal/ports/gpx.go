@@ -0,0 +1,118 @@
+package ports
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+// Waypoint is a named position read from a GPX file
+type Waypoint struct {
+	Name      string
+	Latitude  float64
+	Longitude float64
+}
+
+// gpxFile is the part of a GPX document holding its waypoints
+type gpxFile struct {
+	Waypoints []struct {
+		Lat  float64 `xml:"lat,attr"`
+		Lon  float64 `xml:"lon,attr"`
+		Name string  `xml:"name"`
+	} `xml:"wpt"`
+}
+
+// ParseGPX reads the waypoints (<wpt>) of a GPX file. Routes and tracks are ignored.
+// Waypoints without a name are named by their position in the file, e.g. "Waypoint 3".
+func ParseGPX(r io.Reader) ([]Waypoint, error) {
+	var doc gpxFile
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parsing GPX: %w", err)
+	}
+
+	waypoints := make([]Waypoint, 0, len(doc.Waypoints))
+	for i, wpt := range doc.Waypoints {
+		name := strings.TrimSpace(wpt.Name)
+		if name == "" {
+			name = fmt.Sprintf("Waypoint %d", i+1)
+		}
+		waypoints = append(waypoints, Waypoint{Name: name, Latitude: wpt.Lat, Longitude: wpt.Lon})
+	}
+	return waypoints, nil
+}
+
+// PortCreator saves a port at a coordinate and lists the saved ports, e.g. Service
+type PortCreator interface {
+	CreatePortAt(ctx context.Context, name string, lat, lon float64) (*models.Port, error)
+	ListPorts() ([]models.Port, error)
+}
+
+// ErrPortExists is the reason a waypoint named like a saved port is skipped, rather
+// than replacing that port
+var ErrPortExists = errors.New("a saved port already has this name")
+
+// SkippedWaypoint is a waypoint no port could be created for
+type SkippedWaypoint struct {
+	Waypoint Waypoint
+	Err      error
+}
+
+// GPXImport is the outcome of importing a GPX file's waypoints as ports
+type GPXImport struct {
+	Created []models.Port
+	Skipped []SkippedWaypoint
+}
+
+// ImportGPX creates a saved port for each waypoint in a GPX file. Waypoints whose
+// zone or tide station can't be found, or that are named like a saved port (including
+// one created earlier in the import), are skipped and reported rather than stopping
+// the import; saved ports are never replaced. Only a file that can't be parsed, or
+// failing to list the saved ports, is an error.
+func ImportGPX(ctx context.Context, creator PortCreator, r io.Reader) (*GPXImport, error) {
+	waypoints, err := ParseGPX(r)
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := creator.ListPorts()
+	if err != nil {
+		return nil, fmt.Errorf("listing saved ports: %w", err)
+	}
+	taken := make(map[string]bool, len(saved))
+	for _, p := range saved {
+		taken[p.Name] = true
+	}
+
+	result := &GPXImport{}
+	for _, wpt := range waypoints {
+		if taken[wpt.Name] {
+			result.Skipped = append(result.Skipped, SkippedWaypoint{Waypoint: wpt, Err: ErrPortExists})
+			continue
+		}
+		port, err := creator.CreatePortAt(ctx, wpt.Name, wpt.Latitude, wpt.Longitude)
+		if err != nil {
+			result.Skipped = append(result.Skipped, SkippedWaypoint{Waypoint: wpt, Err: err})
+			continue
+		}
+		taken[port.Name] = true
+		result.Created = append(result.Created, *port)
+	}
+	return result, nil
+}
+
+// WriteGPXImportReport prints each port created, a warning for each waypoint
+// skipped, and a count of both
+func WriteGPXImportReport(w io.Writer, r *GPXImport) {
+	for _, port := range r.Created {
+		fmt.Fprintf(w, "Added    %s (zone %s, station %s)\n", port.Name, port.MarineZoneID, port.TideStationID)
+	}
+	for _, s := range r.Skipped {
+		fmt.Fprintf(w, "Warning: skipped %s (%.4f, %.4f): %v\n", s.Waypoint.Name, s.Waypoint.Latitude, s.Waypoint.Longitude, s.Err)
+	}
+	fmt.Fprintf(w, "\nImported %d of %d waypoints\n", len(r.Created), len(r.Created)+len(r.Skipped))
+}
@@ -0,0 +1,163 @@
+package ports
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ngmaloney/marine-terminal/internal/database"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+// openWaypointsFixture opens the sample GPX file of three waypoints and a route
+func openWaypointsFixture(t *testing.T) *os.File {
+	t.Helper()
+	f, err := os.Open("../../testdata/waypoints.gpx")
+	if err != nil {
+		t.Fatalf("Failed to open fixture: %v", err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestParseGPX(t *testing.T) {
+	waypoints, err := ParseGPX(openWaypointsFixture(t))
+	if err != nil {
+		t.Fatalf("ParseGPX() error = %v", err)
+	}
+
+	want := []Waypoint{
+		{Name: "Stage Harbor", Latitude: 41.68, Longitude: -69.95},
+		{Name: "Waypoint 2", Latitude: 41.285, Longitude: -70.097},
+		{Name: "Mid-Atlantic", Latitude: 38, Longitude: -40},
+	}
+	if len(waypoints) != len(want) {
+		t.Fatalf("ParseGPX() = %+v, want the %d waypoints and not the route", waypoints, len(want))
+	}
+	for i := range want {
+		if waypoints[i] != want[i] {
+			t.Errorf("waypoint %d = %+v, want %+v", i, waypoints[i], want[i])
+		}
+	}
+
+	if _, err := ParseGPX(strings.NewReader("<gpx><wpt lat=")); err == nil {
+		t.Error("ParseGPX() of a truncated file should fail")
+	}
+}
+
+// mockPortCreator creates ports for waypoints north of 40°N and fails the rest
+type mockPortCreator struct {
+	saved []models.Port
+	names []string
+}
+
+func (c *mockPortCreator) ListPorts() ([]models.Port, error) {
+	return c.saved, nil
+}
+
+func (c *mockPortCreator) CreatePortAt(ctx context.Context, name string, lat, lon float64) (*models.Port, error) {
+	c.names = append(c.names, name)
+	if lat < 40 {
+		return nil, errors.New("no marine zones within 50 mi")
+	}
+	return &models.Port{Name: name, Latitude: lat, Longitude: lon, MarineZoneID: "ANZ254", TideStationID: "8447435"}, nil
+}
+
+func TestImportGPX(t *testing.T) {
+	creator := &mockPortCreator{}
+	result, err := ImportGPX(context.Background(), creator, openWaypointsFixture(t))
+	if err != nil {
+		t.Fatalf("ImportGPX() error = %v", err)
+	}
+
+	if got := strings.Join(creator.names, ", "); got != "Stage Harbor, Waypoint 2, Mid-Atlantic" {
+		t.Errorf("created ports for %s, want every waypoint", got)
+	}
+	if len(result.Created) != 2 || result.Created[0].Name != "Stage Harbor" {
+		t.Errorf("Created = %+v, want Stage Harbor and Waypoint 2", result.Created)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Waypoint.Name != "Mid-Atlantic" {
+		t.Fatalf("Skipped = %+v, want Mid-Atlantic", result.Skipped)
+	}
+
+	var buf bytes.Buffer
+	WriteGPXImportReport(&buf, result)
+	for _, want := range []string{
+		"Added    Stage Harbor (zone ANZ254, station 8447435)",
+		"Warning: skipped Mid-Atlantic (38.0000, -40.0000): no marine zones within 50 mi",
+		"Imported 2 of 3 waypoints",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	if _, err := ImportGPX(context.Background(), creator, strings.NewReader("not xml")); err == nil {
+		t.Error("ImportGPX() of an unparseable file should fail")
+	}
+}
+
+func TestImportGPX_SkipsSavedNames(t *testing.T) {
+	// Stage Harbor is already saved, and the file names two waypoints alike
+	creator := &mockPortCreator{saved: []models.Port{{Name: "Stage Harbor", MarineZoneID: "ANZ250"}}}
+	gpx := `<gpx>
+		<wpt lat="41.68" lon="-69.95"><name>Stage Harbor</name></wpt>
+		<wpt lat="41.52" lon="-70.67"><name>Woods Hole</name></wpt>
+		<wpt lat="41.53" lon="-70.66"><name>Woods Hole</name></wpt>
+	</gpx>`
+	result, err := ImportGPX(context.Background(), creator, strings.NewReader(gpx))
+	if err != nil {
+		t.Fatalf("ImportGPX() error = %v", err)
+	}
+
+	if got := strings.Join(creator.names, ", "); got != "Woods Hole" {
+		t.Errorf("created ports for %s, want only the first Woods Hole", got)
+	}
+	if len(result.Skipped) != 2 {
+		t.Fatalf("Skipped = %+v, want Stage Harbor and the second Woods Hole", result.Skipped)
+	}
+	for _, s := range result.Skipped {
+		if !errors.Is(s.Err, ErrPortExists) {
+			t.Errorf("%s skipped with %v, want ErrPortExists", s.Waypoint.Name, s.Err)
+		}
+	}
+
+	var buf bytes.Buffer
+	WriteGPXImportReport(&buf, result)
+	if want := "Warning: skipped Stage Harbor (41.6800, -69.9500): a saved port already has this name"; !strings.Contains(buf.String(), want) {
+		t.Errorf("report missing %q:\n%s", want, buf.String())
+	}
+}
+
+func TestService_CreatePortAt(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "ports.db")
+	database.SetDBPath(dbPath)
+	t.Cleanup(func() { database.SetDBPath("") })
+	useWhereAmIDB(t, dbPath)
+
+	geocoder := &countingGeocoder{}
+	s := NewServiceWithGeocoder(geocoder)
+	port, err := s.CreatePortAt(context.Background(), "Stage Harbor", 41.68, -69.95)
+	if err != nil {
+		t.Fatalf("CreatePortAt() error = %v", err)
+	}
+	if geocoder.calls != 0 {
+		t.Errorf("geocoded %d times, want the coordinate used as is", geocoder.calls)
+	}
+	if port.MarineZoneID != "ANZ254" || port.TideStationID != "8447435" || port.PreferredZoneType != "coastal" {
+		t.Errorf("port zone/station/type = %s/%s/%s, want ANZ254/8447435/coastal", port.MarineZoneID, port.TideStationID, port.PreferredZoneType)
+	}
+
+	saved, err := s.ListPorts()
+	if err != nil || len(saved) != 1 || saved[0].Name != "Stage Harbor" || saved[0].Latitude != 41.68 {
+		t.Errorf("ListPorts() = %+v, %v, want the port saved at its coordinate", saved, err)
+	}
+
+	if _, err := s.CreatePortAt(context.Background(), "Mid-Atlantic", 38, -40); err == nil || !strings.Contains(err.Error(), "no marine zones") {
+		t.Errorf("CreatePortAt() far offshore error = %v, want no marine zones", err)
+	}
+}
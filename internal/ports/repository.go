@@ -20,6 +20,10 @@ func NewRepository() *Repository {
 
 // SavePort saves a user port configuration to the database
 func (r *Repository) SavePort(port *models.Port) error {
+	if err := port.Validate(); err != nil {
+		return fmt.Errorf("invalid port: %w", err)
+	}
+
 	// Ensure schema exists (safe to call multiple times)
 	if err := database.EnsureUserSchema(database.DBPath()); err != nil {
 		return err
@@ -32,8 +36,8 @@ func (r *Repository) SavePort(port *models.Port) error {
 	defer db.Close()
 
 	query := `
-		INSERT INTO user_ports (name, state, city, zipcode, marine_zone_id, tide_station_id, latitude, longitude, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO user_ports (name, state, city, zipcode, marine_zone_id, tide_station_id, latitude, longitude, created_at, preferred_datum, preferred_zone_type)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(name) DO UPDATE SET
 			state = excluded.state,
 			city = excluded.city,
@@ -42,12 +46,17 @@ func (r *Repository) SavePort(port *models.Port) error {
 			tide_station_id = excluded.tide_station_id,
 			latitude = excluded.latitude,
 			longitude = excluded.longitude,
-			created_at = excluded.created_at
+			created_at = excluded.created_at,
+			preferred_datum = excluded.preferred_datum,
+			preferred_zone_type = excluded.preferred_zone_type
 	`
 
 	if port.CreatedAt.IsZero() {
 		port.CreatedAt = time.Now()
 	}
+	if port.PreferredDatum == "" {
+		port.PreferredDatum = models.DatumMLLW
+	}
 
 	res, err := db.Exec(query,
 		port.Name,
@@ -59,6 +68,8 @@ func (r *Repository) SavePort(port *models.Port) error {
 		port.Latitude,
 		port.Longitude,
 		port.CreatedAt,
+		port.PreferredDatum,
+		port.PreferredZoneType,
 	)
 	if err != nil {
 		return fmt.Errorf("saving port: %w", err)
@@ -85,7 +96,7 @@ func (r *Repository) ListPorts() ([]models.Port, error) {
 	}
 	defer db.Close()
 
-	rows, err := db.Query("SELECT id, name, state, city, zipcode, marine_zone_id, tide_station_id, latitude, longitude, created_at FROM user_ports ORDER BY name")
+	rows, err := db.Query("SELECT id, name, state, city, zipcode, marine_zone_id, tide_station_id, latitude, longitude, created_at, preferred_datum, preferred_zone_type, is_home FROM user_ports ORDER BY name")
 	if err != nil {
 		return nil, fmt.Errorf("querying ports: %w", err)
 	}
@@ -96,19 +107,53 @@ func (r *Repository) ListPorts() ([]models.Port, error) {
 		var p models.Port
 		var state, city, zipcode sql.NullString // Handle potential nulls
 
-		if err := rows.Scan(&p.ID, &p.Name, &state, &city, &zipcode, &p.MarineZoneID, &p.TideStationID, &p.Latitude, &p.Longitude, &p.CreatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &state, &city, &zipcode, &p.MarineZoneID, &p.TideStationID, &p.Latitude, &p.Longitude, &p.CreatedAt, &p.PreferredDatum, &p.PreferredZoneType, &p.Home); err != nil {
 			return nil, fmt.Errorf("scanning port: %w", err)
 		}
 		p.State = state.String
 		p.City = city.String
 		p.Zipcode = zipcode.String
-		p.StationID = p.TideStationID
+		p.SetStation(p.TideStationID)
 		ports = append(ports, p)
 	}
 
 	return ports, nil
 }
 
+// SetHomePort makes the port named name the home port, replacing any earlier one
+func (r *Repository) SetHomePort(name string) error {
+	if err := database.EnsureUserSchema(database.DBPath()); err != nil {
+		return err
+	}
+
+	db, err := sql.Open("sqlite", database.DBPath())
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("UPDATE user_ports SET is_home = 1 WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("setting home port: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("setting home port: %w", err)
+	} else if n == 0 {
+		return fmt.Errorf("port not found: %s", name)
+	}
+	if _, err := tx.Exec("UPDATE user_ports SET is_home = 0 WHERE name != ?", name); err != nil {
+		return fmt.Errorf("clearing previous home port: %w", err)
+	}
+
+	return tx.Commit()
+}
+
 // DeletePort removes a port by name
 func (r *Repository) DeletePort(name string) error {
 	if err := database.EnsureUserSchema(database.DBPath()); err != nil {
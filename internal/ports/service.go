@@ -10,35 +10,58 @@ import (
 	"github.com/ngmaloney/marine-terminal/internal/geocoding"
 	"github.com/ngmaloney/marine-terminal/internal/models"
 	"github.com/ngmaloney/marine-terminal/internal/stations"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
 )
 
+// portStationRadiusMiles is how far from a port's location its tide station may be
+const portStationRadiusMiles = 50.0
+
 // Service orchestrates port operations
 type Service struct {
 	repo     *Repository
-	geocoder *geocoding.Geocoder
+	geocoder geocoding.Geocoder
 }
 
 // NewService creates a new port service
 func NewService() *Service {
+	return NewServiceWithGeocoder(geocoding.NewGeocoder())
+}
+
+// NewServiceWithGeocoder creates a port service that resolves locations with geocoder
+func NewServiceWithGeocoder(geocoder geocoding.Geocoder) *Service {
 	return &Service{
 		repo:     NewRepository(),
-		geocoder: geocoding.NewGeocoder(),
+		geocoder: geocoder,
 	}
 }
 
-// CreatePort builds and saves a port configuration
+// CreatePort builds and saves a port configuration. inputLocation is geocoded unless
+// it's a "lat,lon" coordinate (see SaveLocation), which is used as is and leaves the
+// port without a city, state or zipcode.
 func (s *Service) CreatePort(ctx context.Context, name, inputLocation, marineZoneCode string) (*models.Port, error) {
-	// 1. Geocode the location to get Lat/Lon
-	loc, err := s.geocoder.Geocode(ctx, inputLocation)
-	if err != nil {
-		return nil, fmt.Errorf("geocoding location: %w", err)
+	// Catch a missing name before spending a geocoding request
+	if err := (&models.Port{Name: name, MarineZoneID: marineZoneCode}).Validate(); err != nil {
+		return nil, fmt.Errorf("invalid port: %w", err)
 	}
-	if loc == nil {
-		return nil, fmt.Errorf("location not found: %s", inputLocation)
+
+	// 1. Geocode the location to get Lat/Lon
+	var loc *geocoding.Location
+	lat, lon, coordErr := geocoding.ParseCoordinates(inputLocation)
+	if coordErr == nil {
+		loc = &geocoding.Location{Latitude: lat, Longitude: lon}
+	} else {
+		var err error
+		loc, err = s.geocoder.Geocode(ctx, inputLocation)
+		if err != nil {
+			return nil, fmt.Errorf("geocoding location: %w", err)
+		}
+		if loc == nil {
+			return nil, fmt.Errorf("location not found: %s", inputLocation)
+		}
 	}
 
 	// 2. Find the nearest tide station
-	tideStations, err := stations.FindNearbyStations(database.DBPath(), loc.Latitude, loc.Longitude, 50.0) // 50 miles search radius
+	tideStations, err := stations.FindNearbyStations(database.DBPath(), loc.Latitude, loc.Longitude, portStationRadiusMiles)
 	if err != nil {
 		return nil, fmt.Errorf("finding tide stations: %w", err)
 	}
@@ -51,14 +74,22 @@ func (s *Service) CreatePort(ctx context.Context, name, inputLocation, marineZon
 	port := &models.Port{
 		Name:          name,
 		MarineZoneID:  marineZoneCode,
-		TideStationID: nearestTideStation.ID,
-		StationID:     nearestTideStation.ID,
 		Latitude:      loc.Latitude,
 		Longitude:     loc.Longitude,
+		PreferredDatum: models.DatumMLLW,
+		// Remember whether a coastal or offshore zone was chosen, to list that type first
+		PreferredZoneType: string(classifyZoneCode(marineZoneCode)),
 	}
 
+	port.SetStation(nearestTideStation.ID)
+
 	// 4. Parse inputLocation to populate State, City, Zipcode
-	populateLocationFields(port, inputLocation)
+	if coordErr != nil {
+		populateLocationFields(port, inputLocation)
+	}
+	if err := port.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid port: %w", err)
+	}
 
 	// 5. Save to database
 	if err := s.repo.SavePort(port); err != nil {
@@ -68,6 +99,102 @@ func (s *Service) CreatePort(ctx context.Context, name, inputLocation, marineZon
 	return port, nil
 }
 
+// CreatePortAt builds and saves a port at a coordinate, without geocoding: the marine
+// zone containing it (or the nearest one) and the nearest tide station are looked up
+// from the coordinate itself. A saved port with the same name is replaced.
+func (s *Service) CreatePortAt(ctx context.Context, name string, lat, lon float64) (*models.Port, error) {
+	port := &models.Port{
+		Name:           name,
+		Latitude:       lat,
+		Longitude:      lon,
+		PreferredDatum: models.DatumMLLW,
+	}
+	if err := port.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid port: %w", err)
+	}
+
+	zone, _, err := findContainingZone(database.DBPath(), lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	if zone == nil {
+		return nil, fmt.Errorf("no marine zones within %.0f mi", whereAmIZoneRadiusMiles)
+	}
+
+	tideStations, err := stations.FindNearbyStations(database.DBPath(), lat, lon, portStationRadiusMiles)
+	if err != nil {
+		return nil, fmt.Errorf("finding tide stations: %w", err)
+	}
+	if len(tideStations) == 0 {
+		return nil, fmt.Errorf("no tide stations within %.0f mi", portStationRadiusMiles)
+	}
+
+	port.MarineZoneID = zone.Code
+	port.PreferredZoneType = string(zonelookup.ClassifyZone(zone.Code, zone.Name))
+	port.SetStation(tideStations[0].ID)
+
+	if err := s.repo.SavePort(port); err != nil {
+		return nil, fmt.Errorf("saving port: %w", err)
+	}
+	return port, nil
+}
+
+// RebuildPort geocodes a saved port's stored location again, or uses its saved
+// coordinates if it has no zipcode or city (e.g. ports imported from GPX waypoints), and
+// looks up its marine zone and the nearest tide station afresh. The port keeps a zone
+// the user chose while it's still nearby (see rebuiltZone). It returns the port as
+// UpdatePort would save it; nothing is saved.
+func (s *Service) RebuildPort(ctx context.Context, port models.Port) (*models.Port, error) {
+	lat, lon := port.Latitude, port.Longitude
+	location := portLocation(port)
+	switch {
+	case location != "":
+		loc, err := s.geocoder.Geocode(ctx, location)
+		if err != nil {
+			return nil, fmt.Errorf("geocoding location: %w", err)
+		}
+		if loc == nil {
+			return nil, fmt.Errorf("location not found: %s", location)
+		}
+		lat, lon = loc.Latitude, loc.Longitude
+	case lat != 0 || lon != 0:
+		location = fmt.Sprintf("%.4f, %.4f", lat, lon)
+	default:
+		return nil, fmt.Errorf("port '%s' has no stored location", port.Name)
+	}
+
+	zone, err := rebuiltZone(database.DBPath(), port, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	if zone == nil {
+		return nil, fmt.Errorf("no marine zones found near %s", location)
+	}
+
+	tideStations, err := stations.FindNearbyStations(database.DBPath(), lat, lon, portStationRadiusMiles)
+	if err != nil {
+		return nil, fmt.Errorf("finding tide stations: %w", err)
+	}
+	if len(tideStations) == 0 {
+		return nil, fmt.Errorf("no tide stations found near %s", location)
+	}
+
+	rebuilt := port
+	rebuilt.MarineZoneID = zone.Code
+	rebuilt.SetStation(tideStations[0].ID)
+	rebuilt.Latitude = lat
+	rebuilt.Longitude = lon
+	return &rebuilt, nil
+}
+
+// UpdatePort saves a port rebuilt by RebuildPort in place of the saved port with the same name
+func (s *Service) UpdatePort(port *models.Port) error {
+	if err := s.repo.SavePort(port); err != nil {
+		return fmt.Errorf("updating port: %w", err)
+	}
+	return nil
+}
+
 func (s *Service) ListPorts() ([]models.Port, error) {
 	return s.repo.ListPorts()
 }
@@ -76,6 +203,55 @@ func (s *Service) DeletePort(name string) error {
 	return s.repo.DeletePort(name)
 }
 
+// SetHomePort makes the saved port named name the home port
+func (s *Service) SetHomePort(name string) error {
+	return s.repo.SetHomePort(name)
+}
+
+// HomePort returns the saved port marked as home, or nil if there isn't one
+func (s *Service) HomePort() (*models.Port, error) {
+	saved, err := s.repo.ListPorts()
+	if err != nil {
+		return nil, err
+	}
+	for i := range saved {
+		if saved[i].Home {
+			return &saved[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// classifyZoneCode classifies a marine zone as coastal or offshore, by its name as well
+// as its code when it's in the local database
+func classifyZoneCode(code string) zonelookup.ZoneType {
+	name := ""
+	if info, err := zonelookup.GetZoneInfoByCode(database.DBPath(), code); err == nil {
+		name = info.Name
+	}
+	return zonelookup.ClassifyZone(code, name)
+}
+
+// portLocation returns the search a port was created from: its zipcode, or its city and state
+func portLocation(port models.Port) string {
+	if port.Zipcode != "" {
+		return port.Zipcode
+	}
+	if port.City != "" && port.State != "" {
+		return port.City + ", " + port.State
+	}
+	return port.City
+}
+
+// SaveLocation returns the location to save a port again from with CreatePort: the
+// search it was created from, or its coordinates ("lat,lon") when it has none
+func SaveLocation(port models.Port) string {
+	if loc := portLocation(port); loc != "" {
+		return loc
+	}
+	return fmt.Sprintf("%.6f,%.6f", port.Latitude, port.Longitude)
+}
+
 // populateLocationFields parses the input string to set City, State, or Zipcode
 func populateLocationFields(port *models.Port, input string) {
 	input = strings.TrimSpace(input)
@@ -0,0 +1,276 @@
+package ports
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ngmaloney/marine-terminal/internal/database"
+	"github.com/ngmaloney/marine-terminal/internal/geocoding"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+// countingGeocoder counts lookups and resolves everything to a fixed location
+type countingGeocoder struct {
+	calls int
+}
+
+func (g *countingGeocoder) Geocode(ctx context.Context, query string) (*geocoding.Location, error) {
+	g.calls++
+	return &geocoding.Location{Latitude: 41.68, Longitude: -69.95, Name: query}, nil
+}
+
+func TestService_CreatePortRejectsEmptyName(t *testing.T) {
+	geocoder := &countingGeocoder{}
+	s := NewServiceWithGeocoder(geocoder)
+
+	_, err := s.CreatePort(context.Background(), "  ", "Chatham, MA", "ANZ254")
+	if err == nil || !strings.Contains(err.Error(), "name cannot be empty") {
+		t.Fatalf("CreatePort() error = %v, want an empty name error", err)
+	}
+	if geocoder.calls != 0 {
+		t.Errorf("geocoded %d times for an invalid port, want 0", geocoder.calls)
+	}
+}
+
+func TestRepository_SavePortRejectsInvalid(t *testing.T) {
+	err := NewRepository().SavePort(&models.Port{Name: "Nowhere", Latitude: 120, MarineZoneID: "ANZ254"})
+	if err == nil || !strings.Contains(err.Error(), "invalid port: latitude") {
+		t.Errorf("SavePort() error = %v, want a latitude error", err)
+	}
+}
+
+func TestService_RebuildAndUpdatePort(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "ports.db")
+	database.SetDBPath(dbPath)
+	t.Cleanup(func() { database.SetDBPath("") })
+	useWhereAmIDB(t, dbPath)
+
+	// Saved against a zone that's no longer nearby and the Nantucket station
+	stale := &models.Port{Name: "Home", City: "Chatham", State: "MA", MarineZoneID: "ANZ230", TideStationID: "8449130", Latitude: 41.5, Longitude: -70.0}
+	if err := NewRepository().SavePort(stale); err != nil {
+		t.Fatalf("SavePort() error = %v", err)
+	}
+
+	var queried string
+	s := NewServiceWithGeocoder(geocoderFunc(func(query string) (*geocoding.Location, error) {
+		queried = query
+		return &geocoding.Location{Latitude: 41.68, Longitude: -69.95, Name: query}, nil
+	}))
+
+	rebuilt, err := s.RebuildPort(context.Background(), *stale)
+	if err != nil {
+		t.Fatalf("RebuildPort() error = %v", err)
+	}
+	if queried != "Chatham, MA" {
+		t.Errorf("geocoded %q, want the port's stored location", queried)
+	}
+	if rebuilt.MarineZoneID != "ANZ254" || rebuilt.TideStationID != "8447435" || rebuilt.StationID != "8447435" {
+		t.Errorf("rebuilt zone/station = %s/%s, want ANZ254/8447435", rebuilt.MarineZoneID, rebuilt.TideStationID)
+	}
+	if rebuilt.Latitude != 41.68 || rebuilt.Longitude != -69.95 {
+		t.Errorf("rebuilt location = %v,%v, want the geocoded one", rebuilt.Latitude, rebuilt.Longitude)
+	}
+
+	// Nothing is saved until UpdatePort
+	saved, err := s.ListPorts()
+	if err != nil || len(saved) != 1 || saved[0].MarineZoneID != "ANZ230" {
+		t.Fatalf("ListPorts() = %+v, %v, want the stale port untouched", saved, err)
+	}
+	if err := s.UpdatePort(rebuilt); err != nil {
+		t.Fatalf("UpdatePort() error = %v", err)
+	}
+	saved, err = s.ListPorts()
+	if err != nil || len(saved) != 1 {
+		t.Fatalf("ListPorts() = %+v, %v, want the one port", saved, err)
+	}
+	if saved[0].MarineZoneID != "ANZ254" || saved[0].TideStationID != "8447435" || saved[0].City != "Chatham" {
+		t.Errorf("saved port = %+v, want it updated in place", saved[0])
+	}
+}
+
+func TestService_RebuildPortKeepsChosenZone(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "ports.db")
+	database.SetDBPath(dbPath)
+	t.Cleanup(func() { database.SetDBPath("") })
+	useWhereAmIDB(t, dbPath)
+
+	geocoder := &countingGeocoder{}
+	s := NewServiceWithGeocoder(geocoder)
+
+	// The user picked ANZ250 over ANZ254, which contains the location; it's still nearby
+	chosen := models.Port{Name: "Outer Beach", Zipcode: "02633", MarineZoneID: "ANZ250", TideStationID: "8449130"}
+	rebuilt, err := s.RebuildPort(context.Background(), chosen)
+	if err != nil {
+		t.Fatalf("RebuildPort() error = %v", err)
+	}
+	if rebuilt.MarineZoneID != "ANZ250" || rebuilt.TideStationID != "8447435" {
+		t.Errorf("rebuilt zone/station = %s/%s, want the chosen ANZ250 kept with station 8447435", rebuilt.MarineZoneID, rebuilt.TideStationID)
+	}
+
+	// A port made from coordinates alone, as GPX imports are, is rebuilt from them
+	waypoint := models.Port{Name: "Waypoint 1", MarineZoneID: "ANZ230", Latitude: 41.68, Longitude: -69.95}
+	rebuilt, err = s.RebuildPort(context.Background(), waypoint)
+	if err != nil {
+		t.Fatalf("RebuildPort(coordinates only) error = %v", err)
+	}
+	if rebuilt.MarineZoneID != "ANZ254" || rebuilt.Latitude != 41.68 || rebuilt.Longitude != -69.95 {
+		t.Errorf("rebuilt = %s at %v,%v, want ANZ254 at the saved coordinates", rebuilt.MarineZoneID, rebuilt.Latitude, rebuilt.Longitude)
+	}
+	if geocoder.calls != 1 {
+		t.Errorf("geocoded %d times, want only the port with a zipcode geocoded", geocoder.calls)
+	}
+}
+
+func TestService_CreatePortRemembersZoneType(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "ports.db")
+	database.SetDBPath(dbPath)
+	t.Cleanup(func() { database.SetDBPath("") })
+	useWhereAmIDB(t, dbPath)
+
+	s := NewServiceWithGeocoder(&countingGeocoder{})
+	// ANZ800 isn't in the test database, so it's classified by its number alone
+	for _, zone := range []string{"ANZ254", "ANZ800"} {
+		if _, err := s.CreatePort(context.Background(), zone, "02633", zone); err != nil {
+			t.Fatalf("CreatePort(%s) error = %v", zone, err)
+		}
+	}
+
+	saved, err := s.ListPorts()
+	if err != nil || len(saved) != 2 {
+		t.Fatalf("ListPorts() = %+v, %v, want two ports", saved, err)
+	}
+	want := map[string]string{"ANZ254": "coastal", "ANZ800": "offshore"}
+	for _, p := range saved {
+		if p.PreferredZoneType != want[p.MarineZoneID] {
+			t.Errorf("%s saved with zone type %q, want %q", p.MarineZoneID, p.PreferredZoneType, want[p.MarineZoneID])
+		}
+	}
+}
+
+// TestService_CreatePortFromSaveLocation tests that a port saved again from its
+// SaveLocation keeps its zipcode, and one without a text location keeps its coordinates
+func TestService_CreatePortFromSaveLocation(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "ports.db")
+	database.SetDBPath(dbPath)
+	t.Cleanup(func() { database.SetDBPath("") })
+	useWhereAmIDB(t, dbPath)
+
+	geocoder := &countingGeocoder{}
+	s := NewServiceWithGeocoder(geocoder)
+
+	zipPort := models.Port{Name: "Chatham", City: "Chatham", State: "MA", Zipcode: "02633"}
+	if got := SaveLocation(zipPort); got != "02633" {
+		t.Errorf("SaveLocation() = %q, want the zipcode", got)
+	}
+	named := models.Port{Name: "Stage Harbor", Latitude: 41.66, Longitude: -69.96}
+	loc := SaveLocation(named)
+	if loc != "41.660000,-69.960000" {
+		t.Fatalf("SaveLocation() = %q, want the coordinates", loc)
+	}
+
+	port, err := s.CreatePort(context.Background(), named.Name, loc, "ANZ254")
+	if err != nil {
+		t.Fatalf("CreatePort() error = %v", err)
+	}
+	if geocoder.calls != 0 {
+		t.Errorf("geocoded %d times for a coordinate, want 0", geocoder.calls)
+	}
+	if port.Latitude != 41.66 || port.Longitude != -69.96 || port.City != "" || port.State != "" {
+		t.Errorf("port = %+v, want the coordinates without a city or state", port)
+	}
+}
+
+func TestService_RebuildPortErrors(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "ports.db")
+	database.SetDBPath(dbPath)
+	t.Cleanup(func() { database.SetDBPath("") })
+	useWhereAmIDB(t, dbPath)
+
+	s := NewServiceWithGeocoder(geocoderFunc(func(query string) (*geocoding.Location, error) {
+		return &geocoding.Location{Latitude: 39.74, Longitude: -104.99, Name: query}, nil
+	}))
+	if _, err := s.RebuildPort(context.Background(), models.Port{Name: "Blank"}); err == nil || !strings.Contains(err.Error(), "no stored location") {
+		t.Errorf("RebuildPort() error = %v, want a missing location error", err)
+	}
+	// Denver is far from any zone in the test database
+	if _, err := s.RebuildPort(context.Background(), models.Port{Name: "Denver", Zipcode: "80202"}); err == nil || !strings.Contains(err.Error(), "no marine zones") {
+		t.Errorf("RebuildPort() error = %v, want a no zones error", err)
+	}
+}
+
+// geocoderFunc lets a function stand in for a Geocoder
+type geocoderFunc func(query string) (*geocoding.Location, error)
+
+func (f geocoderFunc) Geocode(ctx context.Context, query string) (*geocoding.Location, error) {
+	return f(query)
+}
+
+func TestService_SetHomePort(t *testing.T) {
+	database.SetDBPath(filepath.Join(t.TempDir(), "ports.db"))
+	t.Cleanup(func() { database.SetDBPath("") })
+
+	repo := NewRepository()
+	for _, name := range []string{"Chatham", "Hyannis"} {
+		if err := repo.SavePort(&models.Port{Name: name, MarineZoneID: "ANZ254", TideStationID: "8447435", Latitude: 41.68, Longitude: -69.95}); err != nil {
+			t.Fatalf("SavePort(%s) error = %v", name, err)
+		}
+	}
+
+	s := NewServiceWithGeocoder(&countingGeocoder{})
+	homes := func() []string {
+		t.Helper()
+		saved, err := s.ListPorts()
+		if err != nil {
+			t.Fatalf("ListPorts() error = %v", err)
+		}
+		var names []string
+		for _, p := range saved {
+			if p.Home {
+				names = append(names, p.Name)
+			}
+		}
+		return names
+	}
+
+	if got := homes(); len(got) != 0 {
+		t.Errorf("home ports = %v before any is set, want none", got)
+	}
+	if home, err := s.HomePort(); err != nil || home != nil {
+		t.Errorf("HomePort() = %v, %v before any is set, want nil", home, err)
+	}
+	if err := s.SetHomePort("Chatham"); err != nil {
+		t.Fatalf("SetHomePort() error = %v", err)
+	}
+	if got := homes(); len(got) != 1 || got[0] != "Chatham" {
+		t.Errorf("home ports = %v, want [Chatham]", got)
+	}
+	if home, err := s.HomePort(); err != nil || home == nil || home.Name != "Chatham" {
+		t.Errorf("HomePort() = %v, %v, want Chatham", home, err)
+	}
+
+	// Only one port is home at a time
+	if err := s.SetHomePort("Hyannis"); err != nil {
+		t.Fatalf("SetHomePort() error = %v", err)
+	}
+	if got := homes(); len(got) != 1 || got[0] != "Hyannis" {
+		t.Errorf("home ports = %v, want [Hyannis]", got)
+	}
+
+	// Overwriting the home port keeps it home
+	if err := repo.SavePort(&models.Port{Name: "Hyannis", MarineZoneID: "ANZ255", TideStationID: "8447435", Latitude: 41.63, Longitude: -70.28}); err != nil {
+		t.Fatalf("SavePort() error = %v", err)
+	}
+	if got := homes(); len(got) != 1 || got[0] != "Hyannis" {
+		t.Errorf("home ports = %v after overwriting, want [Hyannis]", got)
+	}
+
+	// An unknown port leaves the home port alone
+	if err := s.SetHomePort("Nowhere"); err == nil || !strings.Contains(err.Error(), "port not found") {
+		t.Errorf("SetHomePort(Nowhere) error = %v, want port not found", err)
+	}
+	if got := homes(); len(got) != 1 || got[0] != "Hyannis" {
+		t.Errorf("home ports = %v, want [Hyannis] unchanged", got)
+	}
+}
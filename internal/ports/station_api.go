@@ -31,8 +31,14 @@ type NOAAStationClient struct {
 
 // NewNOAAStationClient creates a client that uses NOAA's Station Metadata API
 func NewNOAAStationClient() *NOAAStationClient {
+	return NewNOAAStationClientWithHTTPClient(&http.Client{Timeout: 30 * time.Second})
+}
+
+// NewNOAAStationClientWithHTTPClient is NewNOAAStationClient sending its requests with
+// httpClient, e.g. one with a different timeout or transport
+func NewNOAAStationClientWithHTTPClient(httpClient *http.Client) *NOAAStationClient {
 	return &NOAAStationClient{
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		httpClient: httpClient,
 		cache:      make(map[string][]models.Port),
 		cacheTime:  make(map[string]time.Time),
 		cacheTTL:   24 * time.Hour, // Stations don't change often
@@ -248,22 +254,7 @@ func (c *NOAAStationClient) fetchStations(ctx context.Context, params url.Values
 	// Convert to Port models
 	ports := make([]models.Port, 0, len(stationResp.Stations))
 	for _, s := range stationResp.Stations {
-		// Extract city from name (e.g., "Boston, MA" -> "Boston")
-		city := s.Name
-		if idx := strings.Index(city, ","); idx > 0 {
-			city = city[:idx]
-		}
-
-		ports = append(ports, models.Port{
-			StationID:   s.ID,
-			Name:        s.Name,
-			City:        city,
-			State:       s.State,
-			Latitude:    s.Latitude,
-			Longitude:   s.Longitude,
-			TideStationID: s.ID,
-			Type:        "coastal",
-		})
+		ports = append(ports, models.PortFromStation(s.ID, s.Name, s.State, s.Latitude, s.Longitude))
 	}
 
 	return ports, nil
@@ -0,0 +1,166 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ngmaloney/marine-terminal/internal/geocoding"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/stations"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
+)
+
+// Search radii for --whereami, matching the ones used by the zone search in the UI
+const (
+	whereAmIZoneRadiusMiles    = 50.0
+	whereAmIStationRadiusMiles = 30.0
+
+	// whereAmIZoneCandidates is how many of the closest zones (by center) are checked
+	// for one whose polygon contains the location
+	whereAmIZoneCandidates = 5
+)
+
+// WhereAmI is the marine zone and tide station found for a location
+type WhereAmI struct {
+	Query    string
+	Location geocoding.Location
+	Zone     *zonelookup.ZoneInfo         // nil if no zone is within range
+	Boundary *zonelookup.BoundaryDistance // nil if the zone's polygon isn't available
+	Station  *stations.TideStationInfo    // nil if no tide station is within range
+}
+
+// LookupWhereAmI geocodes query and finds the marine zone containing it (or the
+// nearest one) and the nearest tide station in the database at dbPath
+func LookupWhereAmI(ctx context.Context, geocoder geocoding.Geocoder, dbPath, query string) (*WhereAmI, error) {
+	loc, err := geocoder.Geocode(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("geocoding location: %w", err)
+	}
+	if loc == nil {
+		return nil, fmt.Errorf("location not found: %s", query)
+	}
+	result := &WhereAmI{Query: query, Location: *loc}
+
+	result.Zone, result.Boundary, err = findContainingZone(dbPath, loc.Latitude, loc.Longitude)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := stations.FindNearbyStations(dbPath, loc.Latitude, loc.Longitude, whereAmIStationRadiusMiles)
+	if err != nil && !errors.Is(err, stations.ErrNoNearbyStations) {
+		return nil, fmt.Errorf("finding tide stations: %w", err)
+	}
+	if len(found) > 0 {
+		result.Station = &found[0]
+	}
+
+	return result, nil
+}
+
+// findContainingZone finds the marine zone whose polygon contains a location among the
+// closest zones by center, falling back to the closest one. The zone is nil if none is
+// within whereAmIZoneRadiusMiles, and the boundary is nil if its polygon isn't available.
+func findContainingZone(dbPath string, lat, lon float64) (*zonelookup.ZoneInfo, *zonelookup.BoundaryDistance, error) {
+	zones, err := zonelookup.GetNearbyMarineZones(dbPath, lat, lon, whereAmIZoneRadiusMiles)
+	if err != nil {
+		return nil, nil, fmt.Errorf("finding marine zones: %w", err)
+	}
+	return containingZone(dbPath, zones, lat, lon)
+}
+
+// containingZone finds the zone whose polygon contains a location among zones, sorted
+// closest first by center, falling back to the closest one
+func containingZone(dbPath string, zones []zonelookup.ZoneInfo, lat, lon float64) (*zonelookup.ZoneInfo, *zonelookup.BoundaryDistance, error) {
+	candidates := zones[:min(len(zones), whereAmIZoneCandidates)]
+	codes := make([]string, len(candidates))
+	for i, z := range candidates {
+		codes[i] = z.Code
+	}
+	distances, err := zonelookup.ZoneBoundaryDistances(dbPath, codes, lat, lon)
+	if err != nil {
+		return nil, nil, fmt.Errorf("measuring zone boundaries: %w", err)
+	}
+
+	var zone *zonelookup.ZoneInfo
+	var boundary *zonelookup.BoundaryDistance
+	for i := range candidates {
+		b, ok := distances[candidates[i].Code]
+		if !ok {
+			continue // No usable polygon for this zone; fall back to center distance
+		}
+		if i == 0 || b.Inside {
+			zone, boundary = &candidates[i], &b
+		}
+		if b.Inside {
+			break
+		}
+	}
+	if zone == nil && len(zones) > 0 {
+		zone = &zones[0]
+	}
+	return zone, boundary, nil
+}
+
+// rebuiltZone picks the marine zone for a port rebuilt at lat, lon. The port keeps its
+// zone while that's still one of the closest zones, as the user may have chosen it over
+// the one containing the location; otherwise it gets the containing (or nearest) zone of
+// its preferred zone type, if any of that type are nearby.
+func rebuiltZone(dbPath string, port models.Port, lat, lon float64) (*zonelookup.ZoneInfo, error) {
+	zones, err := zonelookup.GetNearbyMarineZones(dbPath, lat, lon, whereAmIZoneRadiusMiles)
+	if err != nil {
+		return nil, fmt.Errorf("finding marine zones: %w", err)
+	}
+	for i := range zones[:min(len(zones), whereAmIZoneCandidates)] {
+		if strings.EqualFold(zones[i].Code, port.MarineZoneID) {
+			return &zones[i], nil
+		}
+	}
+
+	if port.PreferredZoneType != "" {
+		var preferred []zonelookup.ZoneInfo
+		for _, z := range zones {
+			if string(zonelookup.ClassifyZone(z.Code, z.Name)) == port.PreferredZoneType {
+				preferred = append(preferred, z)
+			}
+		}
+		if len(preferred) > 0 {
+			zones = preferred
+		}
+	}
+	zone, _, err := containingZone(dbPath, zones, lat, lon)
+	return zone, err
+}
+
+// WriteWhereAmIReport prints the location, zone and tide station, with a command
+// line that loads the zone directly
+func WriteWhereAmIReport(w io.Writer, r *WhereAmI) {
+	name := r.Location.Name
+	if name == "" {
+		name = r.Query
+	}
+	fmt.Fprintf(w, "Location:      %s (%.4f, %.4f)\n", name, r.Location.Latitude, r.Location.Longitude)
+
+	switch {
+	case r.Zone == nil:
+		fmt.Fprintf(w, "Marine zone:   none within %.0f mi\n", whereAmIZoneRadiusMiles)
+	case r.Boundary != nil && r.Boundary.Inside:
+		fmt.Fprintf(w, "Marine zone:   %s - %s (inside the zone)\n", r.Zone.Code, r.Zone.Name)
+	case r.Boundary != nil:
+		fmt.Fprintf(w, "Marine zone:   %s - %s (%.1f mi from the zone boundary)\n", r.Zone.Code, r.Zone.Name, r.Boundary.Miles)
+	default:
+		fmt.Fprintf(w, "Marine zone:   %s - %s (%.1f mi away)\n", r.Zone.Code, r.Zone.Name, r.Zone.Distance)
+	}
+
+	if r.Station == nil {
+		fmt.Fprintf(w, "Tide station:  none within %.0f mi\n", whereAmIStationRadiusMiles)
+	} else {
+		fmt.Fprintf(w, "Tide station:  %s - %s (%.1f mi away)\n", r.Station.ID, r.Station.Name, r.Station.Distance)
+	}
+
+	if r.Zone != nil {
+		fmt.Fprintf(w, "\nLoad it with:  marine-terminal --station %s --location %q\n", r.Zone.Code, r.Query)
+	}
+}
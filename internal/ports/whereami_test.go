@@ -0,0 +1,123 @@
+package ports
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ngmaloney/marine-terminal/internal/geocoding"
+	"github.com/ngmaloney/marine-terminal/internal/stations"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
+	_ "modernc.org/sqlite"
+)
+
+// fixedGeocoder resolves every query to loc, or fails with err
+type fixedGeocoder struct {
+	loc *geocoding.Location
+	err error
+}
+
+func (g fixedGeocoder) Geocode(ctx context.Context, query string) (*geocoding.Location, error) {
+	return g.loc, g.err
+}
+
+// useWhereAmIDB points the zone and station lookups at an in-memory database with
+// two zones near Chatham, MA and two tide stations
+func useWhereAmIDB(t *testing.T, dbPath string) {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open in-memory database: %v", err)
+	}
+	db.SetMaxOpenConns(1) // Every connection to :memory: is a separate database
+
+	// ANZ250's center is closest, but the location is inside ANZ254's polygon
+	_, err = db.Exec(`
+		CREATE TABLE marine_zones (zone_code TEXT, zone_name TEXT, geometry TEXT NOT NULL, center_lat REAL, center_lon REAL);
+		INSERT INTO marine_zones VALUES
+			('ANZ250', 'Coastal Waters East of Cape Cod', '[[-69.9,41.6],[-69.8,41.6],[-69.8,41.8],[-69.9,41.8],[-69.9,41.6]]', 41.70, -69.90),
+			('ANZ254', 'Nantucket Sound', '[[-70.3,41.4],[-69.92,41.4],[-69.92,41.75],[-70.3,41.75],[-70.3,41.4]]', 41.55, -70.10);
+		CREATE TABLE tide_stations (id TEXT PRIMARY KEY, name TEXT NOT NULL, state TEXT, latitude REAL NOT NULL, longitude REAL NOT NULL);
+		INSERT INTO tide_stations VALUES
+			('8447435', 'Chatham, Lydia Cove', 'MA', 41.688, -69.951),
+			('8449130', 'Nantucket Island', 'MA', 41.285, -70.097);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create test data: %v", err)
+	}
+
+	if err := zonelookup.SetDB(dbPath, db); err != nil {
+		t.Fatal(err)
+	}
+	oldGetDB := stations.GetDB
+	stations.GetDB = func(string) (*sql.DB, error) { return db, nil }
+	t.Cleanup(func() {
+		stations.GetDB = oldGetDB
+		zonelookup.ResetDB()
+	})
+}
+
+func TestLookupWhereAmI(t *testing.T) {
+	const dbPath = "whereami-test.db"
+	useWhereAmIDB(t, dbPath)
+
+	geocoder := fixedGeocoder{loc: &geocoding.Location{Latitude: 41.68, Longitude: -69.95, Name: "Chatham, MA"}}
+	result, err := LookupWhereAmI(context.Background(), geocoder, dbPath, "02633")
+	if err != nil {
+		t.Fatalf("LookupWhereAmI() error = %v", err)
+	}
+
+	if result.Zone == nil || result.Zone.Code != "ANZ254" {
+		t.Fatalf("Zone = %+v, want ANZ254 (contains the location)", result.Zone)
+	}
+	if result.Boundary == nil || !result.Boundary.Inside {
+		t.Errorf("Boundary = %+v, want inside", result.Boundary)
+	}
+	if result.Station == nil || result.Station.ID != "8447435" {
+		t.Errorf("Station = %+v, want 8447435", result.Station)
+	}
+
+	var out bytes.Buffer
+	WriteWhereAmIReport(&out, result)
+	for _, want := range []string{
+		"Chatham, MA (41.6800, -69.9500)",
+		"ANZ254 - Nantucket Sound (inside the zone)",
+		"8447435 - Chatham, Lydia Cove (0.",
+		`--station ANZ254 --location "02633"`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report missing %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestLookupWhereAmI_NothingNearby(t *testing.T) {
+	const dbPath = "whereami-test.db"
+	useWhereAmIDB(t, dbPath)
+
+	// Denver is far from any zone or station in the test database
+	geocoder := fixedGeocoder{loc: &geocoding.Location{Latitude: 39.74, Longitude: -104.99, Name: "Denver, CO"}}
+	result, err := LookupWhereAmI(context.Background(), geocoder, dbPath, "Denver, CO")
+	if err != nil {
+		t.Fatalf("LookupWhereAmI() error = %v", err)
+	}
+	if result.Zone != nil || result.Station != nil {
+		t.Errorf("result = %+v, want no zone or station", result)
+	}
+
+	var out bytes.Buffer
+	WriteWhereAmIReport(&out, result)
+	if !strings.Contains(out.String(), "Marine zone:   none within 50 mi") || strings.Contains(out.String(), "Load it with") {
+		t.Errorf("report should say no zone was found, got:\n%s", out.String())
+	}
+}
+
+func TestLookupWhereAmI_GeocodeError(t *testing.T) {
+	geocoder := fixedGeocoder{err: fmt.Errorf("zipcode 99999 not found")}
+	if _, err := LookupWhereAmI(context.Background(), geocoder, "unused.db", "99999"); err == nil || !strings.Contains(err.Error(), "99999") {
+		t.Errorf("LookupWhereAmI() error = %v, want the geocoding error", err)
+	}
+}
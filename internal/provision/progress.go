@@ -0,0 +1,42 @@
+// Package provision defines the structured progress updates emitted while provisioning local data
+package provision
+
+import "log"
+
+// Progress reports how far along a provisioning step is.
+// Fraction is in the range [0, 1], or negative when the step has no measurable progress.
+type Progress struct {
+	Label    string
+	Fraction float64
+}
+
+// Status creates a progress update with a label only (no measurable fraction)
+func Status(label string) Progress {
+	return Progress{Label: label, Fraction: -1}
+}
+
+// Step creates a progress update estimating completion from processed vs total items
+func Step(label string, done, total int) Progress {
+	if total <= 0 {
+		return Status(label)
+	}
+	fraction := float64(done) / float64(total)
+	if fraction > 1 {
+		fraction = 1
+	}
+	return Progress{Label: label, Fraction: fraction}
+}
+
+// HasFraction reports whether the update carries a measurable completion fraction
+func (p Progress) HasFraction() bool {
+	return p.Fraction >= 0
+}
+
+// Send delivers an update on the channel, or logs the label if no channel is provided
+func Send(progressChan chan<- Progress, p Progress) {
+	if progressChan != nil {
+		progressChan <- p
+	} else {
+		log.Println(p.Label)
+	}
+}
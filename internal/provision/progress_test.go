@@ -0,0 +1,48 @@
+package provision
+
+import "testing"
+
+func TestStep(t *testing.T) {
+	tests := []struct {
+		name         string
+		done         int
+		total        int
+		wantFraction float64
+	}{
+		{"halfway", 50, 100, 0.5},
+		{"complete", 100, 100, 1},
+		{"overshoot is clamped", 120, 100, 1},
+		{"unknown total", 10, 0, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Step("Processing...", tt.done, tt.total)
+			if got.Fraction != tt.wantFraction {
+				t.Errorf("Step(%d, %d).Fraction = %v, want %v", tt.done, tt.total, got.Fraction, tt.wantFraction)
+			}
+			if got.Label != "Processing..." {
+				t.Errorf("Step().Label = %q, want %q", got.Label, "Processing...")
+			}
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	p := Status("Downloading...")
+	if p.HasFraction() {
+		t.Errorf("Status() should not carry a fraction, got %v", p.Fraction)
+	}
+}
+
+func TestSend(t *testing.T) {
+	ch := make(chan Progress, 1)
+	Send(ch, Step("Working", 1, 4))
+	got := <-ch
+	if got.Fraction != 0.25 {
+		t.Errorf("Send() delivered fraction %v, want 0.25", got.Fraction)
+	}
+
+	// A nil channel should log instead of blocking
+	Send(nil, Status("logged"))
+}
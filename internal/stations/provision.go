@@ -3,15 +3,21 @@ package stations
 import (
 	"context"
 	"database/sql"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/ngmaloney/marine-terminal/internal/database"
+	"github.com/ngmaloney/marine-terminal/internal/provision"
 	_ "modernc.org/sqlite"
 )
 
@@ -57,8 +63,53 @@ func NeedsProvisioning(dbPath string) (bool, error) {
 	return count == 0, nil
 }
 
-// ProvisionStationsDatabase fetches all active tide stations from NOAA and stores them in the SQLite database
-func ProvisionStationsDatabase(dbPath string, progressChan chan<- string) error {
+// IsEmpty reports whether the tide_stations table is missing or contains no rows.
+// A provisioned but empty table usually means an earlier provisioning run failed partway.
+func IsEmpty(dbPath string) (bool, error) {
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return true, nil
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return false, fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	var tables int
+	err = db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name='tide_stations'").Scan(&tables)
+	if err != nil {
+		return false, fmt.Errorf("checking for tide_stations table: %w", err)
+	}
+	if tables == 0 {
+		return true, nil
+	}
+
+	var hasRows bool
+	if err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM tide_stations)").Scan(&hasRows); err != nil {
+		return false, fmt.Errorf("counting tide stations: %w", err)
+	}
+	return !hasRows, nil
+}
+
+// Count returns the number of rows in the tide_stations table
+func Count(dbPath string) (int, error) {
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return 0, fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM tide_stations").Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting tide stations: %w", err)
+	}
+	return count, nil
+}
+
+// ProvisionStationsDatabase fetches all active tide stations from NOAA and stores them in the SQLite database.
+// If NOAA can't be reached, the stations bundled in testdata/tide_stations.csv are used instead.
+func ProvisionStationsDatabase(dbPath string, progressChan chan<- provision.Progress) error {
 	provisionMu.Lock()
 	defer provisionMu.Unlock()
 
@@ -70,19 +121,30 @@ func ProvisionStationsDatabase(dbPath string, progressChan chan<- string) error
 		return nil
 	}
 
+	provision.Send(progressChan, provision.Status("Tide stations table not found, provisioning..."))
+	return provisionStations(dbPath, progressChan)
+}
+
+// ReprovisionStationsDatabase re-fetches tide stations even if the table already exists,
+// e.g. when a previous provisioning run left it empty
+func ReprovisionStationsDatabase(dbPath string, progressChan chan<- provision.Progress) error {
+	provisionMu.Lock()
+	defer provisionMu.Unlock()
+
+	provision.Send(progressChan, provision.Status("Re-provisioning tide stations..."))
+	return provisionStations(dbPath, progressChan)
+}
+
+// provisionStations downloads station metadata and fills the tide_stations table.
+// Callers must hold provisionMu.
+func provisionStations(dbPath string, progressChan chan<- provision.Progress) error {
 	sendProgress := func(msg string) {
-		if progressChan != nil {
-			progressChan <- msg
-		} else {
-			log.Println(msg)
-		}
+		provision.Send(progressChan, provision.Status(msg))
 	}
 
-	sendProgress("Tide stations table not found, provisioning...")
-
 	// Create data directory if it doesn't exist
 	dataDir := filepath.Dir(dbPath)
-	if err = os.MkdirAll(dataDir, 0755); err != nil {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
 		return fmt.Errorf("creating data directory: %w", err)
 	}
 
@@ -90,7 +152,19 @@ func ProvisionStationsDatabase(dbPath string, progressChan chan<- string) error
 	sendProgress(fmt.Sprintf("Downloading tide station data from %s...", stationAPIBaseURL))
 	stations, err := fetchAllTideStations(context.Background())
 	if err != nil {
-		return fmt.Errorf("fetching all tide stations: %w", err)
+		// Without any stations tides can't be shown at all, so fall back to the bundled
+		// ones. A table that already has stations is kept as it is.
+		empty, emptyErr := IsEmpty(dbPath)
+		if emptyErr != nil || !empty {
+			return fmt.Errorf("fetching all tide stations: %w", err)
+		}
+		bundled, bundledErr := loadBundledStations(getStationsCSVPath())
+		if bundledErr != nil {
+			return fmt.Errorf("fetching all tide stations: %w (bundled data: %v)", err, bundledErr)
+		}
+		log.Printf("Fetching tide stations failed (%v); using %d bundled stations", err, len(bundled))
+		sendProgress(fmt.Sprintf("Download failed; using %d bundled tide stations", len(bundled)))
+		stations = bundled
 	}
 
 	// Open database (or create if it doesn't exist)
@@ -110,6 +184,71 @@ func ProvisionStationsDatabase(dbPath string, progressChan chan<- string) error
 	return nil
 }
 
+// getStationsCSVPath returns the path to the bundled tide stations CSV file, a fallback
+// for when NOAA's station list can't be downloaded. It looks for testdata/tide_stations.csv
+// relative to the module root.
+func getStationsCSVPath() string {
+	// Try current directory first (for running from repo root)
+	path := "testdata/tide_stations.csv"
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+
+	// Try relative to this source file location (for tests)
+	_, filename, _, ok := runtime.Caller(0)
+	if ok {
+		// Get the repo root by going up from internal/stations/
+		repoRoot := filepath.Join(filepath.Dir(filename), "..", "..")
+		path = filepath.Join(repoRoot, "testdata", "tide_stations.csv")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	// Fall back to original path
+	return "testdata/tide_stations.csv"
+}
+
+// loadBundledStations reads stations from a CSV file with an id,name,state,lat,lng header.
+// Rows that can't be parsed are skipped.
+func loadBundledStations(csvPath string) ([]Station, error) {
+	file, err := os.Open(csvPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening bundled tide stations: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	// Skip header
+	if _, err := reader.Read(); err != nil {
+		return nil, fmt.Errorf("reading bundled tide stations: %w", err)
+	}
+
+	var stations []Station
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil || len(record) < 5 {
+			continue // Skip invalid records
+		}
+		lat, err := strconv.ParseFloat(record[3], 64)
+		if err != nil {
+			continue
+		}
+		lon, err := strconv.ParseFloat(record[4], 64)
+		if err != nil {
+			continue
+		}
+		stations = append(stations, Station{ID: record[0], Name: record[1], State: record[2], Latitude: lat, Longitude: lon})
+	}
+	if len(stations) == 0 {
+		return nil, fmt.Errorf("no stations in bundled data at %s", csvPath)
+	}
+	return stations, nil
+}
+
 // fetchAllTideStations fetches all active tide stations from the NOAA MDAPI
 func fetchAllTideStations(ctx context.Context) ([]Station, error) {
 	client := &http.Client{Timeout: 30 * time.Second}
@@ -138,10 +277,15 @@ func fetchAllTideStations(ctx context.Context) ([]Station, error) {
 	return stationResp.Stations, nil
 }
 
-// buildStationsDatabase creates the tide_stations table and inserts fetched stations
-func buildStationsDatabase(db *sql.DB, stations []Station, progressChan chan<- string) error {
+// buildStationsDatabase creates the tide_stations table and replaces its contents with the fetched stations.
+// Existing rows are kept if no stations could be inserted.
+func buildStationsDatabase(db *sql.DB, stations []Station, progressChan chan<- provision.Progress) error {
 	var err error
 
+	if len(stations) == 0 {
+		return fmt.Errorf("no tide stations to insert")
+	}
+
 	_, err = db.Exec(`
 		CREATE TABLE IF NOT EXISTS tide_stations (
 			id TEXT PRIMARY KEY,
@@ -163,6 +307,10 @@ func buildStationsDatabase(db *sql.DB, stations []Station, progressChan chan<- s
 	}
 	defer tx.Rollback() // Rollback on error
 
+	if _, err := tx.Exec("DELETE FROM tide_stations"); err != nil {
+		return fmt.Errorf("clearing tide_stations table: %w", err)
+	}
+
 	stmt, err := tx.Prepare("INSERT OR IGNORE INTO tide_stations (id, name, state, latitude, longitude) VALUES (?, ?, ?, ?, ?)")
 	if err != nil {
 		return err
@@ -179,17 +327,25 @@ func buildStationsDatabase(db *sql.DB, stations []Station, progressChan chan<- s
 		count++
 		if count%500 == 0 {
 			if progressChan != nil {
-				progressChan <- fmt.Sprintf("Inserted %d tide stations...", count)
+				progressChan <- provision.Step(fmt.Sprintf("Inserted %d tide stations...", count), count, len(stations))
 			}
 		}
 	}
 
+	if count == 0 {
+		return fmt.Errorf("no tide stations could be inserted")
+	}
+
+	if err := database.RecordProvisioned(tx, database.MetadataStationsProvisionedAt, time.Now()); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("committing transaction: %w", err)
 	}
 
 	if progressChan != nil {
-		progressChan <- fmt.Sprintf("Successfully inserted %d tide stations", count)
+		progressChan <- provision.Step(fmt.Sprintf("Successfully inserted %d tide stations", count), len(stations), len(stations))
 	}
 	return nil
 }
@@ -10,6 +10,7 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/ngmaloney/marine-terminal/internal/database"
 	_ "modernc.org/sqlite"
 )
 
@@ -119,6 +120,13 @@ func TestBuildStationsDatabase(t *testing.T) {
 	if err != nil || name != "Station One" {
 		t.Errorf("Expected name \"Station One\" for ID \"1\", got %v", name)
 	}
+
+	// When the stations were provisioned is recorded with them
+	var provisioned string
+	err = db.QueryRow("SELECT value FROM metadata WHERE key = ?", database.MetadataStationsProvisionedAt).Scan(&provisioned)
+	if err != nil || provisioned == "" {
+		t.Errorf("Expected the provisioning date in metadata, got %q, %v", provisioned, err)
+	}
 }
 
 func TestProvisionStationsDatabase(t *testing.T) {
@@ -185,3 +193,139 @@ func TestProvisionStationsDatabase(t *testing.T) {
 		t.Errorf("Expected 1 station after second provisioning call, got %d (err: %v)", count, err)
 	}
 }
+
+func TestProvisionStationsDatabase_BundledFallback(t *testing.T) {
+	resetSingletons()
+	dbPath := filepath.Join(t.TempDir(), "marine-terminal.db")
+
+	// NOAA is down
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	oldURL := stationAPIBaseURL
+	stationAPIBaseURL = server.URL
+	defer func() { stationAPIBaseURL = oldURL }()
+
+	if err := ProvisionStationsDatabase(dbPath, nil); err != nil {
+		t.Fatalf("ProvisionStationsDatabase() error = %v", err)
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open db after provisioning: %v", err)
+	}
+	defer db.Close()
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM tide_stations").Scan(&count); err != nil || count == 0 {
+		t.Fatalf("Expected bundled stations in DB, got %d (err: %v)", count, err)
+	}
+
+	var name string
+	err = db.QueryRow("SELECT name FROM tide_stations WHERE id = '8447435'").Scan(&name)
+	if err != nil || name != "Chatham, Lydia Cove" {
+		t.Errorf("Expected bundled station 'Chatham, Lydia Cove', got %v (err: %v)", name, err)
+	}
+
+	// An existing table isn't replaced by the bundled stations when a later download fails
+	if _, err := db.Exec("DELETE FROM tide_stations WHERE id != '8447435'"); err != nil {
+		t.Fatalf("Failed to trim stations: %v", err)
+	}
+	if err := ReprovisionStationsDatabase(dbPath, nil); err == nil {
+		t.Error("Expected ReprovisionStationsDatabase() to report the failed download")
+	}
+	if err := db.QueryRow("SELECT COUNT(*) FROM tide_stations").Scan(&count); err != nil || count != 1 {
+		t.Errorf("Expected the existing station to be kept, got %d (err: %v)", count, err)
+	}
+}
+
+func TestLoadBundledStations(t *testing.T) {
+	stations, err := loadBundledStations(getStationsCSVPath())
+	if err != nil {
+		t.Fatalf("loadBundledStations() error = %v", err)
+	}
+	for _, s := range stations {
+		if s.ID == "" || s.Name == "" || s.State == "" || s.Latitude == 0 || s.Longitude == 0 {
+			t.Errorf("Incomplete bundled station: %+v", s)
+		}
+	}
+
+	if _, err := loadBundledStations(filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("Expected an error for a missing CSV")
+	}
+}
+
+func TestIsEmpty(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	// Missing file counts as empty
+	empty, err := IsEmpty(dbPath)
+	if err != nil || !empty {
+		t.Errorf("missing db: IsEmpty() = %v, %v; want true, nil", empty, err)
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	// Table exists but has no rows
+	if _, err := db.Exec(`CREATE TABLE tide_stations (id TEXT PRIMARY KEY, name TEXT, state TEXT, latitude REAL, longitude REAL)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	empty, err = IsEmpty(dbPath)
+	if err != nil || !empty {
+		t.Errorf("empty table: IsEmpty() = %v, %v; want true, nil", empty, err)
+	}
+
+	// Table with rows
+	if _, err := db.Exec(`INSERT INTO tide_stations VALUES ('1', 'Station One', 'MA', 10.0, -10.0)`); err != nil {
+		t.Fatalf("Failed to insert station: %v", err)
+	}
+	empty, err = IsEmpty(dbPath)
+	if err != nil || empty {
+		t.Errorf("populated table: IsEmpty() = %v, %v; want false, nil", empty, err)
+	}
+}
+
+func TestBuildStationsDatabase_Replace(t *testing.T) {
+	resetSingletons()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	if err := buildStationsDatabase(db, []Station{
+		{ID: "OLD", Name: "Retired Station", State: "MA", Latitude: 10.0, Longitude: -10.0},
+	}, nil); err != nil {
+		t.Fatalf("initial build error = %v", err)
+	}
+
+	// An empty fetch must not wipe the existing table
+	if err := buildStationsDatabase(db, nil, nil); err == nil {
+		t.Error("Expected error when rebuilding with no stations")
+	}
+	if count, err := Count(dbPath); err != nil || count != 1 {
+		t.Errorf("Count() after failed rebuild = %d, %v; want 1, nil", count, err)
+	}
+
+	// A successful rebuild replaces stale rows
+	if err := buildStationsDatabase(db, []Station{
+		{ID: "NEW1", Name: "Station One", State: "MA", Latitude: 10.0, Longitude: -10.0},
+		{ID: "NEW2", Name: "Station Two", State: "CA", Latitude: 20.0, Longitude: -20.0},
+	}, nil); err != nil {
+		t.Fatalf("rebuild error = %v", err)
+	}
+	if count, err := Count(dbPath); err != nil || count != 2 {
+		t.Errorf("Count() after rebuild = %d, %v; want 2, nil", count, err)
+	}
+	var exists bool
+	if err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM tide_stations WHERE id = 'OLD')").Scan(&exists); err != nil || exists {
+		t.Errorf("stale station still present (err: %v)", err)
+	}
+}
@@ -2,11 +2,12 @@ package stations
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"math"
 	"sort"
-	"sync"
 
+	"github.com/ngmaloney/marine-terminal/internal/database"
 	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
 	_ "modernc.org/sqlite"
 )
@@ -22,32 +23,29 @@ type TideStationInfo struct {
 }
 
 var (
-	db   *sql.DB
-	once sync.Once
-	initErr error
+	// conn is the shared connection used by GetDB
+	conn = database.NewConn(func(dbPath string) (*sql.DB, error) {
+		// Provision database if it doesn't exist
+		if err := ProvisionStationsDatabase(dbPath, nil); err != nil {
+			return nil, err
+		}
+		return database.OpenTuned(dbPath)
+	})
 
 	// GetDB is a function variable to allow mocking in tests
 	GetDB = func(dbPath string) (*sql.DB, error) {
-		once.Do(func() {
-			// Provision database if it doesn't exist
-			initErr = ProvisionStationsDatabase(dbPath, nil)
-			if initErr != nil {
-				return
-			}
-
-			db, initErr = sql.Open("sqlite", dbPath)
-			if initErr != nil {
-				return
-			}
-			// Set pragmas for performance
-			_, _ = db.Exec("PRAGMA journal_mode=WAL")
-			_, _ = db.Exec("PRAGMA synchronous=NORMAL")
-			_, _ = db.Exec("PRAGMA cache_size=10000")
-		})
-		return db, initErr
+		return conn.Get(dbPath)
 	}
 )
 
+// ResetDB closes the shared connection so the next GetDB opens the database again
+func ResetDB() error {
+	return conn.Reset()
+}
+
+// ErrNoNearbyStations is returned by FindNearbyStations when no station is within range
+var ErrNoNearbyStations = errors.New("no tide stations found")
+
 // FindNearbyStations finds tide stations near the given coordinates within a max distance.
 func FindNearbyStations(dbPath string, lat, lon float64, maxDistanceMiles float64) ([]TideStationInfo, error) {
 	db, err := GetDB(dbPath)
@@ -102,7 +100,7 @@ func FindNearbyStations(dbPath string, lat, lon float64, maxDistanceMiles float6
 	}
 
 	if len(potentialStations) == 0 {
-		return nil, fmt.Errorf("no tide stations found near %.4f, %.4f within %.1f miles", lat, lon, maxDistanceMiles)
+		return nil, fmt.Errorf("%w near %.4f, %.4f within %.1f miles", ErrNoNearbyStations, lat, lon, maxDistanceMiles)
 	}
 
 	// Sort by distance to find the nearest
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+// alertLogSize caps how many alerts the session's alert log remembers
+const alertLogSize = 20
+
+// loggedAlert is a marine alert seen earlier in the session, kept so it can still be
+// listed once it expires or is lifted
+type loggedAlert struct {
+	zone  string
+	alert models.Alert
+}
+
+// alertLogKey identifies an alert across fetches: by ID, or by event and headline for
+// alerts without one
+func alertLogKey(a models.Alert) string {
+	if a.ID != "" {
+		return a.ID
+	}
+	return a.Event + "\x00" + a.Headline
+}
+
+// logAlerts adds the marine alerts fetched for zone to log, replacing earlier copies
+// of the same alert, and drops the oldest entries beyond alertLogSize
+func logAlerts(log []loggedAlert, zone string, alerts *models.AlertData) []loggedAlert {
+	if alerts == nil {
+		return log
+	}
+	for _, a := range alerts.Alerts {
+		if !a.IsMarine() {
+			continue
+		}
+		entry := loggedAlert{zone: zone, alert: a}
+		replaced := false
+		for i, logged := range log {
+			if logged.zone == zone && alertLogKey(logged.alert) == alertLogKey(a) {
+				log[i] = entry
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			log = append(log, entry)
+		}
+	}
+	if len(log) > alertLogSize {
+		log = append([]loggedAlert(nil), log[len(log)-alertLogSize:]...)
+	}
+	return log
+}
+
+// recentlyExpired returns the logged alerts for zone that are no longer active: past
+// their expiry, or missing from the current alerts because NOAA lifted them. The
+// most recently seen come first. Without current alerts, e.g. after a failed fetch,
+// nothing can be told to have been lifted and it returns nothing.
+func recentlyExpired(log []loggedAlert, zone string, current *models.AlertData, clock models.Clock) []models.Alert {
+	if current == nil {
+		return nil
+	}
+	active := make(map[string]bool)
+	for _, a := range current.ActiveMarineAlertsAt(clock) {
+		active[alertLogKey(a)] = true
+	}
+	var expired []models.Alert
+	for i := len(log) - 1; i >= 0; i-- {
+		if log[i].zone == zone && !active[alertLogKey(log[i].alert)] {
+			expired = append(expired, log[i].alert)
+		}
+	}
+	return expired
+}
+
+// formatRecentlyExpired lists expired alerts struck through, each with when it expired,
+// or "lifted" for alerts withdrawn before their expiry
+func formatRecentlyExpired(st styles, expired []models.Alert, now time.Time) string {
+	if len(expired) == 0 {
+		return st.muted.Render("No recently expired alerts")
+	}
+	lines := []string{st.label.Render("Recently expired:")}
+	for _, a := range expired {
+		when := "lifted"
+		if !a.Expires.IsZero() && !a.Expires.After(now) {
+			when = "expired " + formatRelativeTime(a.Expires, now)
+		}
+		lines = append(lines, st.muted.Strikethrough(true).Render(a.Event)+st.muted.Render(st.text(fmt.Sprintf(" — %s", when))))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+// alertNotifier is how new severe alerts are announced; the zero value stays quiet
+type alertNotifier struct {
+	bell    bool   // Ring the terminal bell
+	command string // Shell command to run, e.g. to send a desktop notification
+}
+
+// enabled reports whether new alerts are announced at all
+func (n alertNotifier) enabled() bool {
+	return n.bell || n.command != ""
+}
+
+// alertBellMsg rings the terminal bell. The bell goes out with the view, drawn with a
+// bell character for bellDuration, so it's written by the program's renderer rather
+// than racing it for the terminal.
+type alertBellMsg struct{}
+
+// bellDoneMsg takes the bell character rung as bell number bell out of the view
+type bellDoneMsg struct {
+	bell int
+}
+
+// bellDuration is how long the view carries the bell character, long enough for the
+// renderer to draw a frame with it
+const bellDuration = 250 * time.Millisecond
+
+// runAlertCommand runs command through the shell with env added to its environment.
+// It's a variable so tests can replace it.
+var runAlertCommand = func(command string, env []string) error {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Env = append(os.Environ(), env...)
+	return cmd.Run()
+}
+
+// activeAlertKeys returns the keys of the active marine alerts, see alertLogKey
+func activeAlertKeys(alerts *models.AlertData, clock models.Clock) map[string]bool {
+	keys := make(map[string]bool)
+	for _, a := range alerts.ActiveMarineAlertsAt(clock) {
+		keys[alertLogKey(a)] = true
+	}
+	return keys
+}
+
+// newSevereAlerts returns the active severe and extreme marine alerts that weren't
+// among the previously active ones in seen
+func newSevereAlerts(seen map[string]bool, alerts *models.AlertData, clock models.Clock) []models.Alert {
+	var fresh []models.Alert
+	for _, a := range alerts.ActiveMarineAlertsAt(clock) {
+		if a.Severity.Rank() >= models.SeveritySevere.Rank() && !seen[alertLogKey(a)] {
+			fresh = append(fresh, a)
+		}
+	}
+	return fresh
+}
+
+// notifyNewAlerts announces new alerts for zone with notifier: the command once per
+// alert with MARINE_ALERT_ZONE, MARINE_ALERT_EVENT and MARINE_ALERT_HEADLINE set, then
+// one bell through alertBellMsg. A failing command is ignored.
+func notifyNewAlerts(notifier alertNotifier, zone string, alerts []models.Alert) tea.Cmd {
+	if !notifier.enabled() || len(alerts) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		if notifier.command != "" {
+			for _, a := range alerts {
+				_ = runAlertCommand(notifier.command, []string{
+					"MARINE_ALERT_ZONE=" + zone,
+					"MARINE_ALERT_EVENT=" + a.Event,
+					"MARINE_ALERT_HEADLINE=" + a.Headline,
+				})
+			}
+		}
+		if notifier.bell {
+			return alertBellMsg{}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
+)
+
+func TestNewSevereAlerts(t *testing.T) {
+	now := time.Date(2025, 11, 26, 12, 0, 0, 0, time.UTC)
+	clock := models.FixedClock(now)
+	alert := func(id, event string, severity models.AlertSeverity) models.Alert {
+		return models.Alert{ID: id, Event: event, Severity: severity, Onset: now.Add(-time.Hour), Expires: now.Add(time.Hour)}
+	}
+	gale := alert("gale", "Gale Warning", models.SeveritySevere)
+	storm := alert("storm", "Storm Warning", models.SeverityExtreme)
+	advisory := alert("sca", "Small Craft Advisory", models.SeverityModerate)
+	expired := alert("old", "Hazardous Seas Warning", models.SeveritySevere)
+	expired.Expires = now.Add(-time.Minute)
+
+	tests := []struct {
+		name   string
+		seen   map[string]bool
+		alerts []models.Alert
+		want   []string
+	}{
+		{"no alerts", map[string]bool{}, nil, nil},
+		{"new severe alert", map[string]bool{"sca": true}, []models.Alert{advisory, gale}, []string{"gale"}},
+		{"already seen", map[string]bool{"gale": true}, []models.Alert{gale}, nil},
+		{"extreme alert joins a seen one", map[string]bool{"gale": true}, []models.Alert{gale, storm}, []string{"storm"}},
+		{"new advisory isn't severe enough", map[string]bool{}, []models.Alert{advisory}, nil},
+		{"expired alert", map[string]bool{}, []models.Alert{expired}, nil},
+		{"reissued after being lifted", map[string]bool{"sca": true}, []models.Alert{gale}, []string{"gale"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, a := range newSevereAlerts(tt.seen, &models.AlertData{Alerts: tt.alerts}, clock) {
+				got = append(got, a.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("newSevereAlerts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModel_NotifiesNewSevereAlerts(t *testing.T) {
+	var commands [][]string
+	origCommand := runAlertCommand
+	runAlertCommand = func(command string, env []string) error {
+		commands = append(commands, append([]string{command}, env...))
+		return nil
+	}
+	t.Cleanup(func() { runAlertCommand = origCommand })
+
+	now := time.Date(2025, 11, 26, 12, 0, 0, 0, time.UTC)
+	alerts := func(ids ...string) zoneAlertsFetchedMsg {
+		data := &models.AlertData{}
+		for _, id := range ids {
+			data.Alerts = append(data.Alerts, models.Alert{
+				ID: id, Event: "Gale Warning", Headline: "Gale Warning " + id, Severity: models.SeveritySevere,
+				Onset: now.Add(-time.Hour), Expires: now.Add(time.Hour),
+			})
+		}
+		return zoneAlertsFetchedMsg{alerts: data}
+	}
+	m := NewModel("", "", "").WithClock(models.FixedClock(now)).WithAlertNotifications(true, "notify")
+	m.state = StateDisplay
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+
+	// New alerts are also flashed; expire the flash at once so running it doesn't wait
+	origFlash := changeFlashDuration
+	changeFlashDuration = 0
+	t.Cleanup(func() { changeFlashDuration = origFlash })
+
+	// Runs the commands from an alerts update, if any, counting the bells they ring
+	bells := 0
+	ring := func(msg tea.Msg) {
+		if _, ok := msg.(alertBellMsg); ok {
+			bells++
+			updated, _ := m.Update(msg)
+			m = updated.(Model)
+		}
+	}
+	update := func(msg zoneAlertsFetchedMsg) {
+		t.Helper()
+		updated, cmd := m.Update(msg)
+		m = updated.(Model)
+		if cmd == nil {
+			return
+		}
+		msgs := []tea.Msg{cmd()}
+		if batch, ok := msgs[0].(tea.BatchMsg); ok {
+			msgs = nil
+			for _, c := range batch {
+				msgs = append(msgs, c())
+			}
+		}
+		for _, msg := range msgs {
+			ring(msg)
+		}
+	}
+
+	update(alerts("a"))
+	if bells != 0 {
+		t.Error("alerts already active when the zone is first shown shouldn't ring the bell")
+	}
+	update(alerts("a"))
+	if bells != 0 {
+		t.Error("a refresh without new alerts shouldn't ring the bell")
+	}
+	update(alerts("a", "b"))
+	if bells != 1 || len(commands) != 1 {
+		t.Fatalf("a new severe alert should ring the bell and run the command once, got %d bells and %d commands", bells, len(commands))
+	}
+	if want := []string{"notify", "MARINE_ALERT_ZONE=ANZ254", "MARINE_ALERT_EVENT=Gale Warning", "MARINE_ALERT_HEADLINE=Gale Warning b"}; !slices.Equal(commands[0], want) {
+		t.Errorf("command = %v, want %v", commands[0], want)
+	}
+
+	// The bell goes out with the view until the latest bell is done
+	m.width, m.height = 100, 40
+	if !strings.HasPrefix(m.View(), "\a") {
+		t.Error("the view should carry the bell character after a bell")
+	}
+	updated, _ := m.Update(bellDoneMsg{bell: m.bell - 1})
+	if !strings.HasPrefix(updated.(Model).View(), "\a") {
+		t.Error("an earlier bell being done shouldn't end the latest one")
+	}
+	updated, _ = m.Update(bellDoneMsg{bell: m.bell})
+	m = updated.(Model)
+	if strings.HasPrefix(m.View(), "\a") {
+		t.Error("the view shouldn't carry the bell character once the bell is done")
+	}
+
+	// A failed fetch keeps the baseline
+	update(zoneAlertsFetchedMsg{err: errors.New("timeout")})
+	update(alerts("a", "b"))
+	if bells != 1 {
+		t.Errorf("known alerts after a failed fetch shouldn't ring the bell, got %d bells", bells)
+	}
+
+	// Another zone starts a new baseline
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ255", Name: "Buzzards Bay"}
+	update(alerts("c"))
+	if bells != 1 {
+		t.Errorf("switching zones shouldn't ring the bell, got %d bells", bells)
+	}
+
+	// Off by default
+	m = NewModel("", "", "").WithClock(models.FixedClock(now))
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+	update(alerts("a"))
+	update(alerts("a", "b"))
+	if bells != 1 || len(commands) != 1 {
+		t.Errorf("notifications should be off unless configured, got %d bells and %d commands", bells, len(commands))
+	}
+}
@@ -1 +1,95 @@
-package ui
\ No newline at end of file
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+// formatRelativeTime describes t relative to now, e.g. "in 3h", "1h 20m ago" or "now".
+// Durations are truncated, so something 59 minutes away is "in 59m", not "in 1h".
+func formatRelativeTime(t, now time.Time) string {
+	d := t.Sub(now)
+	past := d < 0
+	if past {
+		d = -d
+	}
+	if d < time.Minute {
+		return "now"
+	}
+
+	var span string
+	switch days, hours, minutes := int(d/(24*time.Hour)), int(d/time.Hour)%24, int(d/time.Minute)%60; {
+	case days > 0 && hours > 0:
+		span = fmt.Sprintf("%dd %dh", days, hours)
+	case days > 0:
+		span = fmt.Sprintf("%dd", days)
+	case hours > 0 && minutes > 0:
+		span = fmt.Sprintf("%dh %dm", hours, minutes)
+	case hours > 0:
+		span = fmt.Sprintf("%dh", hours)
+	default:
+		span = fmt.Sprintf("%dm", minutes)
+	}
+
+	if past {
+		return span + " ago"
+	}
+	return "in " + span
+}
+
+// alertTimes describes when an alert started (or starts) and expires relative to now,
+// e.g. "Started 1h ago • expires in 3h", followed by the exact times if exact is set
+func alertTimes(st styles, a models.Alert, now time.Time, exact bool) string {
+	var parts, absolute []string
+	if !a.Onset.IsZero() {
+		verb := "Started"
+		if a.Onset.After(now) {
+			verb = "Starts"
+		}
+		parts = append(parts, verb+" "+formatRelativeTime(a.Onset, now))
+		absolute = append(absolute, st.label.Render("Onset: ")+st.muted.Render(st.formatTime(a.Onset, "Jan 2, 3:04 PM")))
+	}
+	if !a.Expires.IsZero() {
+		verb := "expires"
+		if len(parts) == 0 {
+			verb = "Expires"
+		}
+		parts = append(parts, verb+" "+formatRelativeTime(a.Expires, now))
+		absolute = append(absolute, st.label.Render("Expires: ")+st.muted.Render(st.formatTime(a.Expires, "Jan 2, 3:04 PM")))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+
+	line := st.muted.Render(st.text(strings.Join(parts, " • ")))
+	if exact {
+		line += "\n" + strings.Join(absolute, "  ")
+	}
+	return line
+}
+
+// alertAreas lists the areas an alert covers, e.g. "Areas: Cape Cod Bay, Nantucket Sound",
+// highlighting the one matching area, the selected zone's name. Lists wider than width
+// wrap; a width of 0 leaves them on one line.
+func alertAreas(st styles, a models.Alert, area string, width int) string {
+	if len(a.Areas) == 0 {
+		return ""
+	}
+	names := make([]string, len(a.Areas))
+	for i, name := range a.Areas {
+		if area != "" && strings.EqualFold(name, area) {
+			names[i] = st.warning.Bold(true).Render(name)
+		} else {
+			names[i] = st.muted.Render(name)
+		}
+	}
+	line := st.label.Render("Areas: ") + strings.Join(names, st.muted.Render(", "))
+	if width > 0 {
+		line = lipgloss.NewStyle().Width(width).Render(line)
+	}
+	return line
+}
@@ -0,0 +1,259 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
+)
+
+func TestFormatRelativeTime(t *testing.T) {
+	now := time.Date(2025, 11, 27, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		offset time.Duration
+		want   string
+	}{
+		{"same instant", 0, "now"},
+		{"under a minute ahead", 59 * time.Second, "now"},
+		{"under a minute ago", -59 * time.Second, "now"},
+		{"one minute ahead", time.Minute, "in 1m"},
+		{"just under an hour", time.Hour - time.Second, "in 59m"},
+		{"exactly an hour", time.Hour, "in 1h"},
+		{"hours and minutes", 3*time.Hour + 20*time.Minute, "in 3h 20m"},
+		{"hours ago", -time.Hour, "1h ago"},
+		{"just under a day", 24*time.Hour - time.Minute, "in 23h 59m"},
+		{"exactly a day", 24 * time.Hour, "in 1d"},
+		{"days and hours ago", -(2*24*time.Hour + 5*time.Hour + 30*time.Minute), "2d 5h ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatRelativeTime(now.Add(tt.offset), now); got != tt.want {
+				t.Errorf("formatRelativeTime(now%+v) = %q, want %q", tt.offset, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatAlerts_RelativeTimes(t *testing.T) {
+	now := time.Date(2025, 11, 27, 12, 0, 0, 0, time.UTC)
+	clock := models.FixedClock(now)
+	st := newStyles(DefaultTheme())
+
+	current := &models.AlertData{Alerts: []models.Alert{{
+		Event: "Small Craft Advisory", Severity: models.SeverityMinor,
+		Onset: now.Add(-time.Hour), Expires: now.Add(3 * time.Hour),
+	}}}
+	out := formatAlerts(st, current, clock, false, false, "", nil, 0)
+	if !strings.Contains(out, "Started 1h ago • expires in 3h") {
+		t.Errorf("alert should show relative onset and expiry, got:\n%s", out)
+	}
+	if strings.Contains(out, "Nov 27") {
+		t.Errorf("exact times should only be shown on request, got:\n%s", out)
+	}
+
+	exact := formatAlerts(st, current, clock, false, true, "", nil, 0)
+	if !strings.Contains(exact, "Nov 27, 11:00 AM") || !strings.Contains(exact, "Nov 27, 3:00 PM") {
+		t.Errorf("expanded view should show the exact onset and expiry, got:\n%s", exact)
+	}
+
+	// Alerts that haven't started aren't listed yet, but the helper reads naturally for them
+	upcoming := models.Alert{Onset: now.Add(6 * time.Hour), Expires: now.Add(30 * time.Hour)}
+	if got := alertTimes(st, upcoming, now, false); !strings.Contains(got, "Starts in 6h • expires in 1d 6h") {
+		t.Errorf("alertTimes() for an upcoming alert = %q", got)
+	}
+
+	noOnset := &models.AlertData{Alerts: []models.Alert{{
+		Event: "Special Marine Warning", Severity: models.SeveritySevere, Expires: now.Add(45 * time.Minute),
+	}}}
+	if out := formatAlerts(st, noOnset, clock, false, false, "", nil, 0); !strings.Contains(out, "Expires in 45m") {
+		t.Errorf("alert without an onset should only show its expiry, got:\n%s", out)
+	}
+}
+
+func TestModel_AlertTimesToggle(t *testing.T) {
+	m := NewModel("", "", "")
+	m.state = StateDisplay
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+	if !updated.(Model).exactAlertTimes {
+		t.Error("A should show exact alert times")
+	}
+	updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+	if updated.(Model).exactAlertTimes {
+		t.Error("A again should hide exact alert times")
+	}
+}
+
+func TestModel_AlertLog(t *testing.T) {
+	now := time.Date(2025, 11, 27, 12, 0, 0, 0, time.UTC)
+	gale := models.Alert{ID: "gale", Event: "Gale Warning", Headline: "Gale Warning until 1 PM", Severity: models.SeveritySevere, Onset: now.Add(-time.Hour), Expires: now.Add(time.Hour)}
+	sca := models.Alert{ID: "sca", Event: "Small Craft Advisory", Headline: "SCA until 6 PM", Severity: models.SeverityModerate, Onset: now.Add(-time.Hour), Expires: now.Add(6 * time.Hour)}
+
+	m := NewModel("", "", "").WithClock(models.FixedClock(now))
+	m.state = StateDisplay
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+	updated, _ := m.Update(zoneAlertsFetchedMsg{alerts: &models.AlertData{Alerts: []models.Alert{gale, sca}}})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("L")})
+	m = updated.(Model)
+	if !m.showAlertLog {
+		t.Fatal("L should show recently expired alerts")
+	}
+	if out := m.renderAlertSimple(); !strings.Contains(out, "No recently expired alerts") {
+		t.Errorf("active alerts shouldn't be listed as expired, got:\n%s", out)
+	}
+
+	// Once the gale warning's expiry passes it moves into the recent log
+	m = m.WithClock(models.FixedClock(now.Add(90 * time.Minute)))
+	expired := recentlyExpired(m.alertLog, "ANZ254", m.alerts, m.clock)
+	if len(expired) != 1 || expired[0].ID != "gale" {
+		t.Fatalf("recentlyExpired() = %v, want the gale warning", expired)
+	}
+	out := m.renderAlertSimple()
+	if !strings.Contains(out, "Recently expired:") || !strings.Contains(out, "expired 30m ago") {
+		t.Errorf("alerts pane should list the expired gale warning, got:\n%s", out)
+	}
+
+	// A refresh that no longer includes the advisory lists it as lifted
+	updated, _ = m.Update(zoneAlertsFetchedMsg{alerts: &models.AlertData{}})
+	m = updated.(Model)
+	expired = recentlyExpired(m.alertLog, "ANZ254", m.alerts, m.clock)
+	if len(expired) != 2 || expired[0].ID != "sca" {
+		t.Fatalf("recentlyExpired() = %v, want the lifted advisory first", expired)
+	}
+	if out := m.renderAlertSimple(); !strings.Contains(out, "Small Craft Advisory") || !strings.Contains(out, "lifted") {
+		t.Errorf("alerts pane should list the lifted advisory, got:\n%s", out)
+	}
+
+	// Another zone has its own log
+	if expired := recentlyExpired(m.alertLog, "ANZ255", nil, m.clock); len(expired) != 0 {
+		t.Errorf("recentlyExpired() for another zone = %v, want none", expired)
+	}
+}
+
+func TestModel_AlertLogAfterFailedFetch(t *testing.T) {
+	now := time.Date(2025, 11, 27, 12, 0, 0, 0, time.UTC)
+	sca := models.Alert{ID: "sca", Event: "Small Craft Advisory", Headline: "SCA until 6 PM", Severity: models.SeverityModerate, Onset: now.Add(-time.Hour), Expires: now.Add(6 * time.Hour)}
+
+	m := NewModel("", "", "").WithClock(models.FixedClock(now))
+	m.state = StateDisplay
+	m.showAlertLog = true
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+	updated, _ := m.Update(zoneAlertsFetchedMsg{alerts: &models.AlertData{Alerts: []models.Alert{sca}}})
+	m = updated.(Model)
+
+	// Coming back to the zone with no alerts loaded, a failed fetch says nothing about
+	// whether the advisory was lifted
+	m.alerts = nil
+	updated, _ = m.Update(zoneAlertsFetchedMsg{err: errors.New("timeout")})
+	m = updated.(Model)
+	if out := m.renderAlertSimple(); strings.Contains(out, "lifted") {
+		t.Errorf("a failed fetch shouldn't list alerts as lifted, got:\n%s", out)
+	}
+
+	// Once a fetch succeeds without it, it was lifted
+	updated, _ = m.Update(zoneAlertsFetchedMsg{alerts: &models.AlertData{}})
+	m = updated.(Model)
+	if out := m.renderAlertSimple(); !strings.Contains(out, "lifted") {
+		t.Errorf("a successful fetch without the advisory should list it as lifted, got:\n%s", out)
+	}
+}
+
+func TestLogAlerts_Capped(t *testing.T) {
+	var log []loggedAlert
+	for i := 0; i < alertLogSize+5; i++ {
+		log = logAlerts(log, "ANZ254", &models.AlertData{Alerts: []models.Alert{{ID: fmt.Sprint(i), Event: "Gale Warning"}}})
+	}
+	if len(log) != alertLogSize || log[0].alert.ID != "5" {
+		t.Errorf("log has %d entries starting at %q, want %d starting at \"5\"", len(log), log[0].alert.ID, alertLogSize)
+	}
+
+	// Seeing an alert again updates it in place
+	log = logAlerts(log, "ANZ254", &models.AlertData{Alerts: []models.Alert{{ID: "5", Event: "Gale Warning", Headline: "updated"}}})
+	if len(log) != alertLogSize || log[0].alert.Headline != "updated" {
+		t.Errorf("re-logging an alert should replace it, got %d entries, first %v", len(log), log[0].alert)
+	}
+}
+
+func TestAlertAreas(t *testing.T) {
+	orig := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	t.Cleanup(func() { lipgloss.SetColorProfile(orig) })
+
+	st := newStyles(DefaultTheme())
+	a := models.Alert{Event: "Small Craft Advisory", Areas: []string{"Vineyard Sound", "Nantucket Sound", "Buzzards Bay"}}
+
+	plain := ansi.Strip(alertAreas(st, a, "", 0))
+	if plain != "Areas: Vineyard Sound, Nantucket Sound, Buzzards Bay" {
+		t.Errorf("alertAreas() = %q, want the areas as a comma list", plain)
+	}
+
+	highlighted := alertAreas(st, a, "nantucket sound", 0)
+	if want := st.warning.Bold(true).Render("Nantucket Sound"); !strings.Contains(highlighted, want) {
+		t.Errorf("alertAreas() should highlight the selected zone's area, got %q", highlighted)
+	}
+	if strings.Contains(highlighted, st.warning.Bold(true).Render("Buzzards Bay")) {
+		t.Error("alertAreas() should only highlight the selected zone's area")
+	}
+
+	if got := alertAreas(st, models.Alert{Event: "Gale Warning"}, "Nantucket Sound", 0); got != "" {
+		t.Errorf("alertAreas() without areas = %q, want empty", got)
+	}
+}
+
+// TestModel_AlertAreaForPort tests that a port's zone is matched against alert areas by
+// the zone's name rather than the port's
+func TestModel_AlertAreaForPort(t *testing.T) {
+	orig := lookupZoneName
+	t.Cleanup(func() { lookupZoneName = orig })
+	var looked []string
+	lookupZoneName = func(code string) tea.Cmd {
+		looked = append(looked, code)
+		return nil
+	}
+
+	port := models.Port{Name: "Home", MarineZoneID: "ANZ254", Latitude: 41.68, Longitude: -69.95}
+
+	// The zone's name comes from the nearby zones when the port is one of them
+	m := NewModel("", "", "")
+	m.zones = []zonelookup.ZoneInfo{{Code: "ANZ254", Name: "Nantucket Sound"}}
+	m, _ = m.loadPort(port)
+	if got := m.alertArea(); got != "Nantucket Sound" {
+		t.Errorf("alertArea() = %q, want the zone's name", got)
+	}
+	if len(looked) != 0 {
+		t.Errorf("looked up %v, want the nearby zone's name used", looked)
+	}
+
+	// Otherwise it's looked up by code
+	m = NewModel("", "", "")
+	m, _ = m.loadPort(port)
+	if len(looked) != 1 || looked[0] != "ANZ254" {
+		t.Fatalf("looked up %v, want ANZ254", looked)
+	}
+	if got := m.alertArea(); got != "" {
+		t.Errorf("alertArea() before the lookup = %q, want none rather than the port's name", got)
+	}
+	updated, _ := m.Update(zoneNameMsg{zoneCode: "ANZ254", name: "Nantucket Sound"})
+	m = updated.(Model)
+	if got := m.alertArea(); got != "Nantucket Sound" {
+		t.Errorf("alertArea() = %q, want the looked up name", got)
+	}
+
+	// A nearby zone cycled to is shown under its own name
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ255", Name: "Vineyard Sound"}
+	if got := m.alertArea(); got != "Vineyard Sound" {
+		t.Errorf("alertArea() = %q, want the cycled zone's name", got)
+	}
+}
@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+// changeFlashDuration is how long the indicators of what a refresh changed are shown.
+// It's a variable so tests can replace it.
+var changeFlashDuration = 15 * time.Second
+
+// conditionsSnapshot is the current conditions and alerts last shown for a zone, kept
+// to tell what the next refresh changed
+type conditionsSnapshot struct {
+	zone       string
+	hasWeather bool
+	wind       models.WindData
+	seas       models.SeaState
+	alerts     map[string]bool // Keys of the active alerts (see alertLogKey); nil until alerts arrive
+}
+
+// fieldChange is how a value changed: a trend arrow and the value it had before. The
+// zero value means it didn't change.
+type fieldChange struct {
+	arrow string
+	was   string
+}
+
+// changed reports whether the value changed
+func (c fieldChange) changed() bool {
+	return c.arrow != ""
+}
+
+// conditionChanges is what changed between two snapshots of a zone's conditions
+type conditionChanges struct {
+	wind      fieldChange
+	seas      fieldChange
+	newAlerts map[string]bool // Keys of the alerts that weren't active before
+}
+
+// empty reports whether nothing changed
+func (c conditionChanges) empty() bool {
+	return !c.wind.changed() && !c.seas.changed() && len(c.newAlerts) == 0
+}
+
+// changesExpiredMsg clears the change indicators flashed after refresh number flash
+type changesExpiredMsg struct {
+	flash int
+}
+
+// diffConditions compares two snapshots of the same zone. Weather is only compared
+// when both have it and alerts when both have them, so the first data seen for a zone
+// doesn't count as a change.
+func diffConditions(prev, cur conditionsSnapshot) conditionChanges {
+	var changes conditionChanges
+	if prev.zone != cur.zone {
+		return changes
+	}
+	if prev.hasWeather && cur.hasWeather {
+		if arrow := windChange(prev.wind, cur.wind); arrow != "" {
+			changes.wind = fieldChange{arrow: arrow, was: formatWind(prev.wind)}
+		}
+		if prev.seas.HeightMin != cur.seas.HeightMin || prev.seas.HeightMax != cur.seas.HeightMax {
+			if arrow := seasTrend(prev.seas, cur.seas); arrow != "" {
+				changes.seas = fieldChange{arrow: arrow, was: formatSeas(prev.seas)}
+			}
+		}
+	}
+	if prev.alerts != nil && cur.alerts != nil {
+		for key := range cur.alerts {
+			if !prev.alerts[key] {
+				if changes.newAlerts == nil {
+					changes.newAlerts = make(map[string]bool)
+				}
+				changes.newAlerts[key] = true
+			}
+		}
+	}
+	return changes
+}
+
+// windChange marks how the wind changed from prev to cur: building or easing when its
+// speed changed, trendShifted when only its direction did, and building or easing
+// again by the gusts when only they changed. It returns "" if nothing did.
+func windChange(prev, cur models.WindData) string {
+	if arrow := windTrend(prev, cur); arrow != "" && arrow != trendHoldSteady {
+		return arrow
+	}
+	if prev.Direction != cur.Direction {
+		return trendShifted
+	}
+	switch prevGust, curGust := gustSpeed(prev), gustSpeed(cur); {
+	case curGust > prevGust:
+		return trendBuilding
+	case curGust < prevGust:
+		return trendSubsiding
+	}
+	return ""
+}
+
+// gustSpeed is the wind's gust speed, or 0 without gusts
+func gustSpeed(wind models.WindData) float64 {
+	if !wind.HasGust {
+		return 0
+	}
+	return wind.GustSpeed
+}
+
+// snapshotFor returns the snapshot to update with the zone's latest data: the last one
+// if it's of the same zone, else a fresh one
+func (m Model) snapshotFor(zone string) conditionsSnapshot {
+	if m.snapshot.zone == zone {
+		return m.snapshot
+	}
+	return conditionsSnapshot{zone: zone}
+}
+
+// recordWeather compares freshly fetched current conditions with the last ones shown
+// for the zone and flashes what changed
+func (m Model) recordWeather(conditions *models.MarineConditions) (Model, tea.Cmd) {
+	if m.selectedZone == nil || conditions == nil {
+		return m, nil
+	}
+	cur := m.snapshotFor(m.selectedZone.Code)
+	cur.hasWeather, cur.wind, cur.seas = true, conditions.Wind, conditions.Seas
+	changes := diffConditions(m.snapshot, cur)
+	m.snapshot = cur
+	return m.flashChanges(func(c *conditionChanges) {
+		c.wind, c.seas = changes.wind, changes.seas
+	})
+}
+
+// recordAlerts compares freshly fetched alerts with the last ones shown for the zone,
+// flashes the new ones and announces any new severe ones. The first alerts seen for a
+// zone only set the baseline.
+func (m Model) recordAlerts(alerts *models.AlertData) (Model, tea.Cmd) {
+	if m.selectedZone == nil || alerts == nil {
+		return m, nil
+	}
+	cur := m.snapshotFor(m.selectedZone.Code)
+	var notify tea.Cmd
+	if cur.alerts != nil {
+		notify = notifyNewAlerts(m.alertNotifier, cur.zone, newSevereAlerts(cur.alerts, alerts, m.clock))
+	}
+	cur.alerts = activeAlertKeys(alerts, m.clock)
+	changes := diffConditions(m.snapshot, cur)
+	m.snapshot = cur
+	m, flash := m.flashChanges(func(c *conditionChanges) {
+		c.newAlerts = changes.newAlerts
+	})
+	return m, tea.Batch(flash, notify)
+}
+
+// flashChanges applies update to the changes on display, clearing those of another
+// zone first, and returns a command clearing them after changeFlashDuration
+func (m Model) flashChanges(update func(*conditionChanges)) (Model, tea.Cmd) {
+	if m.changesZone != m.snapshot.zone {
+		m.changes = conditionChanges{}
+		m.changesZone = m.snapshot.zone
+	}
+	update(&m.changes)
+	if m.changes.empty() {
+		return m, nil
+	}
+	m.changeFlash++
+	flash := m.changeFlash
+	return m, tea.Tick(changeFlashDuration, func(time.Time) tea.Msg {
+		return changesExpiredMsg{flash: flash}
+	})
+}
+
+// changeNote renders the indicator shown after a changed value, e.g. "↑ was SW 10 kt"
+func changeNote(st styles, c fieldChange) string {
+	if !c.changed() {
+		return ""
+	}
+	return "  " + st.warning.Render(st.text(c.arrow+" was "+c.was))
+}
@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
+)
+
+func TestDiffConditions(t *testing.T) {
+	sw := models.WindData{Direction: "SW", SpeedMin: 10, SpeedMax: 15}
+	seas := models.SeaState{HeightMin: 2, HeightMax: 3}
+	prev := conditionsSnapshot{zone: "ANZ254", hasWeather: true, wind: sw, seas: seas, alerts: map[string]bool{"gale": true}}
+
+	tests := []struct {
+		name      string
+		before    *conditionsSnapshot // prev if nil
+		cur       conditionsSnapshot
+		wantWind  fieldChange
+		wantSeas  fieldChange
+		newAlerts []string
+	}{
+		{
+			name: "unchanged",
+			cur:  prev,
+		},
+		{
+			name:     "wind building and seas subsiding",
+			cur:      conditionsSnapshot{zone: "ANZ254", hasWeather: true, wind: models.WindData{Direction: "SW", SpeedMin: 15, SpeedMax: 20}, seas: models.SeaState{HeightMin: 1, HeightMax: 2}},
+			wantWind: fieldChange{arrow: trendBuilding, was: "SW 10-15 kt"},
+			wantSeas: fieldChange{arrow: trendSubsiding, was: "2-3 ft"},
+		},
+		{
+			name:     "wind backing at the same speed",
+			cur:      conditionsSnapshot{zone: "ANZ254", hasWeather: true, wind: models.WindData{Direction: "S", SpeedMin: 10, SpeedMax: 15}, seas: seas},
+			wantWind: fieldChange{arrow: trendShifted, was: "SW 10-15 kt"},
+		},
+		{
+			name:     "wind shifting from calm",
+			before:   &conditionsSnapshot{zone: "ANZ254", hasWeather: true, wind: models.WindData{Direction: "SW"}},
+			cur:      conditionsSnapshot{zone: "ANZ254", hasWeather: true, wind: models.WindData{Direction: "N"}},
+			wantWind: fieldChange{arrow: trendShifted, was: "SW 0 kt"},
+		},
+		{
+			name:     "gusts picking up",
+			cur:      conditionsSnapshot{zone: "ANZ254", hasWeather: true, wind: models.WindData{Direction: "SW", SpeedMin: 10, SpeedMax: 15, GustSpeed: 25, HasGust: true}, seas: seas},
+			wantWind: fieldChange{arrow: trendBuilding, was: "SW 10-15 kt"},
+		},
+		{
+			name:     "gusts easing off",
+			before:   &conditionsSnapshot{zone: "ANZ254", hasWeather: true, wind: models.WindData{Direction: "SW", SpeedMin: 10, SpeedMax: 15, GustSpeed: 25, HasGust: true}, seas: seas},
+			cur:      conditionsSnapshot{zone: "ANZ254", hasWeather: true, wind: models.WindData{Direction: "SW", SpeedMin: 10, SpeedMax: 15, GustSpeed: 20, HasGust: true}, seas: seas},
+			wantWind: fieldChange{arrow: trendSubsiding, was: "SW 10-15 kt, gusts 25 kt"},
+		},
+		{
+			name:      "new alert",
+			cur:       conditionsSnapshot{zone: "ANZ254", hasWeather: true, wind: sw, seas: seas, alerts: map[string]bool{"gale": true, "sca": true}},
+			newAlerts: []string{"sca"},
+		},
+		{
+			name: "expired alert isn't a change",
+			cur:  conditionsSnapshot{zone: "ANZ254", hasWeather: true, wind: sw, seas: seas, alerts: map[string]bool{}},
+		},
+		{
+			name: "another zone",
+			cur:  conditionsSnapshot{zone: "ANZ255", hasWeather: true, wind: models.WindData{Direction: "N", SpeedMin: 25, SpeedMax: 30}, alerts: map[string]bool{"storm": true}},
+		},
+		{
+			name:   "no weather before",
+			before: &conditionsSnapshot{zone: "ANZ254"},
+			cur:    conditionsSnapshot{zone: "ANZ254", hasWeather: true, wind: models.WindData{Direction: "N", SpeedMin: 25, SpeedMax: 30}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := prev
+			if tt.before != nil {
+				before = *tt.before
+			}
+			got := diffConditions(before, tt.cur)
+			if got.wind != tt.wantWind || got.seas != tt.wantSeas {
+				t.Errorf("wind, seas changes = %+v, %+v; want %+v, %+v", got.wind, got.seas, tt.wantWind, tt.wantSeas)
+			}
+			if len(got.newAlerts) != len(tt.newAlerts) {
+				t.Fatalf("newAlerts = %v, want %v", got.newAlerts, tt.newAlerts)
+			}
+			for _, key := range tt.newAlerts {
+				if !got.newAlerts[key] {
+					t.Errorf("newAlerts = %v, want %s", got.newAlerts, key)
+				}
+			}
+			if got.empty() != (tt.wantWind == fieldChange{} && tt.wantSeas == fieldChange{} && len(tt.newAlerts) == 0) {
+				t.Errorf("empty() = %v for %+v", got.empty(), got)
+			}
+		})
+	}
+}
+
+func TestModel_FlashesChangedConditions(t *testing.T) {
+	now := time.Date(2025, 11, 26, 12, 0, 0, 0, time.UTC)
+	m := NewModel("", "", "").WithClock(models.FixedClock(now))
+	m.width, m.height = 100, 40
+	m.state = StateDisplay
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+
+	weather := func(min, max float64) zoneWeatherFetchedMsg {
+		conditions := &models.MarineConditions{Wind: models.WindData{Direction: "SW", SpeedMin: min, SpeedMax: max}, Seas: models.SeaState{HeightMin: 2, HeightMax: 3}}
+		forecast := &models.ThreeDayForecast{Periods: []models.MarineForecast{{PeriodName: "TODAY", Wind: conditions.Wind, Seas: conditions.Seas}}}
+		return zoneWeatherFetchedMsg{conditions: conditions, forecast: forecast}
+	}
+	alerts := &models.AlertData{Alerts: []models.Alert{{
+		ID: "sca", Event: "Small Craft Advisory", Headline: "Small Craft Advisory in effect", Severity: models.SeverityModerate,
+		Onset: now.Add(-time.Hour), Expires: now.Add(time.Hour),
+	}}}
+
+	updated, cmd := m.Update(weather(10, 15))
+	updated, _ = updated.(Model).Update(zoneAlertsFetchedMsg{alerts: &models.AlertData{}})
+	m = updated.(Model)
+	if cmd != nil || strings.Contains(m.renderWeatherSimple(), " was ") {
+		t.Fatal("the first conditions seen for a zone shouldn't be flashed as changes")
+	}
+
+	updated, cmd = m.Update(weather(15, 20))
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("a change should schedule the indicators to clear")
+	}
+	if out := m.renderWeatherSimple(); !strings.Contains(out, "SW 15-20 kt  ↑ was SW 10-15 kt") {
+		t.Errorf("weather should flash the wind change, got:\n%s", out)
+	}
+	updated, _ = m.Update(zoneAlertsFetchedMsg{alerts: alerts})
+	m = updated.(Model)
+	if out := m.renderAlertSimple(); !strings.Contains(out, "Small Craft Advisory  NEW") {
+		t.Errorf("alerts should mark the new advisory, got:\n%s", out)
+	}
+
+	// Only the latest flash clears the indicators
+	updated, _ = m.Update(changesExpiredMsg{flash: m.changeFlash - 1})
+	if m = updated.(Model); m.changes.empty() {
+		t.Error("an earlier flash expiring shouldn't clear newer changes")
+	}
+	updated, _ = m.Update(changesExpiredMsg{flash: m.changeFlash})
+	m = updated.(Model)
+	if out := m.renderWeatherSimple() + m.renderAlertSimple(); strings.Contains(out, " was ") || strings.Contains(out, "NEW") {
+		t.Errorf("indicators should clear when the flash expires, got:\n%s", out)
+	}
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/ngmaloney/marine-terminal/internal/geocoding"
+)
+
+// cityItem wraps one state's match for a city-only search for use in a list
+type cityItem struct {
+	location geocoding.Location
+}
+
+// FilterValue implements list.Item
+func (c cityItem) FilterValue() string {
+	return c.location.Name
+}
+
+// Title implements list.DefaultItem
+func (c cityItem) Title() string {
+	return c.location.Name
+}
+
+// Description implements list.DefaultItem
+func (c cityItem) Description() string {
+	return fmt.Sprintf("%.4f, %.4f", c.location.Latitude, c.location.Longitude)
+}
+
+// createCityList creates a list.Model for choosing between the places a city-only
+// search matched
+func createCityList(city string, candidates []geocoding.Location, width, height int, st styles) list.Model {
+	items := make([]list.Item, len(candidates))
+	for i, loc := range candidates {
+		items[i] = cityItem{location: loc}
+	}
+
+	delegate := list.NewDefaultDelegate()
+	l := st.newList(items, delegate, width, height)
+	l.Title = fmt.Sprintf("Which %s?", city)
+	l.SetShowHelp(true)
+	l.SetFilteringEnabled(true)
+	// '?' opens the application-wide help overlay instead of the list's full help
+	l.KeyMap.ShowFullHelp.SetEnabled(false)
+	l.KeyMap.CloseFullHelp.SetEnabled(false)
+
+	return l
+}
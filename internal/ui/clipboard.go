@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// writeClipboard puts text on the system clipboard.
+// It's a variable so tests can replace it.
+var writeClipboard = clipboard.WriteAll
+
+// copiedMsg is sent when a value has been copied to the clipboard, or copying failed
+type copiedMsg struct {
+	label string // What was copied, e.g. "zone code"
+	value string
+	err   error
+}
+
+// copyToClipboard copies value to the system clipboard
+func copyToClipboard(label, value string) tea.Cmd {
+	return func() tea.Msg {
+		return copiedMsg{label: label, value: value, err: writeClipboard(value)}
+	}
+}
+
+// copyNoteText is the help line confirmation for the last copy. Without a usable
+// clipboard (e.g. no xclip/xsel over SSH) the value is shown to copy by hand.
+func (m Model) copyNoteText() string {
+	if m.lastCopy == nil {
+		return ""
+	}
+	if m.lastCopy.err != nil {
+		return m.styles.warning.Render(m.styles.text(fmt.Sprintf("⚠ Clipboard unavailable • %s: %s", m.lastCopy.label, m.lastCopy.value)))
+	}
+	return m.styles.success.Render(m.styles.text(fmt.Sprintf("✓ Copied %s %s", m.lastCopy.label, m.lastCopy.value)))
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ngmaloney/marine-terminal/internal/stations"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
+)
+
+func TestModel_CopyKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		clipErr  error
+		wantCopy string
+		wantNote string
+	}{
+		{name: "zone code", key: "c", wantCopy: "ANZ254", wantNote: "✓ Copied zone code ANZ254"},
+		{name: "station ID", key: "i", wantCopy: "8447435", wantNote: "✓ Copied station ID 8447435"},
+		{name: "no clipboard", key: "c", clipErr: errors.New("no clipboard utilities available"), wantCopy: "ANZ254", wantNote: "Clipboard unavailable • zone code: ANZ254"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var copied string
+			orig := writeClipboard
+			writeClipboard = func(text string) error {
+				copied = text
+				return tt.clipErr
+			}
+			t.Cleanup(func() { writeClipboard = orig })
+
+			m := NewModel("", "", "")
+			m.state = StateDisplay
+			m.width, m.height = 160, 40
+			m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+			m.tideStation = &stations.TideStationInfo{ID: "8447435", Name: "Chatham"}
+
+			updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tt.key)})
+			m = updatedModel.(Model)
+			if cmd == nil {
+				t.Fatalf("'%s' should copy to the clipboard", tt.key)
+			}
+			updatedModel, _ = m.Update(cmd())
+			m = updatedModel.(Model)
+
+			if copied != tt.wantCopy {
+				t.Errorf("clipboard = %q, want %q", copied, tt.wantCopy)
+			}
+			if !strings.Contains(m.View(), tt.wantNote) {
+				t.Errorf("help line should show %q", tt.wantNote)
+			}
+
+			// The confirmation goes away with the next key
+			updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+			if strings.Contains(updatedModel.(Model).View(), tt.wantNote) {
+				t.Error("copy confirmation should clear on the next key")
+			}
+		})
+	}
+}
+
+func TestModel_CopyStationWithoutStation(t *testing.T) {
+	m := NewModel("", "", "")
+	m.state = StateDisplay
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")}); cmd != nil {
+		t.Error("'i' without a tide station should not copy anything")
+	}
+}
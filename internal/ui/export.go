@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ngmaloney/marine-terminal/internal/database"
+)
+
+// exportDir is where display snapshots are written. When empty they go next to
+// the database. It's a variable so tests can redirect it.
+var exportDir string
+
+// exportDirectory returns the directory display snapshots are written to
+func exportDirectory() string {
+	if exportDir != "" {
+		return exportDir
+	}
+	return filepath.Join(filepath.Dir(database.DBPath()), "exports")
+}
+
+// displayExportedMsg is sent when a display snapshot has been written
+type displayExportedMsg struct {
+	path string // Plain-text snapshot; the styled copy has the same name with .ansi
+	err  error
+}
+
+// exportDisplay writes the rendered display twice: as plain text for pasting into
+// messages, and with its ANSI styling intact for viewing in a terminal (e.g. cat)
+func exportDisplay(view string, now time.Time) tea.Cmd {
+	return func() tea.Msg {
+		dir := exportDirectory()
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return displayExportedMsg{err: fmt.Errorf("creating export directory: %w", err)}
+		}
+
+		base := filepath.Join(dir, "marine-terminal-"+now.Format("20060102-150405"))
+		if err := os.WriteFile(base+".txt", []byte(ansi.Strip(view)+"\n"), 0o644); err != nil {
+			return displayExportedMsg{err: fmt.Errorf("writing plain-text export: %w", err)}
+		}
+		if err := os.WriteFile(base+".ansi", []byte(view+"\n"), 0o644); err != nil {
+			return displayExportedMsg{err: fmt.Errorf("writing ANSI export: %w", err)}
+		}
+		return displayExportedMsg{path: base + ".txt"}
+	}
+}
+
+// exportNoteText is the help line confirmation for the last export
+func (m Model) exportNoteText() string {
+	if m.exportErr != nil {
+		return m.styles.warning.Render(m.styles.text("⚠ Export failed: " + m.exportErr.Error()))
+	}
+	if m.exportPath != "" {
+		return m.styles.success.Render(m.styles.text(fmt.Sprintf("✓ Saved %s (styled copy: .ansi)", m.exportPath)))
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
+)
+
+func TestExportDisplay(t *testing.T) {
+	orig := exportDir
+	exportDir = t.TempDir()
+	t.Cleanup(func() { exportDir = orig })
+
+	now := time.Date(2025, 11, 27, 14, 30, 5, 0, time.UTC)
+	view := "\x1b[1;36m⚓ ANZ254 - Nantucket Sound\x1b[0m\nWind: SW 10-15 kt"
+	msg := exportDisplay(view, now)().(displayExportedMsg)
+	if msg.err != nil {
+		t.Fatalf("exportDisplay() error = %v", msg.err)
+	}
+
+	wantPath := filepath.Join(exportDir, "marine-terminal-20251127-143005.txt")
+	if msg.path != wantPath {
+		t.Errorf("path = %s, want %s", msg.path, wantPath)
+	}
+
+	plain, err := os.ReadFile(wantPath)
+	if err != nil {
+		t.Fatalf("reading plain-text export: %v", err)
+	}
+	if string(plain) != "⚓ ANZ254 - Nantucket Sound\nWind: SW 10-15 kt\n" {
+		t.Errorf("plain-text export = %q, want styling stripped", plain)
+	}
+
+	styled, err := os.ReadFile(strings.TrimSuffix(wantPath, ".txt") + ".ansi")
+	if err != nil {
+		t.Fatalf("reading ANSI export: %v", err)
+	}
+	if string(styled) != view+"\n" {
+		t.Errorf("ANSI export = %q, want the view unchanged", styled)
+	}
+}
+
+func TestModel_ExportKey(t *testing.T) {
+	orig := exportDir
+	exportDir = t.TempDir()
+	t.Cleanup(func() { exportDir = orig })
+
+	now := time.Date(2025, 11, 27, 14, 30, 5, 0, time.UTC)
+	m := NewModel("", "", "").WithClock(models.FixedClock(now))
+	m.state = StateDisplay
+	m.width = 100
+	m.height = 40
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = updatedModel.(Model)
+	if cmd == nil {
+		t.Fatal("'x' should export the display")
+	}
+	updatedModel, _ = m.Update(cmd())
+	m = updatedModel.(Model)
+
+	if !strings.Contains(m.View(), "Saved "+filepath.Join(exportDir, "marine-terminal-20251127-143005.txt")) {
+		t.Error("help line should confirm the export path")
+	}
+	exported, err := os.ReadFile(m.exportPath)
+	if err != nil {
+		t.Fatalf("reading export: %v", err)
+	}
+	if !strings.Contains(string(exported), "ANZ254 - Nantucket Sound") {
+		t.Errorf("export should contain the display, got %q", exported)
+	}
+
+	// The confirmation goes away with the next key
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if strings.Contains(updatedModel.(Model).View(), "Saved ") {
+		t.Error("export confirmation should clear on the next key")
+	}
+}
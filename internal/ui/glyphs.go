@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/paginator"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// asciiGlyphs maps each symbol and emoji the UI draws to the plain ASCII shown in its
+// place in ASCII mode. Longer sequences come first so "⚠️" isn't read as "⚠" plus a
+// stray variation selector.
+var asciiGlyphs = []string{
+	"⚠\ufe0f", "[!]",
+	"⚠", "[!]",
+	"\ufe0f", "",
+	"✓", "[ok]",
+	"✗", "[x]",
+	"⚓", ">>",
+	"🌊", ">>",
+	"⛅", ">>",
+	"📜", ">>",
+	"📅", ">>",
+	"🔭", ">>",
+	"⌨", ">>",
+	"📍", "@",
+	"▶", ">",
+	"•", "|",
+	"●", "O",
+	"·", ".",
+	"—", "-",
+	"─", "-",
+	"°", "",
+	"↑", "^",
+	"↓", "v",
+	"←", "<",
+	"→", ">",
+	"↻", "~",
+}
+
+// asciiGlyphReplacer swaps every glyph in asciiGlyphs for its ASCII equivalent
+var asciiGlyphReplacer = strings.NewReplacer(asciiGlyphs...)
+
+// asciiBorder draws boxes with +, - and | instead of box-drawing characters
+var asciiBorder = lipgloss.Border{
+	Top:          "-",
+	Bottom:       "-",
+	Left:         "|",
+	Right:        "|",
+	TopLeft:      "+",
+	TopRight:     "+",
+	BottomLeft:   "+",
+	BottomRight:  "+",
+	MiddleLeft:   "+",
+	MiddleRight:  "+",
+	Middle:       "+",
+	MiddleTop:    "+",
+	MiddleBottom: "+",
+}
+
+// withASCII returns a copy of the styles that draws glyphs and borders in plain ASCII
+// when enabled
+func (s styles) withASCII(enabled bool) styles {
+	s.ascii = enabled
+	if enabled {
+		s.sectionBox = s.sectionBox.Border(asciiBorder)
+		s.modal = s.modal.Border(asciiBorder)
+	}
+	return s
+}
+
+// text returns text with its glyphs swapped for ASCII in ASCII mode, and unchanged
+// otherwise. Everything the UI draws with a symbol or emoji goes through it.
+func (s styles) text(text string) string {
+	if !s.ascii {
+		return text
+	}
+	return asciiGlyphReplacer.Replace(text)
+}
+
+// newList creates a list.Model, swapping the glyphs bubbles draws (the selection bar,
+// pagination dots and the arrows in its key help) for ASCII in ASCII mode
+func (s styles) newList(items []list.Item, delegate list.DefaultDelegate, width, height int) list.Model {
+	if s.ascii {
+		delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.Border(asciiBorder, false, false, false, true)
+		delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.Border(asciiBorder, false, false, false, true)
+	}
+	l := list.New(items, delegate, width, height)
+	if !s.ascii {
+		return l
+	}
+
+	l.Paginator.Type = paginator.Arabic
+	l.Help.ShortSeparator = s.text(l.Help.ShortSeparator)
+	l.Help.FullSeparator = s.text(l.Help.FullSeparator)
+	l.Help.Ellipsis = "..."
+	l.Styles.DividerDot = l.Styles.DividerDot.SetString(s.text(l.Styles.DividerDot.Value()))
+	for _, b := range []*key.Binding{
+		&l.KeyMap.CursorUp, &l.KeyMap.CursorDown, &l.KeyMap.PrevPage, &l.KeyMap.NextPage,
+	} {
+		b.SetHelp(s.text(b.Help().Key), b.Help().Desc)
+	}
+	return l
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ngmaloney/marine-terminal/internal/geocoding"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/stations"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
+)
+
+func TestStyles_Text(t *testing.T) {
+	st := newStyles(DefaultTheme())
+	in := "⚠️  MARINE ALERTS • 15 kt ↑ • 52°F"
+	if got := st.text(in); got != in {
+		t.Errorf("text should leave glyphs alone outside ASCII mode, got %q", got)
+	}
+	if got, want := st.withASCII(true).text(in), "[!]  MARINE ALERTS | 15 kt ^ | 52F"; got != want {
+		t.Errorf("text() = %q, want %q", got, want)
+	}
+}
+
+// asciiModeScreens renders each main screen of a model with sample data loaded
+func asciiModeScreens(t *testing.T, m Model) map[string]string {
+	t.Helper()
+	base := time.Date(2025, 11, 26, 12, 0, 0, 0, time.UTC)
+	m = m.WithClock(models.FixedClock(base))
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 60})
+	m = updated.(Model)
+
+	m.state = StateDisplay
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+	m.zones = []zonelookup.ZoneInfo{*m.selectedZone, {Code: "ANZ255", Name: "Buzzards Bay"}}
+	m.location = &geocoding.Location{Name: "Chatham, MA"}
+	m.searchQuery = "Chatham, MA"
+	m.tideStation = &stations.TideStationInfo{ID: "8447435", Name: "Chatham"}
+	m.weather = &models.MarineConditions{
+		Wind: models.WindData{Direction: "SW", SpeedMin: 20, SpeedMax: 25},
+		Seas: models.SeaState{HeightMin: 4, HeightMax: 6},
+	}
+	m.forecast = &models.ThreeDayForecast{Periods: []models.MarineForecast{
+		{PeriodName: "TODAY", Wind: m.weather.Wind, Seas: m.weather.Seas},
+		{PeriodName: "TONIGHT", Wind: models.WindData{Direction: "W", SpeedMin: 10, SpeedMax: 15}, Seas: models.SeaState{HeightMin: 2, HeightMax: 3}},
+		{PeriodName: "THU", Wind: models.WindData{Direction: "W", SpeedMin: 15, SpeedMax: 20}, Seas: models.SeaState{HeightMin: 2, HeightMax: 3}},
+	}}
+	m.alerts = &models.AlertData{Alerts: []models.Alert{{
+		Event:    "Gale Warning",
+		Headline: "Gale Warning until 6 PM",
+		Severity: models.SeveritySevere,
+		Onset:    base.Add(-time.Hour),
+		Expires:  base.Add(6 * time.Hour),
+	}}}
+	m.tides = &models.TideData{Events: testTideEvents()}
+	m.tideConditions = &models.MarineConditions{
+		Temperature:      48,
+		WaterTemperature: 51,
+		Pressure:         1012,
+		AirTempHistory:   hourlyObservations(base.Add(-4*time.Hour), 44, 46, 48, 47),
+		WaterTempHistory: hourlyObservations(base.Add(-4*time.Hour), 50, 51, 51, 52),
+	}
+	m.metErr = fmt.Errorf("met data offline")
+
+	screens := map[string]string{"weather": m.View()}
+	m.activePane = PaneTides
+	m = m.rebuildTideChart()
+	screens["tides"] = m.View()
+	m.showHelp = true
+	screens["help"] = m.View()
+	m.showHelp = false
+	m.state = StateError
+	m.err = fmt.Errorf("request failed")
+	screens["error"] = m.View()
+
+	m.state = StateSavedPorts
+	m.savedPorts = []models.Port{{Name: "Home", StationID: "ANZ254", City: "Chatham", State: "MA"}}
+	m.portList = createPortList(m.savedPorts, 96, 30, m.styles)
+	screens["saved ports"] = m.View()
+	return screens
+}
+
+func TestModel_ASCIIMode(t *testing.T) {
+	for name, view := range asciiModeScreens(t, NewModel("", "", "").WithASCII(true)) {
+		for i, r := range view {
+			if r > 0x7F {
+				t.Errorf("%s screen has non-ASCII %q at byte %d:\n%s", name, r, i, view)
+				break
+			}
+		}
+	}
+
+	// The same screens draw the Unicode glyphs by default
+	screens := asciiModeScreens(t, NewModel("", "", ""))
+	for name, glyph := range map[string]string{"weather": "⚓", "tides": "🌊", "help": "⌨", "error": "✗"} {
+		if !strings.Contains(screens[name], glyph) {
+			t.Errorf("%s screen should show %q outside ASCII mode", name, glyph)
+		}
+	}
+}
+
+func TestModel_WithASCIIKeepsTheme(t *testing.T) {
+	m := NewModel("", "", "").WithASCII(true).WithTheme(themes["high-contrast"]).WithASCIIChart(false)
+	if !m.styles.ascii || !m.asciiChart {
+		t.Errorf("ASCII mode should survive later options, ascii = %v asciiChart = %v", m.styles.ascii, m.asciiChart)
+	}
+}
@@ -3,12 +3,17 @@ package ui
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ngmaloney/marine-terminal/internal/geocoding"
 	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/noaa"
+	"github.com/ngmaloney/marine-terminal/internal/stations"
 	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
 )
 
@@ -42,8 +47,9 @@ func (m *mockWeatherClient) GetMarineForecastByZone(ctx context.Context, marineZ
 }
 
 type mockAlertClient struct {
-	alerts *models.AlertData
-	err    error
+	alerts   *models.AlertData
+	err      error
+	gotZones []string // Zones of the last multi-zone query
 }
 
 func (m *mockAlertClient) GetActiveAlerts(ctx context.Context, lat, lon float64) (*models.AlertData, error) {
@@ -60,6 +66,77 @@ func (m *mockAlertClient) GetActiveAlertsByZone(ctx context.Context, marineZone
 	return m.alerts, nil
 }
 
+func (m *mockAlertClient) GetActiveAlertsByZones(ctx context.Context, marineZones []string) (*models.AlertData, error) {
+	m.gotZones = marineZones
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.alerts, nil
+}
+
+func (m *mockAlertClient) GetActiveAlertsByZonesAndPoint(ctx context.Context, marineZones []string, lat, lon float64) (*models.AlertData, error) {
+	m.gotZones = marineZones
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.alerts, nil
+}
+
+type mockTideClient struct {
+	tides      *models.TideData
+	gotDatum   string
+	gotStart   time.Time
+	gotEnd     time.Time
+	err        error
+	datums     *models.DatumOffsets
+	datumsErr  error
+	waterLevel    *models.WaterLevel // nil reports a station without a water level sensor
+	waterLevelErr error
+}
+
+func (m *mockTideClient) GetTidePredictions(ctx context.Context, stationID, datum string, startDate, endDate time.Time) (*models.TideData, error) {
+	m.gotDatum = datum
+	m.gotStart, m.gotEnd = startDate, endDate
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.tides, nil
+}
+
+func (m *mockTideClient) GetMeteorologicalData(ctx context.Context, stationID string, startDate, endDate time.Time) (*models.MarineConditions, error) {
+	return &models.MarineConditions{}, nil
+}
+
+func (m *mockTideClient) GetDatums(ctx context.Context, stationID string) (*models.DatumOffsets, error) {
+	if m.datumsErr != nil {
+		return nil, m.datumsErr
+	}
+	return m.datums, nil
+}
+
+func (m *mockTideClient) GetWaterLevel(ctx context.Context, stationID, datum string) (*models.WaterLevel, error) {
+	if m.waterLevelErr != nil {
+		return nil, m.waterLevelErr
+	}
+	if m.waterLevel == nil {
+		return nil, noaa.ErrNoWaterLevel
+	}
+	return m.waterLevel, nil
+}
+
+type mockGeocoder struct {
+	locations map[string]*geocoding.Location
+	queries   []string
+}
+
+func (m *mockGeocoder) Geocode(ctx context.Context, query string) (*geocoding.Location, error) {
+	m.queries = append(m.queries, query)
+	if loc, ok := m.locations[query]; ok {
+		return loc, nil
+	}
+	return nil, fmt.Errorf("location not found: %s", query)
+}
+
 // TestIntegration_SearchAndGeocode tests the geocoding workflow
 func TestIntegration_SearchAndGeocode(t *testing.T) {
 	// Create model
@@ -149,7 +226,7 @@ func TestIntegration_ZoneSelection(t *testing.T) {
 		{Code: "ANZ251", Name: "Cape Cod Bay", Distance: 5.2},
 		{Code: "ANZ250", Name: "Coastal Waters East of Cape Cod", Distance: 12.8},
 	}
-	m.zoneList = createZoneList(m.zones, 80, 20)
+	m.zoneList = createZoneList(m.zones, zonelookup.ZoneTypeCoastal, 80, 20, m.styles)
 
 	// Step 1: User presses Enter to select first zone
 	enterMsg := tea.KeyMsg{Type: tea.KeyEnter}
@@ -173,10 +250,10 @@ func TestIntegration_ZoneSelection(t *testing.T) {
 	// Step 1.5: Simulate saving the port
 	// We simulate the portSavedMsg directly to bypass DB interactions in unit test
 	savedPort := &models.Port{
-		Name: "Test Port",
+		Name:         "Test Port",
 		MarineZoneID: "ANZ251",
-		Latitude: 41.0,
-		Longitude: -70.0,
+		Latitude:     41.0,
+		Longitude:    -70.0,
 	}
 	saveMsg := portSavedMsg{port: savedPort}
 	updatedModel, cmd := m.Update(saveMsg)
@@ -205,7 +282,7 @@ func TestIntegration_ZoneSelection(t *testing.T) {
 		conditions: mockConditions,
 		forecast:   mockForecast,
 	}
-	updatedModel, _ = m.Update(weatherMsg)
+	updatedModel, _ = m.Update(zoneLoadMsg{load: m.zoneLoad, part: weatherMsg})
 	m = updatedModel.(Model)
 
 	if m.weather == nil {
@@ -217,10 +294,14 @@ func TestIntegration_ZoneSelection(t *testing.T) {
 	if m.loadingWeather {
 		t.Error("loadingWeather should be false after data received")
 	}
+	// The display shows the forecast while the alerts are still loading
+	if m.state != StateDisplay || !m.loadingAlerts {
+		t.Errorf("state = %v loadingAlerts = %v, want the display with alerts still loading", m.state, m.loadingAlerts)
+	}
 
-	// Step 3: Simulate alerts arriving
+	// Step 3: Simulate the alerts arriving
 	alertsMsg := zoneAlertsFetchedMsg{alerts: mockAlerts}
-	updatedModel, _ = m.Update(alertsMsg)
+	updatedModel, _ = m.Update(zoneLoadMsg{load: m.zoneLoad, part: alertsMsg})
 	m = updatedModel.(Model)
 
 	if m.alerts == nil {
@@ -273,9 +354,9 @@ func TestIntegration_ErrorHandling(t *testing.T) {
 		t.Error("weather should remain nil after error")
 	}
 
-	// Alerts should still work
+	// Alerts should still work when they arrive
 	alertsMsg := zoneAlertsFetchedMsg{alerts: &models.AlertData{}}
-	updatedModel, _ = m.Update(alertsMsg)
+	updatedModel, _ = m.Update(zoneLoadMsg{load: m.zoneLoad, part: alertsMsg})
 	m = updatedModel.(Model)
 
 	if m.alerts == nil {
@@ -307,3 +388,1471 @@ func TestIntegration_SearchAgain(t *testing.T) {
 		t.Errorf("state = %v, want StateSavedPorts", m.state)
 	}
 }
+
+// TestIntegration_DeleteActivePort tests deleting the port currently on display
+func TestIntegration_DeleteActivePort(t *testing.T) {
+	active := models.Port{Name: "Stage Harbor", MarineZoneID: "ANZ254", Latitude: 41.66, Longitude: -69.96, Zipcode: "02633"}
+	other := models.Port{Name: "Woods Hole", MarineZoneID: "ANZ232", Latitude: 41.52, Longitude: -70.67, Zipcode: "02543"}
+
+	t.Run("loads next saved port", func(t *testing.T) {
+		m := NewModel("", "", "")
+		m.width, m.height = 100, 40
+		m.savedPorts = []models.Port{active, other}
+		updatedModel, _ := m.loadPort(active)
+		m = updatedModel
+		m.weather = &models.MarineConditions{Location: "ANZ254"}
+		m.state = StateConfirmDelete
+		m.portToDelete = &active
+
+		updatedTeaModel, cmd := m.Update(portDeletedMsg{name: active.Name})
+		m = updatedTeaModel.(Model)
+
+		if m.currentPort == nil || m.currentPort.Name != other.Name {
+			t.Fatalf("currentPort = %v, want %s", m.currentPort, other.Name)
+		}
+		if m.selectedZone == nil || m.selectedZone.Code != other.MarineZoneID {
+			t.Errorf("selectedZone = %v, want %s", m.selectedZone, other.MarineZoneID)
+		}
+		if m.weather != nil {
+			t.Error("weather from the deleted port should be cleared")
+		}
+		if m.state != StateLoading {
+			t.Errorf("state = %v, want StateLoading", m.state)
+		}
+		if cmd == nil {
+			t.Error("Expected command to fetch data for the next port")
+		}
+	})
+
+	t.Run("falls back to search when no ports remain", func(t *testing.T) {
+		m := NewModel("", "", "")
+		m.width, m.height = 100, 40
+		m.savedPorts = []models.Port{active}
+		updatedModel, _ := m.loadPort(active)
+		m = updatedModel
+		m.alerts = &models.AlertData{}
+		m.state = StateConfirmDelete
+		m.portToDelete = &active
+
+		updatedTeaModel, _ := m.Update(portDeletedMsg{name: active.Name})
+		m = updatedTeaModel.(Model)
+
+		if m.state != StateSearch {
+			t.Errorf("state = %v, want StateSearch", m.state)
+		}
+		if m.selectedZone != nil || m.currentPort != nil || m.alerts != nil {
+			t.Error("display state should be cleared after deleting the active port")
+		}
+		if view := m.View(); strings.Contains(view, active.Name) || strings.Contains(view, active.MarineZoneID) {
+			t.Error("View() should no longer reference the deleted port")
+		}
+	})
+
+	t.Run("deleting another port keeps display", func(t *testing.T) {
+		m := NewModel("", "", "")
+		m.savedPorts = []models.Port{active, other}
+		updatedModel, _ := m.loadPort(active)
+		m = updatedModel
+		m.state = StateConfirmDelete
+
+		updatedTeaModel, _ := m.Update(portDeletedMsg{name: other.Name})
+		m = updatedTeaModel.(Model)
+
+		if m.currentPort == nil || m.currentPort.Name != active.Name {
+			t.Errorf("currentPort = %v, want %s", m.currentPort, active.Name)
+		}
+		if m.state != StateSavedPorts {
+			t.Errorf("state = %v, want StateSavedPorts", m.state)
+		}
+	})
+}
+
+// TestIntegration_HomePort tests marking a saved port as home and jumping to it
+func TestIntegration_HomePort(t *testing.T) {
+	home := models.Port{Name: "Stage Harbor", MarineZoneID: "ANZ254", Latitude: 41.66, Longitude: -69.96, Zipcode: "02633"}
+	other := models.Port{Name: "Woods Hole", MarineZoneID: "ANZ232", Latitude: 41.52, Longitude: -70.67, Zipcode: "02543"}
+
+	m := NewModel("", "", "")
+	m.width, m.height = 100, 40
+	m.savedPorts = []models.Port{home, other}
+	m.portsFetched = true
+	m.portList = createPortList(m.savedPorts, m.width-4, m.height-10, m.styles)
+	m, _ = m.loadPort(other)
+	m.state = StateSavedPorts
+
+	// Without a home port 'H' does nothing
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	m = updatedModel.(Model)
+	if m.state != StateSavedPorts || cmd != nil {
+		t.Fatalf("state = %v, cmd = %v; want no change without a home port", m.state, cmd)
+	}
+
+	// '*' marks the selected port as home
+	updatedModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("*")})
+	m = updatedModel.(Model)
+	if cmd == nil {
+		t.Fatal("Expected command to save the home port")
+	}
+	updatedModel, _ = m.Update(homePortSetMsg{name: home.Name})
+	m = updatedModel.(Model)
+	if !m.savedPorts[0].Home || m.savedPorts[1].Home {
+		t.Errorf("home flags = %v/%v, want only %s home", m.savedPorts[0].Home, m.savedPorts[1].Home, home.Name)
+	}
+	if !strings.Contains(m.View(), "Stage Harbor (home)") {
+		t.Error("Expected the saved ports list to mark the home port")
+	}
+
+	// 'H' loads the home port from the saved ports list, re-fetching its data
+	m.weather = &models.MarineConditions{Location: other.MarineZoneID}
+	updatedModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	m = updatedModel.(Model)
+	if m.currentPort == nil || m.currentPort.Name != home.Name {
+		t.Fatalf("currentPort = %v, want %s", m.currentPort, home.Name)
+	}
+	if m.selectedZone == nil || m.selectedZone.Code != home.MarineZoneID {
+		t.Errorf("selectedZone = %v, want %s", m.selectedZone, home.MarineZoneID)
+	}
+	if m.state != StateLoading || !m.loadingWeather || cmd == nil {
+		t.Errorf("state = %v, loadingWeather = %v, cmd = %v; want the home port loading", m.state, m.loadingWeather, cmd)
+	}
+
+	// 'H' is text while typing a search
+	m.state = StateSearch
+	m.searchInput.Focus()
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	m = updatedModel.(Model)
+	if m.state != StateSearch || m.searchInput.Value() != "H" {
+		t.Errorf("state = %v, input = %q; want 'H' typed into the search", m.state, m.searchInput.Value())
+	}
+}
+
+// TestIntegration_HomePortBeforePortsFetched tests 'H' after starting with --port,
+// when the saved ports were never fetched
+func TestIntegration_HomePortBeforePortsFetched(t *testing.T) {
+	home := models.Port{Name: "Stage Harbor", MarineZoneID: "ANZ254", Latitude: 41.66, Longitude: -69.96, Home: true}
+	other := models.Port{Name: "Woods Hole", MarineZoneID: "ANZ232", Latitude: 41.52, Longitude: -70.67}
+
+	m := NewModel("", "", "")
+	m.width, m.height = 100, 40
+	m, _ = m.loadPort(other)
+	m.state = StateDisplay
+
+	// The home port is read from the database, then loaded
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	m = updatedModel.(Model)
+	if cmd == nil {
+		t.Fatal("Expected a command to read the home port")
+	}
+	updatedModel, _ = m.Update(homePortFetchedMsg{port: &home})
+	m = updatedModel.(Model)
+	if m.currentPort == nil || m.currentPort.Name != home.Name || m.state != StateLoading {
+		t.Fatalf("currentPort = %v, state = %v; want %s loading", m.currentPort, m.state, home.Name)
+	}
+
+	// Without a home port the saved ports list opens, where one can be made home
+	m.state = StateDisplay
+	updatedModel, _ = m.Update(homePortFetchedMsg{})
+	if m = updatedModel.(Model); m.state != StateSavedPorts {
+		t.Errorf("state = %v with no home port, want StateSavedPorts", m.state)
+	}
+}
+
+// TestIntegration_UpdatePort tests re-resolving a saved port's zone and station
+func TestIntegration_UpdatePort(t *testing.T) {
+	stale := models.Port{Name: "Stage Harbor", City: "Chatham", State: "MA", MarineZoneID: "ANZ250", TideStationID: "8449130"}
+	other := models.Port{Name: "Woods Hole", MarineZoneID: "ANZ232", TideStationID: "8447930"}
+	rebuilt := stale
+	rebuilt.MarineZoneID, rebuilt.TideStationID, rebuilt.StationID = "ANZ254", "8447435", "8447435"
+
+	newSavedPorts := func() Model {
+		m := NewModel("", "", "")
+		m.width, m.height = 100, 40
+		m.savedPorts = []models.Port{stale, other}
+		m.portList = createPortList(m.savedPorts, 96, 30, m.styles)
+		m.state = StateSavedPorts
+		return m
+	}
+	update := func(m Model, msg tea.Msg) (Model, tea.Cmd) {
+		updated, cmd := m.Update(msg)
+		return updated.(Model), cmd
+	}
+	keyU := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")}
+	keyY := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}
+
+	t.Run("shows the change and saves it on confirm", func(t *testing.T) {
+		m, cmd := update(newSavedPorts(), keyU)
+		if m.state != StateConfirmUpdate || m.portUpdate == nil || m.portUpdate.before.Name != stale.Name {
+			t.Fatalf("'u' = state %v, portUpdate %v, want update confirmation for %s", m.state, m.portUpdate, stale.Name)
+		}
+		if cmd == nil {
+			t.Error("Expected a command to look up the port's zone and station")
+		}
+		// 'y' does nothing until the lookup finishes
+		if m, cmd = update(m, keyY); cmd != nil || m.state != StateConfirmUpdate {
+			t.Errorf("'y' before the lookup = state %v, cmd %v, want no update yet", m.state, cmd)
+		}
+
+		m, _ = update(m, portRebuiltMsg{name: stale.Name, port: &rebuilt})
+		view := m.viewConfirmUpdate()
+		for _, want := range []string{"ANZ250 → ANZ254", "8449130 → 8447435", "y: Update"} {
+			if !strings.Contains(view, want) {
+				t.Errorf("viewConfirmUpdate() missing %q:\n%s", want, view)
+			}
+		}
+
+		if _, cmd = update(m, keyY); cmd == nil {
+			t.Fatal("Expected 'y' to save the updated port")
+		}
+		m, _ = update(m, portUpdatedMsg{port: &rebuilt})
+		if m.state != StateSavedPorts || m.portUpdate != nil {
+			t.Errorf("state = %v, portUpdate = %v, want back in StateSavedPorts", m.state, m.portUpdate)
+		}
+		if len(m.savedPorts) != 2 || m.savedPorts[0].MarineZoneID != "ANZ254" || m.savedPorts[1].Name != other.Name {
+			t.Errorf("savedPorts = %+v, want Stage Harbor updated in place", m.savedPorts)
+		}
+	})
+
+	t.Run("reloads the port on display", func(t *testing.T) {
+		m := newSavedPorts()
+		m, _ = m.loadPort(stale)
+		m.portUpdate = &portUpdate{before: stale, after: &rebuilt}
+		m.state = StateConfirmUpdate
+
+		m, cmd := update(m, portUpdatedMsg{port: &rebuilt})
+		if m.currentPort == nil || m.currentPort.MarineZoneID != "ANZ254" {
+			t.Errorf("currentPort = %v, want the updated port", m.currentPort)
+		}
+		if m.selectedZone == nil || m.selectedZone.Code != "ANZ254" || m.state != StateLoading || cmd == nil {
+			t.Errorf("selectedZone = %v, state = %v, want ANZ254 reloading", m.selectedZone, m.state)
+		}
+	})
+
+	t.Run("cancel ignores a late lookup", func(t *testing.T) {
+		m, _ := update(newSavedPorts(), keyU)
+		m, _ = update(m, tea.KeyMsg{Type: tea.KeyEsc})
+		if m.state != StateSavedPorts || m.portUpdate != nil {
+			t.Fatalf("esc = state %v, portUpdate %v, want update cancelled", m.state, m.portUpdate)
+		}
+		m, _ = update(m, portRebuiltMsg{name: stale.Name, port: &rebuilt})
+		if m.state != StateSavedPorts || m.portUpdate != nil {
+			t.Errorf("late lookup = state %v, portUpdate %v, want it ignored", m.state, m.portUpdate)
+		}
+	})
+
+	t.Run("lookup failure shows the error", func(t *testing.T) {
+		m, _ := update(newSavedPorts(), keyU)
+		m, _ = update(m, portRebuiltMsg{name: stale.Name, err: fmt.Errorf("no marine zones within 50 miles")})
+		if m.state != StateError || m.err == nil || m.portUpdate != nil {
+			t.Errorf("state = %v, err = %v, want StateError", m.state, m.err)
+		}
+	})
+}
+
+// TestIntegration_SaveOverwriteConfirmation tests that saving under an existing port name asks first
+func TestIntegration_SaveOverwriteConfirmation(t *testing.T) {
+	existing := models.Port{Name: "Stage Harbor", MarineZoneID: "ANZ254", Latitude: 41.66, Longitude: -69.96, Zipcode: "02633"}
+
+	newPrompt := func() Model {
+		m := NewModel("", "", "")
+		m.width, m.height = 100, 40
+		m.savedPorts = []models.Port{existing}
+		m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ251", Name: "Cape Cod Bay"}
+		m.searchQuery = "02633"
+		m.state = StateSavePrompt
+		m.saveInput.SetValue(existing.Name)
+		m.saveInput.Focus()
+
+		updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = updatedModel.(Model)
+		if cmd != nil {
+			t.Fatal("Expected no save command before the overwrite is confirmed")
+		}
+		if m.state != StateConfirmOverwrite {
+			t.Fatalf("state = %v, want StateConfirmOverwrite", m.state)
+		}
+		if view := m.View(); !strings.Contains(view, "Port 'Stage Harbor' exists") {
+			t.Errorf("View() should ask to overwrite the existing port, got:\n%s", view)
+		}
+		return m
+	}
+
+	t.Run("confirm saves", func(t *testing.T) {
+		m := newPrompt()
+		updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+		m = updatedModel.(Model)
+
+		if cmd == nil || !m.saving {
+			t.Error("Expected confirming to save the port")
+		}
+		if m.portToOverwrite != "" {
+			t.Errorf("portToOverwrite = %q, want cleared", m.portToOverwrite)
+		}
+	})
+
+	t.Run("cancel returns to name prompt", func(t *testing.T) {
+		m := newPrompt()
+		updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+		m = updatedModel.(Model)
+
+		if cmd != nil || m.saving {
+			t.Error("Expected cancelling not to save the port")
+		}
+		if m.state != StateSavePrompt {
+			t.Errorf("state = %v, want StateSavePrompt", m.state)
+		}
+		if m.saveInput.Value() != existing.Name {
+			t.Errorf("saveInput = %q, want the entered name kept for editing", m.saveInput.Value())
+		}
+	})
+
+	t.Run("new name saves without asking", func(t *testing.T) {
+		m := NewModel("", "", "")
+		m.savedPorts = []models.Port{existing}
+		m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ251", Name: "Cape Cod Bay"}
+		m.state = StateSavePrompt
+		m.saveInput.SetValue("Woods Hole")
+
+		updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = updatedModel.(Model)
+
+		if cmd == nil || !m.saving {
+			t.Error("Expected a new port name to be saved immediately")
+		}
+		if m.state != StateSavePrompt {
+			t.Errorf("state = %v, want StateSavePrompt while saving", m.state)
+		}
+	})
+}
+
+// TestIntegration_ListFiltering tests that filter text typed into the saved ports and zone
+// lists isn't taken as a command, and that the list keys still work without a filter
+func TestIntegration_ListFiltering(t *testing.T) {
+	savedPorts := []models.Port{
+		{Name: "Stage Harbor", MarineZoneID: "ANZ254"},
+		{Name: "Woods Hole", MarineZoneID: "ANZ232"},
+	}
+	press := func(m Model, keys ...string) (Model, tea.Cmd) {
+		var cmd tea.Cmd
+		for _, k := range keys {
+			var msg tea.KeyMsg
+			switch k {
+			case "enter":
+				msg = tea.KeyMsg{Type: tea.KeyEnter}
+			case "esc":
+				msg = tea.KeyMsg{Type: tea.KeyEsc}
+			default:
+				msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+			}
+			var updated tea.Model
+			updated, cmd = m.Update(msg)
+			m = updated.(Model)
+		}
+		return m, cmd
+	}
+	newSavedPorts := func() Model {
+		m := NewModel("", "", "")
+		m.width, m.height = 100, 40
+		m.savedPorts = savedPorts
+		m.portList = createPortList(savedPorts, 96, 30, m.styles)
+		m.state = StateSavedPorts
+		return m
+	}
+
+	t.Run("saved ports filter takes typed keys", func(t *testing.T) {
+		m, cmd := press(newSavedPorts(), "/", "n", "d", "q")
+		if m.state != StateSavedPorts {
+			t.Fatalf("state = %v, want StateSavedPorts while filtering", m.state)
+		}
+		if m.portToDelete != nil {
+			t.Error("'d' while filtering should not start a delete")
+		}
+		if cmd != nil {
+			if _, quit := cmd().(tea.QuitMsg); quit {
+				t.Error("'q' while filtering should not quit")
+			}
+		}
+		if got := m.portList.FilterValue(); got != "ndq" {
+			t.Errorf("filter = %q, want %q", got, "ndq")
+		}
+	})
+
+	t.Run("esc clears an applied filter before leaving", func(t *testing.T) {
+		m := newSavedPorts()
+		m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254"}
+		m, _ = press(m, "/", "w", "enter")
+		if m.portList.FilterState() != list.FilterApplied {
+			t.Fatalf("FilterState() = %v, want FilterApplied", m.portList.FilterState())
+		}
+		m, _ = press(m, "esc")
+		if m.state != StateSavedPorts || m.portList.FilterState() != list.Unfiltered {
+			t.Errorf("state = %v, FilterState() = %v, want filter cleared in StateSavedPorts", m.state, m.portList.FilterState())
+		}
+		m, _ = press(m, "esc")
+		if m.state != StateDisplay {
+			t.Errorf("state = %v, want StateDisplay after esc without a filter", m.state)
+		}
+	})
+
+	t.Run("saved ports keys work without a filter", func(t *testing.T) {
+		m, _ := press(newSavedPorts(), "d")
+		if m.state != StateConfirmDelete || m.portToDelete == nil || m.portToDelete.Name != "Stage Harbor" {
+			t.Errorf("'d' = state %v, portToDelete %v, want delete confirmation for Stage Harbor", m.state, m.portToDelete)
+		}
+
+		m, _ = press(newSavedPorts(), "n")
+		if m.state != StateSearch {
+			t.Errorf("'n' = state %v, want StateSearch", m.state)
+		}
+
+		m, _ = press(newSavedPorts(), "enter")
+		if m.currentPort == nil || m.currentPort.Name != "Stage Harbor" {
+			t.Errorf("enter loaded %v, want Stage Harbor", m.currentPort)
+		}
+	})
+
+	t.Run("zone list filter takes typed keys", func(t *testing.T) {
+		m := NewModel("", "", "")
+		m.width, m.height = 100, 40
+		m.zones = []zonelookup.ZoneInfo{
+			{Code: "ANZ251", Name: "Cape Cod Bay", Distance: 5.2},
+			{Code: "ANZ254", Name: "Nantucket Sound", Distance: 8.1},
+		}
+		m.zoneList = createZoneList(m.zones, zonelookup.ZoneTypeCoastal, 96, 30, m.styles)
+		m.state = StateZoneList
+
+		m, _ = press(m, "/", "s", "o", "u", "n", "d")
+		if m.state != StateZoneList {
+			t.Fatalf("state = %v, want StateZoneList while filtering ('s' is new search otherwise)", m.state)
+		}
+		m, _ = press(m, "enter")
+		if m.zoneList.FilterState() != list.FilterApplied {
+			t.Errorf("FilterState() = %v, want FilterApplied", m.zoneList.FilterState())
+		}
+	})
+}
+
+// TestIntegration_JumpToZoneByCode tests loading a zone directly by its code
+func TestIntegration_JumpToZoneByCode(t *testing.T) {
+	m := NewModel("", "", "")
+	m.state = StateSearch
+	m.searchInput.Focus()
+
+	// Tab switches from search to zone code entry
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updatedModel.(Model)
+	if m.state != StateZoneCode {
+		t.Fatalf("state = %v, want StateZoneCode", m.state)
+	}
+
+	// Invalid format is rejected without a lookup
+	for _, char := range "ANX12" {
+		updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{char}})
+		m = updatedModel.(Model)
+	}
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(Model)
+	if m.err == nil {
+		t.Error("Expected error for invalid zone code format")
+	}
+	if cmd != nil {
+		t.Error("Invalid zone code should not trigger a lookup")
+	}
+	if m.state != StateZoneCode {
+		t.Errorf("state = %v, want StateZoneCode", m.state)
+	}
+
+	// Valid format triggers a lookup
+	m.zoneCodeInput.SetValue("anz254")
+	updatedModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(Model)
+	if m.state != StateLoading {
+		t.Errorf("state = %v, want StateLoading", m.state)
+	}
+	if cmd == nil {
+		t.Fatal("Expected command to look up zone")
+	}
+
+	// Unknown code returns to the zone code entry with an error
+	updatedModel, _ = m.Update(zoneCodeFoundMsg{err: fmt.Errorf("zone code ANZ999 not found")})
+	m = updatedModel.(Model)
+	if m.state != StateZoneCode || m.err == nil {
+		t.Errorf("state = %v, err = %v; want StateZoneCode with error", m.state, m.err)
+	}
+
+	// Found zone loads directly using its center coordinates
+	zone := &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound", CenterLat: 41.5, CenterLon: -70.2}
+	updatedModel, cmd = m.Update(zoneCodeFoundMsg{zone: zone})
+	m = updatedModel.(Model)
+	if m.selectedZone == nil || m.selectedZone.Code != "ANZ254" {
+		t.Fatalf("selectedZone = %v, want ANZ254", m.selectedZone)
+	}
+	if m.location == nil || m.location.Latitude != 41.5 || m.location.Longitude != -70.2 {
+		t.Errorf("location = %v, want zone center 41.5, -70.2", m.location)
+	}
+	if !m.loadingWeather || !m.loadingAlerts {
+		t.Error("Expected weather and alerts to be loading")
+	}
+	if cmd == nil {
+		t.Error("Expected command to fetch zone data")
+	}
+}
+
+// TestIntegration_PortDatumThreadedToTides tests that a saved port's datum is used for tide predictions
+func TestIntegration_PortDatumThreadedToTides(t *testing.T) {
+	tideClient := &mockTideClient{tides: &models.TideData{StationID: "8447435", Datum: models.DatumMSL}}
+
+	m := NewModel("", "", "")
+	m.tideClient = tideClient
+	port := models.Port{Name: "Chatham", MarineZoneID: "ANZ254", Latitude: 41.68, Longitude: -69.95, PreferredDatum: models.DatumMSL}
+	m, _ = m.loadPort(port)
+
+	updatedModel, cmd := m.Update(tideStationFoundMsg{stations: []stations.TideStationInfo{{ID: "8447435", Name: "Chatham"}}})
+	m = updatedModel.(Model)
+	if cmd == nil {
+		t.Fatal("Expected command to fetch tide data")
+	}
+
+	msg := cmd()
+	if _, ok := msg.(tideDataFetchedMsg); !ok {
+		t.Fatalf("cmd() returned %T, want tideDataFetchedMsg", msg)
+	}
+	if tideClient.gotDatum != models.DatumMSL {
+		t.Errorf("GetTidePredictions datum = %q, want MSL", tideClient.gotDatum)
+	}
+
+	// Ports saved before datum preferences existed default to MLLW
+	m.currentPort = &models.Port{Name: "Legacy"}
+	if got := m.tideDatum(); got != models.DatumMLLW {
+		t.Errorf("tideDatum() for legacy port = %q, want MLLW", got)
+	}
+}
+
+// TestIntegration_LoadedPortSavesFromItsLocation tests that a loaded port is labelled
+// by its DisplayName but saved again from its zipcode, or its coordinates without one
+func TestIntegration_LoadedPortSavesFromItsLocation(t *testing.T) {
+	m := NewModel("", "", "")
+	m.width, m.height = 100, 40
+	m, _ = m.loadPort(models.Port{Name: "Chatham", City: "Chatham", State: "MA", Zipcode: "02633", MarineZoneID: "ANZ254", Latitude: 41.68, Longitude: -69.95})
+	m.state = StateDisplay
+	if m.searchQuery != "02633" {
+		t.Errorf("searchQuery = %q, want the zipcode to save from", m.searchQuery)
+	}
+	if !strings.Contains(m.View(), "📍 Chatham, MA") {
+		t.Error("the location line should show the port's display name")
+	}
+
+	m, _ = m.loadPort(models.Port{Name: "Stage Harbor", MarineZoneID: "ANZ254", Latitude: 41.66, Longitude: -69.96})
+	if m.searchQuery != "41.660000,-69.960000" {
+		t.Errorf("searchQuery = %q, want the coordinates to save from", m.searchQuery)
+	}
+	if got := m.locationLabel(); got != "Stage Harbor" {
+		t.Errorf("locationLabel() = %q, want the port's name", got)
+	}
+}
+
+type mockLocator struct {
+	location *geocoding.Location
+	err      error
+}
+
+func (m *mockLocator) Locate(ctx context.Context) (*geocoding.Location, error) {
+	return m.location, m.err
+}
+
+// TestIntegration_StartHere tests the --here startup flow and its fallback to search
+func TestIntegration_StartHere(t *testing.T) {
+	here := &geocoding.Location{Latitude: 41.68, Longitude: -69.95, Name: "Chatham, MA"}
+
+	m := NewModel("", "", "").WithLocator(&mockLocator{location: here})
+	msg := locateHere(m.locator, m.httpTimeout)()
+	located, ok := msg.(hereLocatedMsg)
+	if !ok {
+		t.Fatalf("locateHere() returned %T, want hereLocatedMsg", msg)
+	}
+
+	updatedModel, cmd := m.Update(located)
+	m = updatedModel.(Model)
+	if cmd == nil {
+		t.Fatal("Expected command to find nearby zones")
+	}
+	if m.location == nil || m.location.Latitude != 41.68 {
+		t.Errorf("Expected location to be set from locator, got %+v", m.location)
+	}
+	if m.searchQuery != "Chatham, MA" {
+		t.Errorf("searchQuery = %q, want %q", m.searchQuery, "Chatham, MA")
+	}
+
+	// A failed lookup falls back to the normal search screen
+	m = NewModel("", "", "").WithLocator(&mockLocator{err: fmt.Errorf("offline")})
+	updatedModel, _ = m.Update(locateHere(m.locator, m.httpTimeout)())
+	m = updatedModel.(Model)
+	if m.state != StateSearch {
+		t.Errorf("Expected StateSearch after failed lookup, got %v", m.state)
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "offline") {
+		t.Errorf("Expected lookup error to be shown, got %v", m.err)
+	}
+}
+
+// TestIntegration_SearchGeocodeZonesWithMocks runs search → geocode → zone list using a mock geocoder
+func TestIntegration_SearchGeocodeZonesWithMocks(t *testing.T) {
+	geocoder := &mockGeocoder{locations: map[string]*geocoding.Location{
+		"02633": {Latitude: 41.6885, Longitude: -69.9511, Name: "Chatham, MA 02633"},
+	}}
+
+	// Always list the zones, so the flow doesn't depend on the auto-select distance
+	m := NewModel("", "", "").WithZoneAutoSelect(0)
+	m.geocoder = geocoder
+	m.state = StateSearch
+	m.searchInput.Focus()
+	m.searchInput.SetValue("02633")
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(Model)
+	if cmd == nil {
+		t.Fatal("Expected command to geocode location")
+	}
+
+	// Run the geocode command against the mock
+	msg := cmd()
+	gm, ok := msg.(geocodeMsg)
+	if !ok {
+		t.Fatalf("geocode command returned %T, want geocodeMsg", msg)
+	}
+	if len(geocoder.queries) != 1 || geocoder.queries[0] != "02633" {
+		t.Errorf("geocoder queries = %v, want [02633]", geocoder.queries)
+	}
+
+	updatedModel, cmd = m.Update(gm)
+	m = updatedModel.(Model)
+	if m.location == nil || m.location.Latitude != 41.6885 {
+		t.Fatalf("Expected location from mock geocoder, got %+v", m.location)
+	}
+	if cmd == nil {
+		t.Fatal("Expected command to find nearby zones")
+	}
+
+	// Simulate the zone lookup for the geocoded coordinates
+	updatedModel, _ = m.Update(zonesFoundMsg{zones: []zonelookup.ZoneInfo{
+		{Code: "ANZ254", Name: "Nantucket Sound", Distance: 3.2},
+		{Code: "ANZ255", Name: "Vineyard Sound", Distance: 12.5},
+	}})
+	m = updatedModel.(Model)
+	if m.state != StateZoneList {
+		t.Errorf("state = %v, want StateZoneList", m.state)
+	}
+	if len(m.zones) != 2 {
+		t.Errorf("len(zones) = %d, want 2", len(m.zones))
+	}
+
+	// An unknown location surfaces the geocoder's error
+	m = NewModel("", "", "")
+	m.geocoder = geocoder
+	updatedModel, _ = m.Update(geocodeLocation(m.geocoder, m.geocodeTimeout, "Nowhere, ZZ")())
+	m = updatedModel.(Model)
+	if m.state != StateError || m.err == nil || !strings.Contains(m.err.Error(), "location not found") {
+		t.Errorf("Expected geocoding error state, got state %v err %v", m.state, m.err)
+	}
+}
+
+// mockZonelessWeatherClient has a point forecast but no marine text product for any zone
+type mockZonelessWeatherClient struct {
+	mockWeatherClient
+	zoneErr error
+}
+
+func (m *mockZonelessWeatherClient) GetMarineForecastByZone(ctx context.Context, marineZone string) (*models.MarineConditions, *models.ThreeDayForecast, error) {
+	return nil, nil, m.zoneErr
+}
+
+// TestIntegration_ZoneWeatherFallsBackToPointForecast tests that a missing zone product
+// is replaced by the gridpoint forecast for the port's coordinates
+func TestIntegration_ZoneWeatherFallsBackToPointForecast(t *testing.T) {
+	forecast := &models.ThreeDayForecast{
+		Periods: []models.MarineForecast{
+			{PeriodName: "Today", Conditions: "Sunny", Wind: models.WindData{Direction: "SW", SpeedMin: 9, SpeedMax: 13}},
+			{PeriodName: "Tonight", Conditions: "Clear"},
+		},
+		UpdatedAt: time.Now(),
+	}
+	location := &geocoding.Location{Latitude: 41.68, Longitude: -69.95, Name: "Chatham"}
+
+	client := &mockZonelessWeatherClient{
+		mockWeatherClient: mockWeatherClient{forecast: forecast},
+		zoneErr:           fmt.Errorf("no forecast product for zone"),
+	}
+	msg, ok := fetchZoneWeather(context.Background(), client, "ANZ999", location)().(zoneWeatherFetchedMsg)
+	if !ok {
+		t.Fatal("fetchZoneWeather() did not return zoneWeatherFetchedMsg")
+	}
+	if msg.err != nil {
+		t.Fatalf("err = %v, want fallback to point forecast", msg.err)
+	}
+	if msg.source != landForecastSource {
+		t.Errorf("source = %q, want %q", msg.source, landForecastSource)
+	}
+	if msg.conditions == nil || msg.conditions.Wind.Direction != "SW" || msg.conditions.Location != "Chatham" {
+		t.Errorf("conditions = %+v, want first period summarized for Chatham", msg.conditions)
+	}
+
+	m := NewModel("", "", "")
+	m.state = StateDisplay
+	updatedModel, _ := m.Update(msg)
+	m = updatedModel.(Model)
+	if !strings.Contains(m.renderWeatherSimple(), landForecastSource) {
+		t.Error("weather pane should name the fallback source")
+	}
+
+	// Without coordinates there is nothing to fall back to
+	msg = fetchZoneWeather(context.Background(), client, "ANZ999", nil)().(zoneWeatherFetchedMsg)
+	if msg.err == nil {
+		t.Error("Expected zone error without a location")
+	}
+
+	// If the point forecast also fails, the zone error is reported
+	client.mockWeatherClient.err = fmt.Errorf("points lookup failed")
+	msg = fetchZoneWeather(context.Background(), client, "ANZ999", location)().(zoneWeatherFetchedMsg)
+	if msg.err == nil || msg.err.Error() != "no forecast product for zone" {
+		t.Errorf("err = %v, want the zone error", msg.err)
+	}
+}
+
+// concurrencyWeatherClient records how many zone forecasts are in flight at once
+type concurrencyWeatherClient struct {
+	mockWeatherClient
+	failZone string
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (c *concurrencyWeatherClient) GetMarineForecastByZone(ctx context.Context, marineZone string) (*models.MarineConditions, *models.ThreeDayForecast, error) {
+	c.mu.Lock()
+	c.inFlight++
+	if c.inFlight > c.maxInFlight {
+		c.maxInFlight = c.inFlight
+	}
+	c.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+
+	if marineZone == c.failZone {
+		return nil, nil, fmt.Errorf("zone forecast unavailable")
+	}
+	return c.conditions, c.forecast, nil
+}
+
+// zoneAlertClient fails for one zone and reports a gale warning everywhere else
+type zoneAlertClient struct {
+	mockAlertClient
+	failZone string
+}
+
+func (c *zoneAlertClient) GetActiveAlertsByZone(ctx context.Context, marineZone string) (*models.AlertData, error) {
+	if marineZone == c.failZone {
+		return nil, fmt.Errorf("alerts unavailable")
+	}
+	return c.alerts, nil
+}
+
+// stationTideClient fails for one station and returns fixed predictions otherwise
+type stationTideClient struct {
+	tides       *models.TideData
+	failStation string
+}
+
+func (c *stationTideClient) GetTidePredictions(ctx context.Context, stationID, datum string, startDate, endDate time.Time) (*models.TideData, error) {
+	if stationID == c.failStation {
+		return nil, fmt.Errorf("tides unavailable")
+	}
+	return c.tides, nil
+}
+
+func (c *stationTideClient) GetMeteorologicalData(ctx context.Context, stationID string, startDate, endDate time.Time) (*models.MarineConditions, error) {
+	return &models.MarineConditions{}, nil
+}
+
+func (c *stationTideClient) GetDatums(ctx context.Context, stationID string) (*models.DatumOffsets, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (c *stationTideClient) GetWaterLevel(ctx context.Context, stationID, datum string) (*models.WaterLevel, error) {
+	return nil, noaa.ErrNoWaterLevel
+}
+
+// TestIntegration_PortsOverview tests the multi-port overview: bounded concurrent
+// fetching, independent per-card states and opening a port from its card
+func TestIntegration_PortsOverview(t *testing.T) {
+	weather := &concurrencyWeatherClient{
+		mockWeatherClient: mockWeatherClient{
+			conditions: &models.MarineConditions{
+				Wind: models.WindData{Direction: "SW", SpeedMin: 10, SpeedMax: 15},
+				Seas: models.SeaState{HeightMin: 2, HeightMax: 4},
+			},
+		},
+		failZone: "ANZ999",
+	}
+	alerts := &zoneAlertClient{
+		mockAlertClient: mockAlertClient{alerts: &models.AlertData{Alerts: []models.Alert{{
+			Event:    "Gale Warning",
+			Severity: models.SeverityModerate,
+			Expires:  time.Now().Add(time.Hour),
+		}}}},
+		failZone: "ANZ999",
+	}
+	tides := &stationTideClient{
+		tides: &models.TideData{Events: []models.TideEvent{
+			{Time: time.Now().Add(2 * time.Hour), Type: models.TideHigh, Height: 4.2},
+		}},
+		failStation: "0000000",
+	}
+
+	m := NewModel("", "", "")
+	m.width = 120
+	m.height = 60
+	m.weatherClient = weather
+	m.alertClient = alerts
+	m.tideClient = tides
+	m.savedPorts = []models.Port{
+		{Name: "Broken Harbor", MarineZoneID: "ANZ999", TideStationID: "0000000"},
+	}
+	for i := 0; i < 6; i++ {
+		m.savedPorts = append(m.savedPorts, models.Port{
+			Name:          fmt.Sprintf("Harbor %d", i),
+			MarineZoneID:  "ANZ254",
+			TideStationID: "8447435",
+			Latitude:      41.68,
+			Longitude:     -69.95,
+		})
+	}
+	m.portList = createPortList(m.savedPorts, 80, 20, m.styles)
+	m.state = StateSavedPorts
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	m = updatedModel.(Model)
+	if m.state != StateOverview {
+		t.Fatalf("state = %v, want StateOverview", m.state)
+	}
+	for _, card := range m.overview {
+		if !card.loading {
+			t.Errorf("card %q should start loading", card.port.Name)
+		}
+	}
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		t.Fatalf("cmd() returned %T, want tea.BatchMsg", cmd())
+	}
+
+	// Run the fetches the way Bubble Tea does, all at once
+	var wg sync.WaitGroup
+	results := make(chan portSummaryFetchedMsg, len(batch))
+	for _, c := range batch {
+		wg.Add(1)
+		go func(c tea.Cmd) {
+			defer wg.Done()
+			if msg, ok := c().(portSummaryFetchedMsg); ok {
+				results <- msg
+			}
+		}(c)
+	}
+	wg.Wait()
+	close(results)
+
+	if weather.maxInFlight > overviewConcurrency {
+		t.Errorf("max concurrent fetches = %d, want at most %d", weather.maxInFlight, overviewConcurrency)
+	}
+
+	// Deliver one result and check the other cards are still loading
+	var msgs []portSummaryFetchedMsg
+	for msg := range results {
+		msgs = append(msgs, msg)
+	}
+	if len(msgs) != len(m.savedPorts) {
+		t.Fatalf("got %d summaries, want %d", len(msgs), len(m.savedPorts))
+	}
+	updatedModel, _ = m.Update(msgs[0])
+	m = updatedModel.(Model)
+	loading := 0
+	for _, card := range m.overview {
+		if card.loading {
+			loading++
+		}
+	}
+	if loading != len(m.savedPorts)-1 {
+		t.Errorf("%d cards loading after one result, want %d", loading, len(m.savedPorts)-1)
+	}
+	for _, msg := range msgs[1:] {
+		updatedModel, _ = m.Update(msg)
+		m = updatedModel.(Model)
+	}
+
+	if m.overview[0].err == nil {
+		t.Error("Broken Harbor card should show an error")
+	}
+	good := m.overview[1]
+	if good.err != nil || good.alert == nil || good.nextTide == nil || good.conditions == nil {
+		t.Errorf("Harbor 0 summary = %+v, want alert, tide and conditions", good)
+	}
+	view := m.viewOverview()
+	for _, want := range []string{"Unavailable", "Gale Warning", "High 4.2 ft", "SW 10-15 kt"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("overview should contain %q", want)
+		}
+	}
+
+	// Results from an earlier overview are ignored
+	stale := msgs[0]
+	stale.generation--
+	stale.summary = portSummary{port: m.overview[stale.index].port, loading: true}
+	updatedModel, _ = m.Update(stale)
+	m = updatedModel.(Model)
+	if m.overview[stale.index].loading {
+		t.Error("stale summary should be ignored")
+	}
+
+	// Selecting a card opens the full display for that port
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updatedModel.(Model)
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(Model)
+	if m.state != StateLoading || m.currentPort == nil || m.currentPort.Name != "Harbor 0" {
+		t.Errorf("Enter should load Harbor 0, got state %v port %v", m.state, m.currentPort)
+	}
+}
+
+// splitTideClient fails tide predictions and meteorological data independently
+type splitTideClient struct {
+	tideErr error
+	metErr  error
+}
+
+func (c *splitTideClient) GetTidePredictions(ctx context.Context, stationID, datum string, startDate, endDate time.Time) (*models.TideData, error) {
+	if c.tideErr != nil {
+		return nil, c.tideErr
+	}
+	return &models.TideData{StationID: stationID, Events: []models.TideEvent{
+		{Time: startDate.Add(3 * time.Hour), Type: models.TideHigh, Height: 4.2},
+	}}, nil
+}
+
+func (c *splitTideClient) GetMeteorologicalData(ctx context.Context, stationID string, startDate, endDate time.Time) (*models.MarineConditions, error) {
+	if c.metErr != nil {
+		return nil, c.metErr
+	}
+	return &models.MarineConditions{Temperature: 51.3, Pressure: 1014.2}, nil
+}
+
+func (c *splitTideClient) GetDatums(ctx context.Context, stationID string) (*models.DatumOffsets, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (c *splitTideClient) GetWaterLevel(ctx context.Context, stationID, datum string) (*models.WaterLevel, error) {
+	return nil, noaa.ErrNoWaterLevel
+}
+
+// TestIntegration_PartialTideFetch tests that tide predictions and met data are shown
+// or annotated independently when either fetch fails
+func TestIntegration_PartialTideFetch(t *testing.T) {
+	tests := []struct {
+		name      string
+		tideErr   error
+		metErr    error
+		wantTides bool
+		wantMet   bool
+		wantNotes []string
+	}{
+		{"both succeed", nil, nil, true, true, nil},
+		{"met fails", nil, fmt.Errorf("no met sensor"), true, false, []string{"Met data unavailable"}},
+		{"tides fail", fmt.Errorf("predictions down"), nil, false, true, []string{"Tide predictions unavailable"}},
+		{"both fail", fmt.Errorf("predictions down"), fmt.Errorf("no met sensor"), false, false, []string{"Tide predictions unavailable", "Met data unavailable"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel("", "", "")
+			m.state = StateDisplay
+			m.activePane = PaneTides
+			m.width = 100
+			m.height = 60
+			m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+			m.tideStation = &stations.TideStationInfo{ID: "8447435", Name: "Chatham"}
+			m.loadingTides = true
+
+			client := &splitTideClient{tideErr: tt.tideErr, metErr: tt.metErr}
+			msg := fetchTideData(context.Background(), client, models.SystemClock, "8447435", models.DatumMLLW, DefaultTideWindowDays)()
+			updatedModel, _ := m.Update(msg)
+			m = updatedModel.(Model)
+
+			if m.loadingTides {
+				t.Error("loadingTides should be cleared")
+			}
+			if (m.tides != nil) != tt.wantTides {
+				t.Errorf("tides present = %v, want %v", m.tides != nil, tt.wantTides)
+			}
+			if (m.tideConditions != nil) != tt.wantMet {
+				t.Errorf("met conditions present = %v, want %v", m.tideConditions != nil, tt.wantMet)
+			}
+
+			view := m.View()
+			if tt.wantMet && !strings.Contains(view, "Air Temp: 51.3°F") {
+				t.Error("view should show the met data that arrived")
+			}
+			if tt.wantTides && !strings.Contains(view, "4.2 ft") {
+				t.Error("view should show the tide predictions that arrived")
+			}
+			for _, note := range tt.wantNotes {
+				if !strings.Contains(view, note) {
+					t.Errorf("view should note %q", note)
+				}
+			}
+			if len(tt.wantNotes) == 0 && strings.Contains(view, "unavailable") {
+				t.Error("view should not annotate failures when both fetches succeed")
+			}
+		})
+	}
+}
+
+// TestIntegration_FailedTideFetchAfterPortSwitch tests that a failed fetch for a new
+// port's station doesn't leave the previous station's tides on display as its own
+func TestIntegration_FailedTideFetchAfterPortSwitch(t *testing.T) {
+	m := NewModel("", "", "")
+	m.width, m.height = 100, 60
+	m.activePane = PaneTides
+	m, _ = m.loadPort(models.Port{Name: "Chatham", MarineZoneID: "ANZ254", Latitude: 41.68, Longitude: -69.95})
+	m.tideStation = &stations.TideStationInfo{ID: "8447435", Name: "Chatham"}
+	msg := fetchTideData(context.Background(), &splitTideClient{}, models.SystemClock, "8447435", models.DatumMLLW, DefaultTideWindowDays)()
+	updatedModel, _ := m.Update(msg)
+	m = updatedModel.(Model)
+	if m.tides == nil || m.tideConditions == nil {
+		t.Fatal("the first port's tides and conditions should be shown")
+	}
+
+	// A failed refresh of the same station keeps its predictions
+	msg = fetchTideData(context.Background(), &splitTideClient{tideErr: fmt.Errorf("predictions down")}, models.SystemClock, "8447435", models.DatumMLLW, DefaultTideWindowDays)()
+	updatedModel, _ = m.Update(msg)
+	m = updatedModel.(Model)
+	if m.tides == nil {
+		t.Error("a failed refresh should keep the same station's predictions")
+	}
+
+	// Another port's station failing clears them and shows the error
+	m, _ = m.loadPort(models.Port{Name: "Boston", MarineZoneID: "ANZ230", Latitude: 42.35, Longitude: -71.05})
+	m.state = StateDisplay
+	m.tideStation = &stations.TideStationInfo{ID: "8443970", Name: "Boston"}
+	failing := &splitTideClient{tideErr: fmt.Errorf("predictions down"), metErr: fmt.Errorf("no met sensor")}
+	updatedModel, _ = m.Update(fetchTideData(context.Background(), failing, models.SystemClock, "8443970", models.DatumMLLW, DefaultTideWindowDays)())
+	m = updatedModel.(Model)
+	if m.tides != nil || m.tideConditions != nil {
+		t.Errorf("tides = %v conditions = %v, want the previous station's data cleared", m.tides, m.tideConditions)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Tide predictions unavailable") || strings.Contains(view, "showing previous predictions") {
+		t.Error("view should show the error without the previous station's predictions")
+	}
+}
+
+// TestIntegration_TideWindow tests fetching a configured number of days of predictions
+// and paging through them a day at a time
+func TestIntegration_TideWindow(t *testing.T) {
+	now := time.Date(2025, 11, 26, 9, 0, 0, 0, time.UTC)
+
+	// A week of tides, four a day about 6h12m apart
+	var events []models.TideEvent
+	for i := 0; i < 28; i++ {
+		typ, height := models.TideHigh, 9.5
+		if i%2 == 1 {
+			typ, height = models.TideLow, 0.5
+		}
+		events = append(events, models.TideEvent{Time: now.Add(time.Duration(i) * 372 * time.Minute), Type: typ, Height: height})
+	}
+	client := &mockTideClient{tides: &models.TideData{StationID: "8443970", Events: events}}
+
+	m := NewModel("", "", "").WithClock(models.FixedClock(now)).WithTideWindowDays(7)
+	m.tideClient = client
+	m.state = StateDisplay
+	m.activePane = PaneTides
+	m.width, m.height = 100, 60
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+
+	updated, cmd := m.Update(tideStationFoundMsg{stations: []stations.TideStationInfo{{ID: "8443970", Name: "Boston"}}})
+	m = updated.(Model)
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+
+	if !client.gotStart.Equal(now) || !client.gotEnd.Equal(now.AddDate(0, 0, 7)) {
+		t.Errorf("predictions requested for %v to %v, want a 7 day window from %v", client.gotStart, client.gotEnd, now)
+	}
+
+	// The events run from Wed Nov 26 to Wed Dec 3
+	view := m.View()
+	if !strings.Contains(view, "Tides for Wed, Nov 26:") || !strings.Contains(view, "day 1 of 8") || !strings.Contains(view, "[/]: Prev/next day") {
+		t.Errorf("first day should be labelled with paging help, got:\n%s", view)
+	}
+	if strings.Contains(view, events[3].Time.Format("Jan 2, 3:04 PM")) {
+		t.Error("first day should only list that day's tides")
+	}
+	// The day's three tides and the first one after midnight
+	if got := m.chartEvents(); len(got) != 4 || !got[0].Time.Equal(events[0].Time) || !got[3].Time.Equal(events[3].Time) {
+		t.Errorf("chart should cover the first day, got %v", got)
+	}
+
+	press := func(m Model, r string) Model {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(r)})
+		return updated.(Model)
+	}
+	m = press(m, "[")
+	if m.tidePage != 0 {
+		t.Errorf("[ on the first page should stay put, page = %d", m.tidePage)
+	}
+	for i := 0; i < 10; i++ {
+		m = press(m, "]")
+	}
+	if m.tidePage != 7 {
+		t.Fatalf("] should stop at the last day, page = %d", m.tidePage)
+	}
+	view = m.View()
+	if !strings.Contains(view, "Tides for Wed, Dec 3:") || !strings.Contains(view, "day 8 of 8") || !strings.Contains(view, events[27].Time.Format("Jan 2, 3:04 PM")) {
+		t.Errorf("last day should list the last events, got:\n%s", view)
+	}
+	// The last tide the day before, then the day's two
+	if got := m.chartEvents(); len(got) != 3 || !got[0].Time.Equal(events[25].Time) {
+		t.Errorf("chart should lead into the last day, got %v", got)
+	}
+
+	m = press(m, "[")
+	view = m.View()
+	if !strings.Contains(view, "Tides for Tue, Dec 2:") || !strings.Contains(view, events[22].Time.Format("Jan 2, 3:04 PM")) || strings.Contains(view, events[26].Time.Format("Jan 2, 3:04 PM")) {
+		t.Errorf("[ should go back a day, got:\n%s", view)
+	}
+	if got := m.chartEvents(); len(got) != 6 || !got[0].Time.Equal(events[21].Time) || !got[5].Time.Equal(events[26].Time) {
+		t.Errorf("chart should be centered on Dec 2, got %v", got)
+	}
+}
+
+// TestIntegration_ObservedWaterLevel tests comparing the latest observed water level with
+// the prediction in the tides pane
+func TestIntegration_ObservedWaterLevel(t *testing.T) {
+	observedAt := time.Date(2025, 11, 27, 12, 6, 0, 0, time.UTC)
+	level := func(observed, predicted float64) *models.WaterLevel {
+		return &models.WaterLevel{StationID: "8443970", Datum: models.DatumMLLW, Time: observedAt, Observed: observed, Predicted: predicted}
+	}
+
+	tests := []struct {
+		name     string
+		level    *models.WaterLevel
+		levelErr error
+		want     string
+		wantNote bool
+	}{
+		{"surge", level(4.1, 3.5), nil, "Observed 4.1 ft at 12:06 PM, 0.6 ft above prediction", false},
+		{"setdown", level(2.9, 3.5), nil, "Observed 2.9 ft at 12:06 PM, 0.6 ft below prediction", false},
+		{"as predicted", level(3.52, 3.5), nil, "Observed 3.5 ft at 12:06 PM, as predicted", false},
+		{"no sensor", nil, nil, "", false},
+		{"fetch failed", nil, fmt.Errorf("timeout"), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel("", "", "")
+			m.state = StateDisplay
+			m.activePane = PaneTides
+			m.width, m.height = 100, 60
+			m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+			m.tideStation = &stations.TideStationInfo{ID: "8443970", Name: "Boston"}
+
+			client := &mockTideClient{tides: &models.TideData{}, waterLevel: tt.level, waterLevelErr: tt.levelErr}
+			updated, _ := m.Update(fetchTideData(context.Background(), client, models.SystemClock, "8443970", models.DatumMLLW, DefaultTideWindowDays)())
+			view := updated.(Model).View()
+
+			if tt.want != "" && !strings.Contains(view, tt.want) {
+				t.Errorf("view should contain %q, got:\n%s", tt.want, view)
+			}
+			if tt.want == "" && strings.Contains(view, "at 12:06 PM") {
+				t.Error("view should not show an observed level without a reading")
+			}
+			if got := strings.Contains(view, "Observed water level unavailable"); got != tt.wantNote {
+				t.Errorf("failure note shown = %v, want %v", got, tt.wantNote)
+			}
+		})
+	}
+}
+
+// TestIntegration_DatumComparison tests showing tide heights in a second datum
+func TestIntegration_DatumComparison(t *testing.T) {
+	boston := &models.DatumOffsets{StationID: "8443970", Elevations: map[string]float64{
+		models.DatumMLLW: 0.0,
+		models.DatumMSL:  4.82,
+	}}
+
+	tests := []struct {
+		name      string
+		datums    *models.DatumOffsets
+		datumsErr error
+		want      string
+	}{
+		{"converted", boston, nil, "10.2 ft  (5.4 ft MSL)"},
+		{"datum not published", &models.DatumOffsets{StationID: "8443970", Elevations: map[string]float64{models.DatumMLLW: 0}}, nil, "MSL not published for this station"},
+		{"offsets unavailable", nil, fmt.Errorf("no datums"), "Datum offsets unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockTideClient{datums: tt.datums, datumsErr: tt.datumsErr}
+			m := NewModel("", "", "")
+			m.tideClient = client
+			m.state = StateDisplay
+			m.activePane = PaneTides
+			m.width = 100
+			m.height = 60
+			m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ230", Name: "Boston Harbor"}
+			m.tideStation = &stations.TideStationInfo{ID: "8443970", Name: "Boston"}
+			m.tides = &models.TideData{StationID: "8443970", Datum: models.DatumMLLW, Events: []models.TideEvent{
+				{Time: time.Now().Add(time.Hour), Type: models.TideHigh, Height: 10.2},
+			}}
+
+			updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+			m = updatedModel.(Model)
+			if m.compareDatum != models.DatumMSL {
+				t.Fatalf("compareDatum = %q, want MSL", m.compareDatum)
+			}
+			if cmd == nil {
+				t.Fatal("expected the station's datum offsets to be fetched")
+			}
+			if !strings.Contains(m.View(), "Loading datum offsets") {
+				t.Error("view should show offsets loading")
+			}
+
+			updatedModel, _ = m.Update(cmd())
+			m = updatedModel.(Model)
+			view := m.View()
+			if !strings.Contains(view, "Datum: MLLW (also MSL)") {
+				t.Error("header should name both datums")
+			}
+			if !strings.Contains(view, tt.want) {
+				t.Errorf("view missing %q", tt.want)
+			}
+
+			// Offsets are fetched once per station
+			updatedModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+			m = updatedModel.(Model)
+			if m.compareDatum != models.DatumMHHW || cmd != nil {
+				t.Errorf("next datum = %q (fetch %v), want MHHW without refetching", m.compareDatum, cmd != nil)
+			}
+		})
+	}
+}
+
+// delayedWeatherClient answers zone forecasts after a delay, recording the context
+type delayedWeatherClient struct {
+	mockWeatherClient
+	delay time.Duration
+	ctx   context.Context
+}
+
+func (c *delayedWeatherClient) GetMarineForecastByZone(ctx context.Context, marineZone string) (*models.MarineConditions, *models.ThreeDayForecast, error) {
+	c.ctx = ctx
+	select {
+	case <-time.After(c.delay):
+		return c.mockWeatherClient.GetMarineForecastByZone(ctx, marineZone)
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+// delayedTideClient answers tide predictions after a delay, recording the context
+type delayedTideClient struct {
+	mockTideClient
+	delay time.Duration
+	ctx   context.Context
+}
+
+func (c *delayedTideClient) GetTidePredictions(ctx context.Context, stationID, datum string, startDate, endDate time.Time) (*models.TideData, error) {
+	c.ctx = ctx
+	select {
+	case <-time.After(c.delay):
+		return c.mockTideClient.GetTidePredictions(ctx, stationID, datum, startDate, endDate)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// runZoneLoad starts a zone load on m and applies its parts in the order they arrive,
+// running the tide data fetch started by the station as well. observe is called after
+// each part with the model and the time since the load started.
+func runZoneLoad(t *testing.T, m Model, observe func(m Model, part tea.Msg, elapsed time.Duration)) Model {
+	t.Helper()
+	m, ctx := m.beginZoneLoad()
+	start := time.Now()
+
+	results := make(chan tea.Msg)
+	run := func(cmd tea.Cmd) { go func() { results <- cmd() }() }
+	pending := 0
+	for _, cmd := range loadZoneData(ctx, m.zoneLoad, m.weatherClient, m.alertClient, m.selectedZone.Code, m.alertZoneCodes(m.selectedZone.Code), m.location)().(tea.BatchMsg) {
+		run(cmd)
+		pending++
+	}
+	for ; pending > 0; pending-- {
+		msg := <-results
+		updated, cmd := m.Update(msg)
+		m = updated.(Model)
+		part := msg
+		if load, ok := msg.(zoneLoadMsg); ok {
+			part = load.part
+		}
+		if _, ok := part.(tideDataFetchedMsg); !ok && cmd != nil {
+			run(cmd)
+			pending++
+		}
+		observe(m, part, time.Since(start))
+	}
+	return m
+}
+
+// TestIntegration_ParallelZoneLoad tests that a zone's forecast, alerts and tides load
+// in parallel and each is shown as soon as it arrives
+func TestIntegration_ParallelZoneLoad(t *testing.T) {
+	orig := nearestTideStation
+	t.Cleanup(func() { nearestTideStation = orig })
+	nearestTideStation = func(lat, lon float64) tea.Cmd {
+		return func() tea.Msg {
+			return tideStationFoundMsg{stations: []stations.TideStationInfo{{ID: "8447435", Name: "Chatham"}}}
+		}
+	}
+
+	const delay = 200 * time.Millisecond
+	weather := &delayedWeatherClient{
+		mockWeatherClient: mockWeatherClient{conditions: &models.MarineConditions{Temperature: 58}, forecast: &models.ThreeDayForecast{}},
+		delay:             delay,
+	}
+	tides := &delayedTideClient{mockTideClient: mockTideClient{tides: &models.TideData{Events: testTideEvents()}}, delay: delay}
+
+	m := NewModel("", "", "")
+	m.weatherClient = weather
+	m.alertClient = &mockAlertClient{alerts: &models.AlertData{}}
+	m.tideClient = tides
+	m.width, m.height = 100, 40
+	m.state = StateLoading
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+	m.location = &geocoding.Location{Latitude: 41.68, Longitude: -69.95}
+
+	var last time.Duration
+	m = runZoneLoad(t, m, func(m Model, part tea.Msg, elapsed time.Duration) {
+		last = elapsed
+		if m.state != StateDisplay {
+			t.Errorf("state = %v after the first part, want StateDisplay", m.state)
+		}
+		if _, ok := part.(zoneAlertsFetchedMsg); ok {
+			// The fast alerts are on screen while the slow forecast is still loading
+			if elapsed >= delay || !m.loadingWeather || m.alerts == nil {
+				t.Errorf("alerts shown at %v with loadingWeather = %v, want them before the forecast", elapsed, m.loadingWeather)
+			}
+			if !strings.Contains(m.View(), "Fetching marine forecast") {
+				t.Error("display should show the forecast still loading")
+			}
+		}
+	})
+
+	if m.weather == nil || m.alerts == nil || m.tides == nil {
+		t.Fatalf("weather = %v alerts = %v tides = %v, want every part loaded", m.weather, m.alerts, m.tides)
+	}
+	// The forecast and the tide data (started as soon as the station was found) overlap
+	if last >= 2*delay-delay/4 {
+		t.Errorf("zone load took %v, want the forecast and tides fetched in parallel (each takes %v)", last, delay)
+	}
+
+	// The forecast and tide requests share the load's context: a new load cancels both
+	m, _ = m.beginZoneLoad()
+	if weather.ctx.Err() == nil || tides.ctx.Err() == nil {
+		t.Error("starting a new zone load should cancel the previous load's requests")
+	}
+
+	// Parts of the superseded load are dropped
+	m.loadingWeather = true
+	updatedModel, _ := m.Update(zoneLoadMsg{load: m.zoneLoad - 1, part: zoneWeatherFetchedMsg{}})
+	if !updatedModel.(Model).loadingWeather {
+		t.Error("a part of a superseded load should be ignored")
+	}
+
+	// So is the tide data its station started, cancelled or not
+	updatedModel, cmd := m.Update(tideStationFoundMsg{stations: []stations.TideStationInfo{{ID: "8447435"}}, ctx: context.Background(), load: m.zoneLoad - 1})
+	m = updatedModel.(Model)
+	tideMsg, ok := cmd().(zoneLoadMsg)
+	if !ok || tideMsg.load != m.zoneLoad-1 {
+		t.Fatalf("tide data fetch returned %#v, want it tagged with load %d", tideMsg, m.zoneLoad-1)
+	}
+	m.tideErr = nil
+	updatedModel, _ = m.Update(zoneLoadMsg{load: m.zoneLoad - 1, part: tideDataFetchedMsg{tideErr: context.Canceled}})
+	if got := updatedModel.(Model); got.tideErr != nil || !got.loadingTides {
+		t.Errorf("tideErr = %v loadingTides = %v after a superseded load's tide data, want it ignored", got.tideErr, got.loadingTides)
+	}
+}
+
+// TestIntegration_ZoneLoadPartialFailure tests that each part of a zone load is shown
+// whichever other parts failed
+func TestIntegration_ZoneLoadPartialFailure(t *testing.T) {
+	orig := nearestTideStation
+	t.Cleanup(func() { nearestTideStation = orig })
+
+	tests := []struct {
+		name        string
+		weatherErr  error
+		alertsErr   error
+		stationErr  error
+		wantWeather bool
+		wantAlerts  bool
+		wantStation bool
+	}{
+		{"all succeed", nil, nil, nil, true, true, true},
+		{"weather fails", fmt.Errorf("forecast down"), nil, nil, false, true, true},
+		{"alerts and station fail", nil, fmt.Errorf("alerts down"), fmt.Errorf("no stations"), true, false, false},
+		{"all fail", fmt.Errorf("forecast down"), fmt.Errorf("alerts down"), fmt.Errorf("no stations"), false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nearestTideStation = func(lat, lon float64) tea.Cmd {
+				return func() tea.Msg {
+					if tt.stationErr != nil {
+						return tideStationFoundMsg{err: tt.stationErr}
+					}
+					return tideStationFoundMsg{stations: []stations.TideStationInfo{{ID: "8447435", Name: "Chatham"}}}
+				}
+			}
+
+			m := NewModel("", "", "")
+			m.weatherClient = &mockWeatherClient{
+				conditions: &models.MarineConditions{Temperature: 58},
+				forecast:   &models.ThreeDayForecast{},
+				err:        tt.weatherErr,
+			}
+			m.alertClient = &mockAlertClient{alerts: &models.AlertData{}, err: tt.alertsErr}
+			m.tideClient = &mockTideClient{tides: &models.TideData{Events: testTideEvents()}}
+			m.state = StateLoading
+			m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+			m.location = &geocoding.Location{Latitude: 41.68, Longitude: -69.95}
+
+			m = runZoneLoad(t, m, func(Model, tea.Msg, time.Duration) {})
+
+			if m.state != StateDisplay {
+				t.Errorf("state = %v, want StateDisplay", m.state)
+			}
+			if m.loadingWeather || m.loadingAlerts || m.loadingTides {
+				t.Error("per-section loading flags should be cleared")
+			}
+			if (m.weather != nil) != tt.wantWeather {
+				t.Errorf("weather present = %v, want %v", m.weather != nil, tt.wantWeather)
+			}
+			if (m.alerts != nil) != tt.wantAlerts {
+				t.Errorf("alerts present = %v, want %v", m.alerts != nil, tt.wantAlerts)
+			}
+			if (m.tideStation != nil) != tt.wantStation || (m.tides != nil) != tt.wantStation {
+				t.Errorf("tide station present = %v tides present = %v, want %v", m.tideStation != nil, m.tides != nil, tt.wantStation)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// keyMap is the single source of truth for keybindings. Handlers match keys
+// against it and the help overlay is rendered from it, so the two stay in sync.
+type keyMap struct {
+	// Global
+	Quit       key.Binding
+	Help       key.Binding
+	GoHome     key.Binding
+	SavedPorts key.Binding
+
+	// Forecast display
+	EditPorts    key.Binding
+	Refresh      key.Binding
+	RawForecast  key.Binding
+	SwitchPane   key.Binding
+	Reprovision  key.Binding
+	UpdateZones  key.Binding
+	PrevZone     key.Binding
+	NextZone     key.Binding
+	AlertFilter  key.Binding
+	AlertTimes   key.Binding
+	Beaufort     key.Binding
+	StatusBar    key.Binding
+	AlertLog     key.Binding
+	DatumCycle   key.Binding
+	TideTime     key.Binding
+	TideClock    key.Binding
+	ClockFormat  key.Binding
+	TidePrevPage key.Binding
+	TideNextPage key.Binding
+	Export       key.Binding
+	CopyZone     key.Binding
+	CopyStation  key.Binding
+
+	// Lists and prompts
+	Select     key.Binding
+	Back       key.Binding
+	NewPort    key.Binding
+	DeletePort key.Binding
+	UpdatePort key.Binding
+	SetHome    key.Binding
+	Overview   key.Binding
+	Filter     key.Binding
+	Up         key.Binding
+	Down       key.Binding
+	NewSearch  key.Binding
+	Confirm    key.Binding
+	Cancel     key.Binding
+
+	// Search
+	Submit        key.Binding
+	ZoneCode      key.Binding
+	StationSearch key.Binding
+
+	// Error
+	Retry       key.Binding
+	WidenSearch key.Binding
+}
+
+// defaultKeyMap returns the application's keybindings
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Quit:       key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q/ctrl+c", "quit (ctrl+c while typing)")),
+		Help:       key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "toggle this help")),
+		GoHome:     key.NewBinding(key.WithKeys("H"), key.WithHelp("H", "jump to the home port")),
+		SavedPorts: key.NewBinding(key.WithKeys("P"), key.WithHelp("P", "saved ports (esc goes back)")),
+
+		EditPorts:    key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "saved ports")),
+		Refresh:      key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "refresh forecast, alerts and tides")),
+		RawForecast:  key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "toggle raw NOAA forecast text")),
+		SwitchPane:   key.NewBinding(key.WithKeys("tab", "shift+tab"), key.WithHelp("tab", "switch weather/tides")),
+		Reprovision:  key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "re-provision empty reference data")),
+		UpdateZones:  key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "update to newer marine zones data")),
+		PrevZone:     key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "previous nearby zone")),
+		NextZone:     key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "next nearby zone")),
+		AlertFilter:  key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "hide/show marine statements")),
+		AlertTimes:   key.NewBinding(key.WithKeys("A"), key.WithHelp("A", "show exact alert onset/expiry times")),
+		Beaufort:     key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "show the wind's Beaufort force")),
+		StatusBar:    key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "hide/show the conditions status bar")),
+		AlertLog:     key.NewBinding(key.WithKeys("L"), key.WithHelp("L", "show recently expired alerts")),
+		DatumCycle:   key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "also show tide heights in another datum")),
+		TideTime:     key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "tide height at a given time")),
+		TideClock:    key.NewBinding(key.WithKeys("C"), key.WithHelp("C", "switch between the tide chart and tide clock")),
+		ClockFormat:  key.NewBinding(key.WithKeys("T"), key.WithHelp("T", "switch between 12- and 24-hour times")),
+		TidePrevPage: key.NewBinding(key.WithKeys("["), key.WithHelp("[", "previous day of tides")),
+		TideNextPage: key.NewBinding(key.WithKeys("]"), key.WithHelp("]", "next day of tides")),
+		Export:       key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "export the display to a text file")),
+		CopyZone:     key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "copy the marine zone code")),
+		CopyStation:  key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "copy the tide station ID")),
+
+		Select:     key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select")),
+		Back:       key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+		NewPort:    key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "new port")),
+		DeletePort: key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete port")),
+		UpdatePort: key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "update the port's zone and station")),
+		SetHome:    key.NewBinding(key.WithKeys("*"), key.WithHelp("*", "make the port home")),
+		Overview:   key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "overview of all saved ports")),
+		Filter:     key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter the list (enter applies, esc clears)")),
+		Up:         key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "previous port")),
+		Down:       key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "next port")),
+		NewSearch:  key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "new search")),
+		Confirm:    key.NewBinding(key.WithKeys("y", "Y"), key.WithHelp("y", "confirm")),
+		Cancel:     key.NewBinding(key.WithKeys("n", "N", "esc"), key.WithHelp("n/esc", "cancel")),
+
+		Submit:        key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "search")),
+		ZoneCode:      key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "jump to zone by code")),
+		StationSearch: key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "search tide stations by name")),
+
+		Retry:       key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "retry failed provisioning")),
+		WidenSearch: key.NewBinding(key.WithKeys("+", "="), key.WithHelp("+", "search a wider radius for marine zones")),
+	}
+}
+
+// helpSection is a titled group of bindings shown in the help overlay
+type helpSection struct {
+	title    string
+	bindings []key.Binding
+}
+
+// helpSections groups the bindings by the screen they apply to
+func (k keyMap) helpSections() []helpSection {
+	return []helpSection{
+		{"Anywhere", []key.Binding{k.Help, k.GoHome, k.SavedPorts, k.Quit}},
+		{"Forecast display", []key.Binding{k.EditPorts, k.Refresh, k.RawForecast, k.SwitchPane, k.PrevZone, k.NextZone, k.Beaufort, k.StatusBar, k.AlertFilter, k.AlertTimes, k.AlertLog, k.DatumCycle, k.TideTime, k.TideClock, k.ClockFormat, k.TidePrevPage, k.TideNextPage, k.Export, k.CopyZone, k.CopyStation, k.Reprovision, k.UpdateZones}},
+		{"Saved ports", []key.Binding{k.Select, k.Filter, k.Overview, k.NewPort, k.DeletePort, k.UpdatePort, k.SetHome, k.Back}},
+		{"Ports overview", []key.Binding{k.Up, k.Down, withHelp(k.Select, "enter", "open port"), k.Back}},
+		{"Delete / overwrite / update confirmation", []key.Binding{k.Confirm, k.Cancel}},
+		{"Zone list / place / station chooser", []key.Binding{k.Select, k.Filter, k.NewSearch, k.Back}},
+		{"Search", []key.Binding{k.Submit, k.ZoneCode, k.StationSearch}},
+		{"Zone code / station name / port name / tide time", []key.Binding{withHelp(k.Select, "enter", "confirm"), k.Back}},
+		{"Error", []key.Binding{k.Reprovision, k.Retry, k.WidenSearch, withHelp(k.Select, "any key", "back to search")}},
+	}
+}
+
+// withHelp returns a copy of b with different help text
+func withHelp(b key.Binding, keys, desc string) key.Binding {
+	b.SetHelp(keys, desc)
+	return b
+}
+
+// viewHelp renders the keybinding reference modal
+func (m Model) viewHelp() string {
+	width := 0
+	for _, section := range m.keys.helpSections() {
+		for _, b := range section.bindings {
+			if w := lipgloss.Width(m.styles.text(b.Help().Key)); w > width {
+				width = w
+			}
+		}
+	}
+
+	content := []string{m.styles.title.Render(m.styles.text("⌨ Keyboard Shortcuts"))}
+	for _, section := range m.keys.helpSections() {
+		content = append(content, "", m.styles.label.Render(section.title))
+		for _, b := range section.bindings {
+			keys := fmt.Sprintf("%-*s", width, m.styles.text(b.Help().Key))
+			content = append(content, "  "+m.styles.value.Render(keys)+"  "+m.styles.muted.Render(b.Help().Desc))
+		}
+	}
+	content = append(content, "", m.styles.help.Render("?/Esc: Close"))
+	return strings.Join(content, "\n")
+}
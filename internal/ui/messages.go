@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"context"
+
 	"github.com/ngmaloney/marine-terminal/internal/models"
 	"github.com/ngmaloney/marine-terminal/internal/stations"
 )
@@ -10,14 +12,35 @@ import (
 // tideStationFoundMsg is sent when tide stations are found
 type tideStationFoundMsg struct {
 	stations []stations.TideStationInfo
+	dbEmpty  bool            // True when the lookup failed because the tide_stations table is empty
+	ctx      context.Context // Zone load that found the station, shared by its tide data fetch; nil outside a zone load
+	load     int             // Number of that zone load; its tide data is delivered as part of it
 	err      error
 }
 
-// tideDataFetchedMsg is sent when tide predictions are fetched
+// tideDataFetchedMsg is sent when tide predictions, the observed water level and station
+// meteorological data are fetched. Each may fail independently.
 type tideDataFetchedMsg struct {
+	stationID  string
 	tides      *models.TideData
 	conditions *models.MarineConditions
-	err        error
+	waterLevel *models.WaterLevel
+	tideErr    error // Tide predictions failed
+	metErr     error // Meteorological data failed
+	levelErr   error // Observed water level failed, or the station has no sensor (noaa.ErrNoWaterLevel)
+}
+
+// observationHistoryMsg is sent when a tide station's stored temperature history has been read
+type observationHistoryMsg struct {
+	stationID string
+	history   *models.MarineConditions // Only AirTempHistory and WaterTempHistory are set
+}
+
+// datumOffsetsFetchedMsg is sent when a tide station's datum elevations have been fetched
+type datumOffsetsFetchedMsg struct {
+	stationID string
+	offsets   *models.DatumOffsets
+	err       error
 }
 
 // weatherFetchedMsg is sent when weather data has been fetched
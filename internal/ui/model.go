@@ -1,15 +1,26 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/NimbleMarkets/ntcharts/linechart/timeserieslinechart"
+	"github.com/muesli/termenv"
 	"github.com/ngmaloney/marine-terminal/internal/database"
 	"github.com/ngmaloney/marine-terminal/internal/geocoding"
 	"github.com/ngmaloney/marine-terminal/internal/models"
@@ -32,6 +43,14 @@ const (
 	StateSavedPorts                   // List saved ports
 	StateSavePrompt                   // Prompt for saving a port
 	StateConfirmDelete                // Prompt for confirming deletion of a port
+	StateZoneCode                     // Jump directly to a marine zone by code
+	StateOverview                     // Compact summary of all saved ports
+	StateConfirmOverwrite             // Prompt for confirming a save that replaces an existing port
+	StateTideTime                     // Prompt for a time to look up the predicted tide height
+	StateCityChoice                   // Choose between the states a city-only search matched
+	StateConfirmUpdate                // Prompt for confirming a saved port's re-resolved zone and station
+	StateStationSearch                // Search NOAA tide stations by name instead of geocoding
+	StateStationList                  // Choose between the stations a name search matched
 )
 
 // ActivePane represents which pane is currently focused
@@ -54,27 +73,70 @@ type Model struct {
 	portService *ports.Service
 
 	// Search
-	searchInput textinput.Model
-	geocoder    *geocoding.Geocoder
+	searchInput   textinput.Model
+	zoneCodeInput textinput.Model
+	stationSearchInput textinput.Model
+	stationClient ports.Client // Searches NOAA stations by name, bypassing geocoding
+	geocoder    geocoding.Geocoder
+	geocodeTimeout time.Duration // How long a search may take to geocode
+	httpTimeout    time.Duration // How long a NOAA request may take
 	searchQuery string // Last search query
 
 	// Location and zones
 	location      *geocoding.Location
 	zones         []zonelookup.ZoneInfo
+	zoneRadius    float64 // Radius of the last zone search, in miles
+	zoneSearchWiden bool  // The last zone search found nothing and can be widened around location
+	zoneLoad       int                // Sequence number of the latest zone load
+	cancelZoneLoad context.CancelFunc // Cancels the latest zone load's requests
 	zoneList      list.Model
+	cityList      list.Model // Places a city-only search matched, when it was ambiguous
+	stationList   list.Model // Stations a station name search matched
 	selectedZone  *zonelookup.ZoneInfo
 	tideStations  []stations.TideStationInfo
 	tideStation   *stations.TideStationInfo
 
 	// Ports
+	currentPort *models.Port // Saved port currently on display (nil if not loaded from a saved port)
 	savedPorts []models.Port
+	portsFetched bool       // savedPorts has been read from the database
 	portList   list.Model
+	portsReturn AppState    // State esc returns to from the saved ports list; StateSavedPorts if none
 	saveInput  textinput.Model
 	saving     bool
 	portToDelete *models.Port // New: for confirmation before deleting
+	portToOverwrite string    // Name of the saved port a pending save would replace
+	portUpdate *portUpdate    // Saved port being re-resolved, for confirmation before updating
+
+	// Overview of all saved ports
+	overview           []portSummary
+	overviewCursor     int
+	overviewGeneration int // Incremented each time the overview opens, to drop stale results
 
 	// Charts
 	tideChart timeserieslinechart.Model
+	asciiChart bool // Draw the tide chart in plain ASCII instead of braille
+
+	// Styles derived from the selected color theme
+	styles styles
+
+	// Keybindings and help overlay
+	keys     keyMap
+	showHelp bool
+
+	// Raw forecast text view
+	showRawForecast bool
+	showBeaufort    bool // Show the Beaufort force next to the current wind
+	showTideClock   bool // Show the tide clock in place of the tide chart
+	hideStatusBar   bool // Hide the conditions status bar above the display
+	hideStatements  bool // Only list warnings, watches and advisories in the alerts box
+	exactAlertTimes bool // Show alert onset and expiry as clock times as well as relative ones
+	showAlertLog    bool          // List recently expired alerts below the active ones
+	alertLog        []loggedAlert // Marine alerts seen this session, oldest first
+	alertNotifier   alertNotifier // Announces new severe alerts; off unless configured
+	bell            int           // Counts bells rung, so only the latest one's bellDoneMsg ends it
+	bellRinging     bool          // The view carries the bell character
+	rawViewport     viewport.Model
 
 	// API clients
 	weatherClient noaa.WeatherClient
@@ -84,9 +146,30 @@ type Model struct {
 	// Data
 	weather  *models.MarineConditions
 	forecast *models.ThreeDayForecast
+	weatherSource string // Non-empty when weather came from a fallback product
+	zoneBoundary  *zonelookup.BoundaryDistance // Where the location lies relative to the selected zone
+	portZoneName  string // NWS name of the current port's zone, which selectedZone.Name holds the port's name in place of
 	alerts   *models.AlertData
+	alertsErr error // Last alerts fetch failed
 	tides    *models.TideData
 	tideConditions *models.MarineConditions
+	tideDataStation string // Station the tides, tide conditions and water level came from
+	tideErr        error // Last tide predictions fetch failed
+	waterLevel     *models.WaterLevel // Latest observed water level, nil if unavailable
+	levelErr       error              // Last water level fetch failed
+	tideTimeInput  textinput.Model
+	tideHeightNote string // Predicted height at the last time entered in the tide height prompt
+	metErr         error // Last station meteorological data fetch failed
+	observations   *database.ObservationRepository // Stores station observations for trends across restarts; nil disables
+	storedTrends   *models.MarineConditions        // Temperature history for tideStation read back from observations
+	settings       *database.SettingsRepository    // Stores display preferences across restarts; nil disables
+	compareDatum   string               // Datum tide heights are also shown in, "" for none
+	datumOffsets   *models.DatumOffsets // Datum elevations for the tide station in datumStation
+	datumErr       error                // Fetching datumOffsets failed
+	datumStation   string               // Tide station datumOffsets/datumErr were fetched for
+	exportPath     string // Last display export, confirmed in the help line until the next key
+	exportErr      error
+	lastCopy       *copiedMsg // Last zone code/station ID copy, confirmed in the help line until the next key
 
 	// Loading states
 	loadingWeather bool
@@ -96,11 +179,35 @@ type Model struct {
 	// Provisioning
 	spinner           spinner.Model
 	provisionStatus   string
+	provisionFraction float64 // Negative when the current step has no measurable progress
+	provisionBar      progress.Model
 	provisionChannels *provisioningStartedMsg
+	provisionJob      provisionJob // Last provisioning job started
+	provisionCtx      context.Context // Cancelled when the program exits, stopping provisioning
+	provisionRetry    bool         // The last provisioning job failed and can be retried
+	provisionCleanup  bool         // Retrying should first remove a possibly corrupt download
+	reprovisionNeeded bool   // Zones table is provisioned but empty
+	tideStationsEmpty bool   // Tide stations table is provisioned but empty
+	newerEdition      string // Newer marine zones shapefile edition available, if any
 
 	initialStationCode string // New: for direct loading via CLI arg
 	initialLocation    string
 	initialPortName    string
+	locator            geocoding.Locator // Set by --here to start from the machine's approximate position
+
+	smallCraft          models.SmallCraftThresholds // Wind and seas highlighted in the forecast
+	rating              models.RatingThresholds     // Wind and seas at which current conditions are rated rougher
+	forecastPeriodLimit int                         // Upcoming forecast periods shown; 0 shows all
+	tideWindowDays      int                         // Days of tide predictions fetched
+	snapshot            conditionsSnapshot          // Conditions last shown, to tell what a refresh changed
+	changes             conditionChanges            // What the last refresh changed, shown briefly
+	changesZone         string                      // Zone the changes are of
+	changeFlash         int                         // Counts flashes of changes, so only the latest expires them
+	zoneAutoSelectMiles float64                     // A lone zone this close is selected without the list; 0 always lists
+	tidePage            int                         // Page of tide events listed and charted
+	pressureUnit        string                      // Unit barometric pressure is shown in
+
+	clock models.Clock // Source of "now" for alert, tide and countdown logic
 }
 
 // NewModel creates a new application model
@@ -116,30 +223,205 @@ func NewModel(initialStationCode, initialLocation, initialPortName string) Model
 	si.CharLimit = 50
 	si.Width = 60
 
+	zi := textinput.New()
+	zi.Placeholder = "Enter a marine zone code (e.g. ANZ254)"
+	zi.CharLimit = 10
+	zi.Width = 60
+
+	ssi := textinput.New()
+	ssi.Placeholder = "Enter a station name or city (e.g. Chatham or Woods Hole)"
+	ssi.CharLimit = 100
+	ssi.Width = 60
+
+	tti := textinput.New()
+	tti.Placeholder = "e.g. 14:30, 2:30 PM or Tue 2:30 PM"
+	tti.CharLimit = 20
+	tti.Width = 40
+
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	s.Style = lipgloss.NewStyle().Foreground(DefaultTheme().Spinner)
 
 	tc := timeserieslinechart.New(80, 15) // Initial size, will be resized on first WindowSizeMsg
+
+	pb := progress.New(progress.WithDefaultGradient(), progress.WithWidth(50))
 	
 	return Model{
 		state:         StateLoading, // Start in loading to check for saved ports
+		portsReturn:   StateSavedPorts,
 		activePane:    PaneWeather,
 		searchInput:   ti,
 		saveInput:     si,
+		zoneCodeInput: zi,
+		stationSearchInput: ssi,
+		stationClient: ports.NewNOAAStationClient(),
+		tideTimeInput: tti,
 		geocoder:      geocoding.NewGeocoder(),
+		geocodeTimeout: geocoding.DefaultTimeout,
+		httpTimeout:    noaa.DefaultHTTPTimeout,
 		weatherClient: noaa.NewWeatherClient(),
 		alertClient:   noaa.NewAlertClient(),
 		tideClient:    noaa.NewTideClient(),
 		portService:   ports.NewService(),
 		spinner:       s,
+		provisionBar:  pb,
+		provisionFraction: -1,
+		provisionCtx:  context.Background(),
+		keys:          defaultKeyMap(),
+		styles:        newStyles(DefaultTheme()),
+		smallCraft:    models.DefaultSmallCraftThresholds,
+		rating:        models.DefaultRatingThresholds,
+		forecastPeriodLimit: DefaultForecastPeriodLimit,
+		tideWindowDays: DefaultTideWindowDays,
+		zoneAutoSelectMiles: DefaultZoneAutoSelectMiles,
+		pressureUnit:  models.PressureMillibars,
+		clock:         models.SystemClock,
 		tideChart:     tc,
+		rawViewport:   viewport.New(80, 15),
 		initialStationCode: initialStationCode,
 		initialLocation:    initialLocation,
 		initialPortName:    initialPortName,
 	}
 }
 
+// WithASCIIChart returns a copy of the model that draws the tide chart with plain
+// ASCII characters, for terminals that can't display braille
+func (m Model) WithASCIIChart(enabled bool) Model {
+	m.asciiChart = enabled || m.styles.ascii
+	return m
+}
+
+// WithASCII returns a copy of the model that draws plain ASCII in place of emoji,
+// symbols, box borders and the braille chart, for terminals that can't display them
+func (m Model) WithASCII(enabled bool) Model {
+	m.styles = m.styles.withASCII(enabled)
+	if enabled {
+		m.asciiChart = true
+		m.spinner.Spinner = spinner.Line
+	}
+	return m
+}
+
+// WithTheme returns a copy of the model that draws the UI in theme's colors
+func (m Model) WithTheme(theme Theme) Model {
+	m.styles = newStyles(theme).withASCII(m.styles.ascii).with24HourClock(m.styles.clock24h)
+	m.spinner.Style = m.spinner.Style.Foreground(theme.Spinner)
+	return m
+}
+
+// WithLocator returns a copy of the model that starts by finding zones near
+// the position reported by locator instead of showing saved ports
+func (m Model) WithLocator(locator geocoding.Locator) Model {
+	m.locator = locator
+	return m
+}
+
+// WithGeocoder returns a copy of the model that resolves searches and saved port
+// locations with geocoder
+func (m Model) WithGeocoder(geocoder geocoding.Geocoder) Model {
+	m.geocoder = geocoder
+	m.portService = ports.NewServiceWithGeocoder(geocoder)
+	return m
+}
+
+// WithHTTPTimeout returns a copy of the model whose NOAA weather, alert, tide and
+// station clients give up on a request after timeout instead of noaa.DefaultHTTPTimeout.
+// Zone loads, locating the current position and the check for a newer marine zones
+// edition are bounded by it too.
+func (m Model) WithHTTPTimeout(timeout time.Duration) Model {
+	httpClient := &http.Client{Timeout: timeout}
+	m.httpTimeout = timeout
+	m.weatherClient = noaa.NewWeatherClientWithHTTPClient(httpClient)
+	m.alertClient = noaa.NewAlertClientWithHTTPClient(httpClient)
+	m.tideClient = noaa.NewTideClientWithHTTPClient(httpClient)
+	m.stationClient = ports.NewNOAAStationClientWithHTTPClient(httpClient)
+	return m
+}
+
+// WithGeocodeTimeout returns a copy of the model that gives up on geocoding a search
+// after timeout instead of geocoding.DefaultTimeout
+func (m Model) WithGeocodeTimeout(timeout time.Duration) Model {
+	m.geocodeTimeout = timeout
+	return m
+}
+
+// WithObservationHistory returns a copy of the model that records tide station
+// temperature observations in repo and seeds its trends from them, so they
+// survive a restart
+func (m Model) WithObservationHistory(repo *database.ObservationRepository) Model {
+	m.observations = repo
+	return m
+}
+
+// WithSettings returns a copy of the model that shows times in the format last chosen
+// with 'T', as stored in repo, and stores the choice there when it changes
+func (m Model) WithSettings(repo *database.SettingsRepository) Model {
+	m.settings = repo
+	if format, err := repo.Get(database.SettingTimeFormat); err == nil && format != "" {
+		m.styles = m.styles.with24HourClock(format == timeFormat24h)
+	}
+	return m
+}
+
+// WithSmallCraftThresholds returns a copy of the model that highlights forecast
+// periods reaching the given wind and sea thresholds, and rates current conditions
+// reaching them as rough
+func (m Model) WithSmallCraftThresholds(thresholds models.SmallCraftThresholds) Model {
+	m.smallCraft = thresholds
+	m.rating.Rough = thresholds
+	return m
+}
+
+// WithForecastPeriodLimit returns a copy of the model that lists at most limit
+// upcoming forecast periods. A limit of 0 lists every period NOAA provides.
+func (m Model) WithForecastPeriodLimit(limit int) Model {
+	m.forecastPeriodLimit = limit
+	return m
+}
+
+// WithTideWindowDays returns a copy of the model that fetches days of tide predictions
+// instead of DefaultTideWindowDays. Use ValidateTideWindowDays to check the value first.
+func (m Model) WithTideWindowDays(days int) Model {
+	m.tideWindowDays = days
+	return m
+}
+
+// WithZoneAutoSelect returns a copy of the model that skips the zone list when a search
+// finds a single zone, or only one within miles, selecting it straight away. A distance
+// of 0 always shows the list.
+func (m Model) WithZoneAutoSelect(miles float64) Model {
+	m.zoneAutoSelectMiles = miles
+	return m
+}
+
+// WithPressureUnit returns a copy of the model that shows barometric pressure in unit,
+// models.PressureMillibars or models.PressureInchesHg
+func (m Model) WithPressureUnit(unit string) Model {
+	m.pressureUnit = unit
+	return m
+}
+
+// WithAlertNotifications returns a copy of the model that announces severe and extreme
+// marine alerts issued while it's running: with the terminal bell if bell is set, and
+// by running command through the shell if it isn't empty
+func (m Model) WithAlertNotifications(bell bool, command string) Model {
+	m.alertNotifier = alertNotifier{bell: bell, command: command}
+	return m
+}
+
+// WithProvisionContext returns a copy of the model whose background provisioning stops,
+// removing any partial download, once ctx is cancelled
+func (m Model) WithProvisionContext(ctx context.Context) Model {
+	m.provisionCtx = ctx
+	return m
+}
+
+// WithClock returns a copy of the model that reads the current time from clock
+func (m Model) WithClock(clock models.Clock) Model {
+	m.clock = clock
+	return m
+}
+
 // Init initializes the application
 func (m Model) Init() tea.Cmd {
 	dbPath := database.DBPath()
@@ -149,49 +431,173 @@ func (m Model) Init() tea.Cmd {
 	if err == nil {
 		zipNeeded, err := geocoding.NeedsProvisioning(dbPath)
 		if err == nil && (zonesNeeded || zipNeeded) {
-			return tea.Batch(m.spinner.Tick, initiateProvisioning())
+			return tea.Batch(m.spinner.Tick, initiateProvisioning(m.provisionCtx))
 		}
 	}
 
+	// Look for a newer marine zones edition in the background
+	return tea.Batch(m.spinner.Tick, m.initialLoad(), checkForNewerEdition(m.httpTimeout))
+}
+
+// initialLoad returns the command that loads the first view once the database is provisioned
+func (m Model) initialLoad() tea.Cmd {
 	// 1. Load by Port Name
 	if m.initialPortName != "" {
-		return tea.Batch(m.spinner.Tick, fetchPortByName(m.portService, m.initialPortName))
+		return fetchPortByName(m.portService, m.initialPortName)
 	}
 
 	// 2. Load by Station + Location
 	if m.initialStationCode != "" && m.initialLocation != "" {
-		m.searchQuery = m.initialLocation
-		return tea.Batch(m.spinner.Tick, geocodeLocation(m.geocoder, m.initialLocation))
+		return geocodeLocation(m.geocoder, m.geocodeTimeout, m.initialLocation)
+	}
+
+	// 3. Start from the current position
+	if m.locator != nil {
+		return locateHere(m.locator, m.httpTimeout)
 	}
 
-	// 3. Default: Fetch saved ports
-	return tea.Batch(m.spinner.Tick, fetchSavedPorts(m.portService))
+	// 4. Default: Fetch saved ports
+	return fetchSavedPorts(m.portService)
 }
 
 // loadPort sets up the model to display a specific port
 func (m Model) loadPort(p models.Port) (Model, tea.Cmd) {
-	if p.Zipcode != "" {
-		m.searchQuery = p.Zipcode
-	} else {
-		m.searchQuery = fmt.Sprintf("%s, %s", p.City, p.State)
-	}
+	// Saving the port again starts from its own location, not its label
+	m.searchQuery = ports.SaveLocation(p)
+	m.currentPort = &p
 	m.selectedZone = &zonelookup.ZoneInfo{
 		Code: p.MarineZoneID,
 		Name: p.Name, 
 	}
+	// Nearby zones from an earlier search only apply if this port is one of them
+	var nameCmd tea.Cmd
+	if i := m.zoneIndex(); i >= 0 {
+		m.portZoneName = m.zones[i].Name
+	} else {
+		m.zones = nil
+		m.portZoneName = ""
+		nameCmd = lookupZoneName(p.MarineZoneID)
+	}
 	m.location = &geocoding.Location{
 		Latitude:  p.Latitude,
 		Longitude: p.Longitude,
-		Name:      m.searchQuery,
+		Name:      p.DisplayName(),
 	}
 	m.state = StateLoading
-	m.loadingWeather = true
-	m.loadingAlerts = true
-	return m, tea.Batch(
-		fetchZoneWeather(m.weatherClient, m.selectedZone.Code),
-		fetchZoneAlerts(m.alertClient, m.selectedZone.Code),
-		findNearestTideStation(m.location.Latitude, m.location.Longitude),
-	)
+	m.portsReturn = StateSavedPorts
+	m.zoneBoundary = nil
+	m, cmd := m.loadZone()
+	return m, tea.Batch(cmd, nameCmd)
+}
+
+// homePort returns the saved port marked as home, if any
+func (m Model) homePort() (models.Port, bool) {
+	for _, p := range m.savedPorts {
+		if p.Home {
+			return p, true
+		}
+	}
+	return models.Port{}, false
+}
+
+// openSavedPorts shows the saved ports list, remembering the current state for esc to
+// return to. If the ports haven't been fetched yet, e.g. after starting with --port, the
+// list starts out with the ports known so far and is filled in once they are.
+func (m Model) openSavedPorts() (tea.Model, tea.Cmd) {
+	m.portsReturn = m.state
+	m.state = StateSavedPorts
+	if m.portsFetched {
+		return m, nil
+	}
+	m.portList = createPortList(m.savedPorts, m.width-4, m.height-10, m.styles)
+	return m, fetchSavedPorts(m.portService)
+}
+
+// closeSavedPorts leaves the saved ports list for the state it was opened from. Lists
+// opened while a port was loading, or by other screens, return to the display if a zone
+// is selected and otherwise stay put, as there's nothing to go back to.
+func (m Model) closeSavedPorts() Model {
+	back := m.portsReturn
+	if back == StateSavedPorts || back == StateLoading {
+		if m.selectedZone == nil {
+			return m
+		}
+		back = StateDisplay
+	}
+	m.state = back
+	m.portsReturn = StateSavedPorts
+	return m
+}
+
+// savedPortsKeyActive reports whether the SavedPorts key opens the saved ports list in
+// the current state. Provisioning has to finish, confirmations have to be answered and
+// errors dismissed first. While loading, only a saved port's zone load leaves the list
+// alone when it lands; a search's results would pull the user out of it.
+func (m Model) savedPortsKeyActive() bool {
+	switch m.state {
+	case StateProvisioning, StateError, StateConfirmDelete, StateConfirmOverwrite, StateConfirmUpdate:
+		return false
+	case StateLoading:
+		return m.currentPort != nil && (m.loadingWeather || m.loadingAlerts)
+	}
+	return true
+}
+
+// goHome loads the home port. If the saved ports haven't been fetched, e.g. after
+// starting with --port, the home port is read from the database first.
+func (m Model) goHome() (tea.Model, tea.Cmd) {
+	if home, ok := m.homePort(); ok {
+		return m.loadPort(home)
+	}
+	if !m.portsFetched {
+		return m, fetchHomePort(m.portService)
+	}
+	return m.noHomePort()
+}
+
+// noHomePort answers 'H' when no port is home by opening the saved ports list, where
+// '*' makes one home
+func (m Model) noHomePort() (tea.Model, tea.Cmd) {
+	if m.state == StateSavedPorts {
+		return m, nil
+	}
+	return m.openSavedPorts()
+}
+
+// tideDatum returns the datum tide predictions should be requested in for the displayed port
+func (m Model) tideDatum() string {
+	if m.currentPort != nil {
+		return m.currentPort.TideDatum()
+	}
+	return models.DatumMLLW
+}
+
+// clearDisplayedPort drops the currently displayed port and all of its fetched data
+func (m Model) clearDisplayedPort() Model {
+	m.currentPort = nil
+	m.selectedZone = nil
+	m.location = nil
+	m.weather = nil
+	m.forecast = nil
+	m.weatherSource = ""
+	m.zoneBoundary = nil
+	m.alerts = nil
+	m.tides = nil
+	m.tideHeightNote = ""
+	m.tidePage = 0
+	m.waterLevel = nil
+	m.levelErr = nil
+	m.tideConditions = nil
+	m.tideDataStation = ""
+	m.storedTrends = nil
+	m.tideErr = nil
+	m.metErr = nil
+	m.tideStation = nil
+	m.tideStations = nil
+	m.datumOffsets = nil
+	m.datumErr = nil
+	m.datumStation = ""
+	return m
 }
 
 // Update handles messages and updates the model
@@ -206,26 +612,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.state == StateZoneList {
 			m.zoneList.SetSize(msg.Width-4, msg.Height-10)
 		}
-		if m.state == StateSavedPorts {
-			m.portList.SetSize(msg.Width-4, msg.Height-10)
+		if m.state == StateCityChoice {
+			m.cityList.SetSize(msg.Width-4, msg.Height-10)
 		}
-		// Update tide chart size based on terminal width
-		chartWidth := msg.Width - 8 // Leave some padding
-		if chartWidth < 40 {
-			chartWidth = 40 // Minimum width
+		if m.state == StateStationList {
+			m.stationList.SetSize(msg.Width-4, msg.Height-10)
 		}
-		m.tideChart = timeserieslinechart.New(chartWidth, 15)
-		// If we have tide data, redraw the chart
-		if m.tides != nil {
-			for _, event := range m.tides.Events {
-				m.tideChart.Push(timeserieslinechart.TimePoint{
-					Time:  event.Time,
-					Value: event.Height,
-				})
-			}
-			m.tideChart.DrawBraille()
+		if m.state == StateSavedPorts {
+			m.portList.SetSize(msg.Width-4, msg.Height-10)
 		}
-		return m, nil
+		// Resize the tide chart to the terminal width
+		return m.rebuildTideChart(), nil
 	}
 
 	// Handle custom messages
@@ -236,6 +633,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case portsFetchedMsg:
+		if m.state == StateSavedPorts {
+			// The list was opened before the ports were fetched: fill it in rather than
+			// loading the first port out from under it
+			if msg.err == nil {
+				m.savedPorts = msg.ports
+				m.portsFetched = true
+				m.portList = createPortList(m.savedPorts, m.width-4, m.height-10, m.styles)
+			}
+			return m, nil
+		}
 		if msg.err != nil {
 			// If error fetching ports, default to search
 			m.state = StateSearch
@@ -243,10 +650,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.savedPorts = msg.ports
+		m.portsFetched = true
 		
 		// If ports exist, populate the list
 		if len(m.savedPorts) > 0 {
-			m.portList = createPortList(m.savedPorts, m.width-4, m.height-10)
+			m.portList = createPortList(m.savedPorts, m.width-4, m.height-10, m.styles)
 			// AUTO-LOAD: If we have ports, load the first one by default
 			return m.loadPort(m.savedPorts[0])
 		}
@@ -276,6 +684,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		found := false
 		for i, p := range m.savedPorts {
 			if p.Name == msg.port.Name {
+				msg.port.Home = p.Home // Overwriting a port keeps it home
 				m.savedPorts[i] = *msg.port
 				found = true
 				break
@@ -286,10 +695,70 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		
 		// Update UI list
-		m.portList = createPortList(m.savedPorts, m.width-4, m.height-10)
+		m.portList = createPortList(m.savedPorts, m.width-4, m.height-10, m.styles)
 		
 		return m.loadPort(*msg.port)
 
+	case portRebuiltMsg:
+		// Ignore lookups for an update that was cancelled
+		if m.state != StateConfirmUpdate || m.portUpdate == nil || m.portUpdate.before.Name != msg.name {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.portUpdate = nil
+			m.err = msg.err
+			m.state = StateError
+			return m, nil
+		}
+		m.portUpdate.after = msg.port
+		return m, nil
+
+	case portUpdatedMsg:
+		m.portUpdate = nil
+		if msg.err != nil {
+			m.err = msg.err
+			m.state = StateError
+			return m, nil
+		}
+		for i, p := range m.savedPorts {
+			if p.Name == msg.port.Name {
+				m.savedPorts[i] = *msg.port
+			}
+		}
+		m.portList = createPortList(m.savedPorts, m.width-4, m.height-10, m.styles)
+		m.state = StateSavedPorts
+
+		// The port on display now has a different zone or station, so reload it
+		if m.currentPort != nil && m.currentPort.Name == msg.port.Name {
+			return m.loadPort(*msg.port)
+		}
+		return m, nil
+
+	case homePortSetMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			m.state = StateError
+			return m, nil
+		}
+		for i := range m.savedPorts {
+			m.savedPorts[i].Home = m.savedPorts[i].Name == msg.name
+		}
+		index := m.portList.Index()
+		m.portList = createPortList(m.savedPorts, m.width-4, m.height-10, m.styles)
+		m.portList.Select(index)
+		return m, nil
+
+	case homePortFetchedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			m.state = StateError
+			return m, nil
+		}
+		if msg.port == nil {
+			return m.noHomePort()
+		}
+		return m.loadPort(*msg.port)
+
 	case portDeletedMsg:
 		if msg.err != nil {
 			m.err = msg.err
@@ -304,7 +773,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		m.savedPorts = updatedPorts
-		m.portList = createPortList(m.savedPorts, m.width-4, m.height-10)
+		m.portList = createPortList(m.savedPorts, m.width-4, m.height-10, m.styles)
+		m.portToDelete = nil
+
+		// If the port on display was deleted, drop its data and move on
+		if m.currentPort != nil && m.currentPort.Name == msg.name {
+			m = m.clearDisplayedPort()
+			if len(m.savedPorts) > 0 {
+				return m.loadPort(m.savedPorts[0])
+			}
+			m.state = StateSearch
+			m.searchInput.Focus()
+			return m, textinput.Blink
+		}
+
 		m.state = StateSavedPorts // Return to saved ports list
 		return m, nil
 
@@ -313,13 +795,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.state = StateProvisioning
 		m.provisionStatus = "Starting data provisioning..."
 		m.provisionChannels = &msg
+		m.provisionJob = msg.job
 		return m, tea.Batch(
 			waitForProvisionStatus(msg.progressChan),
 			waitForProvisionResult(msg.resultChan),
 		)
 
 	case provisionStatusMsg:
-		m.provisionStatus = string(msg)
+		m.provisionStatus = msg.Label
+		m.provisionFraction = msg.Fraction
 		// Continue waiting for more status updates using stored channel
 		if m.provisionChannels != nil {
 			return m, waitForProvisionStatus(m.provisionChannels.progressChan)
@@ -330,13 +814,49 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.provisionChannels = nil // clear channels
 		if msg.err != nil {
 			m.err = fmt.Errorf("provisioning failed: %w", msg.err)
+			m.provisionRetry = m.provisionJob != nil
+			// Download failures left nothing behind; anything later may have used a corrupt download
+			m.provisionCleanup = !errors.Is(msg.err, zonelookup.ErrShapefileDownload)
 			m.state = StateError
 			return m, nil
 		}
-		// Success! Check saved ports again
-		return m, fetchSavedPorts(m.portService)
+		// Success! Continue with the normal startup load
+		return m, m.initialLoad()
+
+	case portSummaryFetchedMsg:
+		if msg.generation == m.overviewGeneration && msg.index < len(m.overview) {
+			m.overview[msg.index] = msg.summary
+		}
+		return m, nil
+
+	case newerEditionMsg:
+		m.newerEdition = msg.edition
+		return m, nil
+
+	case hereLocatedMsg:
+		if msg.err != nil {
+			// Fall back to a normal search
+			m.err = fmt.Errorf("couldn't determine your location: %w", msg.err)
+			m.state = StateSearch
+			m.searchInput.Focus()
+			return m, nil
+		}
+		m.err = nil
+		m.currentPort = nil
+		m.location = msg.location
+		m.searchQuery = msg.location.Name
+		return m, tea.Batch(
+			nearbyZoneSearch(msg.location.Latitude, msg.location.Longitude, zoneSearchRadiusMiles),
+			findNearestTideStation(msg.location.Latitude, msg.location.Longitude),
+		)
 
 	case geocodeMsg:
+		var ambiguous *geocoding.AmbiguousCityError
+		if errors.As(msg.err, &ambiguous) {
+			m.cityList = createCityList(ambiguous.City, ambiguous.Candidates, m.width-4, m.height-10, m.styles)
+			m.state = StateCityChoice
+			return m, nil
+		}
 		if msg.err != nil {
 			m.err = fmt.Errorf("geocoding failed: %w", msg.err)
 			m.state = StateError
@@ -347,32 +867,46 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// If we are direct loading with station code
 		if m.initialStationCode != "" {
 			// Skip zone finding, assume station code is valid
+			m.currentPort = nil
 			m.selectedZone = &zonelookup.ZoneInfo{
 				Code: m.initialStationCode,
 				Name: "Direct Loaded",
 			}
 			m.state = StateLoading
-			m.loadingWeather = true
-			m.loadingAlerts = true
-			return m, tea.Batch(
-				fetchZoneWeather(m.weatherClient, m.selectedZone.Code),
-				fetchZoneAlerts(m.alertClient, m.selectedZone.Code),
-				findNearestTideStation(m.location.Latitude, m.location.Longitude),
-			)
+			m.zoneBoundary = nil
+			return m.loadZone()
 		}
 
 		return m, tea.Batch(
-			findNearbyZones(msg.location.Latitude, msg.location.Longitude),
+			nearbyZoneSearch(msg.location.Latitude, msg.location.Longitude, zoneSearchRadiusMiles),
 			findNearestTideStation(msg.location.Latitude, msg.location.Longitude),
 		)
 
 	case tideStationFoundMsg:
+		m.tideStationsEmpty = msg.dbEmpty
 		if msg.err == nil && len(msg.stations) > 0 {
 			m.tideStations = msg.stations
 			m.tideStation = &msg.stations[0] // Auto-select closest
 			// Fetch tide data for this station
 			m.loadingTides = true
-			return m, fetchTideData(m.tideClient, m.tideStation.ID)
+			ctx := msg.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			fetch := fetchTideData(ctx, m.tideClient, m.clock, m.tideStation.ID, m.tideDatum(), m.tideWindowDays)
+			if msg.ctx != nil {
+				// A superseded load's tide data, cancelled or not, must not touch the display
+				fetch = zoneLoadPart(msg.load, fetch)
+			}
+			cmds := []tea.Cmd{fetch}
+			if m.compareDatum != "" && m.datumStation != m.tideStation.ID {
+				cmds = append(cmds, fetchDatumOffsets(m.tideClient, m.tideStation.ID))
+			}
+			if m.observations != nil {
+				m.storedTrends = nil
+				cmds = append(cmds, loadObservationHistory(m.observations, m.tideStation.ID))
+			}
+			return m, tea.Batch(cmds...)
 		} else if msg.err != nil {
 			// Log error but don't stop app?
 			// For now, if tide lookup fails, we just don't have tide data.
@@ -384,31 +918,102 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tideDataFetchedMsg:
 		m.loadingTides = false
-		if msg.err != nil {
-			// Handle error, maybe just log or show in UI
-		} else {
-			m.tides = msg.tides
+		m.tideErr = msg.tideErr
+		m.metErr = msg.metErr
+		if msg.stationID != m.tideDataStation {
+			// Another station's data isn't worth keeping when this one's fails
+			m.tides = nil
+			m.tideHeightNote = ""
+			m.tidePage = 0
+			m.tideConditions = nil
+			m.waterLevel = nil
+			m.tideDataStation = msg.stationID
+		}
+		// Keep existing data for whichever half failed
+		var record tea.Cmd
+		if msg.metErr == nil {
 			m.tideConditions = msg.conditions
-			
-			// Update chart data - recreate chart to ensure clean state
+			if m.observations != nil && msg.conditions != nil {
+				record = recordObservationHistory(m.observations, msg.stationID, msg.conditions)
+			}
+		}
+		m.levelErr = msg.levelErr
+		if msg.levelErr == nil || errors.Is(msg.levelErr, noaa.ErrNoWaterLevel) {
+			// A station without a sensor has no reading to keep
+			m.waterLevel = msg.waterLevel
+		}
+		if msg.tideErr == nil {
+			m.tides = msg.tides
+			m.tideHeightNote = "" // Looked up against the previous predictions
+			m.tidePage = 0
+
 			if m.tides != nil {
-				// Get current chart dimensions
-				chartWidth := m.width - 8
-				if chartWidth < 40 {
-					chartWidth = 40
-				}
-				// Create fresh chart to avoid artifacts
-				m.tideChart = timeserieslinechart.New(chartWidth, 15)
-
-				for _, event := range m.tides.Events {
-					m.tideChart.Push(timeserieslinechart.TimePoint{
-						Time:  event.Time,
-						Value: event.Height,
-					})
-				}
-				m.tideChart.DrawBraille()
+				m = m.rebuildTideChart()
 			}
 		}
+		return m, record
+
+	case observationHistoryMsg:
+		if m.tideStation == nil || msg.stationID != m.tideStation.ID {
+			return m, nil // Station changed while reading
+		}
+		m.storedTrends = msg.history
+		return m, nil
+
+	case displayExportedMsg:
+		m.exportPath = msg.path
+		m.exportErr = msg.err
+		return m, nil
+
+	case copiedMsg:
+		m.lastCopy = &msg
+		return m, nil
+
+	case datumOffsetsFetchedMsg:
+		if m.tideStation == nil || msg.stationID != m.tideStation.ID {
+			return m, nil // Station changed while fetching
+		}
+		m.datumStation = msg.stationID
+		m.datumOffsets = msg.offsets
+		m.datumErr = msg.err
+		return m, nil
+
+	case zoneCodeFoundMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			m.state = StateZoneCode
+			m.zoneCodeInput.Focus()
+			return m, nil
+		}
+		m.err = nil
+		m.currentPort = nil
+		m.selectedZone = msg.zone
+		m.searchQuery = msg.zone.Code
+		m.location = &geocoding.Location{
+			Latitude:  msg.zone.CenterLat,
+			Longitude: msg.zone.CenterLon,
+			Name:      msg.zone.Name,
+		}
+		m.state = StateLoading
+		m.zoneBoundary = nil
+		return m.loadZone()
+
+	case stationsSearchedMsg:
+		if msg.err != nil {
+			m.err = fmt.Errorf("station search failed: %w", msg.err)
+			m.state = StateStationSearch
+			m.stationSearchInput.Focus()
+			return m, nil
+		}
+		if len(msg.stations) == 0 {
+			m.err = fmt.Errorf("no stations match '%s'", msg.query)
+			m.state = StateStationSearch
+			m.stationSearchInput.Focus()
+			return m, nil
+		}
+		m.err = nil
+		m.stationList = createStationList(msg.query, msg.stations, m.width-4, m.height-10, m.styles)
+		m.state = StateStationList
 		return m, nil
 
 	case zonesFoundMsg:
@@ -417,13 +1022,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.state = StateError
 			return m, nil
 		}
+		if len(msg.zones) == 0 && msg.dbEmpty {
+			m.err = fmt.Errorf("the marine zones database is empty, so zones near '%s' can't be looked up; provisioning may not have completed", m.searchQuery)
+			m.reprovisionNeeded = true
+			m.state = StateError
+			return m, nil
+		}
+		m.zoneRadius = msg.radius
 		if len(msg.zones) == 0 {
-			m.err = fmt.Errorf("no marine zones found near '%s'", m.searchQuery)
+			m.err = fmt.Errorf("no marine zones found within %.0f mi of '%s'", msg.radius, m.searchQuery)
+			m.zoneSearchWiden = m.location != nil && msg.radius+zoneSearchRadiusStep <= maxZoneSearchRadiusMiles
 			m.state = StateError
 			return m, nil
 		}
 		m.zones = msg.zones
-		m.zoneList = createZoneList(msg.zones, m.width-4, m.height-10)
+		m.zoneList = createZoneList(msg.zones, m.preferredZoneType(), m.width-4, m.height-10, m.styles)
+		if zone, ok := autoSelectZone(msg.zones, m.zoneAutoSelectMiles); ok {
+			return m.selectZone(zone)
+		}
 		m.state = StateZoneList
 		return m, nil
 
@@ -434,32 +1050,83 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.weather = msg.conditions
 			m.forecast = msg.forecast
+			m.weatherSource = msg.source
+			return m.recordWeather(msg.conditions)
 		}
-		if m.state != StateDisplay {
-			m.state = StateDisplay
+		return m, nil
+
+	case zoneBoundaryMsg:
+		if msg.err == nil && m.selectedZone != nil && msg.zoneCode == m.selectedZone.Code {
+			m.zoneBoundary = msg.distance
+		}
+		return m, nil
+
+	case zoneNameMsg:
+		if m.currentPort != nil && msg.zoneCode == m.currentPort.MarineZoneID {
+			m.portZoneName = msg.name
 		}
 		return m, nil
 
 	case zoneAlertsFetchedMsg:
 		m.loadingAlerts = false
+		m.alertsErr = msg.err
 		if msg.err != nil {
 			// Keep existing data if fetch failed
 		} else {
 			m.alerts = msg.alerts
+			if m.selectedZone != nil {
+				m.alertLog = logAlerts(m.alertLog, m.selectedZone.Code, msg.alerts)
+			}
+			return m.recordAlerts(msg.alerts)
 		}
-		if m.state != StateDisplay {
-			m.state = StateDisplay
+		return m, nil
+
+	case alertBellMsg:
+		m.bell++
+		m.bellRinging = true
+		bell := m.bell
+		return m, tea.Tick(bellDuration, func(time.Time) tea.Msg {
+			return bellDoneMsg{bell: bell}
+		})
+
+	case bellDoneMsg:
+		if msg.bell == m.bell {
+			m.bellRinging = false
+		}
+		return m, nil
+
+	case changesExpiredMsg:
+		if msg.flash == m.changeFlash {
+			m.changes = conditionChanges{}
 		}
 		return m, nil
 
+	case zoneLoadMsg:
+		if msg.load != m.zoneLoad {
+			return m, nil // Superseded by a newer load
+		}
+		// Show the display with the first part to arrive; the rest fill in as they do
+		updated, cmd := m.Update(msg.part)
+		m = updated.(Model)
+		if m.state == StateLoading {
+			m.state = StateDisplay
+		}
+		return m, cmd
+
 	}
 
 	// Handle keyboard input
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		inputState := m.state == StateSearch || m.state == StateSavePrompt || m.state == StateZoneCode || m.state == StateTideTime || m.state == StateStationSearch ||
+			(m.state == StateSavedPorts && m.portList.FilterState() == list.Filtering) ||
+			(m.state == StateZoneList && m.zoneList.FilterState() == list.Filtering) ||
+			(m.state == StateCityChoice && m.cityList.FilterState() == list.Filtering) ||
+			(m.state == StateStationList && m.stationList.FilterState() == list.Filtering)
+
 		// Global keys
-		if keyMsg.String() == "ctrl+c" || keyMsg.String() == "q" {
+		if key.Matches(keyMsg, m.keys.Quit) {
 			// Allow quitting unless in input fields where 'q' might be text
-			if m.state != StateSearch && m.state != StateSavePrompt {
+			if !inputState {
 				return m, tea.Quit
 			}
 			// In inputs, ctrl+c quits
@@ -468,11 +1135,40 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// The help overlay captures keys until it is closed
+		if m.showHelp {
+			if key.Matches(keyMsg, m.keys.Help, m.keys.Back) {
+				m.showHelp = false
+			}
+			return m, nil
+		}
+		if !inputState && key.Matches(keyMsg, m.keys.Help) {
+			m.showHelp = true
+			return m, nil
+		}
+		// 'H' jumps to the home port, except while provisioning has yet to finish
+		if !inputState && m.state != StateProvisioning && key.Matches(keyMsg, m.keys.GoHome) {
+			return m.goHome()
+		}
+		// 'p' opens the saved ports list; esc returns to where it was pressed
+		if !inputState && key.Matches(keyMsg, m.keys.SavedPorts) && m.savedPortsKeyActive() {
+			return m.openSavedPorts()
+		}
+
 		// State-specific handling
 		switch m.state {
 		case StateSearch:
 			return m.handleSearchInput(keyMsg)
 
+		case StateZoneCode:
+			return m.handleZoneCodeInput(keyMsg)
+
+		case StateStationSearch:
+			return m.handleStationSearchInput(keyMsg)
+
+		case StateTideTime:
+			return m.handleTideTimeInput(keyMsg)
+
 		case StateSavedPorts:
 			return m.handleSavedPorts(msg)
 
@@ -482,41 +1178,175 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case StateConfirmDelete:
 			return m.handleConfirmDelete(keyMsg)
 
+		case StateConfirmOverwrite:
+			return m.handleConfirmOverwrite(keyMsg)
+
+		case StateConfirmUpdate:
+			return m.handleConfirmUpdate(keyMsg)
+
+		case StateOverview:
+			return m.handleOverview(keyMsg)
+
 		case StateZoneList:
 			return m.handleZoneList(msg)
 
+		case StateCityChoice:
+			return m.handleCityList(msg)
+
+		case StateStationList:
+			return m.handleStationList(msg)
+
 		case StateDisplay:
+			m.exportPath = ""
+			m.exportErr = nil
+			m.lastCopy = nil
+			// 'x' to export the display as it is shown now
+			if key.Matches(keyMsg, m.keys.Export) {
+				return m, exportDisplay(m.View(), m.clock.Now())
+			}
+			// 'c'/'i' to copy the zone code or tide station ID
+			if key.Matches(keyMsg, m.keys.CopyZone) && m.selectedZone != nil {
+				return m, copyToClipboard("zone code", m.selectedZone.Code)
+			}
+			if key.Matches(keyMsg, m.keys.CopyStation) && m.tideStation != nil {
+				return m, copyToClipboard("station ID", m.tideStation.ID)
+			}
 			// 'e' to edit/change port
-			if keyMsg.String() == "e" {
-				m.state = StateSavedPorts
-				return m, nil
+			if key.Matches(keyMsg, m.keys.EditPorts) {
+				return m.openSavedPorts()
 			}
-			// 'r' to refresh data
-			if keyMsg.String() == "r" {
-				if m.selectedZone != nil && m.location != nil {
-					m.loadingWeather = true
-					m.loadingAlerts = true
-					return m, tea.Batch(
-						fetchZoneWeather(m.weatherClient, m.selectedZone.Code),
-						fetchZoneAlerts(m.alertClient, m.selectedZone.Code),
-						findNearestTideStation(m.location.Latitude, m.location.Longitude),
-					)
+			// 'v' to toggle the raw NOAA forecast text
+			if key.Matches(keyMsg, m.keys.RawForecast) && m.activePane == PaneWeather {
+				m.showRawForecast = !m.showRawForecast
+				if m.showRawForecast {
+					m.rawViewport = m.newRawForecastViewport()
 				}
 				return m, nil
 			}
-			// Tab to switch panes
-			if keyMsg.Type == tea.KeyTab || keyMsg.Type == tea.KeyShiftTab {
-				if m.activePane == PaneWeather {
-					m.activePane = PaneTides
-				} else {
-					m.activePane = PaneWeather
+			if m.showRawForecast {
+				if key.Matches(keyMsg, m.keys.Back) {
+					m.showRawForecast = false
+					return m, nil
+				}
+				if !key.Matches(keyMsg, m.keys.SwitchPane) {
+					m.rawViewport, cmd = m.rawViewport.Update(msg)
+					return m, cmd
+				}
+			}
+			// 'm' to also show tide heights in another datum
+			if key.Matches(keyMsg, m.keys.DatumCycle) && m.activePane == PaneTides && m.tideStation != nil {
+				m.compareDatum = nextCompareDatum(m.compareDatum, m.tideDatum())
+				if m.compareDatum != "" && m.datumStation != m.tideStation.ID {
+					return m, fetchDatumOffsets(m.tideClient, m.tideStation.ID)
 				}
 				return m, nil
 			}
-			return m, nil
-
-		case StateError:
+			// 'C' to switch between the tide chart and tide clock
+			if key.Matches(keyMsg, m.keys.TideClock) && m.activePane == PaneTides {
+				m.showTideClock = !m.showTideClock
+				return m, nil
+			}
+			// '[' and ']' to page through the days of tides and chart
+			if key.Matches(keyMsg, m.keys.TidePrevPage, m.keys.TideNextPage) && m.activePane == PaneTides && m.tides != nil {
+				page := m.tidePage + 1
+				if key.Matches(keyMsg, m.keys.TidePrevPage) {
+					page = m.tidePage - 1
+				}
+				if page >= 0 && page < m.tidePageCount() {
+					m.tidePage = page
+					m = m.rebuildTideChart()
+				}
+				return m, nil
+			}
+			// 't' to look up the predicted tide height at a given time
+			if key.Matches(keyMsg, m.keys.TideTime) && m.activePane == PaneTides && m.tides != nil {
+				m.err = nil
+				m.state = StateTideTime
+				m.tideTimeInput.SetValue("")
+				m.tideTimeInput.Focus()
+				return m, textinput.Blink
+			}
+			// 'T' to switch between 12- and 24-hour times
+			if key.Matches(keyMsg, m.keys.ClockFormat) {
+				m.styles = m.styles.with24HourClock(!m.styles.clock24h)
+				if m.settings != nil {
+					return m, saveTimeFormat(m.settings, m.styles.timeFormat())
+				}
+				return m, nil
+			}
+			// 'b' to show the wind's Beaufort force
+			if key.Matches(keyMsg, m.keys.Beaufort) {
+				m.showBeaufort = !m.showBeaufort
+				return m, nil
+			}
+			// 'g' to hide or show the conditions status bar
+			if key.Matches(keyMsg, m.keys.StatusBar) {
+				m.hideStatusBar = !m.hideStatusBar
+				return m, nil
+			}
+			// 'a' to hide or show informational alerts
+			if key.Matches(keyMsg, m.keys.AlertFilter) {
+				m.hideStatements = !m.hideStatements
+				return m, nil
+			}
+			// 'A' to show the exact alert onset and expiry times
+			if key.Matches(keyMsg, m.keys.AlertTimes) {
+				m.exactAlertTimes = !m.exactAlertTimes
+				return m, nil
+			}
+			// 'L' to list recently expired alerts
+			if key.Matches(keyMsg, m.keys.AlertLog) {
+				m.showAlertLog = !m.showAlertLog
+				return m, nil
+			}
+			// Left/right to cycle through the zones near the searched location
+			if key.Matches(keyMsg, m.keys.PrevZone) {
+				return m.cycleZone(-1)
+			}
+			if key.Matches(keyMsg, m.keys.NextZone) {
+				return m.cycleZone(1)
+			}
+			// 'r' to refresh data
+			if key.Matches(keyMsg, m.keys.Refresh) {
+				if m.selectedZone != nil && m.location != nil {
+					return m.loadZone()
+				}
+				return m, nil
+			}
+			// 'p' to re-provision an empty tide station database
+			if key.Matches(keyMsg, m.keys.Reprovision) && m.tideStationsEmpty {
+				return m.startReprovisioning()
+			}
+			// 'u' to update to a newer marine zones edition
+			if key.Matches(keyMsg, m.keys.UpdateZones) && m.newerEdition != "" {
+				return m.startZonesUpdate()
+			}
+			// Tab to switch panes
+			if key.Matches(keyMsg, m.keys.SwitchPane) {
+				if m.activePane == PaneWeather {
+					m.activePane = PaneTides
+				} else {
+					m.activePane = PaneWeather
+				}
+				m.showRawForecast = false
+				return m, nil
+			}
+			return m, nil
+
+		case StateError:
+			if m.reprovisionNeeded && key.Matches(keyMsg, m.keys.Reprovision) {
+				return m.startReprovisioning()
+			}
+			if m.provisionRetry && key.Matches(keyMsg, m.keys.Retry) {
+				return m.retryFailedProvisioning()
+			}
+			if m.zoneSearchWiden && key.Matches(keyMsg, m.keys.WidenSearch) {
+				return m.widenZoneSearch()
+			}
 			// Any key returns to search (except quit keys)
+			m.reprovisionNeeded = false
+			m.provisionRetry = false
+			m.zoneSearchWiden = false
 			m.state = StateSearch
 			m.err = nil
 			m.searchInput.Focus()
@@ -526,13 +1356,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Update appropriate component based on state
 	switch m.state {
-	case StateProvisioning:
+	case StateProvisioning, StateOverview:
 		var cmdSpinner tea.Cmd
 		m.spinner, cmdSpinner = m.spinner.Update(msg)
 		return m, cmdSpinner
 		
 	case StateSearch:
 		m.searchInput, cmd = m.searchInput.Update(msg)
+	case StateZoneCode:
+		m.zoneCodeInput, cmd = m.zoneCodeInput.Update(msg)
+	case StateStationSearch:
+		m.stationSearchInput, cmd = m.stationSearchInput.Update(msg)
+	case StateTideTime:
+		m.tideTimeInput, cmd = m.tideTimeInput.Update(msg)
 	case StateSavePrompt:
 		m.saveInput, cmd = m.saveInput.Update(msg)
 	// StateZoneList is handled by handleZoneList() above, don't update twice
@@ -543,12 +1379,175 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// zoneIndex returns the position of the selected zone among the nearby zones, or -1
+func (m Model) zoneIndex() int {
+	if m.selectedZone == nil {
+		return -1
+	}
+	for i, z := range m.zones {
+		if z.Code == m.selectedZone.Code {
+			return i
+		}
+	}
+	return -1
+}
+
+// preferredZoneType is the type of zone to list first when both coastal and offshore
+// zones are nearby: the type chosen for the port on display, or else for the saved port
+// at the searched location
+func (m Model) preferredZoneType() zonelookup.ZoneType {
+	if m.currentPort != nil {
+		if m.currentPort.PreferredZoneType != "" {
+			return zonelookup.ZoneType(m.currentPort.PreferredZoneType)
+		}
+		return zonelookup.ZoneTypeCoastal
+	}
+	for _, p := range m.savedPorts {
+		if p.PreferredZoneType == "" {
+			continue
+		}
+		if (p.Zipcode != "" && p.Zipcode == m.searchQuery) || strings.EqualFold(p.DisplayName(), m.searchQuery) {
+			return zonelookup.ZoneType(p.PreferredZoneType)
+		}
+	}
+	return zonelookup.ZoneTypeCoastal
+}
+
+// alertZoneCodes returns the zones to fetch alerts for while code is on display: code
+// itself, plus the other nearby zones when code is one of them, since an alert for an
+// adjacent zone can matter near a boundary
+func (m Model) alertZoneCodes(code string) []string {
+	codes := []string{code}
+	nearby := false
+	for _, z := range m.zones {
+		if z.Code == code {
+			nearby = true
+		} else {
+			codes = append(codes, z.Code)
+		}
+	}
+	if !nearby {
+		return []string{code}
+	}
+	return codes
+}
+
+// cycleZone switches the display to the nearby zone step places from the current one,
+// wrapping around, and re-fetches its forecast and alerts
+func (m Model) cycleZone(step int) (tea.Model, tea.Cmd) {
+	i := m.zoneIndex()
+	if i < 0 || len(m.zones) < 2 || m.location == nil {
+		return m, nil
+	}
+	next := m.zones[(i+step+len(m.zones))%len(m.zones)]
+	m.selectedZone = &next
+	m.weather = nil
+	m.forecast = nil
+	m.weatherSource = ""
+	m.alerts = nil
+	m.zoneBoundary = nil
+	m.showRawForecast = false
+	m, ctx := m.beginZoneLoad()
+	cmds := []tea.Cmd{
+		zoneLoadPart(m.zoneLoad, fetchZoneWeather(ctx, m.weatherClient, next.Code, m.location)),
+		zoneLoadPart(m.zoneLoad, fetchZoneAlerts(ctx, m.alertClient, m.alertZoneCodes(next.Code), m.location)),
+		measureZoneBoundary(next.Code, m.location),
+	}
+	// The location and so its tide station stay the same, but the new load cancelled
+	// whatever tide work the previous one hadn't finished: start it again under this one
+	switch {
+	case m.tideStation == nil:
+		cmds = append(cmds, zoneLoadPart(m.zoneLoad, findZoneLoadStation(ctx, m.zoneLoad, m.location)))
+	case m.loadingTides:
+		cmds = append(cmds, zoneLoadPart(m.zoneLoad, fetchTideData(ctx, m.tideClient, m.clock, m.tideStation.ID, m.tideDatum(), m.tideWindowDays)))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// beginZoneLoad supersedes any zone load still in flight, cancelling its requests, and
+// returns the context shared by the new load's requests
+func (m Model) beginZoneLoad() (Model, context.Context) {
+	if m.cancelZoneLoad != nil {
+		m.cancelZoneLoad()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), zoneLoadTimeout(m.httpTimeout))
+	m.zoneLoad++
+	m.cancelZoneLoad = cancel
+	m.loadingWeather = true
+	m.loadingAlerts = true
+	return m, ctx
+}
+
+// loadZone loads the selected zone's forecast, alerts and tides in parallel. The
+// display is shown as soon as the first part arrives and fills in as the rest do.
+func (m Model) loadZone() (Model, tea.Cmd) {
+	m, ctx := m.beginZoneLoad()
+	return m, tea.Batch(
+		loadZoneData(ctx, m.zoneLoad, m.weatherClient, m.alertClient, m.selectedZone.Code, m.alertZoneCodes(m.selectedZone.Code), m.location),
+		measureZoneBoundary(m.selectedZone.Code, m.location),
+	)
+}
+
+// startReprovisioning rebuilds reference tables that were provisioned but left empty
+func (m Model) startReprovisioning() (tea.Model, tea.Cmd) {
+	m.err = nil
+	m.reprovisionNeeded = false
+	m.tideStationsEmpty = false
+	m.state = StateProvisioning
+	m.provisionStatus = "Starting re-provisioning..."
+	m.provisionFraction = -1
+	return m, tea.Batch(m.spinner.Tick, initiateReprovisioning(m.provisionCtx))
+}
+
+// retryFailedProvisioning runs the provisioning job that just failed again
+func (m Model) retryFailedProvisioning() (tea.Model, tea.Cmd) {
+	m.err = nil
+	m.provisionRetry = false
+	m.state = StateProvisioning
+	m.provisionStatus = "Retrying provisioning..."
+	m.provisionFraction = -1
+	return m, tea.Batch(m.spinner.Tick, retryProvisioning(m.provisionCtx, m.provisionJob, m.provisionCleanup))
+}
+
+// widenZoneSearch repeats the last zone search, which found nothing, a step wider
+// around the same location
+func (m Model) widenZoneSearch() (tea.Model, tea.Cmd) {
+	m.err = nil
+	m.zoneSearchWiden = false
+	m.state = StateLoading
+	return m, tea.Batch(m.spinner.Tick, nearbyZoneSearch(m.location.Latitude, m.location.Longitude, m.zoneRadius+zoneSearchRadiusStep))
+}
+
+// startZonesUpdate rebuilds the marine zones table from the newest shapefile edition
+func (m Model) startZonesUpdate() (tea.Model, tea.Cmd) {
+	m.err = nil
+	m.state = StateProvisioning
+	m.provisionStatus = fmt.Sprintf("Updating marine zones to %s...", m.newerEdition)
+	m.provisionFraction = -1
+	m.newerEdition = ""
+	return m, tea.Batch(m.spinner.Tick, initiateZonesUpdate(m.provisionCtx))
+}
+
 func (m Model) handleSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	if m.err != nil && msg.Type != tea.KeyEnter {
 		m.err = nil
 	}
-	if msg.Type == tea.KeyEnter {
+	if key.Matches(msg, m.keys.ZoneCode) {
+		m.err = nil
+		m.state = StateZoneCode
+		m.searchInput.Blur()
+		m.zoneCodeInput.Focus()
+		return m, textinput.Blink
+	}
+	if key.Matches(msg, m.keys.StationSearch) {
+		m.err = nil
+		m.state = StateStationSearch
+		m.searchInput.Blur()
+		m.stationSearchInput.Focus()
+		return m, textinput.Blink
+	}
+	if key.Matches(msg, m.keys.Submit) {
 		query := m.searchInput.Value()
 		if query == "" {
 			return m, nil
@@ -556,23 +1555,110 @@ func (m Model) handleSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.searchQuery = query
 		m.err = nil
 		m.state = StateLoading
-		return m, geocodeLocation(m.geocoder, query)
+		return m, geocodeLocation(m.geocoder, m.geocodeTimeout, query)
 	}
 	m.searchInput, cmd = m.searchInput.Update(msg)
 	return m, cmd
 }
 
+func (m Model) handleZoneCodeInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if m.err != nil && msg.Type != tea.KeyEnter {
+		m.err = nil
+	}
+	if key.Matches(msg, m.keys.Back, m.keys.ZoneCode) {
+		m.state = StateSearch
+		m.zoneCodeInput.Blur()
+		m.searchInput.Focus()
+		return m, textinput.Blink
+	}
+	if key.Matches(msg, m.keys.Select) {
+		code := strings.ToUpper(strings.TrimSpace(m.zoneCodeInput.Value()))
+		if code == "" {
+			return m, nil
+		}
+		if !zonelookup.IsValidZoneCode(code) {
+			m.err = fmt.Errorf("invalid zone code %q: expected format like ANZ254", code)
+			return m, nil
+		}
+		m.err = nil
+		m.state = StateLoading
+		return m, lookupZoneByCode(code)
+	}
+	m.zoneCodeInput, cmd = m.zoneCodeInput.Update(msg)
+	return m, cmd
+}
+
+func (m Model) handleStationSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if m.err != nil && msg.Type != tea.KeyEnter {
+		m.err = nil
+	}
+	if key.Matches(msg, m.keys.Back, m.keys.StationSearch) {
+		m.state = StateSearch
+		m.stationSearchInput.Blur()
+		m.searchInput.Focus()
+		return m, textinput.Blink
+	}
+	if key.Matches(msg, m.keys.Select) {
+		query := strings.TrimSpace(m.stationSearchInput.Value())
+		if query == "" {
+			return m, nil
+		}
+		m.err = nil
+		m.state = StateLoading
+		return m, searchStations(m.stationClient, query)
+	}
+	m.stationSearchInput, cmd = m.stationSearchInput.Update(msg)
+	return m, cmd
+}
+
+func (m Model) handleTideTimeInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if m.err != nil && msg.Type != tea.KeyEnter {
+		m.err = nil
+	}
+	if key.Matches(msg, m.keys.Back) {
+		m.err = nil
+		m.state = StateDisplay
+		m.tideTimeInput.Blur()
+		return m, nil
+	}
+	if key.Matches(msg, m.keys.Select) {
+		note, err := tideHeightAt(m.styles, m.tides, m.tideTimeInput.Value(), m.clock.Now())
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.err = nil
+		m.tideHeightNote = note
+		m.state = StateDisplay
+		m.tideTimeInput.Blur()
+		return m, nil
+	}
+	m.tideTimeInput, cmd = m.tideTimeInput.Update(msg)
+	return m, cmd
+}
+
 func (m Model) handleSavePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
-	if msg.Type == tea.KeyEsc {
+	if key.Matches(msg, m.keys.Back) {
 		m.state = StateDisplay
 		return m, nil
 	}
-	if msg.Type == tea.KeyEnter {
+	if key.Matches(msg, m.keys.Select) {
 		name := m.saveInput.Value()
 		if name == "" {
 			return m, nil
 		}
+		// Saving under an existing name replaces that port, so ask first
+		for _, p := range m.savedPorts {
+			if p.Name == name {
+				m.portToOverwrite = name
+				m.state = StateConfirmOverwrite
+				return m, nil
+			}
+		}
 		m.saving = true
 		return m, savePort(m.portService, name, m.searchQuery, m.selectedZone.Code)
 	}
@@ -580,47 +1666,75 @@ func (m Model) handleSavePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m Model) handleConfirmOverwrite(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, m.keys.Confirm) {
+		name := m.portToOverwrite
+		m.portToOverwrite = ""
+		m.saving = true
+		return m, savePort(m.portService, name, m.searchQuery, m.selectedZone.Code)
+	} else if key.Matches(msg, m.keys.Cancel) {
+		// Back to the name prompt to pick another name
+		m.portToOverwrite = ""
+		m.state = StateSavePrompt
+		m.saveInput.Focus()
+		return m, nil
+	}
+	return m, nil
+}
+
 func (m Model) handleSavedPorts(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
-	if keyMsg, ok := msg.(tea.KeyMsg); ok {
-		if keyMsg.Type == tea.KeyEnter {
+	// While a filter is being typed every key is filter text, and esc clears an applied filter
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && !listCapturesKey(m.portList, keyMsg, m.keys.Back) {
+		if key.Matches(keyMsg, m.keys.Select) {
 			if item, ok := m.portList.SelectedItem().(portItem); ok {
 				return m.loadPort(item.port)
 			}
 		}
-		if keyMsg.String() == "n" {
+		if key.Matches(keyMsg, m.keys.Overview) && len(m.savedPorts) > 0 {
+			return m.startOverview()
+		}
+		if key.Matches(keyMsg, m.keys.NewPort) {
+			m.portsReturn = StateSavedPorts
 			m.state = StateSearch
 			m.searchInput.Focus()
 			return m, textinput.Blink
 		}
-		// Handle escape key - return to weather pane if we have a selected zone
-		if keyMsg.Type == tea.KeyEsc {
-			if m.selectedZone != nil {
-				m.state = StateDisplay
-				return m, nil
-			}
-			// If no zone selected, stay in saved ports
-			return m, nil
+		// Handle escape key - return to where the list was opened from
+		if key.Matches(keyMsg, m.keys.Back) {
+			return m.closeSavedPorts(), nil
 		}
 		// New: handle delete key
-		if keyMsg.String() == "d" {
+		if key.Matches(keyMsg, m.keys.DeletePort) {
 			if item, ok := m.portList.SelectedItem().(portItem); ok {
 				m.portToDelete = &item.port
 				m.state = StateConfirmDelete
 				return m, nil
 			}
 		}
+		if key.Matches(keyMsg, m.keys.SetHome) {
+			if item, ok := m.portList.SelectedItem().(portItem); ok {
+				return m, setHomePort(m.portService, item.port.Name)
+			}
+		}
+		if key.Matches(keyMsg, m.keys.UpdatePort) {
+			if item, ok := m.portList.SelectedItem().(portItem); ok {
+				m.portUpdate = &portUpdate{before: item.port}
+				m.state = StateConfirmUpdate
+				return m, rebuildPort(m.portService, item.port)
+			}
+		}
 	}
 	m.portList, cmd = m.portList.Update(msg)
 	return m, cmd
 }
 
 func (m Model) handleConfirmDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	if msg.String() == "y" || msg.String() == "Y" {
+	if key.Matches(msg, m.keys.Confirm) {
 		if m.portToDelete != nil {
 			return m, deletePort(m.portService, m.portToDelete.Name)
 		}
-	} else if msg.String() == "n" || msg.String() == "N" || msg.Type == tea.KeyEsc {
+	} else if key.Matches(msg, m.keys.Cancel) {
 		// Cancel deletion
 		m.portToDelete = nil
 		m.state = StateSavedPorts
@@ -629,36 +1743,135 @@ func (m Model) handleConfirmDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) handleConfirmUpdate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, m.keys.Confirm) {
+		// Nothing to confirm until the lookup finishes
+		if m.portUpdate != nil && m.portUpdate.after != nil {
+			return m, updatePort(m.portService, m.portUpdate.after)
+		}
+	} else if key.Matches(msg, m.keys.Cancel) {
+		m.portUpdate = nil
+		m.state = StateSavedPorts
+		return m, nil
+	}
+	return m, nil
+}
+
+// selectZone picks zone for the port being defined and prompts for the port's name
+func (m Model) selectZone(zone zonelookup.ZoneInfo) (tea.Model, tea.Cmd) {
+	m.selectedZone = &zone
+	// Transition to save prompt to define the port
+	m.state = StateSavePrompt
+	// Default name to location (city/state) or search query
+	defaultName := m.searchQuery
+	if m.location != nil && m.location.Name != "" {
+		defaultName = m.location.Name
+	}
+	m.saveInput.SetValue(defaultName)
+	m.saveInput.Focus()
+	return m, nil
+}
+
 func (m Model) handleZoneList(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
-	if keyMsg, ok := msg.(tea.KeyMsg); ok {
-		if keyMsg.Type == tea.KeyEnter {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && !listCapturesKey(m.zoneList, keyMsg, m.keys.Back) {
+		if key.Matches(keyMsg, m.keys.Select) {
 			if item, ok := m.zoneList.SelectedItem().(zoneItem); ok {
-				m.selectedZone = &item.zone
-				// Transition to save prompt to define the port
-				m.state = StateSavePrompt
-				// Default name to location (city/state) or search query
-				defaultName := m.searchQuery
-				if m.location != nil && m.location.Name != "" {
-					defaultName = m.location.Name
-				}
-				m.saveInput.SetValue(defaultName)
-				m.saveInput.Focus()
-				return m, nil
+				return m.selectZone(item.zone)
 			}
 		}
-		if keyMsg.String() == "s" || keyMsg.Type == tea.KeyEsc {
+		if key.Matches(keyMsg, m.keys.NewSearch, m.keys.Back) {
 			m.state = StateSearch
 			m.searchInput.Focus()
 			return m, textinput.Blink
 		}
 	}
+	prev := m.zoneList.Index()
 	m.zoneList, cmd = m.zoneList.Update(msg)
+	m.zoneList = skipZoneHeader(m.zoneList, prev)
+	return m, cmd
+}
+
+func (m Model) handleCityList(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && !listCapturesKey(m.cityList, keyMsg, m.keys.Back) {
+		if key.Matches(keyMsg, m.keys.Select) {
+			if item, ok := m.cityList.SelectedItem().(cityItem); ok {
+				// Carry on as if the search had named the state
+				m.state = StateLoading
+				return m.Update(geocodeMsg{location: &item.location})
+			}
+		}
+		if key.Matches(keyMsg, m.keys.NewSearch, m.keys.Back) {
+			m.state = StateSearch
+			m.searchInput.Focus()
+			return m, textinput.Blink
+		}
+	}
+	m.cityList, cmd = m.cityList.Update(msg)
 	return m, cmd
 }
 
+func (m Model) handleStationList(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && !listCapturesKey(m.stationList, keyMsg, m.keys.Back) {
+		if key.Matches(keyMsg, m.keys.Select) {
+			if item, ok := m.stationList.SelectedItem().(stationItem); ok {
+				// The station's own position stands in for a geocoded location, so the
+				// nearest tide station found for it is the station itself
+				station := item.station
+				m.err = nil
+				m.currentPort = nil
+				m.location = &geocoding.Location{
+					Latitude:  station.Latitude,
+					Longitude: station.Longitude,
+					Name:      item.Title(),
+				}
+				m.searchQuery = station.Name
+				m.state = StateLoading
+				return m, tea.Batch(
+					nearbyZoneSearch(station.Latitude, station.Longitude, zoneSearchRadiusMiles),
+					findNearestTideStation(station.Latitude, station.Longitude),
+				)
+			}
+		}
+		if key.Matches(keyMsg, m.keys.NewSearch) {
+			m.state = StateSearch
+			m.searchInput.Focus()
+			return m, textinput.Blink
+		}
+		if key.Matches(keyMsg, m.keys.Back) {
+			m.state = StateStationSearch
+			m.stationSearchInput.Focus()
+			return m, textinput.Blink
+		}
+	}
+	m.stationList, cmd = m.stationList.Update(msg)
+	return m, cmd
+}
+
+// listCapturesKey reports whether a list's filter should handle msg instead of the
+// screen's own keys: any key while the filter is being typed, or back while one is applied
+func listCapturesKey(l list.Model, msg tea.KeyMsg, back key.Binding) bool {
+	switch l.FilterState() {
+	case list.Filtering:
+		return true
+	case list.FilterApplied:
+		return key.Matches(msg, back)
+	}
+	return false
+}
+
 // View and render methods
 func (m Model) View() string {
+	if m.bellRinging {
+		return "\a" + m.view()
+	}
+	return m.view()
+}
+
+// view renders the display and any modal over it
+func (m Model) view() string {
 	if m.width == 0 {
 		return "Loading..."
 	}
@@ -677,6 +1890,21 @@ func (m Model) View() string {
 	case StateZoneList:
 		modalContent = m.viewZoneList()
 		showModal = true
+	case StateCityChoice:
+		modalContent = m.viewCityList()
+		showModal = true
+	case StateZoneCode:
+		modalContent = m.viewZoneCode()
+		showModal = true
+	case StateStationSearch:
+		modalContent = m.viewStationSearch()
+		showModal = true
+	case StateStationList:
+		modalContent = m.viewStationList()
+		showModal = true
+	case StateTideTime:
+		modalContent = m.viewTideTime()
+		showModal = true
 	case StateSavedPorts:
 		modalContent = m.viewSavedPorts()
 		showModal = true
@@ -686,6 +1914,15 @@ func (m Model) View() string {
 	case StateConfirmDelete:
 		modalContent = m.viewConfirmDelete()
 		showModal = true
+	case StateConfirmOverwrite:
+		modalContent = m.viewConfirmOverwrite()
+		showModal = true
+	case StateConfirmUpdate:
+		modalContent = m.viewConfirmUpdate()
+		showModal = true
+	case StateOverview:
+		modalContent = m.viewOverview()
+		showModal = true
 	case StateLoading:
 		modalContent = m.viewLoading()
 		showModal = true
@@ -696,53 +1933,138 @@ func (m Model) View() string {
 		modalContent = m.viewError()
 		showModal = true
 	}
+	if m.showHelp {
+		modalContent = m.viewHelp()
+		showModal = true
+	}
 	if showModal {
-		modal := modalStyle.Render(modalContent)
-		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal, lipgloss.WithWhitespaceChars(" "), lipgloss.WithWhitespaceForeground(colorMuted))
+		modal := m.styles.modal.Render(modalContent)
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal, lipgloss.WithWhitespaceChars(" "), lipgloss.WithWhitespaceForeground(m.styles.theme.Muted))
 	}
 	return background
 }
 
 func (m Model) renderEmptyState() string {
-	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, lipgloss.JoinVertical(lipgloss.Center, titleStyle.Render("⚓ Marine Terminal"), mutedStyle.Render("Press 'E' to view ports")))
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, lipgloss.JoinVertical(lipgloss.Center, m.styles.title.Render(m.styles.text("⚓ Marine Terminal")), m.styles.muted.Render("Press 'E' to view ports")))
 }
 
 func (m Model) viewProvisioning() string {
 	sp := m.spinner.View()
-	status := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(m.provisionStatus)
-	return lipgloss.JoinVertical(lipgloss.Center, titleStyle.Render("⚓ Setup"), "", fmt.Sprintf("%s %s", sp, status), "", helpStyle.Render("Downloading marine zones..."))
+	status := m.styles.muted.Render(m.provisionStatus)
+	content := []string{m.styles.title.Render(m.styles.text("⚓ Setup")), "", fmt.Sprintf("%s %s", sp, status)}
+	if m.provisionFraction >= 0 {
+		if canRenderProgressBar() && !m.styles.ascii {
+			content = append(content, "", m.provisionBar.ViewAs(m.provisionFraction))
+		} else {
+			content = append(content, "", fmt.Sprintf("%.0f%% complete", m.provisionFraction*100))
+		}
+	}
+	content = append(content, "", m.styles.help.Render("Downloading marine zones..."))
+	return lipgloss.JoinVertical(lipgloss.Center, content...)
+}
+
+// canRenderProgressBar reports whether the terminal supports the styled progress bar.
+// Terminals without color support fall back to a textual percentage.
+func canRenderProgressBar() bool {
+	return lipgloss.ColorProfile() != termenv.Ascii
 }
 
 func (m Model) viewError() string {
-	title := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B")).Bold(true).Render("✗ Error")
+	title := m.styles.alertDanger.Render(m.styles.text("✗ Error"))
 	msg := "An unknown error occurred"
 	if m.err != nil { msg = m.err.Error() }
-	return lipgloss.JoinVertical(lipgloss.Left, title, "", msg, "", helpStyle.Render("Esc: Back • Q: Quit"))
+	if hint := provisionErrorHint(m.err); hint != "" {
+		msg += "\n\n" + m.styles.muted.Render(hint)
+	}
+	help := "Esc: Back • Q: Quit"
+	if m.reprovisionNeeded {
+		help = "p: Re-provision data • Esc: Back • Q: Quit"
+	}
+	if m.provisionRetry {
+		help = "r: Retry • Esc: Back • Q: Quit"
+		if m.provisionCleanup {
+			help = "r: Delete the download and retry • Esc: Back • Q: Quit"
+		}
+	}
+	if m.zoneSearchWiden {
+		help = fmt.Sprintf("+: Search within %.0f mi • Esc: Back • Q: Quit", m.zoneRadius+zoneSearchRadiusStep)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, title, "", msg, "", m.styles.help.Render(m.styles.text(help)))
+}
+
+// provisionErrorHint explains what kind of problem made provisioning the marine zones
+// fail and how to fix it, or returns "" if err isn't a provisioning failure
+func provisionErrorHint(err error) string {
+	var perr *zonelookup.ProvisionError
+	if !errors.As(err, &perr) {
+		return ""
+	}
+	switch perr.Kind() {
+	case zonelookup.FailureNetwork:
+		return "Network problem: NOAA's marine zones shapefile couldn't be downloaded. Check the internet connection (and any proxy or firewall) and retry."
+	case zonelookup.FailureDisk:
+		return fmt.Sprintf("Disk problem: the marine zones couldn't be saved. Check there's free space and that %s is writable, or choose another location with --db-path.", filepath.Dir(database.DBPath()))
+	default:
+		return "Data problem: the download isn't a usable marine zones shapefile. Retry to download it again, or choose another edition with --shapefile."
+	}
 }
 
 func (m Model) viewSearch() string {
-	title := titleStyle.Render("New Port Setup")
-	subtitle := mutedStyle.Render("Enter Zipcode or City, State")
+	title := m.styles.title.Render("New Port Setup")
+	subtitle := m.styles.muted.Render("Enter Zipcode or City, State")
 	sb := m.searchInput.View()
 	errorMsg := ""
 	if m.err != nil {
-		errorMsg = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B")).Bold(true).Render("✗ " + m.err.Error())
+		errorMsg = m.styles.alertDanger.Render(m.styles.text("✗ " + m.err.Error()))
 	}
 	content := []string{title, subtitle, "", sb}
 	if errorMsg != "" { content = append(content, "", errorMsg) }
-	content = append(content, "", mutedStyle.Render("e.g. 02633, Chatham MA"))
+	content = append(content, "", m.styles.muted.Render("e.g. 02633, Chatham MA"), m.styles.help.Render(m.styles.text("Tab: Jump to zone by code • Shift+Tab: Search stations by name")))
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+func (m Model) viewZoneCode() string {
+	title := m.styles.title.Render("Jump to Zone")
+	subtitle := m.styles.muted.Render("Enter a NOAA marine zone code")
+	content := []string{title, subtitle, "", m.zoneCodeInput.View()}
+	if m.err != nil {
+		content = append(content, "", m.styles.alertDanger.Render(m.styles.text("✗ "+m.err.Error())))
+	}
+	content = append(content, "", m.styles.muted.Render("e.g. ANZ254, GMZ830, PZZ135"), m.styles.help.Render(m.styles.text("Enter: Load zone • Tab/Esc: Back to search")))
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+func (m Model) viewStationSearch() string {
+	title := m.styles.title.Render("Find Station")
+	subtitle := m.styles.muted.Render("Enter part of a NOAA tide station's name or city")
+	content := []string{title, subtitle, "", m.stationSearchInput.View()}
+	if m.err != nil {
+		content = append(content, "", m.styles.alertDanger.Render(m.styles.text("✗ "+m.err.Error())))
+	}
+	content = append(content, "", m.styles.muted.Render("e.g. Woods Hole, Boston MA"), m.styles.help.Render(m.styles.text("Enter: Search • Shift+Tab/Esc: Back to search")))
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+func (m Model) viewTideTime() string {
+	title := m.styles.title.Render("Tide Height")
+	subtitle := m.styles.muted.Render("Enter a time to see the predicted height")
+	content := []string{title, subtitle, "", m.tideTimeInput.View()}
+	if m.err != nil {
+		content = append(content, "", m.styles.alertDanger.Render(m.styles.text("✗ "+m.err.Error())))
+	}
+	content = append(content, "", m.styles.help.Render(m.styles.text("Enter: Look up • Esc: Back")))
 	return lipgloss.JoinVertical(lipgloss.Left, content...)
 }
 
 func (m Model) viewSavedPorts() string {
-	title := titleStyle.Render("Saved Ports")
-	help := mutedStyle.Render("Enter: Select • n: New Port • d: Delete Port")
+	title := m.styles.title.Render("Saved Ports")
+	help := m.styles.muted.Render(m.styles.text("Enter: Select • /: Filter • o: Overview • n: New Port • d: Delete Port • u: Update Port • *: Set Home"))
 	return lipgloss.JoinVertical(lipgloss.Left, title, "", m.portList.View(), "", help)
 }
 
 func (m Model) viewSavePrompt() string {
-	title := titleStyle.Render("Save Port")
-	subtitle := mutedStyle.Render("Enter a name for this configuration")
+	title := m.styles.title.Render("Save Port")
+	subtitle := m.styles.muted.Render("Enter a name for this configuration")
 	return lipgloss.JoinVertical(lipgloss.Left, title, subtitle, "", m.saveInput.View())
 }
 
@@ -752,80 +2074,379 @@ func (m Model) viewConfirmDelete() string {
 		portName = m.portToDelete.Name
 	}
 
-	title := alertDangerStyle.Render("Delete Port")
+	title := m.styles.alertDanger.Render("Delete Port")
 	prompt := fmt.Sprintf("Are you sure you want to delete '%s'? (y/n)", portName)
-	return lipgloss.JoinVertical(lipgloss.Left, title, "", prompt, "", helpStyle.Render("y: Confirm • n/Esc: Cancel"))
+	return lipgloss.JoinVertical(lipgloss.Left, title, "", prompt, "", m.styles.help.Render(m.styles.text("y: Confirm • n/Esc: Cancel")))
+}
+
+func (m Model) viewConfirmOverwrite() string {
+	title := m.styles.warning.Bold(true).Render("Overwrite Port")
+	prompt := m.styles.text(fmt.Sprintf("Port '%s' exists — overwrite? (y/n)", m.portToOverwrite))
+	return lipgloss.JoinVertical(lipgloss.Left, title, "", prompt, "", m.styles.help.Render(m.styles.text("y: Overwrite • n/Esc: Choose another name")))
+}
+
+func (m Model) viewConfirmUpdate() string {
+	if m.portUpdate == nil {
+		return ""
+	}
+	before := m.portUpdate.before
+	title := m.styles.title.Render(fmt.Sprintf("Update '%s'", before.Name))
+	if m.portUpdate.after == nil {
+		lookup := m.styles.muted.Render(m.styles.text(m.spinner.View() + " Looking up the current zone and station…"))
+		return lipgloss.JoinVertical(lipgloss.Left, title, "", lookup, "", m.styles.help.Render(m.styles.text("n/Esc: Cancel")))
+	}
+	after := m.portUpdate.after
+	change := func(label, old, new string) string {
+		if old == "" {
+			old = "none"
+		}
+		if old == new {
+			return m.styles.label.Render(label) + " " + new + m.styles.muted.Render(" (unchanged)")
+		}
+		return m.styles.label.Render(label) + " " + m.styles.text(fmt.Sprintf("%s → %s", old, new))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, title, "",
+		change("Zone:", before.MarineZoneID, after.MarineZoneID),
+		change("Station:", before.TideStationID, after.TideStationID),
+		"", m.styles.help.Render(m.styles.text("y: Update • n/Esc: Cancel")))
 }
 
 func (m Model) viewZoneList() string {
-	return lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render("Select Zone"), "", m.zoneList.View())
+	return lipgloss.JoinVertical(lipgloss.Left, m.styles.title.Render("Select Zone"), "", m.zoneList.View())
+}
+
+func (m Model) viewCityList() string {
+	return lipgloss.JoinVertical(lipgloss.Left, m.styles.title.Render("Select Place"), "", m.cityList.View())
+}
+
+func (m Model) viewStationList() string {
+	return lipgloss.JoinVertical(lipgloss.Left, m.styles.title.Render("Select Station"), "", m.stationList.View())
 }
 
 func (m Model) viewLoading() string {
 	return fmt.Sprintf("%s Loading...", m.spinner.View())
 }
 
+// zoneProximity describes where the location is relative to the selected zone, using
+// the zone's polygon when it has been measured and its center otherwise
+func (m Model) zoneProximity() string {
+	if m.zoneBoundary == nil {
+		return fmt.Sprintf("%.1f mi away", m.selectedZone.Distance)
+	}
+	if m.zoneBoundary.Inside {
+		return "inside the zone"
+	}
+	return fmt.Sprintf("%.1f mi from the zone boundary", m.zoneBoundary.Miles)
+}
+
+// tideFetchNote annotates which part of the last tide station fetch failed
+func (m Model) tideFetchNote() string {
+	var notes []string
+	if m.tideErr != nil {
+		notes = append(notes, "⚠ Tide predictions unavailable")
+		if m.tides != nil {
+			notes[len(notes)-1] += " (showing previous predictions)"
+		}
+	}
+	if m.metErr != nil {
+		notes = append(notes, "⚠ Met data unavailable (air temp, pressure)")
+	}
+	if m.levelErr != nil && !errors.Is(m.levelErr, noaa.ErrNoWaterLevel) {
+		notes = append(notes, "⚠ Observed water level unavailable")
+		if m.waterLevel != nil {
+			notes[len(notes)-1] += " (showing previous reading)"
+		}
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+	return m.styles.warning.Render(m.styles.text(strings.Join(notes, "\n")))
+}
+
+// nextCompareDatum returns the comparison datum after current, skipping base (the datum
+// predictions are fetched in), or "" after the last one to turn the comparison off
+func nextCompareDatum(current, base string) string {
+	var choices []string
+	for _, d := range models.ComparisonDatums {
+		if d != base {
+			choices = append(choices, d)
+		}
+	}
+	if current == "" {
+		return choices[0]
+	}
+	for i, d := range choices {
+		if d == current && i+1 < len(choices) {
+			return choices[i+1]
+		}
+	}
+	return ""
+}
+
+// convertedHeight formats a tide height in the comparison datum, or returns "" if
+// it can't be converted for the displayed station
+func (m Model) convertedHeight(height float64) string {
+	if m.compareDatum == "" || m.tideStation == nil || m.datumStation != m.tideStation.ID {
+		return ""
+	}
+	converted, err := m.datumOffsets.Convert(height, m.tideDatum(), m.compareDatum)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("  (%.1f ft %s)", converted, m.compareDatum)
+}
+
+// datumNote explains why tide heights aren't shown in the comparison datum, if they aren't
+func (m Model) datumNote() string {
+	if m.compareDatum == "" || m.tideStation == nil {
+		return ""
+	}
+	switch {
+	case m.datumStation != m.tideStation.ID:
+		return m.styles.muted.Render("Loading datum offsets...")
+	case m.datumErr != nil:
+		return m.styles.warning.Render(m.styles.text("⚠ Datum offsets unavailable for this station"))
+	case !m.datumOffsets.Has(m.compareDatum):
+		return m.styles.warning.Render(m.styles.text(fmt.Sprintf("⚠ %s not published for this station", m.compareDatum)))
+	case !m.datumOffsets.Has(m.tideDatum()):
+		return m.styles.warning.Render(m.styles.text(fmt.Sprintf("⚠ %s not published for this station", m.tideDatum())))
+	}
+	return ""
+}
+
+// boxWidth is the width of the box the weather and tides panes are drawn in, counting
+// its padding but not its border
+func boxWidth(termWidth int) int {
+	width := termWidth - 4
+	if width < 40 {
+		width = 40
+	}
+	return width
+}
+
+// boxContentWidth is the width left for content inside the box's padding
+func boxContentWidth(termWidth int) int {
+	return boxWidth(termWidth) - 2*sectionBoxPaddingX
+}
+
+// locationLabel names the location on display: the port's DisplayName, or else the
+// search that found it
+func (m Model) locationLabel() string {
+	if m.currentPort != nil {
+		return m.currentPort.DisplayName()
+	}
+	return m.searchQuery
+}
+
 func (m Model) renderWeatherView() string {
 	if m.selectedZone == nil { return "No zone" }
-	header := lipgloss.NewStyle().Foreground(colorPrimary).Bold(true).Padding(0, 1).MarginBottom(1).Render(fmt.Sprintf("⚓ %s - %s", m.selectedZone.Code, m.selectedZone.Name))
+	boxWidth := boxWidth(m.width)
+	// Lines outside the box are kept within its outer edge
+	outerWidth := boxWidth + m.styles.sectionBox.GetHorizontalBorderSize()
+	header := m.styles.header.Render(ansi.Truncate(m.styles.text(fmt.Sprintf("⚓ %s - %s", m.selectedZone.Code, m.selectedZone.Name)), outerWidth-m.styles.header.GetHorizontalFrameSize(), "…"))
 	loc := ""
 	if m.location != nil {
-		loc = mutedStyle.Render(fmt.Sprintf("📍 %s (%.1f mi away)", m.searchQuery, m.selectedZone.Distance))
+		loc = m.styles.text(fmt.Sprintf("📍 %s (%s)", m.locationLabel(), m.zoneProximity()))
+		if i := m.zoneIndex(); i >= 0 && len(m.zones) > 1 {
+			loc += m.styles.text(fmt.Sprintf("  •  Zone %d of %d", i+1, len(m.zones)))
+		}
+		loc = m.styles.muted.Render(ansi.Truncate(loc, outerWidth, "…"))
 	}
 	
-	weatherTab := tabStyle.Render("Weather")
-	if m.activePane == PaneWeather { weatherTab = activeTabStyle.Render("Weather") }
-	tidesTab := tabStyle.Render("Tides")
-	if m.activePane == PaneTides { tidesTab = activeTabStyle.Render("Tides") }
+	weatherTab := m.styles.tab.Render("Weather")
+	if m.activePane == PaneWeather { weatherTab = m.styles.activeTab.Render("Weather") }
+	tidesTab := m.styles.tab.Render("Tides")
+	if m.activePane == PaneTides { tidesTab = m.styles.activeTab.Render("Tides") }
 	tabBar := lipgloss.JoinHorizontal(lipgloss.Top, weatherTab, tidesTab)
 	
-	boxWidth := m.width - 4
-	if boxWidth < 40 { boxWidth = 40 }
-	boxStyle := sectionBoxStyle.Copy().Width(boxWidth)
+	boxStyle := m.styles.sectionBox.Copy().Width(boxWidth)
 	
 	var content string
-	if m.activePane == PaneWeather {
+	if m.activePane == PaneWeather && m.showRawForecast {
 		content = lipgloss.JoinVertical(lipgloss.Left,
-			lipgloss.JoinVertical(lipgloss.Left, boxHeaderStyle.Render("⛅ MARINE FORECAST"), m.renderWeatherSimple()),
+			m.styles.boxHeader.Render(m.styles.text("📜 RAW NOAA FORECAST TEXT")),
+			m.rawViewport.View(),
 			"",
-			lipgloss.JoinVertical(lipgloss.Left, boxHeaderStyle.Render("⚠️  MARINE ALERTS"), m.renderAlertSimple()),
+			m.styles.muted.Render(m.styles.text("↑/↓: Scroll • v/Esc: Back to formatted view")),
+		)
+	} else if m.activePane == PaneWeather {
+		content = lipgloss.JoinVertical(lipgloss.Left,
+			lipgloss.JoinVertical(lipgloss.Left, m.styles.boxHeader.Render(m.styles.text("⛅ MARINE FORECAST")), m.renderWeatherSimple()),
+			"",
+			lipgloss.JoinVertical(lipgloss.Left, m.styles.boxHeader.Render(m.styles.text("⚠️  MARINE ALERTS")), m.renderAlertSimple()),
 		)
 	} else {
 		tideInfo := "No nearby tide station found."
+		if m.tideStationsEmpty {
+			tideInfo = "The tide station database is empty; provisioning may not have completed.\nPress 'p' to re-provision."
+		}
 		if m.tideStation != nil {
-			tideInfo = fmt.Sprintf("Station: %s (%s)\n", m.tideStation.Name, m.tideStation.ID)
+			tideInfo = fmt.Sprintf("Station: %s (%s)  Datum: %s", m.tideStation.Name, m.tideStation.ID, m.tideDatum())
+			if m.compareDatum != "" {
+				tideInfo += fmt.Sprintf(" (also %s)", m.compareDatum)
+			}
+			tideInfo += "\n"
 			if m.loadingTides {
-				tideInfo += "\n" + m.spinner.View() + " Loading tide predictions..."
+				tideInfo += "\n" + renderSkeleton(m.styles, m.spinner.View()+" Loading tide predictions...", tideSkeletonLines)
 			} else {
 				if m.tideConditions != nil {
-					tideInfo += fmt.Sprintf("Air Temp: %.1f°F  Pressure: %.1f mb\n", m.tideConditions.Temperature, m.tideConditions.Pressure)
+					tideInfo += m.styles.text(fmt.Sprintf("Air Temp: %.1f°F  Pressure: %s", m.tideConditions.Temperature, models.FormatPressure(m.tideConditions.Pressure, m.pressureUnit)))
+					if len(m.tideConditions.WaterTempHistory) > 0 {
+						tideInfo += m.styles.text(fmt.Sprintf("  Water Temp: %.1f°F", m.tideConditions.WaterTemperature))
+					}
+					tideInfo += "\n"
+					if wind := formatWindSummary(m.tideConditions.WindHistory); wind != "" {
+						tideInfo += m.styles.text(wind) + "\n"
+					}
+				}
+				if trend := m.renderTempTrends(boxWidth - 6); trend != "" {
+					tideInfo += "\n" + trend + "\n"
+				}
+				if m.waterLevel != nil {
+					tideInfo += "\n" + formatWaterLevel(m.styles, *m.waterLevel) + "\n"
 				}
 				if m.tides != nil {
-					tideInfo += "\nUpcoming Tides:"
-					for i, event := range m.tides.Events {
-						if i >= 6 { break }
-						tideInfo += fmt.Sprintf("\n  %s  %-4s  %.1f ft", event.Time.Format("Jan 2, 3:04 PM"), event.Type, event.Height)
+					if tideRange := formatTideRange(m.styles, m.tides); tideRange != "" {
+						tideInfo += "\n" + tideRange + "\n"
+					}
+					if day := m.tidePageDay(); day.IsZero() {
+						tideInfo += "\nUpcoming Tides:"
+					} else {
+						tideInfo += "\nTides for " + day.Format("Mon, Jan 2") + ":"
+						if pages := m.tidePageCount(); pages > 1 {
+							tideInfo += m.styles.muted.Render(fmt.Sprintf("  day %d of %d", m.tidePage+1, pages))
+						}
+					}
+					for _, event := range m.tidePageEvents() {
+						tideInfo += fmt.Sprintf("\n  %s  %-4s  %.1f ft", m.styles.formatTime(event.Time, "Jan 2, 3:04 PM"), event.Type, event.Height) + m.convertedHeight(event.Height)
+					}
+					if m.tideHeightNote != "" {
+						tideInfo += "\n\n" + m.styles.value.Render(m.tideHeightNote)
+					}
+					if note := m.datumNote(); note != "" {
+						tideInfo += "\n\n" + note
 					}
-					tideInfo += "\n\n" + m.tideChart.View()
-				} else { tideInfo += "\nNo tide predictions available." }
+					if m.showTideClock {
+						tideInfo += "\n\n" + renderTideClock(m.styles, m.tides, m.clock.Now())
+					} else {
+						tideInfo += "\n\n" + m.renderTideChart()
+					}
+				} else if m.tideErr == nil { tideInfo += "\nNo tide predictions available." }
+				if note := m.tideFetchNote(); note != "" {
+					tideInfo += "\n\n" + note
+				}
 			}
 		}
-		content = lipgloss.JoinVertical(lipgloss.Left, boxHeaderStyle.Render("🌊 TIDES"), tideInfo)
+		content = lipgloss.JoinVertical(lipgloss.Left, m.styles.boxHeader.Render(m.styles.text("🌊 TIDES")), tideInfo)
 	}
 	
-	return lipgloss.JoinVertical(lipgloss.Left, header, loc, "", tabBar, "", boxStyle.Render(content), "", helpStyle.Render("e: Edit Port • r: Refresh • Tab: Switch tab • q: Quit"))
+	extraHelp := ""
+	if m.zoneIndex() >= 0 && len(m.zones) > 1 {
+		extraHelp = "←/→: Zone • "
+	}
+	if m.activePane == PaneTides {
+		extraHelp += "m: Datum • t: Height at time • C: Chart/clock • "
+		if m.tidePageCount() > 1 {
+			extraHelp += "[/]: Prev/next day • "
+		}
+	}
+	help := m.styles.help.Render(m.styles.text("e: Edit Port • r: Refresh • v: Raw forecast • Tab: Switch tab • x: Export • c/i: Copy zone/station • " + extraHelp + "?: Help • q: Quit"))
+	if m.newerEdition != "" {
+		help = lipgloss.JoinVertical(lipgloss.Left,
+			m.styles.warning.Render(m.styles.text(fmt.Sprintf("New NOAA marine zones data available (%s) • u: Update", m.newerEdition))),
+			help,
+		)
+	}
+	if note := m.exportNoteText(); note != "" {
+		help = lipgloss.JoinVertical(lipgloss.Left, note, help)
+	}
+	if note := m.copyNoteText(); note != "" {
+		help = lipgloss.JoinVertical(lipgloss.Left, note, help)
+	}
+	help = lipgloss.NewStyle().Width(outerWidth).Render(help)
+	view := lipgloss.JoinVertical(lipgloss.Left, header, loc, "", tabBar, "", boxStyle.Render(content), "", help)
+	if bar := m.renderStatusBar(outerWidth); bar != "" && !m.hideStatusBar {
+		view = lipgloss.JoinVertical(lipgloss.Left, bar, view)
+	}
+	return view
+}
+
+// newRawForecastViewport builds a scrollable viewport over the unparsed forecast text
+func (m Model) newRawForecastViewport() viewport.Model {
+	width := boxContentWidth(m.width)
+	height := m.height - 16
+	if height < 5 {
+		height = 5
+	}
+
+	vp := viewport.New(width, height)
+	vp.SetContent(lipgloss.NewStyle().Width(width).Render(rawForecastText(m.forecast)))
+	return vp
+}
+
+// rawForecastText returns the unparsed NOAA text for the first forecast period
+func rawForecastText(forecast *models.ThreeDayForecast) string {
+	if forecast == nil || len(forecast.Periods) == 0 {
+		return "No raw forecast text available."
+	}
+	period := forecast.Periods[0]
+	if period.RawText == "" {
+		return "No raw forecast text available."
+	}
+	return fmt.Sprintf("%s\n\n%s", period.PeriodName, period.RawText)
 }
 
 func (m Model) renderWeatherSimple() string {
-	if m.loadingWeather { return fmt.Sprintf("%s Fetching marine forecast...", m.spinner.View()) }
+	if m.loadingWeather {
+		return renderSkeleton(m.styles, fmt.Sprintf("%s Fetching marine forecast...", m.spinner.View()), weatherSkeletonLines)
+	}
 	if m.weather == nil { return "No marine weather data available." }
-	return formatWeather(m.weather, m.forecast)
+	weather := formatWeather(m.styles, m.weather, m.forecast, m.smallCraft, m.forecastPeriodLimit, m.showBeaufort, m.changes)
+	if rating := formatRating(m.styles, m.weather, m.rating); rating != "" {
+		weather = rating + "\n\n" + weather
+	}
+	if m.tideStation != nil {
+		if comparison := formatObservedComparison(m.styles, m.weather, m.tideConditions, m.tideStation.Name); comparison != "" {
+			weather += "\n\n" + comparison
+		}
+	}
+	if m.weatherSource != "" {
+		weather = m.styles.warning.Render("Source: "+m.weatherSource) + "\n\n" + weather
+	}
+	return weather
+}
+
+// alertArea is the selected zone's name as alerts list it among their areas. A port's
+// zone is shown under the port's name, so its NWS name is kept aside.
+func (m Model) alertArea() string {
+	if m.selectedZone == nil {
+		return ""
+	}
+	if m.currentPort != nil && m.selectedZone.Code == m.currentPort.MarineZoneID && m.selectedZone.Name == m.currentPort.Name {
+		return m.portZoneName
+	}
+	return m.selectedZone.Name
 }
 
 func (m Model) renderAlertSimple() string {
-	if m.loadingAlerts { return fmt.Sprintf("%s Fetching marine alerts...", m.spinner.View()) }
-	if m.alerts == nil || len(m.alerts.Alerts) == 0 { return "No active marine alerts." }
-	return formatAlerts(m.alerts)
+	if m.loadingAlerts {
+		return renderSkeleton(m.styles, fmt.Sprintf("%s Fetching marine alerts...", m.spinner.View()), alertSkeletonLines)
+	}
+	var alerts string
+	if m.alerts == nil || len(m.alerts.Alerts) == 0 {
+		alerts = "No active marine alerts."
+	} else {
+		alerts = formatAlerts(m.styles, m.alerts, m.clock, m.hideStatements, m.exactAlertTimes, m.alertArea(), m.changes.newAlerts, boxContentWidth(m.width))
+	}
+	if m.showAlertLog && m.selectedZone != nil {
+		current := m.alerts
+		if m.alertsErr != nil {
+			current = nil // The alerts kept from before can't say what was lifted since
+		}
+		expired := recentlyExpired(m.alertLog, m.selectedZone.Code, current, m.clock)
+		alerts += "\n\n" + formatRecentlyExpired(m.styles, expired, m.clock.Now())
+	}
+	return alerts
 }
 
 func formatWind(wind models.WindData) string {
@@ -842,48 +2463,223 @@ func formatSeas(seas models.SeaState) string {
 	return fmt.Sprintf("%.0f-%.0f ft", seas.HeightMin, seas.HeightMax)
 }
 
-func formatWeather(current *models.MarineConditions, forecast *models.ThreeDayForecast) string {
-	if current == nil && forecast == nil { return mutedStyle.Render("No weather data available") }
+// formatWaveComponents renders swell and wind wave components as separate groups
+func formatWaveComponents(st styles, components []models.WaveComponent) []string {
+	var swell, windWaves []string
+	for _, wave := range components {
+		if wave.Kind == models.WaveKindSwell {
+			swell = append(swell, formatWave(wave, wavePeriod))
+		} else {
+			windWaves = append(windWaves, formatWave(wave, wavePeriod))
+		}
+	}
+	var lines []string
+	if len(swell) > 0 { lines = append(lines, st.muted.Render("  Swell: "+strings.Join(swell, ", "))) }
+	if len(windWaves) > 0 { lines = append(lines, st.muted.Render("  Wind waves: "+strings.Join(windWaves, ", "))) }
+	return lines
+}
+
+// Period formats for formatWave: "W 6 ft at 10 sec" in the component lists, and the
+// compact "W 6 ft @ 10s" for the dominant wave
+const (
+	wavePeriod        = " at %d sec"
+	compactWavePeriod = " @ %ds"
+)
+
+func formatWave(wave models.WaveComponent, period string) string {
+	text := fmt.Sprintf("%.0f ft", wave.Height)
+	if wave.Direction != "" { text = wave.Direction + " " + text }
+	if wave.Period > 0 { text += fmt.Sprintf(period, wave.Period) }
+	return text
+}
+
+// formatTideRange summarizes the lowest low and highest high over the prediction window
+func formatTideRange(st styles, tides *models.TideData) string {
+	lowest := tides.LowestLow()
+	highest := tides.HighestHigh()
+	if lowest == nil || highest == nil {
+		return ""
+	}
+	return fmt.Sprintf("Range: %.1f ft (%s) to %.1f ft (%s)",
+		lowest.Height, st.formatTime(lowest.Time, "Mon 3:04 PM"),
+		highest.Height, st.formatTime(highest.Time, "Mon 3:04 PM"))
+}
+
+// formatWaterLevel compares the latest observed water level with the prediction, e.g.
+// "Observed 4.1 ft at 12:06 PM, 0.6 ft above prediction"
+func formatWaterLevel(st styles, level models.WaterLevel) string {
+	observed := fmt.Sprintf("Observed %.1f ft at %s", level.Observed, st.formatTime(level.Time, "3:04 PM"))
+	residual := level.Residual()
+	switch {
+	case math.Abs(residual) < 0.05:
+		return observed + ", as predicted"
+	case residual > 0:
+		return fmt.Sprintf("%s, %.1f ft above prediction", observed, residual)
+	default:
+		return fmt.Sprintf("%s, %.1f ft below prediction", observed, -residual)
+	}
+}
+
+// windSummaryPeriod is how far back the tides pane summarizes observed wind
+const windSummaryPeriod = 3 * time.Hour
+
+// formatWindSummary summarizes the station's recent wind, e.g. "Wind last 3h: W 12-18 kt".
+// It returns "" if the station reported no wind.
+func formatWindSummary(history []models.WindObservation) string {
+	summary, ok := models.SummarizeWind(history, windSummaryPeriod)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("Wind last %.0fh: %s", windSummaryPeriod.Hours(), summary)
+}
+
+// formatRating renders the go/no-go rating of the current wind and seas, colored by
+// how rough they are. It returns "" if there is nothing to rate.
+func formatRating(st styles, current *models.MarineConditions, thresholds models.RatingThresholds) string {
+	rating := current.Rating(thresholds)
+	var style lipgloss.Style
+	switch rating {
+	case models.RatingUnknown:
+		return ""
+	case models.RatingCalm:
+		style = st.success
+	case models.RatingModerate:
+		style = st.value
+	case models.RatingRough:
+		style = st.warning
+	default:
+		style = st.alertDanger
+	}
+	return st.label.Render("Conditions: ") + style.Bold(true).Render(strings.ToUpper(rating.String()))
+}
+
+// smallCraftNote marks forecast periods that reach the small craft thresholds
+const smallCraftNote = "⚠ small craft conditions"
+
+// squallNote follows the wind of a period whose forecast mentions squalls
+const squallNote = "squalls possible"
+
+// DefaultForecastPeriodLimit is the number of upcoming forecast periods listed by default
+const DefaultForecastPeriodLimit = 6
+
+const (
+	// DefaultTideWindowDays is the number of days of tide predictions fetched by default
+	DefaultTideWindowDays = 3
+	// MaxTideWindowDays caps the prediction window, a month being plenty for trip planning
+	MaxTideWindowDays = 31
+)
+
+// ValidateTideWindowDays checks a tide prediction window length in days
+func ValidateTideWindowDays(days int) error {
+	if days < 1 || days > MaxTideWindowDays {
+		return fmt.Errorf("tide prediction window must be between 1 and %d days, got %d", MaxTideWindowDays, days)
+	}
+	return nil
+}
+
+// formatWeather renders current conditions and up to limit upcoming periods (all of them
+// when limit is 0), highlighting any period whose wind or seas reach the small craft thresholds.
+// With beaufort set, the current wind's Beaufort force is shown after it. Changes to
+// the current wind and seas since the last refresh are noted after them.
+func formatWeather(st styles, current *models.MarineConditions, forecast *models.ThreeDayForecast, thresholds models.SmallCraftThresholds, limit int, beaufort bool, changes conditionChanges) string {
+	if current == nil && forecast == nil { return st.muted.Render("No weather data available") }
 	var lines []string
 	if current != nil && forecast != nil && len(forecast.Periods) > 0 {
-		lines = append(lines, lipgloss.NewStyle().Foreground(colorSecondary).Bold(true).Render(forecast.Periods[0].PeriodName))
-		if current.Wind.Direction != "" { lines = append(lines, labelStyle.Render("Wind: ") + valueStyle.Render(formatWind(current.Wind))) }
-		if current.Seas.HeightMin > 0 || current.Seas.HeightMax > 0 { lines = append(lines, labelStyle.Render("Seas: ") + valueStyle.Render(formatSeas(current.Seas))) }
-		for _, wave := range current.Seas.Components { lines = append(lines, mutedStyle.Render(fmt.Sprintf("  %s %.0f ft at %d sec", wave.Direction, wave.Height, wave.Period))) }
+		heading := lipgloss.NewStyle().Foreground(st.theme.Secondary).Bold(true).Render(forecast.Periods[0].PeriodName)
+		if thresholds.Exceeded(current.Wind, current.Seas) {
+			heading += "  " + st.warning.Bold(true).Render(st.text(smallCraftNote))
+		}
+		lines = append(lines, heading)
+		if current.Wind.Direction != "" {
+			wind := st.label.Render("Wind: ") + st.value.Render(formatWind(current.Wind))
+			if beaufort {
+				wind += st.muted.Render("  " + current.Wind.Beaufort().String())
+			}
+			if current.Wind.Squalls {
+				wind += "  " + st.warning.Render(squallNote)
+			}
+			lines = append(lines, wind+changeNote(st, changes.wind))
+		}
+		if current.Seas.HeightMin > 0 || current.Seas.HeightMax > 0 { lines = append(lines, st.label.Render("Seas: ") + st.value.Render(formatSeas(current.Seas)) + changeNote(st, changes.seas)) }
+		// With several components, call out the one that sets the feel of the seas
+		if dominant, ok := current.Seas.Dominant(); ok && len(current.Seas.Components) > 1 {
+			lines = append(lines, st.label.Render("Dominant: ")+st.value.Bold(true).Render(formatWave(dominant, compactWavePeriod)))
+		}
+		lines = append(lines, formatWaveComponents(st, current.Seas.Components)...)
 	}
 	if forecast != nil && len(forecast.Periods) > 1 {
-		lines = append(lines, "", labelStyle.Render("📅 3-Day Forecast:"))
-		max := 6
-		if len(forecast.Periods)-1 < max { max = len(forecast.Periods)-1 }
+		lines = append(lines, "", st.label.Render(st.text("📅 3-Day Forecast:")))
+		max := len(forecast.Periods) - 1
+		if limit > 0 && limit < max { max = limit }
 		for i := 1; i <= max; i++ {
-			p := forecast.Periods[i]
-			lines = append(lines, fmt.Sprintf("  %s %s", valueStyle.Render(p.PeriodName+":"), mutedStyle.Render(fmt.Sprintf("%s, Seas %s", formatWind(p.Wind), formatSeas(p.Seas)))))
+			p, prev := forecast.Periods[i], forecast.Periods[i-1]
+			summary := st.text(fmt.Sprintf("%s, Seas %s",
+				withTrend(formatWind(p.Wind), windTrend(prev.Wind, p.Wind)),
+				withTrend(formatSeas(p.Seas), seasTrend(prev.Seas, p.Seas))))
+			if p.Wind.Squalls {
+				summary += ", " + squallNote
+			}
+			if thresholds.Exceeded(p.Wind, p.Seas) {
+				lines = append(lines, fmt.Sprintf("  %s %s", st.warning.Bold(true).Render(p.PeriodName+":"), st.warning.Render(summary+"  "+st.text(smallCraftNote))))
+				continue
+			}
+			if p.IsNight() {
+				lines = append(lines, fmt.Sprintf("  %s %s", st.muted.Render(p.PeriodName+":"), st.night.Render(summary)))
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("  %s %s", st.value.Render(p.PeriodName+":"), st.muted.Render(summary)))
 		}
 	}
 	return strings.Join(lines, "\n")
 }
 
-func formatAlerts(alerts *models.AlertData) string {
-	if alerts == nil { return mutedStyle.Render("No alert data available") }
-	activedAlerts := make([]models.Alert, 0)
-	for _, a := range alerts.Alerts { if a.IsActive() && a.IsMarine() { activedAlerts = append(activedAlerts, a) } }
-	if len(activedAlerts) == 0 { return successStyle.Bold(true).Render("✓ No active marine alerts") }
+// formatAlerts lists the active marine alerts, most severe first. With hideStatements
+// set, informational alerts are left out and only counted. Headlines wider than width
+// are cut short with an ellipsis; a width of 0 leaves them whole. The area matching
+// area, the selected zone's name, is highlighted in each alert's list of areas, and
+// alerts whose keys (see alertLogKey) are in fresh are marked new.
+func formatAlerts(st styles, alerts *models.AlertData, clock models.Clock, hideStatements, exactTimes bool, area string, fresh map[string]bool, width int) string {
+	if alerts == nil { return st.muted.Render("No alert data available") }
+	activedAlerts := alerts.ActiveMarineAlertsAt(clock)
+	if len(activedAlerts) == 0 { return st.success.Bold(true).Render(st.text("✓ No active marine alerts")) }
+	hidden := 0
+	if hideStatements {
+		var shown []models.Alert
+		for _, a := range activedAlerts {
+			if a.IsStatement() {
+				hidden++
+				continue
+			}
+			shown = append(shown, a)
+		}
+		activedAlerts = shown
+	}
+	hiddenNote := st.muted.Render(st.text(fmt.Sprintf("%d statement(s) hidden • a: show all", hidden)))
+	if len(activedAlerts) == 0 {
+		return st.success.Bold(true).Render(st.text("✓ No marine warnings or advisories")) + "\n" + hiddenNote
+	}
 	var lines []string
 	for i, a := range activedAlerts {
 		if i > 0 { lines = append(lines, "") }
-		lines = append(lines, getAlertStyle(a.Severity).Render(fmt.Sprintf("️%s", a.Event)))
-		lines = append(lines, valueStyle.Render(a.Headline))
-		lines = append(lines, labelStyle.Render("Expires: ") + mutedStyle.Render(a.Expires.Format("Jan 2, 3:04 PM")))
+		event := st.alert(a.Severity).Render(st.text(fmt.Sprintf("️%s", a.Event)))
+		if fresh[alertLogKey(a)] {
+			event += "  " + st.warning.Bold(true).Render("NEW")
+		}
+		lines = append(lines, event)
+		headline := a.Headline
+		if width > 0 {
+			headline = ansi.Truncate(headline, width, "…")
+		}
+		lines = append(lines, st.value.Render(headline))
+		if areas := alertAreas(st, a, area, width); areas != "" {
+			lines = append(lines, areas)
+		}
+		if times := alertTimes(st, a, clock.Now(), exactTimes); times != "" {
+			lines = append(lines, times)
+		}
 	}
-	return strings.Join(lines, "\n")
-}
-
-func getAlertStyle(s models.AlertSeverity) lipgloss.Style {
-	switch s {
-	case models.SeverityExtreme: return alertExtremeStyle
-	case models.SeveritySevere: return alertSevereStyle
-	case models.SeverityModerate: return alertModerateStyle
-	case models.SeverityMinor: return alertMinorStyle
-	default: return valueStyle
+	if hidden > 0 {
+		lines = append(lines, "", hiddenNote)
 	}
+	return strings.Join(lines, "\n")
 }
@@ -1,9 +1,25 @@
 package ui
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/ngmaloney/marine-terminal/internal/database"
+	"github.com/ngmaloney/marine-terminal/internal/geocoding"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/noaa"
+	"github.com/ngmaloney/marine-terminal/internal/provision"
+	"github.com/ngmaloney/marine-terminal/internal/stations"
 	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
 )
 
@@ -230,7 +246,7 @@ func TestModel_View_States(t *testing.T) {
 				m.zones = []zonelookup.ZoneInfo{
 					{Code: "ANZ251", Name: "Cape Cod Bay", Distance: 5.2},
 				}
-				m.zoneList = createZoneList(m.zones, 80, 20)
+				m.zoneList = createZoneList(m.zones, zonelookup.ZoneTypeCoastal, 80, 20, m.styles)
 			}
 
 			view := m.View()
@@ -282,3 +298,883 @@ func TestActivePane_Constants(t *testing.T) {
 		t.Errorf("PaneTides = %d, want 1", PaneTides)
 	}
 }
+
+func TestFormatTideRange(t *testing.T) {
+	tides := &models.TideData{
+		Events: []models.TideEvent{
+			{Time: time.Date(2025, 11, 26, 2, 14, 0, 0, time.UTC), Type: models.TideLow, Height: 0.3},
+			{Time: time.Date(2025, 11, 27, 8, 40, 0, 0, time.UTC), Type: models.TideHigh, Height: 5.6},
+		},
+	}
+
+	want := "Range: 0.3 ft (Wed 2:14 AM) to 5.6 ft (Thu 8:40 AM)"
+	if got := formatTideRange(newStyles(DefaultTheme()), tides); got != want {
+		t.Errorf("formatTideRange() = %q, want %q", got, want)
+	}
+
+	if got := formatTideRange(newStyles(DefaultTheme()), &models.TideData{}); got != "" {
+		t.Errorf("formatTideRange() on empty data = %q, want empty", got)
+	}
+}
+
+func TestFormatWindSummary(t *testing.T) {
+	start := time.Date(2025, 11, 27, 12, 0, 0, 0, time.UTC)
+	history := []models.WindObservation{
+		{Time: start, Direction: "W", Speed: 12.2},
+		{Time: start.Add(time.Hour), Direction: "WNW", Speed: 17.8},
+		{Time: start.Add(2 * time.Hour), Direction: "SW", Speed: 14},
+	}
+
+	if got, want := formatWindSummary(history), "Wind last 3h: W 12-18 kt"; got != want {
+		t.Errorf("formatWindSummary() = %q, want %q", got, want)
+	}
+	if got := formatWindSummary(nil); got != "" {
+		t.Errorf("formatWindSummary() without wind = %q, want empty", got)
+	}
+}
+
+func TestModel_ProvisionProgress(t *testing.T) {
+	m := NewModel("", "", "")
+	m.state = StateProvisioning
+	m.width = 80
+	m.height = 24
+
+	updatedModel, _ := m.Update(provisionStatusMsg(provision.Step("Processed 50 of 100 zones...", 50, 100)))
+	m = updatedModel.(Model)
+
+	if m.provisionFraction != 0.5 {
+		t.Errorf("provisionFraction = %v, want 0.5", m.provisionFraction)
+	}
+	if m.provisionStatus != "Processed 50 of 100 zones..." {
+		t.Errorf("provisionStatus = %q, want label from progress update", m.provisionStatus)
+	}
+	if view := m.viewProvisioning(); !strings.Contains(view, "50%") {
+		t.Errorf("viewProvisioning() should show completion percentage, got %q", view)
+	}
+
+	updatedModel, _ = m.Update(provisionStatusMsg(provision.Status("Extracting shapefile...")))
+	m = updatedModel.(Model)
+
+	if m.provisionFraction >= 0 {
+		t.Errorf("provisionFraction = %v, want negative for status-only update", m.provisionFraction)
+	}
+}
+
+func TestModel_ProvisionRetry(t *testing.T) {
+	dir := t.TempDir()
+	database.SetDBPath(filepath.Join(dir, "marine-terminal.db"))
+	t.Cleanup(func() { database.SetDBPath("") })
+	zipPath := filepath.Join(dir, "mz18mr25.zip")
+
+	// The job records whether the earlier run's download was still there when it ran again
+	var zipSeen bool
+	job := func(ctx context.Context, progressChan chan<- provision.Progress) error {
+		_, err := os.Stat(zipPath)
+		zipSeen = err == nil
+		return nil
+	}
+
+	// retry fails a run of job with err, presses r and runs the retried job to completion
+	retry := func(t *testing.T, err error, wantHelp string) {
+		t.Helper()
+		if err := os.WriteFile(zipPath, []byte("corrupt"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		m := NewModel("", "", "")
+		m.width, m.height = 100, 30
+		updated, _ := m.Update(provisioningStartedMsg{job: job})
+		updated, _ = updated.(Model).Update(provisionResultMsg{err: err})
+		m = updated.(Model)
+		if m.state != StateError || !m.provisionRetry {
+			t.Fatalf("failed provisioning should offer a retry, state = %v retry = %v", m.state, m.provisionRetry)
+		}
+		if !strings.Contains(m.View(), wantHelp) {
+			t.Errorf("error view should offer %q, got:\n%s", wantHelp, m.View())
+		}
+
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+		if updated.(Model).state != StateProvisioning || cmd == nil {
+			t.Fatalf("r should restart provisioning, state = %v", updated.(Model).state)
+		}
+		var started *provisioningStartedMsg
+		for _, c := range cmd().(tea.BatchMsg) {
+			if msg, ok := c().(provisioningStartedMsg); ok {
+				started = &msg
+			}
+		}
+		if started == nil {
+			t.Fatal("retry should start a provisioning job")
+		}
+		go func() {
+			for range started.progressChan {
+			}
+		}()
+		if err := <-started.resultChan; err != nil {
+			t.Fatalf("retried job error: %v", err)
+		}
+		if started.job == nil {
+			t.Error("retried run should keep its job for another retry")
+		}
+	}
+
+	t.Run("build failure removes the download first", func(t *testing.T) {
+		retry(t, fmt.Errorf("building database: %w", errors.New("no marine zones read")), "r: Delete the download and retry")
+		if zipSeen {
+			t.Error("the possibly corrupt download should be removed before retrying")
+		}
+	})
+
+	t.Run("download failure retries as is", func(t *testing.T) {
+		retry(t, fmt.Errorf("%w: %w", zonelookup.ErrShapefileDownload, errors.New("timeout")), "r: Retry")
+		if !zipSeen {
+			t.Error("a download failure shouldn't remove other files before retrying")
+		}
+	})
+
+	t.Run("other keys dismiss the retry", func(t *testing.T) {
+		m := NewModel("", "", "")
+		updated, _ := m.Update(provisioningStartedMsg{job: job})
+		updated, _ = updated.(Model).Update(provisionResultMsg{err: errors.New("boom")})
+		updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeyEsc})
+		if m := updated.(Model); m.state != StateSearch || m.provisionRetry {
+			t.Errorf("esc should go back to search, state = %v retry = %v", m.state, m.provisionRetry)
+		}
+	})
+}
+
+func TestModel_ProvisionErrorHint(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&zonelookup.ProvisionError{Stage: zonelookup.StageDownload, Err: errors.New("bad status: 503 Service Unavailable")}, "Network problem"},
+		{&zonelookup.ProvisionError{Stage: zonelookup.StageExtract, Err: errors.New("zip: not a valid zip file")}, "Data problem"},
+		{&zonelookup.ProvisionError{Stage: zonelookup.StageSetup, Err: errors.New("mkdir data: permission denied")}, "Disk problem"},
+	}
+	for _, tt := range tests {
+		m := NewModel("", "", "")
+		m.width, m.height = 100, 40
+		updated, _ := m.Update(provisionResultMsg{err: tt.err})
+		if view := updated.(Model).View(); !strings.Contains(view, tt.want) {
+			t.Errorf("error view for %v should explain it as a %q, got:\n%s", tt.err, tt.want, view)
+		}
+	}
+
+	// Other errors aren't explained
+	if hint := provisionErrorHint(errors.New("boom")); hint != "" {
+		t.Errorf("provisionErrorHint() for a non-provisioning error = %q, want empty", hint)
+	}
+}
+
+func TestStartProvisioning_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	job := func(ctx context.Context, progressChan chan<- provision.Progress) error {
+		// Nobody reads this update once the program has quit
+		provision.Send(progressChan, provision.Status("Downloading..."))
+		<-ctx.Done()
+		provision.Send(progressChan, provision.Status("Removing the partial download..."))
+		stopped <- ctx.Err()
+		return ctx.Err()
+	}
+
+	started := startProvisioning(ctx, job)().(provisioningStartedMsg)
+	if msg := <-started.progressChan; msg.Label != "Downloading..." {
+		t.Fatalf("first update = %q, want Downloading...", msg.Label)
+	}
+
+	// Quit: cancel without reading anything more
+	cancel()
+	if !WaitForProvisioning(5 * time.Second) {
+		t.Fatal("a cancelled provisioning job should finish instead of blocking on its updates")
+	}
+	if err := <-stopped; !errors.Is(err, context.Canceled) {
+		t.Errorf("job saw %v, want context.Canceled", err)
+	}
+	if err := <-started.resultChan; !errors.Is(err, context.Canceled) {
+		t.Errorf("result = %v, want context.Canceled", err)
+	}
+}
+
+func TestValidateTideWindowDays(t *testing.T) {
+	for _, days := range []int{1, DefaultTideWindowDays, MaxTideWindowDays} {
+		if err := ValidateTideWindowDays(days); err != nil {
+			t.Errorf("ValidateTideWindowDays(%d) error: %v", days, err)
+		}
+	}
+	for _, days := range []int{0, -3, MaxTideWindowDays + 1} {
+		if err := ValidateTideWindowDays(days); err == nil {
+			t.Errorf("ValidateTideWindowDays(%d) should fail", days)
+		}
+	}
+}
+
+func TestModel_RawForecastToggle(t *testing.T) {
+	m := NewModel("", "", "")
+	m.state = StateDisplay
+	m.width = 100
+	m.height = 40
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+	m.forecast = &models.ThreeDayForecast{
+		Periods: []models.MarineForecast{
+			{PeriodName: "TODAY", RawText: "SW winds 10 to 15 kt. Seas 2 to 3 ft. Patchy fog."},
+		},
+	}
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+	m = updatedModel.(Model)
+
+	if !m.showRawForecast {
+		t.Fatal("Expected 'v' to open the raw forecast view")
+	}
+	view := m.View()
+	if !strings.Contains(view, "RAW NOAA FORECAST TEXT") || !strings.Contains(view, "Patchy fog") {
+		t.Error("Raw forecast view should be labeled and show the unparsed text")
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updatedModel.(Model)
+
+	if m.showRawForecast {
+		t.Error("Expected Esc to return to the formatted view")
+	}
+	if m.state != StateDisplay {
+		t.Errorf("state = %v, want StateDisplay", m.state)
+	}
+}
+
+func TestFormatWeather_SmallCraftHighlight(t *testing.T) {
+	current := &models.MarineConditions{
+		Wind: models.WindData{Direction: "SW", SpeedMin: 10, SpeedMax: 15},
+		Seas: models.SeaState{HeightMin: 2, HeightMax: 3},
+	}
+	forecast := &models.ThreeDayForecast{
+		Periods: []models.MarineForecast{
+			{PeriodName: "TODAY", Wind: current.Wind, Seas: current.Seas},
+			{PeriodName: "TONIGHT", Wind: models.WindData{Direction: "SW", SpeedMin: 15, SpeedMax: 20}, Seas: models.SeaState{HeightMin: 3, HeightMax: 4}},
+			{PeriodName: "SAT", Wind: models.WindData{Direction: "W", SpeedMin: 20, SpeedMax: 25}, Seas: models.SeaState{HeightMin: 4, HeightMax: 6}},
+		},
+	}
+
+	lines := strings.Split(formatWeather(newStyles(DefaultTheme()), current, forecast, models.DefaultSmallCraftThresholds, DefaultForecastPeriodLimit, false, conditionChanges{}), "\n")
+	for _, line := range lines {
+		flagged := strings.Contains(line, smallCraftNote)
+		switch {
+		case strings.Contains(line, "SAT:") && !flagged:
+			t.Errorf("SAT exceeds thresholds but isn't highlighted: %q", line)
+		case (strings.Contains(line, "TODAY") || strings.Contains(line, "TONIGHT:")) && flagged:
+			t.Errorf("period below thresholds is highlighted: %q", line)
+		}
+	}
+
+	// Lower thresholds flag the current period too
+	out := formatWeather(newStyles(DefaultTheme()), current, forecast, models.SmallCraftThresholds{WindKnots: 15, SeasFeet: 5}, DefaultForecastPeriodLimit, false, conditionChanges{})
+	if !strings.Contains(strings.Split(out, "\n")[0], smallCraftNote) {
+		t.Error("Expected current period to be highlighted with a 15 kt threshold")
+	}
+}
+
+func TestFormatRating(t *testing.T) {
+	st := newStyles(DefaultTheme())
+	conditions := func(wind, seas float64) *models.MarineConditions {
+		return &models.MarineConditions{Wind: models.WindData{Direction: "SW", SpeedMax: wind}, Seas: models.SeaState{HeightMax: seas}}
+	}
+
+	if got := formatRating(st, conditions(8, 2), models.DefaultRatingThresholds); !strings.Contains(got, "CALM") {
+		t.Errorf("formatRating() = %q, want CALM", got)
+	}
+	if got := formatRating(st, conditions(35, 6), models.DefaultRatingThresholds); !strings.Contains(got, "DANGEROUS") {
+		t.Errorf("formatRating() = %q, want DANGEROUS", got)
+	}
+	if got := formatRating(st, conditions(0, 0), models.DefaultRatingThresholds); got != "" {
+		t.Errorf("formatRating() with no wind or seas = %q, want nothing", got)
+	}
+
+	// The weather pane rates conditions at the small craft thresholds as rough
+	m := NewModel("", "", "").WithSmallCraftThresholds(models.SmallCraftThresholds{WindKnots: 15, SeasFeet: 5})
+	m.weather = conditions(16, 2)
+	if !strings.Contains(m.renderWeatherSimple(), "Conditions: ROUGH") {
+		t.Errorf("weather pane should rate 16 kt rough with a 15 kt threshold, got:\n%s", m.renderWeatherSimple())
+	}
+}
+
+func TestFormatWeather_PeriodLimit(t *testing.T) {
+	current := &models.MarineConditions{Wind: models.WindData{Direction: "SW", SpeedMin: 10, SpeedMax: 15}}
+	forecast := &models.ThreeDayForecast{}
+	for i := 0; i < 10; i++ {
+		forecast.Periods = append(forecast.Periods, models.MarineForecast{PeriodName: fmt.Sprintf("P%d", i), Wind: current.Wind})
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{"default", DefaultForecastPeriodLimit, 6},
+		{"just the next period", 1, 1},
+		{"all periods", 0, 9},
+		{"clamped to available", 20, 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := formatWeather(newStyles(DefaultTheme()), current, forecast, models.DefaultSmallCraftThresholds, tt.limit, false, conditionChanges{})
+			got := 0
+			for i := 1; i < len(forecast.Periods); i++ {
+				if strings.Contains(out, fmt.Sprintf("P%d:", i)) {
+					got++
+				}
+			}
+			if got != tt.want {
+				t.Errorf("listed %d periods, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// The model passes its configured limit to the weather pane
+	m := NewModel("", "", "").WithForecastPeriodLimit(2)
+	m.weather = current
+	m.forecast = forecast
+	out := m.renderWeatherSimple()
+	if !strings.Contains(out, "P2:") || strings.Contains(out, "P3:") {
+		t.Errorf("weather pane with limit 2 = %q", out)
+	}
+}
+
+func TestFormatWaveComponents(t *testing.T) {
+	lines := formatWaveComponents(newStyles(DefaultTheme()), []models.WaveComponent{
+		{Kind: models.WaveKindWindWave, Height: 2},
+		{Kind: models.WaveKindSwell, Direction: "W", Height: 6, Period: 10},
+		{Kind: models.WaveKindSwell, Direction: "S", Height: 3, Period: 12},
+	})
+
+	if len(lines) != 2 {
+		t.Fatalf("len(lines) = %d, want 2: %q", len(lines), lines)
+	}
+	if !strings.Contains(lines[0], "Swell: W 6 ft at 10 sec, S 3 ft at 12 sec") {
+		t.Errorf("swell line = %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "Wind waves: 2 ft") {
+		t.Errorf("wind wave line = %q", lines[1])
+	}
+
+	if lines := formatWaveComponents(newStyles(DefaultTheme()), nil); len(lines) != 0 {
+		t.Errorf("expected no lines without components, got %q", lines)
+	}
+}
+
+func TestFormatWeather_DominantWave(t *testing.T) {
+	st := newStyles(DefaultTheme())
+	seas := models.SeaState{HeightMin: 4, HeightMax: 6, Components: []models.WaveComponent{
+		{Kind: models.WaveKindWindWave, Height: 2},
+		{Kind: models.WaveKindSwell, Direction: "W", Height: 6, Period: 10},
+	}}
+	forecast := &models.ThreeDayForecast{Periods: []models.MarineForecast{{PeriodName: "TODAY", Seas: seas}}}
+	out := formatWeather(st, &models.MarineConditions{Seas: seas}, forecast, models.DefaultSmallCraftThresholds, 0, false, conditionChanges{})
+	if !strings.Contains(out, "Dominant: W 6 ft @ 10s") {
+		t.Errorf("weather should call out the dominant wave, got:\n%s", out)
+	}
+
+	seas.Components = seas.Components[1:]
+	out = formatWeather(st, &models.MarineConditions{Seas: seas}, forecast, models.DefaultSmallCraftThresholds, 0, false, conditionChanges{})
+	if strings.Contains(out, "Dominant:") {
+		t.Errorf("a single component shouldn't be called out again, got:\n%s", out)
+	}
+}
+
+func TestFormatWeather_Squalls(t *testing.T) {
+	st := newStyles(DefaultTheme())
+	squally := models.WindData{Direction: "S", SpeedMin: 10, SpeedMax: 15, GustSpeed: 35, HasGust: true, Squalls: true}
+	calm := models.WindData{Direction: "W", SpeedMin: 5, SpeedMax: 10}
+	forecast := &models.ThreeDayForecast{Periods: []models.MarineForecast{
+		{PeriodName: "TODAY", Wind: calm},
+		{PeriodName: "TONIGHT", Wind: squally},
+	}}
+
+	out := formatWeather(st, &models.MarineConditions{Wind: calm}, forecast, models.DefaultSmallCraftThresholds, 0, false, conditionChanges{})
+	if strings.Count(out, "squalls possible") != 1 {
+		t.Fatalf("Expected only the squally period to note squalls, got:\n%s", out)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, "squalls possible") && !strings.Contains(line, "TONIGHT:") {
+			t.Errorf("squalls noted on the wrong line: %q", line)
+		}
+	}
+
+	// Current conditions note squalls on the wind line
+	current := &models.ThreeDayForecast{Periods: forecast.Periods[1:]}
+	out = formatWeather(st, &models.MarineConditions{Wind: squally}, current, models.DefaultSmallCraftThresholds, 0, false, conditionChanges{})
+	if !strings.Contains(out, "Wind: S 10-15 kt, gusts 35 kt  squalls possible") {
+		t.Errorf("Expected squalls noted after the current wind, got:\n%s", out)
+	}
+}
+
+func TestFormatWeather_NightPeriods(t *testing.T) {
+	orig := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	t.Cleanup(func() { lipgloss.SetColorProfile(orig) })
+
+	st := newStyles(DefaultTheme())
+	wind := models.WindData{Direction: "W", SpeedMin: 5, SpeedMax: 10}
+	forecast := &models.ThreeDayForecast{Periods: []models.MarineForecast{
+		{PeriodName: "TODAY", Wind: wind},
+		{PeriodName: "TONIGHT", Wind: wind},
+		{PeriodName: "FRI", Wind: wind},
+		{PeriodName: "FRI NIGHT", Wind: wind},
+	}}
+
+	out := formatWeather(st, &models.MarineConditions{Wind: wind}, forecast, models.DefaultSmallCraftThresholds, 0, false, conditionChanges{})
+	summary := "W 5-10 kt →, Seas 0 ft"
+	for _, want := range []string{
+		st.muted.Render("TONIGHT:") + " " + st.night.Render(summary),
+		st.value.Render("FRI:") + " " + st.muted.Render(summary),
+		st.muted.Render("FRI NIGHT:") + " " + st.night.Render(summary),
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("formatWeather() missing %q, got:\n%q", want, out)
+		}
+	}
+}
+
+func TestModel_HelpOverlay(t *testing.T) {
+	m := NewModel("", "", "")
+	m.state = StateDisplay
+	m.width = 100
+	m.height = 60
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	m = updatedModel.(Model)
+	if !m.showHelp {
+		t.Fatal("Expected '?' to open the help overlay")
+	}
+	if !strings.Contains(m.View(), "Keyboard Shortcuts") {
+		t.Error("View should render the help overlay")
+	}
+
+	// Other keys are swallowed while help is open
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	m = updatedModel.(Model)
+	if m.state != StateDisplay || !m.showHelp {
+		t.Error("Keys other than ?/Esc should not act while help is open")
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updatedModel.(Model)
+	if m.showHelp {
+		t.Error("Expected Esc to close the help overlay")
+	}
+
+	// In text inputs '?' is just a character
+	m.state = StateSearch
+	m.searchInput.Focus()
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	m = updatedModel.(Model)
+	if m.showHelp {
+		t.Error("'?' should not open help while typing a search")
+	}
+	if m.searchInput.Value() != "?" {
+		t.Errorf("searchInput.Value() = %q, want %q", m.searchInput.Value(), "?")
+	}
+}
+
+func TestKeyMap_HelpSectionsDocumented(t *testing.T) {
+	for _, section := range defaultKeyMap().helpSections() {
+		for _, b := range section.bindings {
+			if b.Help().Key == "" || b.Help().Desc == "" {
+				t.Errorf("section %q has a binding without help text: %v", section.title, b.Keys())
+			}
+		}
+	}
+}
+
+func TestModel_NewerEditionOffer(t *testing.T) {
+	m := NewModel("", "", "")
+	m.state = StateDisplay
+	m.width = 100
+	m.height = 40
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+
+	// Without a newer edition, 'u' does nothing
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	m = updatedModel.(Model)
+	if cmd != nil || m.state != StateDisplay {
+		t.Fatal("'u' should be ignored when no newer edition is available")
+	}
+
+	updatedModel, _ = m.Update(newerEditionMsg{edition: "mz05mr26"})
+	m = updatedModel.(Model)
+	if !strings.Contains(m.View(), "mz05mr26") {
+		t.Error("View should offer the newer edition")
+	}
+
+	updatedModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	m = updatedModel.(Model)
+	if m.state != StateProvisioning {
+		t.Errorf("state = %v, want StateProvisioning", m.state)
+	}
+	if cmd == nil {
+		t.Error("Expected command to start the update")
+	}
+	if m.newerEdition != "" {
+		t.Error("Offer should be cleared once the update starts")
+	}
+}
+
+func TestRawForecastText(t *testing.T) {
+	if got := rawForecastText(nil); got != "No raw forecast text available." {
+		t.Errorf("rawForecastText(nil) = %q", got)
+	}
+
+	forecast := &models.ThreeDayForecast{Periods: []models.MarineForecast{{PeriodName: "TONIGHT", RawText: "N winds 5 kt."}}}
+	if got := rawForecastText(forecast); !strings.Contains(got, "TONIGHT") || !strings.Contains(got, "N winds 5 kt.") {
+		t.Errorf("rawForecastText() = %q, want period name and raw text", got)
+	}
+}
+
+func TestModel_AlertsUseClock(t *testing.T) {
+	expires := time.Date(2025, 11, 27, 18, 0, 0, 0, time.UTC)
+	alerts := &models.AlertData{Alerts: []models.Alert{{
+		Event:    "Gale Warning",
+		Headline: "Gale Warning until 6 PM",
+		Severity: models.SeveritySevere,
+		Onset:    expires.Add(-12 * time.Hour),
+		Expires:  expires,
+	}}}
+
+	m := NewModel("", "", "").WithClock(models.FixedClock(expires.Add(-time.Minute)))
+	m.alerts = alerts
+	if !strings.Contains(m.renderAlertSimple(), "Gale Warning") {
+		t.Error("alert should be shown a minute before it expires")
+	}
+
+	m = m.WithClock(models.FixedClock(expires))
+	if strings.Contains(m.renderAlertSimple(), "Gale Warning") {
+		t.Error("alert should be hidden once it expires")
+	}
+}
+
+func TestModel_ZoneProximityHeader(t *testing.T) {
+	m := NewModel("", "", "")
+	m.state = StateDisplay
+	m.width = 100
+	m.height = 40
+	m.searchQuery = "Chatham, MA"
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound", Distance: 7.4}
+	m.location = &geocoding.Location{Latitude: 41.68, Longitude: -69.95}
+
+	// Until the polygon is measured, the center distance is shown
+	if !strings.Contains(m.View(), "7.4 mi away") {
+		t.Error("header should fall back to the center distance")
+	}
+
+	updatedModel, _ := m.Update(zoneBoundaryMsg{zoneCode: "ANZ254", distance: &zonelookup.BoundaryDistance{Inside: true, Miles: 1.2}})
+	m = updatedModel.(Model)
+	if !strings.Contains(m.View(), "inside the zone") {
+		t.Error("header should say the location is inside the zone")
+	}
+
+	updatedModel, _ = m.Update(zoneBoundaryMsg{zoneCode: "ANZ254", distance: &zonelookup.BoundaryDistance{Miles: 2.3}})
+	m = updatedModel.(Model)
+	if !strings.Contains(m.View(), "2.3 mi from the zone boundary") {
+		t.Error("header should show the distance to the zone boundary")
+	}
+
+	// Measurements for a zone no longer on display are ignored
+	updatedModel, _ = m.Update(zoneBoundaryMsg{zoneCode: "ANZ250", distance: &zonelookup.BoundaryDistance{Inside: true}})
+	m = updatedModel.(Model)
+	if strings.Contains(m.View(), "inside the zone") {
+		t.Error("stale boundary measurement should be ignored")
+	}
+}
+
+func TestModel_CycleZones(t *testing.T) {
+	m := NewModel("", "", "")
+	m.state = StateDisplay
+	m.width = 120
+	m.height = 40
+	m.searchQuery = "Chatham, MA"
+	m.location = &geocoding.Location{Latitude: 41.68, Longitude: -69.95}
+	m.zones = []zonelookup.ZoneInfo{
+		{Code: "ANZ254", Name: "Nantucket Sound"},
+		{Code: "ANZ255", Name: "Vineyard Sound"},
+		{Code: "ANZ250", Name: "Cape Cod Bay"},
+	}
+	first := m.zones[0]
+	m.selectedZone = &first
+
+	if !strings.Contains(m.View(), "Zone 1 of 3") {
+		t.Error("header should show the zone's position among nearby zones")
+	}
+
+	tests := []struct {
+		key  tea.KeyMsg
+		want string
+	}{
+		{tea.KeyMsg{Type: tea.KeyRight}, "ANZ255"},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")}, "ANZ250"},
+		{tea.KeyMsg{Type: tea.KeyRight}, "ANZ254"}, // Wraps to the first zone
+		{tea.KeyMsg{Type: tea.KeyLeft}, "ANZ250"},  // Wraps to the last zone
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")}, "ANZ255"},
+	}
+	for _, tt := range tests {
+		updatedModel, cmd := m.Update(tt.key)
+		m = updatedModel.(Model)
+		if m.selectedZone.Code != tt.want {
+			t.Fatalf("after %s selected zone = %s, want %s", tt.key, m.selectedZone.Code, tt.want)
+		}
+		if cmd == nil || !m.loadingWeather || !m.loadingAlerts {
+			t.Errorf("after %s the new zone's forecast and alerts should be fetched", tt.key)
+		}
+	}
+	if !strings.Contains(m.View(), "Zone 2 of 3") {
+		t.Error("header should follow the selected zone")
+	}
+
+	// A port loaded without nearby zones has nothing to cycle through
+	m.zones = nil
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m = updatedModel.(Model)
+	if cmd != nil || m.selectedZone.Code != "ANZ255" {
+		t.Error("cycling should do nothing without nearby zones")
+	}
+	if strings.Contains(m.View(), "Zone 1 of") {
+		t.Error("zone position should be hidden without nearby zones")
+	}
+}
+
+// TestModel_CycleZoneWhileTidesLoad tests that cycling zones while the tide data is
+// still loading fetches it again under the new load rather than leaving it loading
+func TestModel_CycleZoneWhileTidesLoad(t *testing.T) {
+	orig := measureZoneBoundary
+	t.Cleanup(func() { measureZoneBoundary = orig })
+	measureZoneBoundary = func(string, *geocoding.Location) tea.Cmd {
+		return func() tea.Msg { return nil }
+	}
+
+	m := NewModel("", "", "")
+	m.weatherClient = &mockWeatherClient{conditions: &models.MarineConditions{}, forecast: &models.ThreeDayForecast{}}
+	m.alertClient = &mockAlertClient{alerts: &models.AlertData{}}
+	m.tideClient = &mockTideClient{tides: &models.TideData{Events: testTideEvents()}}
+	m.width, m.height = 100, 40
+	m.state = StateDisplay
+	m.location = &geocoding.Location{Latitude: 41.68, Longitude: -69.95}
+	m.zones = []zonelookup.ZoneInfo{{Code: "ANZ254", Name: "Nantucket Sound"}, {Code: "ANZ255", Name: "Vineyard Sound"}}
+	first := m.zones[0]
+	m.selectedZone = &first
+
+	// The station was found and its tide data is on the way when the zone is cycled
+	m, _ = m.beginZoneLoad()
+	updatedModel, _ := m.Update(zoneLoadMsg{load: m.zoneLoad, part: tideStationFoundMsg{stations: []stations.TideStationInfo{{ID: "8447435", Name: "Chatham"}}, ctx: context.Background(), load: m.zoneLoad}})
+	m = updatedModel.(Model)
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m = updatedModel.(Model)
+
+	// The first load's tide data is dropped, the refetch under the new one lands
+	updatedModel, _ = m.Update(zoneLoadMsg{load: m.zoneLoad - 1, part: tideDataFetchedMsg{tideErr: context.Canceled}})
+	m = updatedModel.(Model)
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg, ok := c().(zoneLoadMsg); ok {
+			updatedModel, _ = m.Update(msg)
+			m = updatedModel.(Model)
+		}
+	}
+	if m.loadingTides || m.tides == nil || m.tideErr != nil {
+		t.Errorf("loadingTides = %v tides = %v tideErr = %v after cycling, want the tide data loaded", m.loadingTides, m.tides, m.tideErr)
+	}
+	if m.tideStation == nil || m.tideStation.ID != "8447435" {
+		t.Errorf("tide station = %v, want it kept", m.tideStation)
+	}
+}
+
+func TestModel_AlertsForNearbyZones(t *testing.T) {
+	alerts := &mockAlertClient{alerts: &models.AlertData{}}
+	m := NewModel("", "", "")
+	m.alertClient = alerts
+	m.location = &geocoding.Location{Latitude: 41.68, Longitude: -69.95}
+	m.zones = []zonelookup.ZoneInfo{{Code: "ANZ254"}, {Code: "ANZ255"}, {Code: "ANZ250"}}
+	selected := m.zones[1]
+	m.selectedZone = &selected
+
+	// The zone on display comes first, then its neighbours
+	fetchZoneAlerts(context.Background(), m.alertClient, m.alertZoneCodes(selected.Code), m.location)()
+	if want := []string{"ANZ255", "ANZ254", "ANZ250"}; !slices.Equal(alerts.gotZones, want) {
+		t.Errorf("alerts queried for %v, want %v", alerts.gotZones, want)
+	}
+
+	// A zone that isn't among the nearby ones is queried alone
+	if got := m.alertZoneCodes("ANZ232"); !slices.Equal(got, []string{"ANZ232"}) {
+		t.Errorf("alertZoneCodes(ANZ232) = %v, want only ANZ232", got)
+	}
+}
+
+func TestModel_BeaufortToggle(t *testing.T) {
+	m := NewModel("", "", "")
+	m.width, m.height = 120, 40
+	m.state = StateDisplay
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+	wind := models.WindData{Direction: "SW", SpeedMin: 15, SpeedMax: 20}
+	m.weather = &models.MarineConditions{Wind: wind}
+	m.forecast = &models.ThreeDayForecast{Periods: []models.MarineForecast{{PeriodName: "TODAY", Wind: wind}}}
+
+	if strings.Contains(m.View(), "Force 5") {
+		t.Error("Beaufort force should be hidden by default")
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	m = updated.(Model)
+	if !strings.Contains(m.View(), "Force 5 (Fresh Breeze)") {
+		t.Error("'b' should show the Beaufort force next to the wind")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	if strings.Contains(updated.(Model).View(), "Force 5") {
+		t.Error("'b' again should hide the Beaufort force")
+	}
+}
+
+func TestModel_NarrowTerminal(t *testing.T) {
+	now := time.Now()
+	m := NewModel("", "", "")
+	m.width, m.height = 60, 50
+	m.state = StateDisplay
+	m.searchQuery = "Chatham, MA"
+	m.location = &geocoding.Location{Latitude: 41.68, Longitude: -69.95}
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound and Vineyard Sound from Woods Hole to Monomoy"}
+	m.alerts = &models.AlertData{Alerts: []models.Alert{{
+		ID:       "scA",
+		Event:    "Small Craft Advisory",
+		Severity: models.SeverityModerate,
+		Headline: "SMALL CRAFT ADVISORY REMAINS IN EFFECT FROM 6 AM EST THIS MORNING THROUGH THIS EVENING by NWS Boston/Norton MA",
+		Onset:    now.Add(-time.Hour),
+		Expires:  now.Add(time.Hour),
+	}}}
+	m.tideStation = &stations.TideStationInfo{ID: "8447435", Name: "Chatham, Lydia Cove"}
+	m.tides = &models.TideData{Events: []models.TideEvent{
+		{Time: now.Add(time.Hour), Type: models.TideHigh, Height: 4.2},
+		{Time: now.Add(7 * time.Hour), Type: models.TideLow, Height: 0.2},
+	}}
+	m = m.rebuildTideChart()
+
+	boxOuterWidth := boxWidth(m.width) + m.styles.sectionBox.GetHorizontalBorderSize()
+	for _, pane := range []ActivePane{PaneWeather, PaneTides} {
+		m.activePane = pane
+		view := m.View()
+		for _, line := range strings.Split(view, "\n") {
+			if w := lipgloss.Width(line); w > boxOuterWidth {
+				t.Errorf("pane %d: line is %d wide, wider than the %d-wide box: %q", pane, w, boxOuterWidth, line)
+			}
+		}
+		if pane == PaneWeather && !strings.Contains(view, "SMALL CRAFT ADVISORY REMAINS IN EFFECT FROM 6 AM ES…") {
+			t.Errorf("long headline should be cut short with an ellipsis:\n%s", view)
+		}
+	}
+}
+
+func TestFormatAlerts_SeverityOrderAndFilter(t *testing.T) {
+	now := time.Date(2025, 11, 27, 12, 0, 0, 0, time.UTC)
+	alert := func(event string, severity models.AlertSeverity) models.Alert {
+		return models.Alert{Event: event, Headline: event + " in effect", Severity: severity, Onset: now.Add(-time.Hour), Expires: now.Add(time.Hour)}
+	}
+	alerts := &models.AlertData{Alerts: []models.Alert{
+		alert("Marine Weather Statement", models.SeverityMinor),
+		alert("Small Craft Advisory", models.SeverityMinor),
+		alert("Storm Warning", models.SeverityExtreme),
+		alert("Gale Warning", models.SeveritySevere),
+	}}
+	clock := models.FixedClock(now)
+
+	// Most severe first; equal severities keep their arrival order
+	out := formatAlerts(newStyles(DefaultTheme()), alerts, clock, false, false, "", nil, 0)
+	order := []string{"Storm Warning", "Gale Warning", "Marine Weather Statement", "Small Craft Advisory"}
+	last := -1
+	for _, event := range order {
+		i := strings.Index(out, event)
+		if i <= last {
+			t.Fatalf("alerts out of severity order, want %v:\n%s", order, out)
+		}
+		last = i
+	}
+
+	filtered := formatAlerts(newStyles(DefaultTheme()), alerts, clock, true, false, "", nil, 0)
+	if strings.Contains(filtered, "Marine Weather Statement") {
+		t.Error("statement should be hidden by the filter")
+	}
+	for _, event := range order[:2] {
+		if !strings.Contains(filtered, event) {
+			t.Errorf("filter should keep %s", event)
+		}
+	}
+	if !strings.Contains(filtered, "Small Craft Advisory") || !strings.Contains(filtered, "1 statement(s) hidden") {
+		t.Errorf("filter should keep advisories and count hidden statements:\n%s", filtered)
+	}
+
+	onlyStatements := &models.AlertData{Alerts: []models.Alert{alert("Marine Weather Statement", models.SeverityMinor)}}
+	if out := formatAlerts(newStyles(DefaultTheme()), onlyStatements, clock, true, false, "", nil, 0); !strings.Contains(out, "No marine warnings or advisories") {
+		t.Errorf("fully filtered alerts should say so:\n%s", out)
+	}
+}
+
+func TestModel_AlertFilterKey(t *testing.T) {
+	m := NewModel("", "", "")
+	m.state = StateDisplay
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m = updatedModel.(Model)
+	if !m.hideStatements {
+		t.Fatal("'a' should hide statements")
+	}
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if updatedModel.(Model).hideStatements {
+		t.Error("'a' again should show statements")
+	}
+}
+
+func TestNextCompareDatum(t *testing.T) {
+	tests := []struct {
+		current, base, want string
+	}{
+		{"", models.DatumMLLW, models.DatumMSL},
+		{models.DatumMSL, models.DatumMLLW, models.DatumMHHW},
+		{models.DatumMHHW, models.DatumMLLW, models.DatumNAVD88},
+		{models.DatumNAVD88, models.DatumMLLW, ""},
+		{"", models.DatumMSL, models.DatumMLLW},
+		{models.DatumMLLW, models.DatumMSL, models.DatumMHHW},
+	}
+
+	for _, tt := range tests {
+		if got := nextCompareDatum(tt.current, tt.base); got != tt.want {
+			t.Errorf("nextCompareDatum(%q, %q) = %q, want %q", tt.current, tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestModel_PressureUnit(t *testing.T) {
+	m := NewModel("", "", "")
+	m.width, m.height = 120, 40
+	m.state = StateDisplay
+	m.activePane = PaneTides
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+	m.tideStation = &stations.TideStationInfo{ID: "8447435", Name: "Chatham, Lydia Cove"}
+	m.tideConditions = &models.MarineConditions{Temperature: 48, Pressure: 1016}
+
+	if view := m.View(); !strings.Contains(view, "Pressure: 1016.0 mb") {
+		t.Errorf("Expected pressure in millibars by default, got:\n%s", view)
+	}
+	if view := m.WithPressureUnit(models.PressureInchesHg).View(); !strings.Contains(view, "Pressure: 30.00 inHg") {
+		t.Errorf("Expected pressure in inches of mercury, got:\n%s", view)
+	}
+}
+
+func TestModel_WithHTTPTimeoutBoundsZoneLoads(t *testing.T) {
+	m, ctx := NewModel("", "", "").beginZoneLoad()
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > zoneLoadTimeout(noaa.DefaultHTTPTimeout) {
+		t.Errorf("default zone load deadline in %v, want at most %v", time.Until(deadline), zoneLoadTimeout(noaa.DefaultHTTPTimeout))
+	}
+	m.cancelZoneLoad()
+
+	// A longer --http-timeout gives zone loads longer too, rather than being cut short
+	m, ctx = NewModel("", "", "").WithHTTPTimeout(2 * time.Minute).beginZoneLoad()
+	defer m.cancelZoneLoad()
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) < 2*time.Minute {
+		t.Errorf("zone load deadline in %v with a 2m HTTP timeout, want longer than the timeout", time.Until(deadline))
+	}
+}
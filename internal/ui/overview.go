@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ngmaloney/marine-terminal/internal/database"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/noaa"
+	"github.com/ngmaloney/marine-terminal/internal/stations"
+)
+
+// overviewConcurrency bounds how many ports are summarized at once so a long
+// list of saved ports doesn't hammer the NOAA APIs
+const overviewConcurrency = 3
+
+// portSummary is the compact per-port data shown on an overview card
+type portSummary struct {
+	port       models.Port
+	loading    bool
+	conditions *models.MarineConditions
+	alert      *models.Alert     // Most severe active marine alert, if any
+	nextTide   *models.TideEvent // Next high or low tide, if known
+	err        error             // Set when none of the summary could be fetched
+}
+
+// portSummaryFetchedMsg is sent when one overview card has finished loading
+type portSummaryFetchedMsg struct {
+	generation int // Ignored unless it matches the overview currently open
+	index      int
+	summary    portSummary
+}
+
+// startOverview opens the overview of all saved ports and starts fetching their summaries
+func (m Model) startOverview() (tea.Model, tea.Cmd) {
+	m.overviewGeneration++
+	m.overview = make([]portSummary, len(m.savedPorts))
+	m.overviewCursor = 0
+	m.state = StateOverview
+
+	sem := make(chan struct{}, overviewConcurrency)
+	cmds := []tea.Cmd{m.spinner.Tick}
+	for i, p := range m.savedPorts {
+		m.overview[i] = portSummary{port: p, loading: true}
+		cmds = append(cmds, fetchPortSummary(m.weatherClient, m.alertClient, m.tideClient, m.clock, sem, m.overviewGeneration, i, p))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// fetchPortSummary fetches the overview summary for one port. sem is shared by all
+// cards of an overview and limits how many fetch at the same time.
+func fetchPortSummary(weather noaa.WeatherClient, alerts noaa.AlertClient, tides noaa.TideClient, clock models.Clock, sem chan struct{}, generation, index int, p models.Port) tea.Cmd {
+	return func() tea.Msg {
+		sem <- struct{}{}
+		defer func() { <-sem }()
+
+		// Each request is bounded by the clients' own timeout
+		ctx := context.Background()
+		summary := portSummary{port: p}
+		var errs []string
+
+		conditions, _, err := weather.GetMarineForecastByZone(ctx, p.MarineZoneID)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("forecast: %v", err))
+		} else {
+			summary.conditions = conditions
+		}
+
+		alertData, err := alerts.GetActiveAlertsByZone(ctx, p.MarineZoneID)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("alerts: %v", err))
+		} else if alert, ok := alertData.MostSevereActiveAt(clock); ok {
+			summary.alert = alert
+		}
+
+		next, err := nextTideForPort(ctx, tides, p, clock.Now())
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("tides: %v", err))
+		} else {
+			summary.nextTide = next
+		}
+
+		if len(errs) == 3 {
+			summary.err = fmt.Errorf("%s", strings.Join(errs, "; "))
+		}
+		return portSummaryFetchedMsg{generation: generation, index: index, summary: summary}
+	}
+}
+
+// nextTideForPort returns the next tide after now at the port's tide station, looking
+// up the nearest station for ports saved without one
+func nextTideForPort(ctx context.Context, client noaa.TideClient, p models.Port, now time.Time) (*models.TideEvent, error) {
+	stationID := p.TideStationID
+	if stationID == "" {
+		found, err := stations.FindNearbyStations(database.DBPath(), p.Latitude, p.Longitude, 30.0)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) == 0 {
+			return nil, fmt.Errorf("no tide station near %s", p.Name)
+		}
+		stationID = found[0].ID
+	}
+
+	data, err := client.GetTidePredictions(ctx, stationID, p.TideDatum(), now, now.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	return data.NextEvent(now), nil
+}
+
+func (m Model) handleOverview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.overviewCursor > 0 {
+			m.overviewCursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.overviewCursor < len(m.overview)-1 {
+			m.overviewCursor++
+		}
+	case key.Matches(msg, m.keys.Select):
+		if m.overviewCursor < len(m.overview) {
+			return m.loadPort(m.overview[m.overviewCursor].port)
+		}
+	case key.Matches(msg, m.keys.Back):
+		m.state = StateSavedPorts
+	}
+	return m, nil
+}
+
+func (m Model) viewOverview() string {
+	content := []string{m.styles.title.Render(m.styles.text("⚓ Ports Overview"))}
+	if len(m.overview) == 0 {
+		content = append(content, "", m.styles.muted.Render("No saved ports."))
+	}
+	for i, s := range m.overview {
+		content = append(content, "", m.renderSummaryCard(s, i == m.overviewCursor))
+	}
+	content = append(content, "", m.styles.help.Render(m.styles.text("↑/↓: Move • Enter: Open port • Esc: Back")))
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+// renderSummaryCard renders one port's card; each card shows its own loading or error state
+func (m Model) renderSummaryCard(s portSummary, selected bool) string {
+	name := m.styles.value.Render(s.port.Name)
+	marker := "  "
+	if selected {
+		name = m.styles.title.Render(s.port.Name)
+		marker = m.styles.text("▶ ")
+	}
+	lines := []string{marker + name}
+
+	switch {
+	case s.loading:
+		lines = append(lines, "  "+fmt.Sprintf("%s Loading...", m.spinner.View()))
+	case s.err != nil:
+		lines = append(lines, "  "+m.styles.alertDanger.Render("Unavailable: ")+m.styles.muted.Render(s.err.Error()))
+	default:
+		lines = append(lines, "  "+m.summaryAlert(s.alert))
+		lines = append(lines, "  "+m.styles.label.Render("Next tide: ")+m.styles.value.Render(summaryTide(m.styles, s.nextTide)))
+		lines = append(lines, "  "+m.styles.label.Render("Wind/Seas: ")+m.styles.value.Render(m.styles.text(summaryConditions(s.conditions))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) summaryAlert(alert *models.Alert) string {
+	if alert == nil {
+		return m.styles.success.Render(m.styles.text("✓ No active marine alerts"))
+	}
+	return m.styles.alert(alert.Severity).Render(alert.Event)
+}
+
+func summaryTide(st styles, event *models.TideEvent) string {
+	if event == nil {
+		return "unavailable"
+	}
+	return fmt.Sprintf("%s %.1f ft at %s", event.Type, event.Height, st.formatTime(event.Time, "3:04 PM"))
+}
+
+func summaryConditions(c *models.MarineConditions) string {
+	if c == nil {
+		return "unavailable"
+	}
+	wind := "wind n/a"
+	if c.Wind.Direction != "" {
+		wind = formatWind(c.Wind)
+	}
+	seas := "seas n/a"
+	if c.Seas.HeightMin > 0 || c.Seas.HeightMax > 0 {
+		seas = "Seas " + formatSeas(c.Seas)
+	}
+	return wind + " • " + seas
+}
@@ -1,8 +1,6 @@
 package ui
 
 import (
-	"fmt"
-
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/ngmaloney/marine-terminal/internal/models"
 )
@@ -10,6 +8,7 @@ import (
 // portItem wraps a Port for use in a list
 type portItem struct {
 	port models.Port
+	st   styles
 }
 
 // FilterValue implements list.Item
@@ -19,32 +18,38 @@ func (p portItem) FilterValue() string {
 
 // Title implements list.DefaultItem
 func (p portItem) Title() string {
+	if p.port.Home {
+		return p.port.Name + " (home)"
+	}
 	return p.port.Name
 }
 
 // Description implements list.DefaultItem
 func (p portItem) Description() string {
 	desc := p.port.StationID
-	if p.port.City != "" || p.port.State != "" {
-		desc += fmt.Sprintf(" • %s, %s", p.port.City, p.port.State)
-	}
-	if p.port.Zipcode != "" {
-		desc += fmt.Sprintf(" %s", p.port.Zipcode)
+	if name := p.port.DisplayName(); name != p.port.Name {
+		if desc != "" {
+			desc += " • "
+		}
+		desc += name
 	}
-	return desc
+	return p.st.text(desc)
 }
 
 // createPortList creates a list.Model from ports
-func createPortList(ports []models.Port, width, height int) list.Model {
+func createPortList(ports []models.Port, width, height int, st styles) list.Model {
 	items := make([]list.Item, len(ports))
 	for i, port := range ports {
-		items[i] = portItem{port: port}
+		items[i] = portItem{port: port, st: st}
 	}
 
-	l := list.New(items, list.NewDefaultDelegate(), width, height)
+	l := st.newList(items, list.NewDefaultDelegate(), width, height)
 	l.Title = "Select a Saved Port"
 	l.SetShowHelp(true)
 	l.SetFilteringEnabled(true)
+	// '?' opens the application-wide help overlay instead of the list's full help
+	l.KeyMap.ShowFullHelp.SetEnabled(false)
+	l.KeyMap.CloseFullHelp.SetEnabled(false)
 
 	return l
 }
@@ -66,4 +66,65 @@ func deletePort(s *ports.Service, name string) tea.Cmd {
 		err := s.DeletePort(name)
 		return portDeletedMsg{name: name, err: err}
 	}
-}
\ No newline at end of file
+}
+
+// homePortSetMsg is sent when the port named name has been made the home port
+type homePortSetMsg struct {
+	name string
+	err  error
+}
+
+// setHomePort makes the saved port named name the home port
+func setHomePort(s *ports.Service, name string) tea.Cmd {
+	return func() tea.Msg {
+		err := s.SetHomePort(name)
+		return homePortSetMsg{name: name, err: err}
+	}
+}
+
+// homePortFetchedMsg carries the home port read from the database; port is nil if
+// no port is marked as home
+type homePortFetchedMsg struct {
+	port *models.Port
+	err  error
+}
+
+// fetchHomePort reads the home port, for when the saved ports haven't been fetched
+func fetchHomePort(s *ports.Service) tea.Cmd {
+	return func() tea.Msg {
+		port, err := s.HomePort()
+		return homePortFetchedMsg{port: port, err: err}
+	}
+}
+
+// portUpdate is a saved port awaiting confirmation of its re-resolved zone and
+// station. after is nil while the lookup is still running.
+type portUpdate struct {
+	before models.Port
+	after  *models.Port
+}
+
+type portRebuiltMsg struct {
+	name string
+	port *models.Port
+	err  error
+}
+
+type portUpdatedMsg struct {
+	port *models.Port
+	err  error
+}
+
+func rebuildPort(s *ports.Service, port models.Port) tea.Cmd {
+	return func() tea.Msg {
+		rebuilt, err := s.RebuildPort(context.Background(), port)
+		return portRebuiltMsg{name: port.Name, port: rebuilt, err: err}
+	}
+}
+
+func updatePort(s *ports.Service, port *models.Port) tea.Cmd {
+	return func() tea.Msg {
+		err := s.UpdatePort(port)
+		return portUpdatedMsg{port: port, err: err}
+	}
+}
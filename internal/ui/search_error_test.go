@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -147,4 +148,138 @@ func TestSearch_NoZonesFound(t *testing.T) {
 	if m.location.Name != "North Pole" {
 		t.Errorf("location.Name = %s, want 'North Pole'", m.location.Name)
 	}
-}
\ No newline at end of file
+}
+// TestSearch_EmptyZonesDatabase tests that an empty zones table is reported distinctly
+func TestSearch_EmptyZonesDatabase(t *testing.T) {
+	m := NewModel("", "", "")
+	m.state = StateLoading
+	m.searchQuery = "02633"
+
+	// A remote location with a populated database gets the usual message
+	updatedModel, _ := m.Update(zonesFoundMsg{})
+	m = updatedModel.(Model)
+	if m.reprovisionNeeded {
+		t.Error("reprovisionNeeded should be false when the database has zones")
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "no marine zones found") {
+		t.Errorf("err = %v, want 'no marine zones found' message", m.err)
+	}
+
+	// An empty database prompts re-provisioning instead
+	m.state = StateLoading
+	updatedModel, _ = m.Update(zonesFoundMsg{dbEmpty: true})
+	m = updatedModel.(Model)
+	if m.state != StateError {
+		t.Errorf("state = %v, want StateError", m.state)
+	}
+	if !m.reprovisionNeeded {
+		t.Error("reprovisionNeeded should be true when the zones table is empty")
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "database is empty") {
+		t.Errorf("err = %v, want empty database message", m.err)
+	}
+
+	// 'p' starts re-provisioning
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m = updatedModel.(Model)
+	if m.state != StateProvisioning {
+		t.Errorf("state = %v, want StateProvisioning", m.state)
+	}
+	if cmd == nil {
+		t.Error("Expected command to start re-provisioning")
+	}
+}
+
+// TestSearch_WidenZoneRadius tests retrying a search that found no zones at a wider radius
+func TestSearch_WidenZoneRadius(t *testing.T) {
+	var searched []float64
+	nearbyZoneSearch = func(lat, lon, radius float64) tea.Cmd {
+		searched = append(searched, radius)
+		return func() tea.Msg { return zonesFoundMsg{radius: radius} }
+	}
+	t.Cleanup(func() { nearbyZoneSearch = findNearbyZones })
+
+	m := NewModel("", "", "")
+	m.width, m.height = 100, 40
+	m.state = StateLoading
+	m.searchQuery = "Kansas City, MO"
+	m.location = &geocoding.Location{Latitude: 39.1, Longitude: -94.58, Name: "Kansas City, MO"}
+
+	updatedModel, _ := m.Update(zonesFoundMsg{radius: zoneSearchRadiusMiles})
+	m = updatedModel.(Model)
+	if m.state != StateError || !strings.Contains(m.err.Error(), "within 50 mi") {
+		t.Fatalf("state = %v err = %v, want the no-zones error", m.state, m.err)
+	}
+	if !strings.Contains(m.View(), "+: Search within 100 mi") {
+		t.Errorf("error view should offer a wider search, got:\n%s", m.View())
+	}
+
+	// '+' searches again around the stored location without geocoding
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}})
+	m = updatedModel.(Model)
+	if m.state != StateLoading || m.err != nil {
+		t.Fatalf("state = %v err = %v, want a new search", m.state, m.err)
+	}
+	if cmd == nil || len(searched) != 1 || searched[0] != 100 {
+		t.Fatalf("searched radii = %v, want a 100 mi zone search", searched)
+	}
+
+	// No wider search is offered past the cap
+	updatedModel, _ = m.Update(zonesFoundMsg{radius: maxZoneSearchRadiusMiles})
+	m = updatedModel.(Model)
+	if m.zoneSearchWiden || strings.Contains(m.View(), "+: Search") {
+		t.Error("search at the maximum radius shouldn't offer a wider one")
+	}
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}})
+	if updatedModel.(Model).state != StateSearch {
+		t.Errorf("'+' at the maximum radius should return to search, state = %v", updatedModel.(Model).state)
+	}
+}
+
+func TestSearch_AmbiguousCity(t *testing.T) {
+	var searchedLat []float64
+	nearbyZoneSearch = func(lat, lon, radius float64) tea.Cmd {
+		searchedLat = append(searchedLat, lat)
+		return func() tea.Msg { return zonesFoundMsg{radius: radius} }
+	}
+	t.Cleanup(func() { nearbyZoneSearch = findNearbyZones })
+
+	m := NewModel("", "", "")
+	m.width, m.height = 100, 40
+	m.state = StateLoading
+	m.searchQuery = "Chatham"
+
+	updatedModel, _ := m.Update(geocodeMsg{err: &geocoding.AmbiguousCityError{City: "Chatham", Candidates: []geocoding.Location{
+		{Latitude: 41.6885, Longitude: -69.9511, Name: "Chatham, MA 02633"},
+		{Latitude: 40.7409, Longitude: -74.3838, Name: "Chatham, NJ 07928"},
+	}}})
+	m = updatedModel.(Model)
+	if m.state != StateCityChoice {
+		t.Fatalf("state = %v, want StateCityChoice", m.state)
+	}
+	view := m.View()
+	for _, want := range []string{"Which Chatham?", "Chatham, MA 02633", "Chatham, NJ 07928", "40.7409, -74.3838"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("chooser should show %q, got:\n%s", want, view)
+		}
+	}
+
+	// Choosing New Jersey searches for zones there
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updatedModel.(Model)
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updatedModel.(Model)
+	if m.state != StateLoading || m.location == nil || m.location.Name != "Chatham, NJ 07928" {
+		t.Fatalf("state = %v location = %v, want loading zones for Chatham, NJ", m.state, m.location)
+	}
+	if cmd == nil || len(searchedLat) != 1 || searchedLat[0] != 40.7409 {
+		t.Errorf("zone searches = %v, want one around Chatham, NJ", searchedLat)
+	}
+
+	// Esc goes back to the search instead
+	m.state = StateCityChoice
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updatedModel.(Model).state != StateSearch {
+		t.Errorf("Esc should return to search, state = %v", updatedModel.(Model).state)
+	}
+}
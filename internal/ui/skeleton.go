@@ -0,0 +1,26 @@
+package ui
+
+import "strings"
+
+// Number of placeholder lines shown in each section while its data loads, roughly
+// the height of the section once loaded so the layout doesn't jump
+const (
+	weatherSkeletonLines = 6
+	alertSkeletonLines   = 2
+	tideSkeletonLines    = 5
+)
+
+// skeletonWidths are the lengths, in dashes, of successive placeholder lines, varied
+// so they read as lines of text
+var skeletonWidths = []int{8, 5, 7, 4, 6}
+
+// renderSkeleton renders status, e.g. a spinner with what's being fetched, above
+// lines of greyed "— — —" placeholders standing in for a section's pending data
+func renderSkeleton(st styles, status string, lines int) string {
+	out := []string{status}
+	for i := 0; i < lines; i++ {
+		placeholder := strings.TrimSpace(strings.Repeat("— ", skeletonWidths[i%len(skeletonWidths)]))
+		out = append(out, st.muted.Render(st.text(placeholder)))
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ngmaloney/marine-terminal/internal/stations"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
+)
+
+// placeholderLines counts the lines of out showing a skeleton placeholder
+func placeholderLines(out string) int {
+	n := 0
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, "— —") {
+			n++
+		}
+	}
+	return n
+}
+
+func TestModel_RenderWeatherView_LoadingSkeleton(t *testing.T) {
+	m := NewModel("", "", "")
+	m.width, m.height = 100, 40
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+	m.tideStation = &stations.TideStationInfo{ID: "8447435", Name: "Chatham"}
+	m.loadingWeather = true
+	m.loadingAlerts = true
+	m.loadingTides = true
+
+	weather := m.renderWeatherView()
+	for _, want := range []string{"Fetching marine forecast", "Fetching marine alerts", "— — — —"} {
+		if !strings.Contains(weather, want) {
+			t.Errorf("weather pane missing %q while loading:\n%s", want, weather)
+		}
+	}
+	if got := placeholderLines(weather); got != weatherSkeletonLines+alertSkeletonLines {
+		t.Errorf("weather pane has %d placeholder lines, want %d", got, weatherSkeletonLines+alertSkeletonLines)
+	}
+
+	m.activePane = PaneTides
+	tides := m.renderWeatherView()
+	if !strings.Contains(tides, "Loading tide predictions") || placeholderLines(tides) != tideSkeletonLines {
+		t.Errorf("tides pane should show %d placeholder lines while loading:\n%s", tideSkeletonLines, tides)
+	}
+
+	m.loadingWeather, m.loadingAlerts, m.loadingTides = false, false, false
+	if out := m.renderWeatherView(); strings.Contains(out, "— —") {
+		t.Errorf("placeholders should go once loading finishes:\n%s", out)
+	}
+}
+
+func TestRenderSkeleton_ASCII(t *testing.T) {
+	st := newStyles(DefaultTheme()).withASCII(true)
+	out := renderSkeleton(st, "Loading...", 2)
+	if strings.Contains(out, "—") || !strings.Contains(out, "- - -") {
+		t.Errorf("renderSkeleton() in ASCII mode = %q, want plain dashes", out)
+	}
+}
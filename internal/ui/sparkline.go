@@ -0,0 +1,212 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/NimbleMarkets/ntcharts/sparkline"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+// sparklineFloor is the smallest column drawn for a real observation, so the
+// lowest reading stays visible and only gaps in the data render as blank columns
+const sparklineFloor = 0.15
+
+// sampleHistory spreads observations over width time buckets, averaging the
+// observations in each bucket. Buckets with no observations are reported as gaps.
+func sampleHistory(history []models.Observation, width int) (values []float64, filled []bool) {
+	if len(history) == 0 || width <= 0 {
+		return nil, nil
+	}
+	if len(history) < width {
+		width = len(history)
+	}
+
+	sums := make([]float64, width)
+	counts := make([]int, width)
+	start := history[0].Time
+	span := history[len(history)-1].Time.Sub(start)
+	for _, obs := range history {
+		i := 0
+		if span > 0 {
+			i = int(float64(obs.Time.Sub(start)) / float64(span) * float64(width-1))
+		}
+		if i < 0 || i >= width {
+			continue
+		}
+		sums[i] += obs.Value
+		counts[i]++
+	}
+
+	values = make([]float64, width)
+	filled = make([]bool, width)
+	for i := range sums {
+		if counts[i] > 0 {
+			values[i] = sums[i] / float64(counts[i])
+			filled[i] = true
+		}
+	}
+	return values, filled
+}
+
+// asciiSparklineRamp draws an ASCII sparkline column, from the lowest reading to the highest
+const asciiSparklineRamp = "_.-=+*#"
+
+// renderSparkline draws a temperature series as a width-column sparkline scaled
+// between its lowest and highest readings. It returns "" if there are too few
+// observations to show a trend.
+func renderSparkline(history []models.Observation, width, height int, color lipgloss.Color) string {
+	scaled := scaleHistory(history, width)
+	if scaled == nil {
+		return ""
+	}
+
+	sl := sparkline.New(len(scaled), height,
+		sparkline.WithMaxValue(1),
+		sparkline.WithNoAutoMaxValue(),
+		sparkline.WithStyle(lipgloss.NewStyle().Foreground(color)),
+	)
+	sl.PushAll(scaled)
+	sl.Draw()
+	return sl.View()
+}
+
+// renderASCIISparkline draws a temperature series as a single line of ASCII
+// characters, rising through asciiSparklineRamp, for terminals without block glyphs
+func renderASCIISparkline(history []models.Observation, width int, color lipgloss.Color) string {
+	scaled := scaleHistory(history, width)
+	if scaled == nil {
+		return ""
+	}
+
+	cells := make([]byte, len(scaled))
+	for i, v := range scaled {
+		if v == 0 {
+			cells[i] = ' '
+			continue
+		}
+		cells[i] = asciiSparklineRamp[int((v-sparklineFloor)/(1-sparklineFloor)*float64(len(asciiSparklineRamp)-1)+0.5)]
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(string(cells))
+}
+
+// scaleHistory samples a temperature series into width columns scaled from
+// sparklineFloor (the lowest reading) to 1 (the highest), with gaps as 0. It
+// returns nil if there are too few observations to show a trend.
+func scaleHistory(history []models.Observation, width int) []float64 {
+	values, filled := sampleHistory(history, width)
+	if len(values) < 2 {
+		return nil
+	}
+
+	min, max := 0.0, 0.0
+	first := true
+	for i, v := range values {
+		if !filled[i] {
+			continue
+		}
+		if first || v < min {
+			min = v
+		}
+		if first || v > max {
+			max = v
+		}
+		first = false
+	}
+	spread := max - min
+	if spread == 0 {
+		spread = 1
+	}
+
+	scaled := make([]float64, len(values))
+	for i, v := range values {
+		if filled[i] {
+			scaled[i] = sparklineFloor + (v-min)/spread*(1-sparklineFloor)
+		}
+	}
+	return scaled
+}
+
+// formatTempTrend renders a labelled sparkline with the range of readings it covers
+func formatTempTrend(st styles, label string, history []models.Observation, width int, color lipgloss.Color) string {
+	const labelWidth = 12
+	if len(history) == 0 {
+		return ""
+	}
+	lo, hi := history[0].Value, history[0].Value
+	for _, obs := range history {
+		if obs.Value < lo {
+			lo = obs.Value
+		}
+		if obs.Value > hi {
+			hi = obs.Value
+		}
+	}
+	summary := st.text(fmt.Sprintf(" %.0f-%.0f°F", lo, hi))
+
+	var chart string
+	if st.ascii {
+		chart = renderASCIISparkline(history, width-labelWidth-lipgloss.Width(summary), color)
+	} else {
+		chart = renderSparkline(history, width-labelWidth-lipgloss.Width(summary), 2, color)
+	}
+	if chart == "" {
+		return ""
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Bottom,
+		st.label.Width(labelWidth).Render(label),
+		chart,
+		st.muted.Render(summary),
+	)
+}
+
+// trendWindow is how much history the temperature sparklines cover
+const trendWindow = 24 * time.Hour
+
+// mergeObservations combines two histories, oldest first, keeping the value from
+// newer where both have a reading at the same time and only the trendWindow
+// leading up to the latest reading
+func mergeObservations(older, newer []models.Observation) []models.Observation {
+	if len(older) == 0 {
+		return newer
+	}
+	merged := append(append([]models.Observation{}, older...), newer...)
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Time.Before(merged[j].Time) })
+
+	start := merged[len(merged)-1].Time.Add(-trendWindow)
+	var out []models.Observation
+	for _, obs := range merged {
+		if obs.Time.Before(start) {
+			continue
+		}
+		if n := len(out); n > 0 && out[n-1].Time.Equal(obs.Time) {
+			out[n-1] = obs
+			continue
+		}
+		out = append(out, obs)
+	}
+	return out
+}
+
+// renderTempTrends renders air and water temperature sparklines for the tide station,
+// sized to fit width columns. History stored before a restart fills in whatever the
+// latest fetch didn't return.
+func (m Model) renderTempTrends(width int) string {
+	var air, water []models.Observation
+	for _, c := range []*models.MarineConditions{m.storedTrends, m.tideConditions} {
+		if c != nil {
+			air = mergeObservations(air, c.AirTempHistory)
+			water = mergeObservations(water, c.WaterTempHistory)
+		}
+	}
+	var lines []string
+	if air := formatTempTrend(m.styles, "Air 24h:", air, width, m.styles.theme.Secondary); air != "" {
+		lines = append(lines, air)
+	}
+	if water := formatTempTrend(m.styles, "Water 24h:", water, width, m.styles.theme.Primary); water != "" {
+		lines = append(lines, water)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ngmaloney/marine-terminal/internal/database"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/stations"
+)
+
+func hourlyObservations(start time.Time, values ...float64) []models.Observation {
+	history := make([]models.Observation, len(values))
+	for i, v := range values {
+		history[i] = models.Observation{Time: start.Add(time.Duration(i) * time.Hour), Value: v}
+	}
+	return history
+}
+
+func TestSampleHistory(t *testing.T) {
+	start := time.Date(2025, 11, 27, 0, 0, 0, 0, time.UTC)
+
+	// Fewer observations than columns: one column per observation
+	values, filled := sampleHistory(hourlyObservations(start, 50, 52, 54), 40)
+	if len(values) != 3 || values[1] != 52 || !filled[0] || !filled[2] {
+		t.Errorf("sampleHistory() = %v %v, want one column per observation", values, filled)
+	}
+
+	// Observations are averaged into buckets when there are more than columns
+	values, _ = sampleHistory(hourlyObservations(start, 50, 52, 60, 62, 70), 3)
+	if len(values) != 3 || values[0] != 51 || values[2] != 70 {
+		t.Errorf("sampleHistory() = %v, want [51 61 70]", values)
+	}
+
+	// A stretch with no observations is reported as a gap
+	history := append(hourlyObservations(start, 50, 51, 52), hourlyObservations(start.Add(9*time.Hour), 53, 54)...)
+	_, filled = sampleHistory(history, 5)
+	gaps := 0
+	for _, ok := range filled {
+		if !ok {
+			gaps++
+		}
+	}
+	if gaps == 0 || !filled[0] || !filled[len(filled)-1] {
+		t.Errorf("filled = %v, want a gap between the ends", filled)
+	}
+
+	if values, _ := sampleHistory(nil, 10); values != nil {
+		t.Errorf("sampleHistory(nil) = %v, want nil", values)
+	}
+}
+
+func TestRenderSparkline_Width(t *testing.T) {
+	start := time.Date(2025, 11, 27, 0, 0, 0, 0, time.UTC)
+	var values []float64
+	for i := 0; i < 100; i++ {
+		values = append(values, 45+float64(i%10))
+	}
+	history := hourlyObservations(start, values...)
+
+	for _, width := range []int{20, 60} {
+		chart := renderSparkline(history, width, 2, DefaultTheme().Primary)
+		lines := strings.Split(chart, "\n")
+		if len(lines) != 2 {
+			t.Fatalf("width %d: %d rows, want 2", width, len(lines))
+		}
+		if w := lipgloss.Width(lines[0]); w != width {
+			t.Errorf("sparkline width = %d, want %d", w, width)
+		}
+	}
+
+	if chart := renderSparkline(history[:1], 20, 2, DefaultTheme().Primary); chart != "" {
+		t.Errorf("single observation should not render a trend, got %q", chart)
+	}
+}
+
+func TestModel_RenderTempTrends(t *testing.T) {
+	start := time.Date(2025, 11, 27, 0, 0, 0, 0, time.UTC)
+	m := NewModel("", "", "")
+	m.tideConditions = &models.MarineConditions{
+		AirTempHistory: hourlyObservations(start, 44, 46, 48, 47),
+	}
+
+	out := m.renderTempTrends(60)
+	if !strings.Contains(out, "Air 24h:") || !strings.Contains(out, "44-48°F") {
+		t.Errorf("renderTempTrends() = %q, want air trend with its range", out)
+	}
+	if strings.Contains(out, "Water") {
+		t.Error("water trend should be omitted without water observations")
+	}
+}
+
+func TestMergeObservations(t *testing.T) {
+	start := time.Date(2025, 11, 27, 0, 0, 0, 0, time.UTC)
+	stored := hourlyObservations(start.Add(-30*time.Hour), 40, 41)
+	stored = append(stored, hourlyObservations(start, 44, 45)...)
+	fetched := hourlyObservations(start.Add(time.Hour), 46, 47)
+
+	got := mergeObservations(stored, fetched)
+	// The 30h-old readings fall outside the window; the fetched value wins at start+1h
+	want := hourlyObservations(start, 44, 46, 47)
+	if len(got) != len(want) {
+		t.Fatalf("mergeObservations() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Time.Equal(want[i].Time) || got[i].Value != want[i].Value {
+			t.Errorf("observation %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestModel_ObservationHistory(t *testing.T) {
+	now := time.Now().Truncate(time.Hour)
+	repo := database.NewObservationRepository(filepath.Join(t.TempDir(), "observations.db"))
+	if err := repo.RecordObservations("8447435", map[string][]models.Observation{
+		database.ObservationAirTemp: hourlyObservations(now.Add(-6*time.Hour), 40, 42, 44),
+	}); err != nil {
+		t.Fatalf("RecordObservations failed: %v", err)
+	}
+
+	m := NewModel("", "", "").WithObservationHistory(repo)
+	m.tideStation = &stations.TideStationInfo{ID: "8447435", Name: "Chatham"}
+
+	// Stored history seeds the trend before the station's met data arrives
+	updated, _ := m.Update(loadObservationHistory(repo, "8447435")())
+	m = updated.(Model)
+	if out := m.renderTempTrends(60); !strings.Contains(out, "40-44°F") {
+		t.Errorf("renderTempTrends() = %q, want the stored air trend", out)
+	}
+
+	// Fetched history is drawn alongside it and recorded for the next run
+	updated, cmd := m.Update(tideDataFetchedMsg{
+		stationID: "8447435",
+		conditions: &models.MarineConditions{
+			AirTempHistory:   hourlyObservations(now.Add(-time.Hour), 48, 50),
+			WaterTempHistory: hourlyObservations(now.Add(-time.Hour), 52, 53),
+		},
+	})
+	m = updated.(Model)
+	if out := m.renderTempTrends(60); !strings.Contains(out, "40-50°F") || !strings.Contains(out, "Water 24h:") {
+		t.Errorf("renderTempTrends() = %q, want stored and fetched history combined", out)
+	}
+	if cmd == nil {
+		t.Fatal("Expected a command recording the fetched history")
+	}
+	cmd()
+	air, err := repo.RecentObservations("8447435", database.ObservationAirTemp)
+	if err != nil {
+		t.Fatalf("RecentObservations failed: %v", err)
+	}
+	if len(air) != 5 {
+		t.Errorf("Expected 5 stored air observations, got %v", air)
+	}
+
+	// History for a station no longer on display is dropped
+	m.storedTrends = nil
+	m.tideStation = &stations.TideStationInfo{ID: "8443970"}
+	updated, _ = m.Update(loadObservationHistory(repo, "8447435")())
+	if updated.(Model).storedTrends != nil {
+		t.Error("Expected stored history for another station to be ignored")
+	}
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/ports"
+)
+
+// stationsSearchedMsg is sent when a search of NOAA stations by name completes
+type stationsSearchedMsg struct {
+	query    string
+	stations []models.Port
+	err      error
+}
+
+// searchStations searches NOAA's tide stations by name or city with client. The search
+// may first fetch NOAA's full list of tide prediction stations; each request is bounded
+// by the client's timeout.
+func searchStations(client ports.Client, query string) tea.Cmd {
+	return func() tea.Msg {
+		found, err := client.SearchByLocation(context.Background(), query)
+		return stationsSearchedMsg{query: query, stations: found, err: err}
+	}
+}
+
+// stationItem wraps a NOAA station matched by name for use in a list
+type stationItem struct {
+	station models.Port
+	st      styles
+}
+
+// FilterValue implements list.Item
+func (s stationItem) FilterValue() string {
+	return s.station.Name + " " + s.station.State
+}
+
+// Title implements list.DefaultItem
+func (s stationItem) Title() string {
+	if s.station.State == "" {
+		return s.station.Name
+	}
+	return fmt.Sprintf("%s, %s", s.station.Name, s.station.State)
+}
+
+// Description implements list.DefaultItem
+func (s stationItem) Description() string {
+	parts := []string{"Station " + s.station.StationID}
+	if s.station.MarineZoneID != "" {
+		parts = append(parts, "zone "+s.station.MarineZoneID)
+	}
+	parts = append(parts, fmt.Sprintf("%.4f, %.4f", s.station.Latitude, s.station.Longitude))
+	return s.st.text(strings.Join(parts, " • "))
+}
+
+// createStationList creates a list.Model for choosing between the stations a name
+// search matched
+func createStationList(query string, found []models.Port, width, height int, st styles) list.Model {
+	items := make([]list.Item, len(found))
+	for i, station := range found {
+		items[i] = stationItem{station: station, st: st}
+	}
+
+	delegate := list.NewDefaultDelegate()
+	l := st.newList(items, delegate, width, height)
+	l.Title = fmt.Sprintf("Stations matching %q", query)
+	l.SetShowHelp(true)
+	l.SetFilteringEnabled(true)
+	// '?' opens the application-wide help overlay instead of the list's full help
+	l.KeyMap.ShowFullHelp.SetEnabled(false)
+	l.KeyMap.CloseFullHelp.SetEnabled(false)
+
+	return l
+}
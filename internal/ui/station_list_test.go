@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ngmaloney/marine-terminal/internal/geocoding"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+type mockStationClient struct {
+	stations []models.Port
+	err      error
+	queries  []string
+}
+
+func (m *mockStationClient) SearchByLocation(ctx context.Context, query string) ([]models.Port, error) {
+	m.queries = append(m.queries, query)
+	return m.stations, m.err
+}
+
+func (m *mockStationClient) GetPortByID(ctx context.Context, stationID string) (*models.Port, error) {
+	for _, s := range m.stations {
+		if s.StationID == stationID {
+			return &s, nil
+		}
+	}
+	return nil, errors.New("station not found")
+}
+
+func TestIntegration_SearchStationsByName(t *testing.T) {
+	client := &mockStationClient{stations: []models.Port{
+		{StationID: "8447930", Name: "Woods Hole", State: "MA", MarineZoneID: "ANZ254", Latitude: 41.5236, Longitude: -70.6711},
+		{StationID: "8447387", Name: "Woods Hole, Buzzards Bay", State: "MA", Latitude: 41.5317, Longitude: -70.6858},
+	}}
+	geocoder := &mockGeocoder{}
+	m := NewModel("", "", "")
+	m.stationClient = client
+	m.geocoder = geocoder
+	m.width, m.height = 100, 40
+	m.state = StateSearch
+	m.searchInput.Focus()
+
+	// Shift+Tab switches from search to the station name search
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	m = updated.(Model)
+	if m.state != StateStationSearch {
+		t.Fatalf("state = %v, want StateStationSearch", m.state)
+	}
+	if !strings.Contains(m.View(), "Find Station") {
+		t.Error("Expected the station search prompt")
+	}
+
+	for _, char := range "woods hole" {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{char}})
+		m = updated.(Model)
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.state != StateLoading || cmd == nil {
+		t.Fatalf("state = %v, cmd = %v; want StateLoading with a search", m.state, cmd)
+	}
+	msg := cmd()
+	if len(client.queries) != 1 || client.queries[0] != "woods hole" {
+		t.Errorf("station client queries = %v, want [woods hole]", client.queries)
+	}
+
+	updated, _ = m.Update(msg)
+	m = updated.(Model)
+	if m.state != StateStationList {
+		t.Fatalf("state = %v, want StateStationList", m.state)
+	}
+	if got := len(m.stationList.Items()); got != 2 {
+		t.Fatalf("station list has %d items, want 2", got)
+	}
+	if view := m.View(); !strings.Contains(view, "Woods Hole, MA") || !strings.Contains(view, "Station 8447930") {
+		t.Errorf("Expected the matched stations to be listed, got:\n%s", view)
+	}
+
+	// Picking a station uses its position as the location, without geocoding
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.state != StateLoading || cmd == nil {
+		t.Fatalf("state = %v, cmd = %v; want StateLoading with a zone search", m.state, cmd)
+	}
+	want := geocoding.Location{Latitude: 41.5236, Longitude: -70.6711, Name: "Woods Hole, MA"}
+	if m.location == nil || *m.location != want {
+		t.Errorf("location = %+v, want %+v", m.location, want)
+	}
+	if m.searchQuery != "Woods Hole" {
+		t.Errorf("searchQuery = %q, want Woods Hole", m.searchQuery)
+	}
+	if len(geocoder.queries) != 0 {
+		t.Errorf("geocoder was called with %v, want no geocoding", geocoder.queries)
+	}
+}
+
+func TestStationSearch_Errors(t *testing.T) {
+	m := NewModel("", "", "")
+	m.stationClient = &mockStationClient{err: errors.New("NOAA API returned status 503")}
+	m.width, m.height = 100, 40
+	m.state = StateStationSearch
+	m.stationSearchInput.Focus()
+
+	// An empty query doesn't search
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd != nil || m.state != StateStationSearch {
+		t.Errorf("state = %v, cmd = %v; an empty query shouldn't search", m.state, cmd)
+	}
+
+	m.stationSearchInput.SetValue("chatham")
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if m.state != StateStationSearch || m.err == nil {
+		t.Fatalf("state = %v, err = %v; want StateStationSearch with an error", m.state, m.err)
+	}
+	if !strings.Contains(m.View(), "station search failed") {
+		t.Error("Expected the search error in the prompt")
+	}
+
+	// Nothing matching is reported the same way
+	updated, _ = m.Update(stationsSearchedMsg{query: "nowhere"})
+	m = updated.(Model)
+	if m.state != StateStationSearch || m.err == nil || !strings.Contains(m.err.Error(), "nowhere") {
+		t.Errorf("state = %v, err = %v; want StateStationSearch with a no matches error", m.state, m.err)
+	}
+
+	// Esc goes back to the geocoding search
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(Model).state != StateSearch {
+		t.Errorf("state = %v, want StateSearch", updated.(Model).state)
+	}
+}
+
+func TestStationList_Back(t *testing.T) {
+	m := NewModel("", "", "")
+	m.width, m.height = 100, 40
+	updated, _ := m.Update(stationsSearchedMsg{query: "boston", stations: []models.Port{{StationID: "8443970", Name: "Boston", State: "MA"}}})
+	m = updated.(Model)
+	if m.state != StateStationList {
+		t.Fatalf("state = %v, want StateStationList", m.state)
+	}
+
+	// Esc returns to the station name to refine it, 's' to a new location search
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(Model).state != StateStationSearch {
+		t.Errorf("esc: state = %v, want StateStationSearch", updated.(Model).state)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if updated.(Model).state != StateSearch {
+		t.Errorf("s: state = %v, want StateSearch", updated.(Model).state)
+	}
+}
+
+func TestStationItem_DescriptionASCII(t *testing.T) {
+	station := models.Port{Name: "Chatham", State: "MA", StationID: "8447435", MarineZoneID: "ANZ254", Latitude: 41.6885, Longitude: -69.9511}
+	item := stationItem{station: station, st: newStyles(DefaultTheme())}
+	if got, want := item.Description(), "Station 8447435 • zone ANZ254 • 41.6885, -69.9511"; got != want {
+		t.Errorf("Description() = %q, want %q", got, want)
+	}
+	item.st = item.st.withASCII(true)
+	if got, want := item.Description(), "Station 8447435 | zone ANZ254 | 41.6885, -69.9511"; got != want {
+		t.Errorf("Description() in ASCII mode = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+// conditionsLevel is the overall severity of conditions shown by the status bar
+type conditionsLevel int
+
+const (
+	levelUnknown  conditionsLevel = iota // Nothing loaded yet to judge by
+	levelCalm                            // No advisories and seas below the rough rating
+	levelAdvisory                        // An advisory or watch, or rough conditions
+	levelWarning                         // A warning or severe alert, or dangerous conditions
+)
+
+// String returns the level's label for the status bar
+func (l conditionsLevel) String() string {
+	switch l {
+	case levelCalm:
+		return "Calm"
+	case levelAdvisory:
+		return "Advisory"
+	case levelWarning:
+		return "Warning"
+	default:
+		return "Unknown"
+	}
+}
+
+// conditionsLevelFor combines the conditions rating with the active marine alerts.
+// Warnings and severe alerts rate a warning, as does a dangerous rating; any other
+// advisory or watch, or a rough rating, rates an advisory. Marine statements don't
+// raise the level.
+func conditionsLevelFor(rating models.ConditionsRating, active []models.Alert) conditionsLevel {
+	level := levelUnknown
+	switch rating {
+	case models.RatingCalm, models.RatingModerate:
+		level = levelCalm
+	case models.RatingRough:
+		level = levelAdvisory
+	case models.RatingDangerous:
+		level = levelWarning
+	}
+	for _, a := range active {
+		switch {
+		case strings.Contains(a.Event, "Warning") || a.Severity.Rank() >= models.SeveritySevere.Rank():
+			level = max(level, levelWarning)
+		case !a.IsStatement():
+			level = max(level, levelAdvisory)
+		default:
+			level = max(level, levelCalm)
+		}
+	}
+	return level
+}
+
+// statusBarColor returns the status bar's background for level: the theme's success
+// color when calm, warning for advisories and danger for warnings
+func statusBarColor(theme Theme, level conditionsLevel) lipgloss.Color {
+	switch level {
+	case levelCalm:
+		return theme.Success
+	case levelAdvisory:
+		return theme.Warning
+	case levelWarning:
+		return theme.Danger
+	default:
+		return theme.Muted
+	}
+}
+
+// conditionsLevel rates the displayed conditions for the status bar
+func (m Model) conditionsLevel() conditionsLevel {
+	rating := models.RatingUnknown
+	if m.weather != nil {
+		rating = m.weather.Rating(m.rating)
+	}
+	return conditionsLevelFor(rating, m.alerts.ActiveMarineAlertsAt(m.clock))
+}
+
+// renderStatusBar renders a full-width bar colored by the overall conditions. The
+// bar is labeled with the level and the most severe alert so it still reads without
+// color. It returns "" until there's something to rate.
+func (m Model) renderStatusBar(width int) string {
+	level := m.conditionsLevel()
+	if level == levelUnknown {
+		return ""
+	}
+	label := "CONDITIONS: " + strings.ToUpper(level.String())
+	if active := m.alerts.ActiveMarineAlertsAt(m.clock); len(active) > 0 {
+		label += " • " + active[0].Event
+	}
+	style := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#000000")).
+		Background(statusBarColor(m.styles.theme, level)).
+		Padding(0, 1).
+		Width(width)
+	return style.Render(ansi.Truncate(m.styles.text(label), width-style.GetHorizontalPadding(), "…"))
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
+)
+
+func TestConditionsLevelFor(t *testing.T) {
+	theme := DefaultTheme()
+	advisory := models.Alert{Event: "Small Craft Advisory", Severity: models.SeverityModerate}
+	gale := models.Alert{Event: "Gale Warning", Severity: models.SeverityModerate}
+	statement := models.Alert{Event: "Marine Weather Statement", Severity: models.SeverityMinor}
+	severe := models.Alert{Event: "Marine Weather Statement", Severity: models.SeveritySevere}
+
+	tests := []struct {
+		name   string
+		rating models.ConditionsRating
+		alerts []models.Alert
+		want   conditionsLevel
+		color  lipgloss.Color
+	}{
+		{"nothing loaded", models.RatingUnknown, nil, levelUnknown, theme.Muted},
+		{"calm", models.RatingCalm, nil, levelCalm, theme.Success},
+		{"moderate", models.RatingModerate, nil, levelCalm, theme.Success},
+		{"statement only", models.RatingUnknown, []models.Alert{statement}, levelCalm, theme.Success},
+		{"rough", models.RatingRough, nil, levelAdvisory, theme.Warning},
+		{"advisory in calm seas", models.RatingCalm, []models.Alert{statement, advisory}, levelAdvisory, theme.Warning},
+		{"dangerous", models.RatingDangerous, nil, levelWarning, theme.Danger},
+		{"warning", models.RatingCalm, []models.Alert{advisory, gale}, levelWarning, theme.Danger},
+		{"severe statement", models.RatingCalm, []models.Alert{severe}, levelWarning, theme.Danger},
+		{"advisory doesn't lower a dangerous rating", models.RatingDangerous, []models.Alert{advisory}, levelWarning, theme.Danger},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := conditionsLevelFor(tt.rating, tt.alerts)
+			if got != tt.want {
+				t.Errorf("conditionsLevelFor() = %v, want %v", got, tt.want)
+			}
+			if color := statusBarColor(theme, got); color != tt.color {
+				t.Errorf("statusBarColor(%v) = %v, want %v", got, color, tt.color)
+			}
+		})
+	}
+}
+
+func TestModel_StatusBar(t *testing.T) {
+	orig := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.Ascii)
+	t.Cleanup(func() { lipgloss.SetColorProfile(orig) })
+
+	now := time.Now()
+	m := NewModel("", "", "")
+	m.width, m.height = 100, 40
+	m.state = StateDisplay
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+
+	if strings.Contains(m.View(), "CONDITIONS:") {
+		t.Error("The status bar should wait for conditions to rate")
+	}
+
+	m.weather = &models.MarineConditions{Wind: models.WindData{Direction: "SW", SpeedMin: 5, SpeedMax: 10}}
+	m.alerts = &models.AlertData{Alerts: []models.Alert{{
+		ID:       "gale",
+		Event:    "Gale Warning",
+		Severity: models.SeverityModerate,
+		Onset:    now.Add(-time.Hour),
+		Expires:  now.Add(time.Hour),
+	}}}
+	// Without color the level is still spelled out
+	view := m.View()
+	if first := strings.SplitN(view, "\n", 2)[0]; !strings.Contains(first, "CONDITIONS: WARNING • Gale Warning") {
+		t.Errorf("Expected the status bar on the first line, got %q", first)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	m = updated.(Model)
+	if strings.Contains(m.View(), "CONDITIONS:") {
+		t.Error("'g' should hide the status bar")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if !strings.Contains(updated.(Model).View(), "CONDITIONS: WARNING") {
+		t.Error("'g' again should show the status bar")
+	}
+}
@@ -1,121 +1,155 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
-
-var (
-	// Color palette
-	colorPrimary   = lipgloss.Color("#00BFFF") // Deep sky blue
-	colorSecondary = lipgloss.Color("#87CEEB") // Sky blue
-	colorDanger    = lipgloss.Color("#FF6B6B") // Red for alerts
-	colorWarning   = lipgloss.Color("#FFD93D") // Yellow for warnings
-	colorSuccess   = lipgloss.Color("#6BCF7F") // Green
-	colorMuted     = lipgloss.Color("#6C757D") // Gray
-	colorBorder    = lipgloss.Color("#4A90E2") // Border blue
-
-	// Base styles
-	baseStyle = lipgloss.NewStyle().
-			Padding(0, 1)
-
-	// Title styles (no padding - paneStyle already has padding)
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(colorPrimary)
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
 
-	activeTitleStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#FFFFFF")).
-				Background(colorPrimary)
+// sectionBoxPaddingX is the horizontal padding inside the weather and tides box
+const sectionBoxPaddingX = 2
 
-	// Pane styles
-	paneStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(colorBorder).
-			Padding(1, 2).
-			MarginRight(1)
+// styles holds the lipgloss styles used to render the UI, derived from a Theme
+type styles struct {
+	theme Theme
+	ascii bool // Draw glyphs and borders in plain ASCII (see text)
 
-	activePaneStyle = lipgloss.NewStyle().
-			Border(lipgloss.ThickBorder()).
-			BorderForeground(colorPrimary).
-			Padding(1, 2).
-			MarginRight(1)
+	clock24h bool // Show times on a 24-hour clock (see formatTime)
 
-	// Content styles
-	labelStyle = lipgloss.NewStyle().
-			Foreground(colorMuted).
-			Bold(true)
+	// Title styles (no padding - the boxes already have padding)
+	title lipgloss.Style
 
-	valueStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF"))
+	// Content styles
+	label lipgloss.Style
+	value lipgloss.Style
 
 	// Alert severity styles
-	alertDangerStyle = lipgloss.NewStyle().
-				Foreground(colorDanger).
-				Bold(true)
+	alertDanger   lipgloss.Style
+	alertExtreme  lipgloss.Style
+	alertSevere   lipgloss.Style
+	alertModerate lipgloss.Style
+	alertMinor    lipgloss.Style
 
-	alertExtremeStyle = lipgloss.NewStyle().
-				Foreground(colorDanger).
-				Bold(true)
+	// Help text style
+	help lipgloss.Style
 
-	alertSevereStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#FF8C42")).
-				Bold(true)
+	// Utility styles
+	muted   lipgloss.Style
+	success lipgloss.Style
+	warning lipgloss.Style
+	night   lipgloss.Style // Dimmer than muted, for night forecast periods
 
-	alertModerateStyle = lipgloss.NewStyle().
-				Foreground(colorWarning).
-				Bold(true)
+	// Section styles
+	header     lipgloss.Style
+	boxHeader  lipgloss.Style
+	sectionBox lipgloss.Style
 
-	alertMinorStyle = lipgloss.NewStyle().
-			Foreground(colorSuccess)
+	// Modal styles
+	modal lipgloss.Style
 
-	// Help text style
-	helpStyle = lipgloss.NewStyle().
-			Foreground(colorMuted).
-			Padding(1, 0)
+	// Tab styles
+	tab       lipgloss.Style
+	activeTab lipgloss.Style
+}
 
-	// Utility styles
-	mutedStyle = lipgloss.NewStyle().
-			Foreground(colorMuted)
+// newStyles builds the UI styles for theme
+func newStyles(theme Theme) styles {
+	return styles{
+		theme: theme,
+
+		title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Primary),
+
+		label: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			Bold(true),
+
+		value: lipgloss.NewStyle().
+			Foreground(theme.Text),
 
-	successStyle = lipgloss.NewStyle().
-			Foreground(colorSuccess)
+		alertDanger: lipgloss.NewStyle().
+			Foreground(theme.Danger).
+			Bold(true),
 
-	// Section header styles
-	sectionHeaderStyle = lipgloss.NewStyle().
-				Foreground(colorPrimary).
-				Bold(true).
-				Padding(0, 1).
-				MarginTop(1)
+		alertExtreme: lipgloss.NewStyle().
+			Foreground(theme.Danger).
+			Bold(true),
 
-	boxHeaderStyle = lipgloss.NewStyle().
-			Foreground(colorPrimary).
+		alertSevere: lipgloss.NewStyle().
+			Foreground(theme.Severe).
+			Bold(true),
+
+		alertModerate: lipgloss.NewStyle().
+			Foreground(theme.Warning).
+			Bold(true),
+
+		alertMinor: lipgloss.NewStyle().
+			Foreground(theme.Success),
+
+		help: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			Padding(1, 0),
+
+		muted: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+
+		night: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			Faint(true),
+
+		success: lipgloss.NewStyle().
+			Foreground(theme.Success),
+
+		warning: lipgloss.NewStyle().
+			Foreground(theme.Warning),
+
+		header: lipgloss.NewStyle().
+			Foreground(theme.Primary).
 			Bold(true).
-			Padding(0, 0, 1, 0) // Padding bottom 1
+			Padding(0, 1).
+			MarginBottom(1),
 
-	sectionBoxStyle = lipgloss.NewStyle().
+		boxHeader: lipgloss.NewStyle().
+			Foreground(theme.Primary).
+			Bold(true).
+			Padding(0, 0, 1, 0), // Padding bottom 1
+
+		sectionBox: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(colorBorder).
-			Padding(1, 2).
-			MarginBottom(1)
+			BorderForeground(theme.Border).
+			Padding(1, sectionBoxPaddingX).
+			MarginBottom(1),
 
-	// Modal styles
-	modalStyle = lipgloss.NewStyle().
+		modal: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(colorPrimary).
+			BorderForeground(theme.Primary).
 			Padding(1, 2).
-			Width(60)
+			Width(60),
 
-	// Tab styles
-	tabStyle = lipgloss.NewStyle().
+		tab: lipgloss.NewStyle().
 			Padding(0, 1).
-			Foreground(colorMuted)
+			Foreground(theme.Muted),
 
-	activeTabStyle = lipgloss.NewStyle().
+		activeTab: lipgloss.NewStyle().
 			Padding(0, 1).
 			Bold(true).
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(colorPrimary)
-
-	tabGapStyle = lipgloss.NewStyle().
-			Padding(0, 1).
-			Foreground(colorMuted)
-)
+			Foreground(theme.ActiveText).
+			Background(theme.Primary),
+	}
+}
+
+// alert returns the style for an alert of the given severity
+func (s styles) alert(severity models.AlertSeverity) lipgloss.Style {
+	switch severity {
+	case models.SeverityExtreme:
+		return s.alertExtreme
+	case models.SeveritySevere:
+		return s.alertSevere
+	case models.SeverityModerate:
+		return s.alertModerate
+	case models.SeverityMinor:
+		return s.alertMinor
+	default:
+		return s.value
+	}
+}
@@ -0,0 +1,195 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is the set of colors the UI is drawn with
+type Theme struct {
+	Name       string
+	Primary    lipgloss.Color // Titles, headers and the active tab
+	Secondary  lipgloss.Color // Forecast period headings and the air temperature trend
+	Text       lipgloss.Color // Values
+	ActiveText lipgloss.Color // Text on a Primary background
+	Muted      lipgloss.Color // Labels, help and secondary text
+	Border     lipgloss.Color // Section box borders
+	Success    lipgloss.Color
+	Warning    lipgloss.Color
+	Danger     lipgloss.Color // Errors and extreme alerts
+	Severe     lipgloss.Color // Severe alerts
+	Spinner    lipgloss.Color
+}
+
+// DefaultThemeName is the theme used when none is selected
+const DefaultThemeName = "default"
+
+var themes = map[string]Theme{
+	DefaultThemeName: {
+		Name:       DefaultThemeName,
+		Primary:    lipgloss.Color("#00BFFF"), // Deep sky blue
+		Secondary:  lipgloss.Color("#87CEEB"), // Sky blue
+		Text:       lipgloss.Color("#FFFFFF"),
+		ActiveText: lipgloss.Color("#FFFFFF"),
+		Muted:      lipgloss.Color("#6C757D"), // Gray
+		Border:     lipgloss.Color("#4A90E2"), // Border blue
+		Success:    lipgloss.Color("#6BCF7F"), // Green
+		Warning:    lipgloss.Color("#FFD93D"), // Yellow for warnings
+		Danger:     lipgloss.Color("#FF6B6B"), // Red for alerts
+		Severe:     lipgloss.Color("#FF8C42"), // Orange
+		Spinner:    lipgloss.Color("205"),
+	},
+	"high-contrast": {
+		Name:       "high-contrast",
+		Primary:    lipgloss.Color("#FFFF00"),
+		Secondary:  lipgloss.Color("#00FFFF"),
+		Text:       lipgloss.Color("#FFFFFF"),
+		ActiveText: lipgloss.Color("#000000"),
+		Muted:      lipgloss.Color("#C0C0C0"),
+		Border:     lipgloss.Color("#FFFFFF"),
+		Success:    lipgloss.Color("#00FF00"),
+		Warning:    lipgloss.Color("#FFFF00"),
+		Danger:     lipgloss.Color("#FF0000"),
+		Severe:     lipgloss.Color("#FF8000"),
+		Spinner:    lipgloss.Color("#FFFF00"),
+	},
+	"monochrome-green": {
+		Name:       "monochrome-green",
+		Primary:    lipgloss.Color("#33FF33"),
+		Secondary:  lipgloss.Color("#22CC22"),
+		Text:       lipgloss.Color("#33FF33"),
+		ActiveText: lipgloss.Color("#002200"),
+		Muted:      lipgloss.Color("#119911"),
+		Border:     lipgloss.Color("#22CC22"),
+		Success:    lipgloss.Color("#33FF33"),
+		Warning:    lipgloss.Color("#AAFF66"),
+		Danger:     lipgloss.Color("#CCFFCC"),
+		Severe:     lipgloss.Color("#99FF99"),
+		Spinner:    lipgloss.Color("#33FF33"),
+	},
+}
+
+// DefaultTheme returns the built-in blue ocean theme
+func DefaultTheme() Theme {
+	return themes[DefaultThemeName]
+}
+
+// ThemeNames lists the built-in themes in alphabetical order
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ThemeByName returns the built-in theme called name
+func ThemeByName(name string) (Theme, error) {
+	theme, ok := themes[strings.ToLower(name)]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q (want one of: %s)", name, strings.Join(ThemeNames(), ", "))
+	}
+	return theme, nil
+}
+
+// LoadTheme returns the built-in theme called nameOrPath, or loads it from a
+// TOML file when nameOrPath ends in .toml
+func LoadTheme(nameOrPath string) (Theme, error) {
+	if strings.HasSuffix(strings.ToLower(nameOrPath), ".toml") {
+		return LoadThemeFile(nameOrPath)
+	}
+	return ThemeByName(nameOrPath)
+}
+
+// LoadThemeFile reads a theme from a TOML file of top-level string keys, e.g.
+//
+//	name = "solarized"
+//	primary = "#268BD2"
+//	muted = "#586E75"
+//
+// Colors the file doesn't set keep their value from the default theme.
+func LoadThemeFile(path string) (Theme, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Theme{}, fmt.Errorf("opening theme file: %w", err)
+	}
+	defer f.Close()
+
+	theme := DefaultTheme()
+	theme.Name = path
+	fields := map[string]*lipgloss.Color{
+		"primary":     &theme.Primary,
+		"secondary":   &theme.Secondary,
+		"text":        &theme.Text,
+		"active_text": &theme.ActiveText,
+		"muted":       &theme.Muted,
+		"border":      &theme.Border,
+		"success":     &theme.Success,
+		"warning":     &theme.Warning,
+		"danger":      &theme.Danger,
+		"severe":      &theme.Severe,
+		"spinner":     &theme.Spinner,
+	}
+
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, rest, ok := strings.Cut(line, "=")
+		if !ok {
+			return Theme{}, fmt.Errorf("%s:%d: expected key = \"value\"", path, lineNum)
+		}
+		key = strings.TrimSpace(key)
+		value, err := parseTOMLString(strings.TrimSpace(rest))
+		if err != nil {
+			return Theme{}, fmt.Errorf("%s:%d: %s: %w", path, lineNum, key, err)
+		}
+
+		if key == "name" {
+			theme.Name = value
+			continue
+		}
+		field, ok := fields[key]
+		if !ok {
+			return Theme{}, fmt.Errorf("%s:%d: unknown theme key %q", path, lineNum, key)
+		}
+		*field = lipgloss.Color(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return Theme{}, fmt.Errorf("reading theme file: %w", err)
+	}
+
+	return theme, nil
+}
+
+// parseTOMLString parses a quoted TOML string value, ignoring a trailing comment
+func parseTOMLString(s string) (string, error) {
+	if len(s) == 0 || (s[0] != '"' && s[0] != '\'') {
+		return "", fmt.Errorf("expected a quoted string")
+	}
+	end := strings.IndexByte(s[1:], s[0])
+	if end < 0 {
+		return "", fmt.Errorf("unterminated string")
+	}
+	end++ // Index within s of the closing quote
+
+	if trailing := strings.TrimSpace(s[end+1:]); trailing != "" && !strings.HasPrefix(trailing, "#") {
+		return "", fmt.Errorf("unexpected %q after value", trailing)
+	}
+	if s[0] == '\'' {
+		return s[1:end], nil // Literal string, no escapes
+	}
+	return strconv.Unquote(s[:end+1])
+}
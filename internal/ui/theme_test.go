@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestWithThemeChangesRenderedColors(t *testing.T) {
+	orig := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	t.Cleanup(func() { lipgloss.SetColorProfile(orig) })
+
+	theme, err := ThemeByName("monochrome-green")
+	if err != nil {
+		t.Fatalf("ThemeByName() error = %v", err)
+	}
+
+	def := NewModel("", "", "")
+	green := NewModel("", "", "").WithTheme(theme)
+
+	defTitle := def.styles.title.Render("Saved Ports")
+	greenTitle := green.styles.title.Render("Saved Ports")
+	if defTitle == greenTitle {
+		t.Fatalf("title rendered the same in both themes: %q", defTitle)
+	}
+	// #00BFFF and #33FF33 as truecolor foreground codes
+	if !strings.Contains(defTitle, "38;2;0;191;255") {
+		t.Errorf("default title = %q, want deep sky blue foreground", defTitle)
+	}
+	if !strings.Contains(greenTitle, "38;2;51;255;51") {
+		t.Errorf("monochrome-green title = %q, want green foreground", greenTitle)
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "solarized.toml")
+	content := `# Solarized dark
+name = "solarized"
+primary = "#268BD2" # blue
+muted = '#586E75'
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	theme, err := LoadTheme(path)
+	if err != nil {
+		t.Fatalf("LoadTheme() error = %v", err)
+	}
+	if theme.Name != "solarized" || theme.Primary != "#268BD2" || theme.Muted != "#586E75" {
+		t.Errorf("theme = %+v, want solarized primary and muted", theme)
+	}
+	if theme.Danger != DefaultTheme().Danger {
+		t.Errorf("Danger = %s, want default %s for unset key", theme.Danger, DefaultTheme().Danger)
+	}
+
+	if _, err := LoadTheme("high-contrast"); err != nil {
+		t.Errorf("LoadTheme(high-contrast) error = %v", err)
+	}
+	if _, err := LoadTheme("neon"); err == nil {
+		t.Error("LoadTheme(neon) succeeded, want unknown theme error")
+	}
+}
+
+func TestLoadThemeFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "unknown key", content: `background = "#000000"`},
+		{name: "unquoted value", content: `primary = #268BD2`},
+		{name: "missing equals", content: `primary "#268BD2"`},
+		{name: "unterminated string", content: `primary = "#268BD2`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "theme.toml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadThemeFile(path); err == nil {
+				t.Errorf("LoadThemeFile(%q) succeeded, want error", tt.content)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+// Clock formats accepted by the tide height prompt, with and without a weekday
+var tideClockLayouts = []string{"15:04", "3:04 PM", "3:04PM", "3 PM", "3PM"}
+
+// parseTideTime reads a time of day such as "14:30", "2:30 PM" or "Tue 2:30 PM" typed
+// into the tide height prompt. Tide predictions carry the station's local wall-clock
+// time, so the result is built from now's wall clock in loc. Without a weekday it is
+// the next time that clock reading comes round (today, or tomorrow if it has passed);
+// with one, it is that day this week, today included.
+func parseTideTime(input string, now time.Time, loc *time.Location) (time.Time, error) {
+	input = strings.ToUpper(strings.Join(strings.Fields(input), " "))
+	if input == "" {
+		return time.Time{}, fmt.Errorf("enter a time like 14:30, 2:30 PM or Tue 2:30 PM")
+	}
+
+	weekday, clock := -1, input
+	if fields := strings.SplitN(input, " ", 2); len(fields) == 2 {
+		if day, ok := parseWeekday(fields[0]); ok {
+			weekday, clock = int(day), fields[1]
+		}
+	}
+
+	var parsed time.Time
+	var err error
+	for _, layout := range tideClockLayouts {
+		if parsed, err = time.Parse(layout, clock); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("couldn't read %q as a time: try 14:30, 2:30 PM or Tue 2:30 PM", input)
+	}
+
+	wallNow := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), 0, 0, loc)
+	at := time.Date(now.Year(), now.Month(), now.Day(), parsed.Hour(), parsed.Minute(), 0, 0, loc)
+	if weekday >= 0 {
+		return at.AddDate(0, 0, (weekday-int(at.Weekday())+7)%7), nil
+	}
+	if at.Before(wallNow) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at, nil
+}
+
+// parseWeekday reads a weekday name or its three-letter abbreviation
+func parseWeekday(s string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToUpper(day.String())
+		if s == name || s == name[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// tideHeightAt looks up the predicted height at the time typed into the tide height
+// prompt and formats it for the tides pane
+func tideHeightAt(st styles, tides *models.TideData, input string, now time.Time) (string, error) {
+	if tides == nil || len(tides.Events) == 0 {
+		return "", fmt.Errorf("no tide predictions loaded")
+	}
+	at, err := parseTideTime(input, now, tides.Events[0].Time.Location())
+	if err != nil {
+		return "", err
+	}
+	height, err := tides.HeightAt(at)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Predicted at %s: %.1f ft", st.formatTime(at, "Mon Jan 2, 3:04 PM"), height), nil
+}
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/stations"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
+)
+
+func TestParseTideTime(t *testing.T) {
+	// Wednesday afternoon
+	now := time.Date(2025, 11, 26, 13, 15, 0, 0, time.UTC)
+	day := func(d, hour, min int) time.Time { return time.Date(2025, 11, d, hour, min, 0, 0, time.UTC) }
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"14:30", day(26, 14, 30)},
+		{"2:30 PM", day(26, 14, 30)},
+		{"2:30pm", day(26, 14, 30)},
+		{"3 pm", day(26, 15, 0)},
+		{"9:00", day(27, 9, 0)}, // Already passed today
+		{"Fri 2:30 PM", day(28, 14, 30)},
+		{"wednesday 08:00", day(26, 8, 0)}, // Named day includes today, even if earlier
+		{"  Thu   06:45 ", day(27, 6, 45)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseTideTime(tt.input, now, time.UTC)
+			if err != nil {
+				t.Fatalf("parseTideTime(%q) error: %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseTideTime(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "noon", "25:00", "Someday 2 PM"} {
+		if _, err := parseTideTime(input, now, time.UTC); err == nil {
+			t.Errorf("parseTideTime(%q) should fail", input)
+		}
+	}
+}
+
+func TestModel_TideTimePrompt(t *testing.T) {
+	base := time.Date(2025, 11, 26, 12, 0, 0, 0, time.UTC)
+	m := NewModel("", "", "").WithClock(models.FixedClock(base))
+	m.width, m.height = 100, 40
+	m.state = StateDisplay
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+	m.tideStation = &stations.TideStationInfo{ID: "8447930", Name: "Woods Hole"}
+	m.tides = &models.TideData{Events: []models.TideEvent{
+		{Time: base, Type: models.TideLow, Height: 1.0},
+		{Time: base.Add(6 * time.Hour), Type: models.TideHigh, Height: 5.0},
+	}}
+
+	press := func(m Model, msg tea.KeyMsg) Model {
+		updated, _ := m.Update(msg)
+		return updated.(Model)
+	}
+	typeText := func(m Model, s string) Model {
+		return press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+	}
+
+	// Only available from the tides pane
+	m = typeText(m, "t")
+	if m.state != StateDisplay {
+		t.Fatalf("t on the weather pane should do nothing, state = %v", m.state)
+	}
+	m.activePane = PaneTides
+	m = typeText(m, "t")
+	if m.state != StateTideTime {
+		t.Fatalf("t on the tides pane should open the prompt, state = %v", m.state)
+	}
+
+	// Outside the predictions: the prompt stays open with the error
+	m = typeText(m, "Fri 2 PM")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.state != StateTideTime || m.err == nil {
+		t.Fatalf("a time outside the predictions should be reported in the prompt, state = %v err = %v", m.state, m.err)
+	}
+	if !strings.Contains(m.View(), "outside the predictions") {
+		t.Error("prompt should show the error")
+	}
+
+	m.tideTimeInput.SetValue("3 PM")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.state != StateDisplay || m.err != nil {
+		t.Fatalf("valid time should close the prompt, state = %v err = %v", m.state, m.err)
+	}
+	if !strings.Contains(m.View(), "Predicted at Wed Nov 26, 3:00 PM: 3.0 ft") {
+		t.Errorf("tides pane should show the predicted height, got:\n%s", m.View())
+	}
+
+	// Esc leaves the prompt without changing the last result
+	m = typeText(m, "t")
+	m = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.state != StateDisplay || m.tideHeightNote == "" {
+		t.Errorf("esc should close the prompt and keep the last result, state = %v note = %q", m.state, m.tideHeightNote)
+	}
+}
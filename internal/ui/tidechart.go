@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/NimbleMarkets/ntcharts/linechart/timeserieslinechart"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+// tideChartHeight is the height of the tide chart in rows
+const tideChartHeight = 15
+
+// asciiChartLabelWidth is the width of the ASCII chart's height axis, e.g. " 10.2 |"
+const asciiChartLabelWidth = 7
+
+// tideChartWidth sizes the tide chart to fill the tides box
+func tideChartWidth(termWidth int) int {
+	return boxContentWidth(termWidth)
+}
+
+// BrailleSupported reports whether the terminal's locale can display the braille
+// characters the tide chart is drawn with. A locale that explicitly isn't UTF-8
+// (e.g. "C" or "POSIX") can't; an unset locale is assumed to be fine.
+func BrailleSupported() bool {
+	return brailleSupportedEnv(os.Getenv)
+}
+
+// brailleSupportedEnv is BrailleSupported reading the locale from getenv
+func brailleSupportedEnv(getenv func(string) string) bool {
+	// LC_ALL overrides LC_CTYPE, which overrides LANG
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := getenv(name); locale != "" {
+			locale = strings.ToLower(locale)
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	return true
+}
+
+// rebuildTideChart redraws the braille tide chart for the current tides and terminal
+// width. A fresh chart is created each time to avoid artifacts.
+func (m Model) rebuildTideChart() Model {
+	m.tideChart = timeserieslinechart.New(tideChartWidth(m.width), tideChartHeight)
+	if m.tides == nil || m.asciiChart {
+		return m
+	}
+	for _, event := range m.chartEvents() {
+		m.tideChart.Push(timeserieslinechart.TimePoint{
+			Time:  event.Time,
+			Value: event.Height,
+		})
+	}
+	m.tideChart.DrawBraille()
+	return m
+}
+
+// renderTideChart renders the tide chart, in plain ASCII when braille is turned off
+func (m Model) renderTideChart() string {
+	if m.asciiChart {
+		if m.tides == nil {
+			return ""
+		}
+		return renderASCIITideChart(m.styles, m.chartEvents(), tideChartWidth(m.width), tideChartHeight)
+	}
+	return m.tideChart.View()
+}
+
+// tideDays returns midnight of each day of the prediction window, from the day of the
+// first tide event through the day of the last, in the first event's time zone
+func (m Model) tideDays() []time.Time {
+	if m.tides == nil || len(m.tides.Events) == 0 {
+		return nil
+	}
+	first := m.tides.Events[0].Time
+	loc := first.Location()
+	last := m.tides.Events[len(m.tides.Events)-1].Time.In(loc)
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc)
+	var days []time.Time
+	for !day.After(last) {
+		days = append(days, day)
+		day = day.AddDate(0, 0, 1)
+	}
+	return days
+}
+
+// tidePageCount is the number of days of tide events paged through
+func (m Model) tidePageCount() int {
+	return max(len(m.tideDays()), 1)
+}
+
+// tidePageDay returns midnight of the day on the current page, or the zero time if
+// there are no tide events
+func (m Model) tidePageDay() time.Time {
+	days := m.tideDays()
+	if m.tidePage >= len(days) {
+		return time.Time{}
+	}
+	return days[m.tidePage]
+}
+
+// tidePageEvents returns the high and low tides on the current page's day
+func (m Model) tidePageEvents() []models.TideEvent {
+	day := m.tidePageDay()
+	if day.IsZero() {
+		return nil
+	}
+	return m.tides.GetEventsForDay(day)
+}
+
+// chartEvents returns the events drawn in the tide chart: the current page's day
+// together with the last tide before it and the first after it, so the curve spans
+// the whole day
+func (m Model) chartEvents() []models.TideEvent {
+	day := m.tidePageDay()
+	if day.IsZero() {
+		return nil
+	}
+	next := day.AddDate(0, 0, 1)
+	start, end := 0, len(m.tides.Events)
+	for i, event := range m.tides.Events {
+		if event.Time.Before(day) {
+			start = i
+		}
+		if !event.Time.Before(next) {
+			end = i + 1
+			break
+		}
+	}
+	return m.tides.Events[start:end]
+}
+
+// renderASCIITideChart plots the tide curve with '*' using only ASCII characters.
+// Between high and low tides the height follows a half cosine, which is close to
+// the shape of a real tide. It returns "" if there are too few events to plot.
+func renderASCIITideChart(st styles, events []models.TideEvent, width, height int) string {
+	plotWidth := width - asciiChartLabelWidth
+	if len(events) < 2 || plotWidth < 2 || height < 2 {
+		return ""
+	}
+
+	start, end := events[0].Time, events[len(events)-1].Time
+	span := end.Sub(start)
+	if span <= 0 {
+		return ""
+	}
+	lo, hi := events[0].Height, events[0].Height
+	for _, e := range events {
+		lo = math.Min(lo, e.Height)
+		hi = math.Max(hi, e.Height)
+	}
+	if hi == lo {
+		hi = lo + 1
+	}
+
+	grid := make([][]byte, height)
+	for row := range grid {
+		grid[row] = []byte(strings.Repeat(" ", plotWidth))
+	}
+
+	seg := 0
+	for col := 0; col < plotWidth; col++ {
+		t := start.Add(time.Duration(float64(span) * float64(col) / float64(plotWidth-1)))
+		for seg < len(events)-2 && t.After(events[seg+1].Time) {
+			seg++
+		}
+		a, b := events[seg], events[seg+1]
+		frac := 0.0
+		if d := b.Time.Sub(a.Time); d > 0 {
+			frac = math.Max(0, math.Min(1, float64(t.Sub(a.Time))/float64(d)))
+		}
+		h := a.Height + (b.Height-a.Height)*(1-math.Cos(math.Pi*frac))/2
+		row := int(math.Round((hi - h) / (hi - lo) * float64(height-1)))
+		grid[row][col] = '*'
+	}
+
+	lines := make([]string, 0, height+2)
+	for row, cells := range grid {
+		label := ""
+		switch row {
+		case 0:
+			label = fmt.Sprintf("%.1f", hi)
+		case height - 1:
+			label = fmt.Sprintf("%.1f", lo)
+		case (height - 1) / 2:
+			label = fmt.Sprintf("%.1f", (hi+lo)/2)
+		}
+		lines = append(lines, fmt.Sprintf("%*s |", asciiChartLabelWidth-2, label)+string(cells))
+	}
+	lines = append(lines, strings.Repeat(" ", asciiChartLabelWidth-1)+"+"+strings.Repeat("-", plotWidth))
+
+	from, to := st.formatTime(start, "Jan 2 3:04 PM"), st.formatTime(end, "Jan 2 3:04 PM")
+	gap := plotWidth - len(from) - len(to)
+	if gap < 1 {
+		gap = 1
+	}
+	lines = append(lines, strings.Repeat(" ", asciiChartLabelWidth)+from+strings.Repeat(" ", gap)+to)
+	return strings.Join(lines, "\n")
+}
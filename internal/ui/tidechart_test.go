@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/stations"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
+)
+
+func TestBrailleSupportedEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{"unset", map[string]string{}, true},
+		{"UTF-8 LANG", map[string]string{"LANG": "en_US.UTF-8"}, true},
+		{"utf8 LANG", map[string]string{"LANG": "C.utf8"}, true},
+		{"C locale", map[string]string{"LANG": "C"}, false},
+		{"POSIX LC_ALL", map[string]string{"LC_ALL": "POSIX", "LANG": "en_US.UTF-8"}, false},
+		{"LC_CTYPE overrides LANG", map[string]string{"LC_CTYPE": "en_US.UTF-8", "LANG": "C"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(name string) string { return tt.env[name] }
+			if got := brailleSupportedEnv(getenv); got != tt.want {
+				t.Errorf("brailleSupportedEnv() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// testTideEvents returns a day of alternating high and low tides
+func testTideEvents() []models.TideEvent {
+	start := time.Date(2025, 11, 27, 3, 0, 0, 0, time.UTC)
+	return []models.TideEvent{
+		{Time: start, Type: models.TideHigh, Height: 9.8},
+		{Time: start.Add(6 * time.Hour), Type: models.TideLow, Height: -0.4},
+		{Time: start.Add(12 * time.Hour), Type: models.TideHigh, Height: 10.2},
+		{Time: start.Add(18 * time.Hour), Type: models.TideLow, Height: 0.1},
+	}
+}
+
+func TestRenderASCIITideChart(t *testing.T) {
+	chart := renderASCIITideChart(newStyles(DefaultTheme()), testTideEvents(), 60, 10)
+	lines := strings.Split(chart, "\n")
+	if len(lines) != 12 {
+		t.Fatalf("chart has %d lines, want 10 rows plus axis and times", len(lines))
+	}
+	for _, r := range chart {
+		if r > 127 {
+			t.Fatalf("chart contains non-ASCII %q", r)
+		}
+	}
+	if !strings.HasPrefix(lines[0], " 10.2 |") || !strings.HasPrefix(lines[9], " -0.4 |") {
+		t.Errorf("height axis should span the lowest and highest tide:\n%s", chart)
+	}
+	// The highest tide is plotted in the top row and the lowest in the bottom row
+	if !strings.Contains(lines[0], "*") || !strings.Contains(lines[9], "*") {
+		t.Errorf("curve should reach the top and bottom rows:\n%s", chart)
+	}
+	for i, line := range lines[:10] {
+		if len(line) != 60 {
+			t.Errorf("row %d is %d columns, want 60", i, len(line))
+		}
+	}
+	if !strings.Contains(lines[11], "Nov 27 3:00 AM") || !strings.Contains(lines[11], "Nov 27 9:00 PM") {
+		t.Errorf("time axis = %q", lines[11])
+	}
+
+	if renderASCIITideChart(newStyles(DefaultTheme()), testTideEvents()[:1], 60, 10) != "" {
+		t.Error("a single event can't be plotted")
+	}
+}
+
+func TestModel_ASCIIChartToggle(t *testing.T) {
+	isBraille := func(r rune) bool { return r >= 0x2800 && r <= 0x28FF }
+
+	for _, ascii := range []bool{false, true} {
+		m := NewModel("", "", "").WithASCIIChart(ascii)
+		m.state = StateDisplay
+		m.activePane = PaneTides
+		m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+		m.tideStation = &stations.TideStationInfo{ID: "8447435", Name: "Chatham"}
+		updatedModel, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 60})
+		m = updatedModel.(Model)
+		updatedModel, _ = m.Update(tideDataFetchedMsg{tides: &models.TideData{Events: testTideEvents()}})
+		m = updatedModel.(Model)
+
+		view := m.View()
+		hasBraille := strings.IndexFunc(view, isBraille) >= 0
+		if hasBraille == ascii {
+			t.Errorf("asciiChart=%v: view contains braille = %v", ascii, hasBraille)
+		}
+		if ascii && !strings.Contains(view, "+"+strings.Repeat("-", tideChartWidth(100)-asciiChartLabelWidth)) {
+			t.Error("ASCII chart should be drawn in the tides pane")
+		}
+	}
+}
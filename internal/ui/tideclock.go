@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+// tideClockRadius is the tide clock's radius in rows. Columns are half as tall as
+// rows are wide, so the face is drawn twice as wide to look round.
+const tideClockRadius = 4
+
+// tideClockAngle converts a position in the tide cycle (see models.TideData.CyclePosition)
+// to an angle clockwise from the top of the clock, in radians. High tide is at the
+// top and low tide at the bottom, so the falling tide runs down the right side and the
+// rising tide up the left, as on a tide clock.
+func tideClockAngle(position float64) float64 {
+	return 2 * math.Pi * math.Mod(position+0.5, 1)
+}
+
+// renderTideClock draws a tide clock face for now, a dotted circle with a marker at the
+// current position in the tide cycle, beside whether the tide is rising or falling and
+// when the next high or low is. It returns a note if now isn't between two predicted
+// extremes.
+func renderTideClock(st styles, tides *models.TideData, now time.Time) string {
+	position, ok := tides.CyclePosition(now)
+	if !ok {
+		return st.muted.Render("No tide cycle at the current time")
+	}
+
+	r := tideClockRadius
+	width, height := 4*r+1, 2*r+1
+	grid := make([][]string, height)
+	for row := range grid {
+		grid[row] = strings.Split(strings.Repeat(" ", width), "")
+	}
+	plot := func(angle float64, glyph string) {
+		col := 2*r + int(math.Round(2*float64(r)*math.Sin(angle)))
+		row := r - int(math.Round(float64(r)*math.Cos(angle)))
+		grid[row][col] = glyph
+	}
+	for step := 0; step < 48; step++ {
+		plot(2*math.Pi*float64(step)/48, "·")
+	}
+	plot(tideClockAngle(position), "●")
+
+	face := []string{centerText("HIGH", width)}
+	for _, cells := range grid {
+		face = append(face, strings.Join(cells, ""))
+	}
+	face = append(face, centerText("LOW", width))
+
+	state := "Rising"
+	if position >= 0.5 {
+		state = "Falling"
+	}
+	info := []string{st.value.Bold(true).Render(state)}
+	if height, err := tides.HeightAt(now); err == nil {
+		info = append(info, fmt.Sprintf("%.1f ft now", height))
+	}
+	if next := tides.NextEvent(now); next != nil {
+		info = append(info, fmt.Sprintf("%s %s (%s)", next.Type, st.formatTime(next.Time, "3:04 PM"), formatRelativeTime(next.Time, now)))
+	}
+	infoBlock := lipgloss.NewStyle().PaddingTop(height/2 - 1).PaddingLeft(3).Render(strings.Join(info, "\n"))
+
+	return st.text(lipgloss.JoinHorizontal(lipgloss.Top, st.value.Render(strings.Join(face, "\n")), infoBlock))
+}
+
+// centerText pads text with spaces to center it in width columns
+func centerText(text string, width int) string {
+	pad := max(width-len(text), 0)
+	return strings.Repeat(" ", pad/2) + text + strings.Repeat(" ", pad-pad/2)
+}
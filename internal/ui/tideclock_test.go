@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/stations"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
+)
+
+// tideCycle is a low, high and low six hours apart from base
+func tideCycle(base time.Time) *models.TideData {
+	return &models.TideData{Events: []models.TideEvent{
+		{Time: base, Type: models.TideLow, Height: 0.2},
+		{Time: base.Add(6 * time.Hour), Type: models.TideHigh, Height: 4.2},
+		{Time: base.Add(12 * time.Hour), Type: models.TideLow, Height: 0.4},
+	}}
+}
+
+func TestTideClockAngle(t *testing.T) {
+	tests := []struct {
+		name     string
+		position float64
+		want     float64
+	}{
+		{"low at the bottom", 0, math.Pi},
+		{"rising on the left", 0.25, 1.5 * math.Pi},
+		{"high at the top", 0.5, 0},
+		{"falling on the right", 0.75, 0.5 * math.Pi},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tideClockAngle(tt.position); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("tideClockAngle(%v) = %v, want %v", tt.position, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderTideClock(t *testing.T) {
+	base := time.Date(2025, 11, 26, 6, 0, 0, 0, time.UTC)
+	tides := tideCycle(base)
+	st := newStyles(DefaultTheme())
+
+	// The marker's row in the face: the label row comes first, then the top of the circle
+	markerRow := func(clock string) int {
+		for i, line := range strings.Split(clock, "\n") {
+			if strings.Contains(line, "●") {
+				return i
+			}
+		}
+		return -1
+	}
+
+	tests := []struct {
+		name    string
+		at      time.Time
+		wantRow int
+		want    []string
+	}{
+		{"low", base, 1 + 2*tideClockRadius, []string{"Rising", "0.2 ft now", "High 12:00 PM (in 6h)"}},
+		{"mid rising", base.Add(3 * time.Hour), 1 + tideClockRadius, []string{"Rising", "2.2 ft now", "High 12:00 PM (in 3h)"}},
+		{"high", base.Add(6 * time.Hour), 1, []string{"Falling", "4.2 ft now", "Low 6:00 PM (in 6h)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := renderTideClock(st, tides, tt.at)
+			if got := markerRow(clock); got != tt.wantRow {
+				t.Errorf("marker on row %d, want %d:\n%s", got, tt.wantRow, clock)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(clock, want) {
+					t.Errorf("clock should show %q:\n%s", want, clock)
+				}
+			}
+		})
+	}
+
+	if clock := renderTideClock(st, tides, base.Add(-time.Hour)); !strings.Contains(clock, "No tide cycle") {
+		t.Errorf("clock outside the predictions = %q", clock)
+	}
+	if clock := renderTideClock(st.withASCII(true), tides, base); strings.ContainsAny(clock, "●·") {
+		t.Errorf("ASCII clock should only use ASCII:\n%s", clock)
+	}
+}
+
+func TestModel_TideClockToggle(t *testing.T) {
+	base := time.Date(2025, 11, 26, 6, 0, 0, 0, time.UTC)
+	m := NewModel("", "", "").WithClock(models.FixedClock(base.Add(3 * time.Hour)))
+	m.width, m.height = 100, 60
+	m.state = StateDisplay
+	m.activePane = PaneTides
+	m.selectedZone = &zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound"}
+	m.tideStation = &stations.TideStationInfo{ID: "8447435", Name: "Chatham, Lydia Cove"}
+	m.tides = tideCycle(base)
+	m = m.rebuildTideChart()
+
+	if strings.Contains(m.View(), "HIGH") {
+		t.Error("the tide chart should be shown by default")
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+	m = updated.(Model)
+	if view := m.View(); !strings.Contains(view, "HIGH") || !strings.Contains(view, "Rising") {
+		t.Errorf("'C' should show the tide clock, got:\n%s", view)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+	if strings.Contains(updated.(Model).View(), "HIGH") {
+		t.Error("'C' again should show the chart")
+	}
+}
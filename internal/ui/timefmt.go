@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ngmaloney/marine-terminal/internal/database"
+)
+
+// Time formats times can be shown in, stored as the database.SettingTimeFormat setting
+const (
+	timeFormat12h = "12h"
+	timeFormat24h = "24h"
+)
+
+// Layouts are written with clock12h and shown with clock24h in its place in 24-hour mode
+const (
+	clock12h = "3:04 PM"
+	clock24h = "15:04"
+)
+
+// with24HourClock returns a copy of the styles that shows times on a 24-hour clock
+// when enabled
+func (s styles) with24HourClock(enabled bool) styles {
+	s.clock24h = enabled
+	return s
+}
+
+// formatTime formats t with layout, which is written with a 12-hour clock (e.g.
+// "Jan 2, 3:04 PM"). Alerts, tides and forecasts all show times through it so they
+// follow the chosen time format.
+func (s styles) formatTime(t time.Time, layout string) string {
+	if s.clock24h {
+		layout = strings.Replace(layout, clock12h, clock24h, 1)
+	}
+	return t.Format(layout)
+}
+
+// timeFormat returns the name of the time format the styles show times in
+func (s styles) timeFormat() string {
+	if s.clock24h {
+		return timeFormat24h
+	}
+	return timeFormat12h
+}
+
+// saveTimeFormat stores the chosen time format in repo so it's used after a restart
+func saveTimeFormat(repo *database.SettingsRepository, format string) tea.Cmd {
+	return func() tea.Msg {
+		_ = repo.Set(database.SettingTimeFormat, format)
+		return nil
+	}
+}
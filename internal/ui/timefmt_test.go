@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ngmaloney/marine-terminal/internal/database"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+func TestModel_ClockFormatToggle(t *testing.T) {
+	settings := database.NewSettingsRepository(filepath.Join(t.TempDir(), "settings.db"))
+	event := &models.TideEvent{Time: time.Date(2025, 11, 27, 15, 4, 0, 0, time.UTC), Type: "High", Height: 9.2}
+
+	m := NewModel("", "", "").WithSettings(settings)
+	m.state = StateDisplay
+	if got := summaryTide(m.styles, event); !strings.HasSuffix(got, "at 3:04 PM") {
+		t.Errorf("summaryTide() = %q, want a 12-hour time by default", got)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	m = updated.(Model)
+	if got := summaryTide(m.styles, event); !strings.HasSuffix(got, "at 15:04") {
+		t.Errorf("summaryTide() after T = %q, want a 24-hour time", got)
+	}
+	if got := m.styles.formatTime(event.Time, "Mon Jan 2, 3:04 PM"); got != "Thu Nov 27, 15:04" {
+		t.Errorf("formatTime() = %q, want Thu Nov 27, 15:04", got)
+	}
+	if cmd == nil {
+		t.Fatal("T should save the time format")
+	}
+	cmd()
+
+	// The choice is used again after a restart, and survives a theme change
+	restarted := NewModel("", "", "").WithSettings(settings).WithTheme(DefaultTheme())
+	if got := summaryTide(restarted.styles, event); !strings.HasSuffix(got, "at 15:04") {
+		t.Errorf("summaryTide() after a restart = %q, want the saved 24-hour time", got)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	if got := summaryTide(updated.(Model).styles, event); !strings.HasSuffix(got, "at 3:04 PM") {
+		t.Errorf("summaryTide() after T again = %q, want a 12-hour time", got)
+	}
+}
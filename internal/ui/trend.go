@@ -0,0 +1,45 @@
+package ui
+
+import "github.com/ngmaloney/marine-terminal/internal/models"
+
+// Trend arrows annotating how a forecast period compares with the one before it
+const (
+	trendBuilding   = "↑"
+	trendSubsiding  = "↓"
+	trendHoldSteady = "→"
+	trendShifted    = "↻" // The wind changed direction at the same speed
+)
+
+// trendArrow compares two min/max ranges, ranking them by their maximum and then
+// their minimum. It returns "" if either range is missing (both values zero).
+func trendArrow(prevMin, prevMax, curMin, curMax float64) string {
+	if (prevMin <= 0 && prevMax <= 0) || (curMin <= 0 && curMax <= 0) {
+		return ""
+	}
+	switch {
+	case curMax > prevMax, curMax == prevMax && curMin > prevMin:
+		return trendBuilding
+	case curMax < prevMax, curMax == prevMax && curMin < prevMin:
+		return trendSubsiding
+	default:
+		return trendHoldSteady
+	}
+}
+
+// windTrend reports whether the wind is building or easing from prev to cur
+func windTrend(prev, cur models.WindData) string {
+	return trendArrow(prev.SpeedMin, prev.SpeedMax, cur.SpeedMin, cur.SpeedMax)
+}
+
+// seasTrend reports whether seas are building or subsiding from prev to cur
+func seasTrend(prev, cur models.SeaState) string {
+	return trendArrow(prev.HeightMin, prev.HeightMax, cur.HeightMin, cur.HeightMax)
+}
+
+// withTrend appends a trend arrow to a formatted value, if there is one
+func withTrend(value, arrow string) string {
+	if arrow == "" {
+		return value
+	}
+	return value + " " + arrow
+}
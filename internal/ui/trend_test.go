@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+func TestTrendArrow(t *testing.T) {
+	tests := []struct {
+		name                             string
+		prevMin, prevMax, curMin, curMax float64
+		want                             string
+	}{
+		{"building max", 10, 15, 15, 20, trendBuilding},
+		{"building min only", 10, 20, 15, 20, trendBuilding},
+		{"subsiding max", 15, 20, 10, 15, trendSubsiding},
+		{"subsiding min only", 15, 20, 10, 20, trendSubsiding},
+		{"steady", 10, 15, 10, 15, trendHoldSteady},
+		{"missing previous", 0, 0, 10, 15, ""},
+		{"missing current", 10, 15, 0, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trendArrow(tt.prevMin, tt.prevMax, tt.curMin, tt.curMax); got != tt.want {
+				t.Errorf("trendArrow() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// periodLine returns the forecast line for the named period
+func periodLine(t *testing.T, out, name string) string {
+	t.Helper()
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, name+":") {
+			return line
+		}
+	}
+	t.Fatalf("no forecast line for %s in:\n%s", name, out)
+	return ""
+}
+
+func TestFormatWeather_Trends(t *testing.T) {
+	forecastOf := func(wind, seas [][2]float64) *models.ThreeDayForecast {
+		f := &models.ThreeDayForecast{}
+		for i := range wind {
+			f.Periods = append(f.Periods, models.MarineForecast{
+				PeriodName: fmt.Sprintf("P%d", i),
+				Wind:       models.WindData{Direction: "SW", SpeedMin: wind[i][0], SpeedMax: wind[i][1]},
+				Seas:       models.SeaState{HeightMin: seas[i][0], HeightMax: seas[i][1]},
+			})
+		}
+		return f
+	}
+	// Thresholds are disabled so every period renders the same way
+	noSmallCraft := models.SmallCraftThresholds{}
+
+	t.Run("building", func(t *testing.T) {
+		f := forecastOf([][2]float64{{5, 10}, {10, 15}, {15, 20}}, [][2]float64{{1, 2}, {2, 3}, {3, 5}})
+		out := formatWeather(newStyles(DefaultTheme()), nil, f, noSmallCraft, 0, false, conditionChanges{})
+		for _, name := range []string{"P1", "P2"} {
+			if line := periodLine(t, out, name); strings.Count(line, trendBuilding) != 2 {
+				t.Errorf("%s should show wind and seas building: %q", name, line)
+			}
+		}
+	})
+
+	t.Run("subsiding", func(t *testing.T) {
+		f := forecastOf([][2]float64{{20, 25}, {15, 20}, {10, 15}}, [][2]float64{{5, 7}, {3, 5}, {2, 3}})
+		out := formatWeather(newStyles(DefaultTheme()), nil, f, noSmallCraft, 0, false, conditionChanges{})
+		for _, name := range []string{"P1", "P2"} {
+			if line := periodLine(t, out, name); strings.Count(line, trendSubsiding) != 2 {
+				t.Errorf("%s should show wind and seas subsiding: %q", name, line)
+			}
+		}
+	})
+
+	t.Run("missing data", func(t *testing.T) {
+		f := forecastOf([][2]float64{{10, 15}, {10, 15}, {15, 20}}, [][2]float64{{2, 3}, {0, 0}, {3, 4}})
+		out := formatWeather(newStyles(DefaultTheme()), nil, f, noSmallCraft, 0, false, conditionChanges{})
+		p1, p2 := periodLine(t, out, "P1"), periodLine(t, out, "P2")
+		if !strings.Contains(p1, "kt "+trendHoldSteady) || strings.Contains(p1, "ft "+trendSubsiding) {
+			t.Errorf("P1 should show steady wind and no seas arrow: %q", p1)
+		}
+		if !strings.Contains(p2, "kt "+trendBuilding) || strings.Contains(p2, "ft "+trendBuilding) {
+			t.Errorf("P2 should show building wind and no seas arrow after a gap: %q", p2)
+		}
+	})
+}
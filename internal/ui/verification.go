@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+// formatObservedComparison lines the current forecast period up against what a nearby
+// station is observing (e.g. "Forecast wind SW 10-15 kt | Observed SW 12 kt"), to show
+// how the forecast is verifying. Tide stations don't measure seas, so only wind and air
+// temperature present in both are compared; it returns "" when there are none.
+func formatObservedComparison(st styles, forecast, observed *models.MarineConditions, source string) string {
+	if forecast == nil || observed == nil {
+		return ""
+	}
+
+	var lines []string
+	if forecast.Wind.Direction != "" && observed.Wind.Direction != "" {
+		lines = append(lines, fmt.Sprintf("Forecast wind %s | Observed %s", formatWind(forecast.Wind), formatWind(observed.Wind)))
+	}
+	if forecast.Temperature != 0 && observed.Temperature != 0 {
+		lines = append(lines, st.text(fmt.Sprintf("Forecast air %.0f°F | Observed %.1f°F", forecast.Temperature, observed.Temperature)))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	heading := st.text("🔭 Forecast vs Observed")
+	if source != "" {
+		heading += " (" + source + ")"
+	}
+	for i, line := range lines {
+		lines[i] = st.muted.Render("  " + line)
+	}
+	return st.label.Render(heading+":") + "\n" + strings.Join(lines, "\n")
+}
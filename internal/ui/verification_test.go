@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ngmaloney/marine-terminal/internal/models"
+)
+
+func TestFormatObservedComparison(t *testing.T) {
+	forecast := &models.MarineConditions{
+		Wind: models.WindData{Direction: "SW", SpeedMin: 10, SpeedMax: 15},
+		Seas: models.SeaState{HeightMin: 5, HeightMax: 7},
+	}
+	observedWind := models.WindData{Direction: "SW", SpeedMin: 12, SpeedMax: 12, GustSpeed: 16, HasGust: true}
+
+	tests := []struct {
+		name     string
+		forecast *models.MarineConditions
+		observed *models.MarineConditions
+		want     []string
+		notWant  []string
+	}{
+		{
+			name:     "wind",
+			forecast: forecast,
+			observed: &models.MarineConditions{Wind: observedWind, Temperature: 48.2},
+			want:     []string{"Forecast wind SW 10-15 kt | Observed SW 12 kt, gusts 16 kt"},
+			notWant:  []string{"seas", "air"},
+		},
+		{
+			name:     "forecast without wind",
+			forecast: &models.MarineConditions{Seas: models.SeaState{HeightMin: 2, HeightMax: 2}, Temperature: 55},
+			observed: &models.MarineConditions{Wind: observedWind, Temperature: 53.2},
+			want:     []string{"Forecast air 55°F | Observed 53.2°F"},
+			notWant:  []string{"wind", "seas"},
+		},
+		{
+			name:     "air temperature",
+			forecast: &models.MarineConditions{Temperature: 55},
+			observed: &models.MarineConditions{Temperature: 53.2},
+			want:     []string{"Forecast air 55°F | Observed 53.2°F"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := formatObservedComparison(newStyles(DefaultTheme()), tt.forecast, tt.observed, "Chatham")
+			if !strings.Contains(out, "Forecast vs Observed (Chatham)") {
+				t.Errorf("output should name the observing station, got:\n%s", out)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output missing %q, got:\n%s", want, out)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(out, "Forecast "+notWant) {
+					t.Errorf("output should not compare %s, got:\n%s", notWant, out)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatObservedComparison_NothingInCommon(t *testing.T) {
+	st := newStyles(DefaultTheme())
+	forecast := &models.MarineConditions{Seas: models.SeaState{HeightMin: 3, HeightMax: 5}}
+	observed := &models.MarineConditions{Pressure: 1016.4, WaterTemperature: 51}
+
+	if out := formatObservedComparison(st, forecast, observed, "Chatham"); out != "" {
+		t.Errorf("formatObservedComparison() = %q, want empty with no shared fields", out)
+	}
+	if out := formatObservedComparison(st, nil, observed, "Chatham"); out != "" {
+		t.Errorf("formatObservedComparison() = %q, want empty without a forecast", out)
+	}
+	if out := formatObservedComparison(st, forecast, nil, "Chatham"); out != "" {
+		t.Errorf("formatObservedComparison() = %q, want empty without observations", out)
+	}
+}
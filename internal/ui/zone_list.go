@@ -27,22 +27,122 @@ func (z zoneItem) Description() string {
 	return "" // Empty to avoid duplicate display
 }
 
-// createZoneList creates a list.Model from zone info
-func createZoneList(zones []zonelookup.ZoneInfo, width, height int) list.Model {
-	items := make([]list.Item, len(zones))
-	for i, zone := range zones {
-		items[i] = zoneItem{zone: zone}
+// zoneHeaderItem heads a group of zones of one type in the zone list. It can't be
+// selected, and filtering leaves it out.
+type zoneHeaderItem struct {
+	zoneType zonelookup.ZoneType
+	st       styles
+}
+
+// FilterValue implements list.Item
+func (h zoneHeaderItem) FilterValue() string {
+	return ""
+}
+
+// Title implements list.DefaultItem
+func (h zoneHeaderItem) Title() string {
+	return h.st.text("── " + h.zoneType.Label() + " ──")
+}
+
+// Description implements list.DefaultItem
+func (h zoneHeaderItem) Description() string {
+	return ""
+}
+
+// DefaultZoneAutoSelectMiles is how close the only nearby zone must be to be selected
+// without showing the zone list
+const DefaultZoneAutoSelectMiles = 10.0
+
+// autoSelectZone returns the zone to select without showing the list: the only zone
+// found, or the only one within withinMiles. It returns false when several zones are
+// plausible, or when withinMiles is 0, which turns auto-selection off.
+func autoSelectZone(zones []zonelookup.ZoneInfo, withinMiles float64) (zonelookup.ZoneInfo, bool) {
+	if withinMiles <= 0 || len(zones) == 0 {
+		return zonelookup.ZoneInfo{}, false
 	}
+	if len(zones) == 1 {
+		return zones[0], true
+	}
+	var nearby []zonelookup.ZoneInfo
+	for _, zone := range zones {
+		if zone.Distance < withinMiles {
+			nearby = append(nearby, zone)
+		}
+	}
+	if len(nearby) != 1 {
+		return zonelookup.ZoneInfo{}, false
+	}
+	return nearby[0], true
+}
+
+// zoneListItems lists zones nearest first. When both coastal and offshore zones are
+// nearby they're grouped by type under headers, preferred's group first.
+func zoneListItems(zones []zonelookup.ZoneInfo, preferred zonelookup.ZoneType, st styles) []list.Item {
+	groups := make(map[zonelookup.ZoneType][]zonelookup.ZoneInfo)
+	for _, zone := range zones {
+		groups[zone.Type()] = append(groups[zone.Type()], zone)
+	}
+
+	var items []list.Item
+	if len(groups) < 2 {
+		for _, zone := range zones {
+			items = append(items, zoneItem{zone: zone})
+		}
+		return items
+	}
+
+	order := []zonelookup.ZoneType{zonelookup.ZoneTypeCoastal, zonelookup.ZoneTypeOffshore}
+	if preferred == zonelookup.ZoneTypeOffshore {
+		order[0], order[1] = order[1], order[0]
+	}
+	for _, zoneType := range order {
+		items = append(items, zoneHeaderItem{zoneType: zoneType, st: st})
+		for _, zone := range groups[zoneType] {
+			items = append(items, zoneItem{zone: zone})
+		}
+	}
+	return items
+}
+
+// createZoneList creates a list.Model from zone info, grouped by type with preferred's
+// zones first when both types are nearby
+func createZoneList(zones []zonelookup.ZoneInfo, preferred zonelookup.ZoneType, width, height int, st styles) list.Model {
+	items := zoneListItems(zones, preferred, st)
 
 	delegate := list.NewDefaultDelegate()
 	delegate.SetHeight(1) // Only title line needed now
 	delegate.SetSpacing(0)
 	delegate.ShowDescription = false // Don't show description since it's empty
 
-	l := list.New(items, delegate, width, height)
+	l := st.newList(items, delegate, width, height)
 	l.Title = "Select a Marine Zone"
 	l.SetShowHelp(true)
-	l.SetFilteringEnabled(false)
+	l.SetFilteringEnabled(true)
+	// '?' opens the application-wide help overlay instead of the list's full help
+	l.KeyMap.ShowFullHelp.SetEnabled(false)
+	l.KeyMap.CloseFullHelp.SetEnabled(false)
+
+	// Start on the first zone rather than a group header
+	return skipZoneHeader(l, -1)
+}
 
+// skipZoneHeader moves the zone list's cursor off a group header onto a zone: on past
+// it in the direction the cursor moved from prev, or the other way at either end of
+// the list
+func skipZoneHeader(l list.Model, prev int) list.Model {
+	if _, ok := l.SelectedItem().(zoneHeaderItem); !ok {
+		return l
+	}
+	i, n := l.Index(), len(l.VisibleItems())
+	step := 1
+	if i < prev {
+		step = -1
+	}
+	if i+step < 0 || i+step >= n {
+		step = -step
+	}
+	if i+step >= 0 && i+step < n {
+		l.Select(i + step)
+	}
 	return l
 }
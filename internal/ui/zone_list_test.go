@@ -0,0 +1,265 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ngmaloney/marine-terminal/internal/models"
+	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
+)
+
+// zoneListTitles lists the titles of a zone list's items, headers included
+func zoneListTitles(items []list.Item) []string {
+	var titles []string
+	for _, item := range items {
+		switch item := item.(type) {
+		case zoneHeaderItem:
+			titles = append(titles, item.zoneType.Label())
+		case zoneItem:
+			titles = append(titles, item.zone.Code)
+		}
+	}
+	return titles
+}
+
+func TestZoneListItems(t *testing.T) {
+	coastal1 := zonelookup.ZoneInfo{Code: "ANZ254", Name: "Coastal waters from Provincetown MA to Chatham MA out 20 nm", Distance: 2}
+	offshore := zonelookup.ZoneInfo{Code: "ANZ270", Name: "Waters from Currituck Beach Light NC to Oregon Inlet NC from 20 to 40 nm", Distance: 5}
+	coastal2 := zonelookup.ZoneInfo{Code: "ANZ232", Name: "Nantucket Sound", Distance: 8}
+	mixed := []zonelookup.ZoneInfo{coastal1, offshore, coastal2}
+
+	tests := []struct {
+		name      string
+		zones     []zonelookup.ZoneInfo
+		preferred zonelookup.ZoneType
+		want      []string
+	}{
+		{"coastal preferred", mixed, zonelookup.ZoneTypeCoastal, []string{"Coastal waters", "ANZ254", "ANZ232", "Offshore waters", "ANZ270"}},
+		{"offshore preferred", mixed, zonelookup.ZoneTypeOffshore, []string{"Offshore waters", "ANZ270", "Coastal waters", "ANZ254", "ANZ232"}},
+		{"one type isn't grouped", []zonelookup.ZoneInfo{coastal1, coastal2}, zonelookup.ZoneTypeOffshore, []string{"ANZ254", "ANZ232"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := zoneListTitles(zoneListItems(tt.zones, tt.preferred, newStyles(DefaultTheme())))
+			if len(got) != len(tt.want) {
+				t.Fatalf("items = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("items = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	// The list starts on the first zone, not its header, and enter on a header does nothing
+	m := NewModel("", "", "")
+	m.width, m.height = 100, 40
+	m.zones = mixed
+	m.zoneList = createZoneList(mixed, zonelookup.ZoneTypeOffshore, 96, 30, m.styles)
+	m.state = StateZoneList
+	if item, ok := m.zoneList.SelectedItem().(zoneItem); !ok || item.zone.Code != "ANZ270" {
+		t.Errorf("SelectedItem() = %v, want ANZ270", m.zoneList.SelectedItem())
+	}
+	m.zoneList.Select(2) // The coastal header
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m = updated.(Model); m.state != StateZoneList || m.selectedZone != nil {
+		t.Errorf("enter on a header = state %v, zone %v, want nothing selected", m.state, m.selectedZone)
+	}
+
+	// Moving through the list steps over headers, and up from the first zone stays put
+	m.zoneList.Select(1)
+	for _, step := range []struct {
+		key  tea.KeyType
+		want string
+	}{{tea.KeyDown, "ANZ254"}, {tea.KeyUp, "ANZ270"}, {tea.KeyUp, "ANZ270"}} {
+		updated, _ = m.Update(tea.KeyMsg{Type: step.key})
+		m = updated.(Model)
+		if item, ok := m.zoneList.SelectedItem().(zoneItem); !ok || item.zone.Code != step.want {
+			t.Errorf("after %s SelectedItem() = %v, want %s", step.key, m.zoneList.SelectedItem(), step.want)
+		}
+	}
+
+	// Headers are drawn in ASCII in ASCII mode
+	header := zoneHeaderItem{zoneType: zonelookup.ZoneTypeCoastal, st: newStyles(DefaultTheme()).withASCII(true)}
+	if got := header.Title(); got != "-- Coastal waters --" {
+		t.Errorf("Title() in ASCII mode = %q, want %q", got, "-- Coastal waters --")
+	}
+}
+
+func TestModel_PreferredZoneType(t *testing.T) {
+	m := NewModel("", "", "")
+	m.savedPorts = []models.Port{
+		{Name: "Outer Banks", Zipcode: "27959", PreferredZoneType: "offshore"},
+		{Name: "Chatham", City: "Chatham", State: "MA", PreferredZoneType: "coastal"},
+	}
+
+	tests := []struct {
+		query   string
+		current *models.Port
+		want    zonelookup.ZoneType
+	}{
+		{"27959", nil, zonelookup.ZoneTypeOffshore},
+		{"chatham, ma", nil, zonelookup.ZoneTypeCoastal},
+		{"02601", &models.Port{PreferredZoneType: "offshore"}, zonelookup.ZoneTypeOffshore},
+		{"02601", nil, zonelookup.ZoneTypeCoastal},
+		// A loaded port's query is its display name; the port itself decides, not
+		// another saved port that shares the name
+		{"Chatham, MA", &models.Port{Name: "Chatham", City: "Chatham", State: "MA", PreferredZoneType: "offshore"}, zonelookup.ZoneTypeOffshore},
+		{"27959", &models.Port{Name: "Kill Devil Hills", Zipcode: "27959"}, zonelookup.ZoneTypeCoastal},
+	}
+	for _, tt := range tests {
+		m.searchQuery = tt.query
+		m.currentPort = tt.current
+		if got := m.preferredZoneType(); got != tt.want {
+			t.Errorf("preferredZoneType() for %q = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestAutoSelectZone(t *testing.T) {
+	near := zonelookup.ZoneInfo{Code: "ANZ254", Name: "Nantucket Sound", Distance: 3}
+	farther := zonelookup.ZoneInfo{Code: "ANZ255", Name: "Vineyard Sound", Distance: 14}
+	alsoNear := zonelookup.ZoneInfo{Code: "ANZ232", Name: "Cape Cod Bay", Distance: 7}
+
+	tests := []struct {
+		name   string
+		zones  []zonelookup.ZoneInfo
+		within float64
+		want   string // "" if the list should be shown
+	}{
+		{"single zone", []zonelookup.ZoneInfo{farther}, DefaultZoneAutoSelectMiles, "ANZ255"},
+		{"only one close zone", []zonelookup.ZoneInfo{near, farther}, DefaultZoneAutoSelectMiles, "ANZ254"},
+		{"several close zones", []zonelookup.ZoneInfo{near, alsoNear, farther}, DefaultZoneAutoSelectMiles, ""},
+		{"no close zones", []zonelookup.ZoneInfo{farther, {Code: "ANZ256", Distance: 20}}, DefaultZoneAutoSelectMiles, ""},
+		{"disabled", []zonelookup.ZoneInfo{near}, 0, ""},
+		{"no zones", nil, DefaultZoneAutoSelectMiles, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone, ok := autoSelectZone(tt.zones, tt.within)
+			if got := zone.Code; ok != (tt.want != "") || got != tt.want {
+				t.Errorf("autoSelectZone() = %q, %v; want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestModel_ZonesFoundAutoSelectsSingleZone(t *testing.T) {
+	m := NewModel("", "", "")
+	m.width, m.height = 100, 40
+	m.searchQuery = "02633"
+	m.state = StateLoading
+
+	updated, _ := m.Update(zonesFoundMsg{zones: []zonelookup.ZoneInfo{
+		{Code: "ANZ254", Name: "Nantucket Sound", Distance: 3.2},
+	}, radius: zoneSearchRadiusMiles})
+	m = updated.(Model)
+	if m.state != StateSavePrompt || m.selectedZone == nil || m.selectedZone.Code != "ANZ254" {
+		t.Fatalf("state = %v, zone = %v, want the lone zone selected and the save prompt shown", m.state, m.selectedZone)
+	}
+	if m.saveInput.Value() != "02633" {
+		t.Errorf("port name defaults to %q, want the search", m.saveInput.Value())
+	}
+
+	// With auto-selection off the list is shown, even for a single zone
+	m = NewModel("", "", "").WithZoneAutoSelect(0)
+	updated, _ = m.Update(zonesFoundMsg{zones: []zonelookup.ZoneInfo{{Code: "ANZ254", Distance: 3.2}}})
+	if m = updated.(Model); m.state != StateZoneList {
+		t.Errorf("state = %v with auto-selection off, want StateZoneList", m.state)
+	}
+}
+
+func TestModel_SavedPortsKeyFromZoneList(t *testing.T) {
+	loaded := models.Port{Name: "Stage Harbor", MarineZoneID: "ANZ254", Latitude: 41.66, Longitude: -69.96, Zipcode: "02633"}
+
+	m := NewModel("", "", "")
+	m.width, m.height = 100, 40
+	m.savedPorts = []models.Port{loaded}
+	m.portList = createPortList(m.savedPorts, m.width-4, m.height-10, m.styles)
+	m, _ = m.loadPort(loaded)
+	m.zones = []zonelookup.ZoneInfo{
+		{Code: "ANZ232", Name: "Buzzards Bay", Distance: 4.1},
+		{Code: "ANZ235", Name: "Rhode Island Sound", Distance: 9.8},
+	}
+	m.zoneList = createZoneList(m.zones, zonelookup.ZoneTypeCoastal, 80, 20, m.styles)
+	m.state = StateZoneList
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	m = updated.(Model)
+	if m.state != StateSavedPorts {
+		t.Fatalf("state = %v after 'P' in the zone list, want StateSavedPorts", m.state)
+	}
+
+	// Esc returns to the zone list, with the loaded port kept
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.state != StateZoneList || m.currentPort == nil || m.currentPort.Name != loaded.Name {
+		t.Errorf("state = %v, currentPort = %v after esc; want the zone list with %s kept", m.state, m.currentPort, loaded.Name)
+	}
+
+	// 'P' is filter text while filtering the zone list
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	m = updated.(Model)
+	if m.state != StateZoneList || m.zoneList.FilterValue() != "P" {
+		t.Errorf("state = %v, filter = %q; want 'P' typed into the filter", m.state, m.zoneList.FilterValue())
+	}
+
+	// On the display 'p' re-provisions an empty tide station table and 'P' still opens the list
+	m.state = StateDisplay
+	m.tideStationsEmpty = true
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	if got := updated.(Model); got.state != StateSavedPorts {
+		t.Errorf("state = %v after 'P' with no tide stations, want StateSavedPorts", got.state)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if m = updated.(Model); m.state != StateProvisioning {
+		t.Errorf("state = %v after 'p' with no tide stations, want StateProvisioning", m.state)
+	}
+}
+
+func TestModel_SavedPortsKeyWhileLoading(t *testing.T) {
+	// A search still loading would pull the user out of the list when it lands
+	m := NewModel("", "", "")
+	m.width, m.height = 100, 40
+	m.state = StateLoading
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	if m = updated.(Model); m.state != StateLoading || cmd != nil {
+		t.Fatalf("state = %v after 'P' during a search, want it ignored", m.state)
+	}
+
+	// As after starting with --port: a port is loading and the saved ports were never fetched
+	ports := []models.Port{{Name: "Stage Harbor", MarineZoneID: "ANZ254"}, {Name: "Woods Hole", MarineZoneID: "ANZ232"}}
+	m, _ = m.loadPort(ports[1])
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	m = updated.(Model)
+	if m.state != StateSavedPorts || cmd == nil {
+		t.Fatalf("state = %v, cmd = %v after 'P'; want the saved ports list with the ports being fetched", m.state, cmd)
+	}
+	// Moving through the list before the ports arrive must not panic
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+
+	// The fetched ports fill in the list instead of loading the first port
+	updated, _ = m.Update(portsFetchedMsg{ports: ports})
+	m = updated.(Model)
+	if m.state != StateSavedPorts || len(m.portList.Items()) != len(ports) {
+		t.Fatalf("state = %v, %d ports listed; want the list showing %d ports", m.state, len(m.portList.Items()), len(ports))
+	}
+
+	// The port's zone load landing leaves the list open
+	updated, _ = m.Update(zoneLoadMsg{load: m.zoneLoad, part: zoneAlertsFetchedMsg{alerts: &models.AlertData{}}})
+	if m = updated.(Model); m.state != StateSavedPorts {
+		t.Fatalf("state = %v after the port's alerts arrived, want StateSavedPorts", m.state)
+	}
+
+	// Esc shows the port as it loads
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = updated.(Model); m.state != StateDisplay {
+		t.Errorf("state = %v after esc from a loading port, want StateDisplay", m.state)
+	}
+}
@@ -2,7 +2,7 @@ package ui
 
 import (
 	"context"
-	"fmt"
+	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -10,6 +10,7 @@ import (
 	"github.com/ngmaloney/marine-terminal/internal/geocoding"
 	"github.com/ngmaloney/marine-terminal/internal/models"
 	"github.com/ngmaloney/marine-terminal/internal/noaa"
+	"github.com/ngmaloney/marine-terminal/internal/provision"
 	"github.com/ngmaloney/marine-terminal/internal/stations"
 	"github.com/ngmaloney/marine-terminal/internal/zonelookup"
 )
@@ -20,19 +21,56 @@ type geocodeMsg struct {
 	err      error
 }
 
+// newerEditionMsg is sent when a newer marine zones shapefile edition has been published
+type newerEditionMsg struct {
+	edition string
+}
+
+// hereLocatedMsg is sent when the approximate current position has been determined
+type hereLocatedMsg struct {
+	location *geocoding.Location
+	err      error
+}
+
 // zonesFoundMsg is sent when nearby zones are found
 type zonesFoundMsg struct {
-	zones []zonelookup.ZoneInfo
-	err   error
+	zones   []zonelookup.ZoneInfo
+	radius  float64 // Search radius in miles
+	dbEmpty bool    // True when no zones were found because the zones table is empty
+	err     error
+}
+
+// zoneCodeFoundMsg is sent when a zone is looked up directly by its code
+type zoneCodeFoundMsg struct {
+	zone *zonelookup.ZoneInfo
+	err  error
 }
 
 // zoneWeatherFetchedMsg is sent when weather data for a zone is fetched
 type zoneWeatherFetchedMsg struct {
 	conditions *models.MarineConditions
 	forecast   *models.ThreeDayForecast
+	source     string // Set when the data didn't come from the zone's marine forecast
 	err        error
 }
 
+// landForecastSource marks weather taken from the point forecast because the zone
+// has no marine text product
+const landForecastSource = "land forecast (no marine product)"
+
+// zoneBoundaryMsg is sent when the location has been measured against a zone's polygon
+type zoneBoundaryMsg struct {
+	zoneCode string
+	distance *zonelookup.BoundaryDistance
+	err      error
+}
+
+// zoneNameMsg is sent when a zone's name has been looked up by its code
+type zoneNameMsg struct {
+	zoneCode string
+	name     string
+}
+
 // zoneAlertsFetchedMsg is sent when alerts for a zone are fetched
 type zoneAlertsFetchedMsg struct {
 	alerts *models.AlertData
@@ -40,9 +78,9 @@ type zoneAlertsFetchedMsg struct {
 }
 
 // geocodeLocation performs geocoding in the background
-func geocodeLocation(geocoder *geocoding.Geocoder, query string) tea.Cmd {
+func geocodeLocation(geocoder geocoding.Geocoder, timeout time.Duration, query string) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
 
 		location, err := geocoder.Geocode(ctx, query)
@@ -50,11 +88,59 @@ func geocodeLocation(geocoder *geocoding.Geocoder, query string) tea.Cmd {
 	}
 }
 
-// findNearbyZones finds marine zones near a location
-func findNearbyZones(lat, lon float64) tea.Cmd {
+// locateHere determines the approximate current position using locator, giving up after
+// timeout, retries included
+func locateHere(locator geocoding.Locator, timeout time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		location, err := locator.Locate(ctx)
+		return hereLocatedMsg{location: location, err: err}
+	}
+}
+
+// Marine zone search radii. A search that finds nothing can be widened a step at a
+// time from the error screen, up to maxZoneSearchRadiusMiles.
+const (
+	zoneSearchRadiusMiles    = 50.0
+	zoneSearchRadiusStep     = 50.0
+	maxZoneSearchRadiusMiles = 250.0
+)
+
+// findNearbyZones finds marine zones within radius miles of a location
+func findNearbyZones(lat, lon, radius float64) tea.Cmd {
+	return func() tea.Msg {
+		zones, err := zonelookup.GetNearbyMarineZones(database.DBPath(), lat, lon, radius)
+		if err == nil && len(zones) == 0 {
+			empty, _ := zonelookup.IsEmpty(database.DBPath())
+			return zonesFoundMsg{zones: zones, radius: radius, dbEmpty: empty}
+		}
+		return zonesFoundMsg{zones: zones, radius: radius, err: err}
+	}
+}
+
+// lookupZoneName looks up the NWS name of a zone, which alerts list among their areas.
+// It's a variable so tests can avoid the zones database.
+var lookupZoneName = func(code string) tea.Cmd {
 	return func() tea.Msg {
-		zones, err := zonelookup.GetNearbyMarineZones(database.DBPath(), lat, lon, 50.0)
-		return zonesFoundMsg{zones: zones, err: err}
+		zone, err := zonelookup.GetZoneInfoByCode(database.DBPath(), code)
+		if err != nil {
+			return nil
+		}
+		return zoneNameMsg{zoneCode: zone.Code, name: zone.Name}
+	}
+}
+
+// nearbyZoneSearch is the zone search used by the model. It's a variable so tests
+// can avoid the zones database.
+var nearbyZoneSearch = findNearbyZones
+
+// lookupZoneByCode looks up a marine zone directly by its code
+func lookupZoneByCode(code string) tea.Cmd {
+	return func() tea.Msg {
+		zone, err := zonelookup.GetZoneInfoByCode(database.DBPath(), code)
+		return zoneCodeFoundMsg{zone: zone, err: err}
 	}
 }
 
@@ -62,45 +148,128 @@ func findNearbyZones(lat, lon float64) tea.Cmd {
 func findNearestTideStation(lat, lon float64) tea.Cmd {
 	return func() tea.Msg {
 		// Use 30-mile radius to find truly nearby stations (reduced from 100)
-		stations, err := stations.FindNearbyStations(database.DBPath(), lat, lon, 30.0)
-		return tideStationFoundMsg{stations: stations, err: err}
+		found, err := stations.FindNearbyStations(database.DBPath(), lat, lon, 30.0)
+		if err != nil {
+			empty, _ := stations.IsEmpty(database.DBPath())
+			return tideStationFoundMsg{err: err, dbEmpty: empty}
+		}
+		return tideStationFoundMsg{stations: found}
 	}
 }
 
-// fetchZoneWeather fetches weather data for a marine zone
-func fetchZoneWeather(client noaa.WeatherClient, zoneCode string) tea.Cmd {
+// nearestTideStation is the tide station lookup used by loadZoneData. It's a
+// variable so tests can avoid the station database.
+var nearestTideStation = findNearestTideStation
+
+// zoneLoadTimeout bounds a whole zone load, including the tide data fetched once its
+// tide station has been found. Each request is bounded by the clients' httpTimeout, and
+// the tide data is requested after the station lookup, so the load gets half as long again.
+func zoneLoadTimeout(httpTimeout time.Duration) time.Duration {
+	return httpTimeout * 3 / 2
+}
+
+// zoneLoadMsg carries one part of a zone load (its forecast, alerts or nearest tide
+// station) as soon as that part arrives
+type zoneLoadMsg struct {
+	load int // Sequence number of the load, so parts of a superseded one are dropped
+	part tea.Msg
+}
+
+// zoneLoadPart delivers cmd's message as a part of zone load number load
+func zoneLoadPart(load int, cmd tea.Cmd) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+		return zoneLoadMsg{load: load, part: cmd()}
+	}
+}
+
+// loadZoneData fetches a zone's forecast, the alerts for alertZones and finds the nearest
+// tide station in parallel under the shared context ctx, delivering each part as it
+// arrives. The station carries ctx and load along so its tide data is fetched under the
+// same context and dropped with the rest of the load once superseded.
+func loadZoneData(ctx context.Context, load int, weather noaa.WeatherClient, alerts noaa.AlertClient, zoneCode string, alertZones []string, location *geocoding.Location) tea.Cmd {
+	return tea.Batch(
+		zoneLoadPart(load, fetchZoneWeather(ctx, weather, zoneCode, location)),
+		zoneLoadPart(load, fetchZoneAlerts(ctx, alerts, alertZones, location)),
+		zoneLoadPart(load, findZoneLoadStation(ctx, load, location)),
+	)
+}
+
+// findZoneLoadStation finds the tide station nearest location as part of zone load
+// number load, carrying the load's ctx along for its tide data fetch
+func findZoneLoadStation(ctx context.Context, load int, location *geocoding.Location) tea.Cmd {
+	return func() tea.Msg {
+		msg := nearestTideStation(location.Latitude, location.Longitude)().(tideStationFoundMsg)
+		msg.ctx, msg.load = ctx, load
+		return msg
+	}
+}
 
+// measureZoneBoundary locates the location relative to the polygon of a marine zone.
+// It's a variable so tests can avoid the zones database.
+var measureZoneBoundary = func(zoneCode string, location *geocoding.Location) tea.Cmd {
+	return func() tea.Msg {
+		distance, err := zonelookup.ZoneBoundaryDistance(database.DBPath(), zoneCode, location.Latitude, location.Longitude)
+		return zoneBoundaryMsg{zoneCode: zoneCode, distance: distance, err: err}
+	}
+}
+
+// fetchZoneWeather fetches weather data for a marine zone. If the zone has no marine
+// text product and the location has coordinates, the point forecast is used instead.
+func fetchZoneWeather(ctx context.Context, client noaa.WeatherClient, zoneCode string, location *geocoding.Location) tea.Cmd {
+	return func() tea.Msg {
 		conditions, forecast, err := client.GetMarineForecastByZone(ctx, zoneCode)
+		if err == nil || location == nil {
+			return zoneWeatherFetchedMsg{
+				conditions: conditions,
+				forecast:   forecast,
+				err:        err,
+			}
+		}
+
+		forecast, pointErr := client.GetMarineForecast(ctx, location.Latitude, location.Longitude)
+		if pointErr != nil || forecast == nil || len(forecast.Periods) == 0 {
+			return zoneWeatherFetchedMsg{err: err}
+		}
 		return zoneWeatherFetchedMsg{
-			conditions: conditions,
+			conditions: conditionsFromPeriod(forecast.Periods[0], location.Name, forecast.UpdatedAt),
 			forecast:   forecast,
-			err:        err,
+			source:     landForecastSource,
 		}
 	}
 }
 
-// fetchZoneAlerts fetches alerts for a marine zone
-func fetchZoneAlerts(client noaa.AlertClient, zoneCode string) tea.Cmd {
+// conditionsFromPeriod summarizes the first forecast period as current conditions
+func conditionsFromPeriod(period models.MarineForecast, name string, updated time.Time) *models.MarineConditions {
+	return &models.MarineConditions{
+		Location:    name,
+		Temperature: period.Temperature,
+		Conditions:  period.Conditions,
+		Wind:        period.Wind,
+		Seas:        period.Seas,
+		UpdatedAt:   updated,
+	}
+}
+
+// fetchZoneAlerts fetches alerts for marine zones in one request. When the location has
+// coordinates, point-based alerts are merged in as well.
+func fetchZoneAlerts(ctx context.Context, client noaa.AlertClient, zoneCodes []string, location *geocoding.Location) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+		if location != nil && (location.Latitude != 0 || location.Longitude != 0) {
+			alerts, err := client.GetActiveAlertsByZonesAndPoint(ctx, zoneCodes, location.Latitude, location.Longitude)
+			return zoneAlertsFetchedMsg{alerts: alerts, err: err}
+		}
 
-		alerts, err := client.GetActiveAlertsByZone(ctx, zoneCode)
+		alerts, err := client.GetActiveAlertsByZones(ctx, zoneCodes)
 		return zoneAlertsFetchedMsg{alerts: alerts, err: err}
 	}
 }
 
-// fetchTideData fetches tide predictions and meteorological data for a station
-func fetchTideData(client noaa.TideClient, stationID string) tea.Cmd {
+// fetchTideData fetches days of tide predictions and the observed water level (relative
+// to datum) and meteorological data for a station
+func fetchTideData(ctx context.Context, client noaa.TideClient, clock models.Clock, stationID, datum string, days int) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-		defer cancel()
-
-		now := time.Now()
-		endDate := now.AddDate(0, 0, 3)
+		now := clock.Now()
+		endDate := now.AddDate(0, 0, days)
 
 		// Channels for results
 		type tideResult struct {
@@ -111,87 +280,253 @@ func fetchTideData(client noaa.TideClient, stationID string) tea.Cmd {
 			data *models.MarineConditions
 			err  error
 		}
+		type levelResult struct {
+			data *models.WaterLevel
+			err  error
+		}
 
 		tideChan := make(chan tideResult)
 		metChan := make(chan metResult)
+		levelChan := make(chan levelResult)
 
 		go func() {
-			data, err := client.GetTidePredictions(ctx, stationID, now, endDate)
+			data, err := client.GetTidePredictions(ctx, stationID, datum, now, endDate)
 			tideChan <- tideResult{data, err}
 		}()
 
 		go func() {
-			data, err := client.GetMeteorologicalData(ctx, stationID, now, endDate)
+			data, err := client.GetMeteorologicalData(ctx, stationID, now.AddDate(0, 0, -1), now)
 			metChan <- metResult{data, err}
 		}()
 
-		// Wait for both
+		go func() {
+			data, err := client.GetWaterLevel(ctx, stationID, datum)
+			levelChan <- levelResult{data, err}
+		}()
+
+		// Wait for all three
 		tRes := <-tideChan
 		mRes := <-metChan
+		lRes := <-levelChan
 
-		// Combine errors if both failed
-		var err error
-		if tRes.err != nil && mRes.err != nil {
-			err = fmt.Errorf("tides: %v, met: %v", tRes.err, mRes.err)
-		} else if tRes.err != nil {
-			err = tRes.err
-		}
-
+		// Each half is reported separately so whatever arrived can still be shown
 		return tideDataFetchedMsg{
+			stationID:  stationID,
 			tides:      tRes.data,
 			conditions: mRes.data,
-			err:        err,
+			waterLevel: lRes.data,
+			tideErr:    tRes.err,
+			metErr:     mRes.err,
+			levelErr:   lRes.err,
 		}
 	}
 }
 
+// loadObservationHistory reads a tide station's stored temperature history so trends show
+// before, or without, a successful meteorological fetch
+func loadObservationHistory(repo *database.ObservationRepository, stationID string) tea.Cmd {
+	return func() tea.Msg {
+		air, err := repo.RecentObservations(stationID, database.ObservationAirTemp)
+		if err != nil {
+			return nil // No stored trend; the fetched history is still shown
+		}
+		water, err := repo.RecentObservations(stationID, database.ObservationWaterTemp)
+		if err != nil {
+			return nil
+		}
+		return observationHistoryMsg{
+			stationID: stationID,
+			history:   &models.MarineConditions{AirTempHistory: air, WaterTempHistory: water},
+		}
+	}
+}
+
+// recordObservationHistory stores a tide station's fetched temperature history for
+// seeding trends after a restart. Failures only cost the stored trend, so they're ignored.
+func recordObservationHistory(repo *database.ObservationRepository, stationID string, conditions *models.MarineConditions) tea.Cmd {
+	return func() tea.Msg {
+		_ = repo.RecordObservations(stationID, map[string][]models.Observation{
+			database.ObservationAirTemp:   conditions.AirTempHistory,
+			database.ObservationWaterTemp: conditions.WaterTempHistory,
+		})
+		return nil
+	}
+}
+
+// fetchDatumOffsets fetches a tide station's datum elevations for converting heights between datums
+func fetchDatumOffsets(client noaa.TideClient, stationID string) tea.Cmd {
+	return func() tea.Msg {
+		offsets, err := client.GetDatums(context.Background(), stationID)
+		return datumOffsetsFetchedMsg{stationID: stationID, offsets: offsets, err: err}
+	}
+}
+
 // Provisioning messages
 
-type provisionStatusMsg string
+// provisionStatusMsg carries a structured progress update from provisioning
+type provisionStatusMsg provision.Progress
 
 type provisionResultMsg struct {
 	err error
 }
 
+// provisionJob is a provisioning run, reporting progress on progressChan. It should
+// stop, cleaning up after itself, once ctx is cancelled.
+type provisionJob func(ctx context.Context, progressChan chan<- provision.Progress) error
+
 // waitForProvisioning returns a message wrapping the channels so the Update loop can subscribe to them
 type provisioningStartedMsg struct {
-	progressChan <-chan string
+	progressChan <-chan provision.Progress
 	resultChan   <-chan error
+	job          provisionJob // The job being run, kept so a failed run can be retried
 }
 
 // Actual command to start and return the channels
-func initiateProvisioning() tea.Cmd {
+func initiateProvisioning(ctx context.Context) tea.Cmd {
+	return startProvisioning(ctx, func(ctx context.Context, progressChan chan<- provision.Progress) error {
+		// Provision marine zones
+		err := zonelookup.ProvisionDatabaseWithProgress(ctx, database.DBPath(), progressChan)
+		if err != nil {
+			return err
+		}
+
+		// Provision zipcodes
+		return geocoding.ProvisionZipcodeDatabaseWithProgress(database.DBPath(), progressChan)
+	})
+}
+
+// initiateReprovisioning rebuilds any reference tables that were provisioned but left empty
+func initiateReprovisioning(ctx context.Context) tea.Cmd {
+	return startProvisioning(ctx, func(ctx context.Context, progressChan chan<- provision.Progress) error {
+		dbPath := database.DBPath()
+
+		if empty, err := zonelookup.IsEmpty(dbPath); err != nil {
+			return err
+		} else if empty {
+			if err := zonelookup.ReprovisionDatabaseWithProgress(ctx, dbPath, progressChan); err != nil {
+				return err
+			}
+		}
+
+		if empty, err := stations.IsEmpty(dbPath); err != nil {
+			return err
+		} else if empty {
+			if err := stations.ReprovisionStationsDatabase(dbPath, progressChan); err != nil {
+				return err
+			}
+		}
+
+		return geocoding.ProvisionZipcodeDatabaseWithProgress(dbPath, progressChan)
+	})
+}
+
+// initiateZonesUpdate rebuilds the marine zones table from the latest shapefile edition
+func initiateZonesUpdate(ctx context.Context) tea.Cmd {
+	return startProvisioning(ctx, func(ctx context.Context, progressChan chan<- provision.Progress) error {
+		return zonelookup.ReprovisionDatabaseWithProgress(ctx, database.DBPath(), progressChan)
+	})
+}
+
+// checkForNewerEdition looks for a marine zones shapefile edition newer than the installed one.
+// It gives up after timeout. Lookup failures are ignored; the check simply runs again on
+// the next start.
+func checkForNewerEdition(timeout time.Duration) tea.Cmd {
 	return func() tea.Msg {
-		progressChan := make(chan string)
-		resultChan := make(chan error)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
 
+		edition, err := zonelookup.NewerEditionAvailable(ctx, database.DBPath())
+		if err != nil || edition == "" {
+			return nil
+		}
+		return newerEditionMsg{edition: edition}
+	}
+}
+
+// provisionRuns counts provisioning jobs still running, so the program can wait for a
+// cancelled one to clean up before it exits
+var provisionRuns sync.WaitGroup
+
+// WaitForProvisioning waits up to timeout for provisioning jobs cancelled through the
+// model's context to stop and remove their partial downloads. It reports whether they
+// all did.
+func WaitForProvisioning(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		provisionRuns.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// startProvisioning runs a provisioning job in the background under ctx and returns
+// the channels the Update loop subscribes to for progress and the final result. Once
+// ctx is cancelled, updates nobody is left to read are dropped so the job can finish.
+func startProvisioning(ctx context.Context, run provisionJob) tea.Cmd {
+	return func() tea.Msg {
+		progressChan := make(chan provision.Progress)
+		resultChan := make(chan error, 1)
+
+		jobProgress := make(chan provision.Progress)
+		forwarded := make(chan struct{})
+		go func() {
+			for p := range jobProgress {
+				select {
+				case progressChan <- p:
+				case <-ctx.Done():
+				}
+			}
+			close(progressChan) // Signal end of progress
+			close(forwarded)
+		}()
+
+		provisionRuns.Add(1)
 		go func() {
+			defer provisionRuns.Done()
 			// Small delay to ensure UI is ready
 			time.Sleep(100 * time.Millisecond)
 
-			// Provision marine zones
-			err := zonelookup.ProvisionDatabaseWithProgress(database.DBPath(), progressChan)
-			if err != nil {
-				resultChan <- err
-				close(progressChan)
-				return
-			}
-
-			// Provision zipcodes
-			err = geocoding.ProvisionZipcodeDatabaseWithProgress(database.DBPath(), progressChan)
-			
+			err := run(ctx, jobProgress)
+			close(jobProgress)
+			<-forwarded
 			resultChan <- err
-			close(progressChan) // Signal end of progress
 		}()
 
 		return provisioningStartedMsg{
 			progressChan: progressChan,
 			resultChan:   resultChan,
+			job:          run,
 		}
 	}
 }
 
-func waitForProvisionStatus(ch <-chan string) tea.Cmd {
+// retryProvisioning runs a failed provisioning job again. With cleanup, the shapefile
+// files left by the failed run are removed first, since a build failure may mean the
+// download was corrupt.
+func retryProvisioning(ctx context.Context, job provisionJob, cleanup bool) tea.Cmd {
+	if !cleanup {
+		return startProvisioning(ctx, job)
+	}
+	start := startProvisioning(ctx, func(ctx context.Context, progressChan chan<- provision.Progress) error {
+		provision.Send(progressChan, provision.Status("Removing the previous download..."))
+		if err := zonelookup.CleanupProvisioningFiles(database.DBPath()); err != nil {
+			return err
+		}
+		return job(ctx, progressChan)
+	})
+	return func() tea.Msg {
+		msg := start().(provisioningStartedMsg)
+		msg.job = job // Retry the job itself, not the cleanup wrapper
+		return msg
+	}
+}
+
+func waitForProvisionStatus(ch <-chan provision.Progress) tea.Cmd {
 	return func() tea.Msg {
 		msg, ok := <-ch
 		if !ok {
@@ -0,0 +1,202 @@
+package zonelookup
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ngmaloney/marine-terminal/internal/database"
+)
+
+// marineZonesIndexURL is the NOAA page linking to the current marine zones shapefile
+var marineZonesIndexURL = "https://www.weather.gov/gis/MarineZones"
+
+// editionKey is the metadata key recording which shapefile edition built marine_zones
+const editionKey = "marine_zones_edition"
+
+// editionRE matches shapefile editions such as mz18mr25 (effective 18 March 2025)
+var editionRE = regexp.MustCompile(`^mz(\d{2})([a-z]{2})(\d{2})$`)
+
+// editionLinkRE finds links to shapefile editions in an index page
+var editionLinkRE = regexp.MustCompile(`mz\d{2}[a-z]{2}\d{2}\.zip`)
+
+// editionMonths maps the two-letter month codes NOAA uses in shapefile names
+var editionMonths = map[string]time.Month{
+	"ja": time.January,
+	"fe": time.February,
+	"mr": time.March,
+	"ap": time.April,
+	"my": time.May,
+	"jn": time.June,
+	"jl": time.July,
+	"au": time.August,
+	"se": time.September,
+	"oc": time.October,
+	"no": time.November,
+	"de": time.December,
+}
+
+// editionDate returns the effective date encoded in an edition name like mz18mr25
+func editionDate(edition string) (time.Time, bool) {
+	m := editionRE.FindStringSubmatch(edition)
+	if m == nil {
+		return time.Time{}, false
+	}
+	month, ok := editionMonths[m[2]]
+	if !ok {
+		return time.Time{}, false
+	}
+	var day, year int
+	fmt.Sscanf(m[1], "%d", &day)
+	fmt.Sscanf(m[3], "%d", &year)
+	return time.Date(2000+year, month, day, 0, 0, 0, 0, time.UTC), true
+}
+
+// latestEditionIn returns the newest shapefile edition linked from an index page
+func latestEditionIn(page string) (string, error) {
+	var latest string
+	var latestDate time.Time
+	for _, link := range editionLinkRE.FindAllString(page, -1) {
+		edition := strings.TrimSuffix(link, ".zip")
+		date, ok := editionDate(edition)
+		if !ok {
+			continue
+		}
+		if latest == "" || date.After(latestDate) {
+			latest = edition
+			latestDate = date
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no marine zones shapefile found in index")
+	}
+	return latest, nil
+}
+
+// DiscoverLatestEdition looks up the newest marine zones shapefile edition published by
+// NOAA. It's bounded by ctx alone, so callers should give it a deadline.
+func DiscoverLatestEdition(ctx context.Context) (string, error) {
+	client := &http.Client{}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", marineZonesIndexURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetching marine zones index: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("marine zones index returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading marine zones index: %w", err)
+	}
+
+	return latestEditionIn(string(body))
+}
+
+// resolveEdition picks the shapefile edition to provision from: the one set with
+// SetShapefileEdition, else the latest published edition, else the default
+func resolveEdition(ctx context.Context) (string, error) {
+	if editionPinned {
+		return shapefileBase, nil
+	}
+	edition, err := DiscoverLatestEdition(ctx)
+	if err != nil {
+		return DefaultShapefileEdition, err
+	}
+	return edition, nil
+}
+
+// InstalledEdition returns the shapefile edition the marine_zones table was built from.
+// It returns "" if the table hasn't been provisioned, and DefaultShapefileEdition for
+// tables provisioned before editions were recorded.
+func InstalledEdition(dbPath string) (string, error) {
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return "", nil
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return "", fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	return installedEditionDB(db)
+}
+
+// installedEditionDB reads the recorded edition using the provided database connection
+func installedEditionDB(db *sql.DB) (string, error) {
+	empty, err := isEmptyDB(db)
+	if err != nil || empty {
+		return "", err
+	}
+
+	var tables int
+	err = db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name='metadata'").Scan(&tables)
+	if err != nil {
+		return "", fmt.Errorf("checking for metadata table: %w", err)
+	}
+	if tables == 0 {
+		return DefaultShapefileEdition, nil
+	}
+
+	var edition string
+	err = db.QueryRow("SELECT value FROM metadata WHERE key = ?", editionKey).Scan(&edition)
+	if err == sql.ErrNoRows {
+		return DefaultShapefileEdition, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading shapefile edition: %w", err)
+	}
+	return edition, nil
+}
+
+// recordEdition stores the shapefile edition marine_zones was built from, and when
+func recordEdition(tx *sql.Tx, edition string) error {
+	if err := database.WriteMetadata(tx, editionKey, edition); err != nil {
+		return fmt.Errorf("recording shapefile edition: %w", err)
+	}
+	return database.RecordProvisioned(tx, database.MetadataZonesProvisionedAt, time.Now())
+}
+
+// NewerEditionAvailable returns the latest published shapefile edition if it is newer
+// than the one the marine_zones table was built from, or "" if the data is current.
+// No check is made when an edition was chosen explicitly with SetShapefileEdition.
+func NewerEditionAvailable(ctx context.Context, dbPath string) (string, error) {
+	if editionPinned {
+		return "", nil
+	}
+
+	installed, err := InstalledEdition(dbPath)
+	if err != nil || installed == "" {
+		return "", err
+	}
+
+	latest, err := DiscoverLatestEdition(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	installedDate, ok := editionDate(installed)
+	latestDate, _ := editionDate(latest)
+	if ok && !latestDate.After(installedDate) {
+		return "", nil
+	}
+	if latest == installed {
+		return "", nil
+	}
+	return latest, nil
+}
@@ -0,0 +1,138 @@
+package zonelookup
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ngmaloney/marine-terminal/internal/database"
+	_ "modernc.org/sqlite"
+)
+
+func TestEditionDate(t *testing.T) {
+	tests := []struct {
+		edition string
+		want    time.Time
+		ok      bool
+	}{
+		{"mz18mr25", time.Date(2025, time.March, 18, 0, 0, 0, 0, time.UTC), true},
+		{"mz05de24", time.Date(2024, time.December, 5, 0, 0, 0, 0, time.UTC), true},
+		{"mz05xx24", time.Time{}, false},
+		{"mz18mr25.zip", time.Time{}, false},
+		{"custom", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.edition, func(t *testing.T) {
+			got, ok := editionDate(tt.edition)
+			if ok != tt.ok || !got.Equal(tt.want) {
+				t.Errorf("editionDate(%q) = %v, %v; want %v, %v", tt.edition, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestLatestEditionIn(t *testing.T) {
+	page := `<a href="/source/gis/Shapefiles/WSOM/mz18mr25.zip">Current</a>
+		<a href="/source/gis/Shapefiles/WSOM/mz05de24.zip">Previous</a>
+		<a href="/source/gis/Shapefiles/WSOM/mz03sp26.zip">Bad month</a>`
+
+	got, err := latestEditionIn(page)
+	if err != nil || got != "mz18mr25" {
+		t.Errorf("latestEditionIn() = %q, %v; want mz18mr25, nil", got, err)
+	}
+
+	if _, err := latestEditionIn("<html>nothing here</html>"); err == nil {
+		t.Error("expected error for page without shapefile links")
+	}
+}
+
+// serveIndex points discovery at a test server listing the given edition
+func serveIndex(t *testing.T, edition string) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<a href="/source/gis/Shapefiles/WSOM/%s.zip">Download</a>`, edition)
+	}))
+	oldURL := marineZonesIndexURL
+	marineZonesIndexURL = server.URL
+	t.Cleanup(func() {
+		marineZonesIndexURL = oldURL
+		server.Close()
+	})
+}
+
+func TestNewerEditionAvailable(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	serveIndex(t, "mz05mr26")
+
+	// Not provisioned yet: nothing to offer
+	if got, err := NewerEditionAvailable(context.Background(), dbPath); err != nil || got != "" {
+		t.Errorf("unprovisioned: NewerEditionAvailable() = %q, %v; want \"\", nil", got, err)
+	}
+
+	if _, err := db.Exec(`CREATE TABLE marine_zones (zone_code TEXT NOT NULL)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO marine_zones VALUES ('ANZ254')`); err != nil {
+		t.Fatalf("Failed to insert zone: %v", err)
+	}
+
+	// Provisioned before editions were recorded: assumed to be the default edition
+	if got, err := InstalledEdition(dbPath); err != nil || got != DefaultShapefileEdition {
+		t.Errorf("InstalledEdition() = %q, %v; want %q, nil", got, err, DefaultShapefileEdition)
+	}
+	if got, err := NewerEditionAvailable(context.Background(), dbPath); err != nil || got != "mz05mr26" {
+		t.Errorf("legacy: NewerEditionAvailable() = %q, %v; want mz05mr26, nil", got, err)
+	}
+
+	// Recording the latest edition means there's nothing newer
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	if err := recordEdition(tx, "mz05mr26"); err != nil {
+		t.Fatalf("recordEdition() error = %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	if got, err := InstalledEdition(dbPath); err != nil || got != "mz05mr26" {
+		t.Errorf("InstalledEdition() = %q, %v; want mz05mr26, nil", got, err)
+	}
+	if at, err := database.NewMetadataRepository(dbPath).ProvisionedAt(database.MetadataZonesProvisionedAt); err != nil || at.IsZero() {
+		t.Errorf("ProvisionedAt() = %v, %v; want the time the edition was recorded", at, err)
+	}
+	if got, err := NewerEditionAvailable(context.Background(), dbPath); err != nil || got != "" {
+		t.Errorf("current: NewerEditionAvailable() = %q, %v; want \"\", nil", got, err)
+	}
+}
+
+func TestResolveEdition_FallsBackToDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	oldURL := marineZonesIndexURL
+	marineZonesIndexURL = server.URL
+	defer func() { marineZonesIndexURL = oldURL }()
+
+	edition, err := resolveEdition(context.Background())
+	if err == nil {
+		t.Error("expected discovery error")
+	}
+	if edition != DefaultShapefileEdition {
+		t.Errorf("resolveEdition() = %q, want %q", edition, DefaultShapefileEdition)
+	}
+}
@@ -0,0 +1,246 @@
+package zonelookup
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/ngmaloney/marine-terminal/internal/debuglog"
+)
+
+// ErrNoGeometry is returned for zones whose polygon is missing or can't be decoded,
+// e.g. in databases provisioned by older versions. Callers fall back to the distance
+// to the zone's center.
+var ErrNoGeometry = errors.New("zone has no usable geometry")
+
+// milesPerDegree is the length of one degree of latitude (and of longitude at the equator)
+const milesPerDegree = 3959.0 * math.Pi / 180
+
+// BoundaryDistance describes where a point lies relative to a zone's polygon
+type BoundaryDistance struct {
+	Inside bool    // Point is within the zone
+	Miles  float64 // Distance to the nearest edge of the zone's polygon
+}
+
+// ring is a closed polygon outline as [lon, lat] pairs, as stored in marine_zones.geometry
+type ring [][2]float64
+
+// parseRing decodes a stored zone geometry
+func parseRing(geometry string) (ring, error) {
+	var coords ring
+	if err := json.Unmarshal([]byte(geometry), &coords); err != nil {
+		return nil, fmt.Errorf("decoding zone geometry: %w", err)
+	}
+	if len(coords) < 3 {
+		return nil, fmt.Errorf("zone geometry has %d points, need at least 3", len(coords))
+	}
+	return coords, nil
+}
+
+// contains reports whether the point is inside the ring, using ray casting
+func (r ring) contains(lat, lon float64) bool {
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		xi, yi := r[i][0], r[i][1]
+		xj, yj := r[j][0], r[j][1]
+		if (yi > lat) != (yj > lat) && lon < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// distanceMiles returns the distance from the point to the nearest edge of the ring.
+// Coordinates are projected onto a plane centered on the point, which is accurate
+// for the short distances involved in zone matching.
+func (r ring) distanceMiles(lat, lon float64) float64 {
+	cosLat := math.Cos(lat * math.Pi / 180)
+	project := func(p [2]float64) (float64, float64) {
+		return (p[0] - lon) * cosLat * milesPerDegree, (p[1] - lat) * milesPerDegree
+	}
+
+	nearest := math.Inf(1)
+	for i := range r {
+		ax, ay := project(r[i])
+		bx, by := project(r[(i+1)%len(r)])
+		if d := pointSegmentDistance(0, 0, ax, ay, bx, by); d < nearest {
+			nearest = d
+		}
+	}
+	return nearest
+}
+
+// pointSegmentDistance returns the planar distance from point p to segment ab
+func pointSegmentDistance(px, py, ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	lengthSq := dx*dx + dy*dy
+	if lengthSq == 0 {
+		return math.Hypot(px-ax, py-ay)
+	}
+	t := ((px-ax)*dx + (py-ay)*dy) / lengthSq
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(px-(ax+t*dx), py-(ay+t*dy))
+}
+
+// boundaryDistance locates the point relative to the ring
+func (r ring) boundaryDistance(lat, lon float64) BoundaryDistance {
+	return BoundaryDistance{
+		Inside: r.contains(lat, lon),
+		Miles:  r.distanceMiles(lat, lon),
+	}
+}
+
+// ZoneBoundaryDistance reports whether the point is inside the zone's polygon and how
+// far it is from the polygon's nearest edge
+func ZoneBoundaryDistance(dbPath, zoneCode string, lat, lon float64) (*BoundaryDistance, error) {
+	db, err := GetDB(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	return zoneBoundaryDistanceFromDB(db, zoneCode, lat, lon)
+}
+
+// zoneBoundaryDistanceFromDB measures the boundary distance using the provided database connection
+func zoneBoundaryDistanceFromDB(db *sql.DB, zoneCode string, lat, lon float64) (*BoundaryDistance, error) {
+	ok, err := hasGeometry(db)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("zone %s: %w: the marine_zones table has no geometry column", zoneCode, ErrNoGeometry)
+	}
+
+	// Multi-part zones are stored one row per part
+	rows, err := db.Query(
+		"SELECT geometry FROM marine_zones WHERE zone_code = ?",
+		strings.ToUpper(strings.TrimSpace(zoneCode)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying zone geometry: %w", err)
+	}
+	defer rows.Close()
+
+	var best *BoundaryDistance
+	var parts int
+	var partErr error
+	for rows.Next() {
+		parts++
+		var geometry sql.NullString
+		if err := rows.Scan(&geometry); err != nil {
+			return nil, fmt.Errorf("scanning zone geometry: %w", err)
+		}
+		r, err := parseStoredRing(geometry)
+		if err != nil {
+			partErr = err
+			continue
+		}
+		if d := r.boundaryDistance(lat, lon); best == nil || closerPart(d, *best) {
+			best = &d
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading zone geometry: %w", err)
+	}
+	if parts == 0 {
+		return nil, fmt.Errorf("zone code %s not found", zoneCode)
+	}
+	if best == nil {
+		return nil, fmt.Errorf("zone %s: %w: %v", zoneCode, ErrNoGeometry, partErr)
+	}
+	return best, nil
+}
+
+// closerPart reports whether d, measured to one part of a multi-part zone, places the
+// point better than best, measured to another: a part containing the point wins,
+// otherwise the nearest part does
+func closerPart(d, best BoundaryDistance) bool {
+	if d.Inside || best.Inside {
+		return d.Inside && !best.Inside
+	}
+	return d.Miles < best.Miles
+}
+
+// ZoneBoundaryDistances measures the boundary distance of each of the zones, as
+// ZoneBoundaryDistance does, in one query. For multi-part zones it's measured to the
+// part containing the point, or else the nearest part. Zones whose polygon is missing or malformed
+// are left out of the result, and logged with debug logging on, so the caller can fall
+// back to their center distance; they don't fail the whole lookup.
+func ZoneBoundaryDistances(dbPath string, zoneCodes []string, lat, lon float64) (map[string]BoundaryDistance, error) {
+	db, err := GetDB(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	return zoneBoundaryDistancesFromDB(db, zoneCodes, lat, lon)
+}
+
+// zoneBoundaryDistancesFromDB measures the boundary distances using the provided database connection
+func zoneBoundaryDistancesFromDB(db *sql.DB, zoneCodes []string, lat, lon float64) (map[string]BoundaryDistance, error) {
+	distances := make(map[string]BoundaryDistance)
+	if len(zoneCodes) == 0 {
+		return distances, nil
+	}
+	ok, err := hasGeometry(db)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		debuglog.Printf("marine_zones table has no geometry column; using zone centers")
+		return distances, nil
+	}
+
+	args := make([]any, len(zoneCodes))
+	for i, code := range zoneCodes {
+		args[i] = strings.ToUpper(strings.TrimSpace(code))
+	}
+	rows, err := db.Query(
+		"SELECT zone_code, geometry FROM marine_zones WHERE zone_code IN (?"+strings.Repeat(", ?", len(args)-1)+")",
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying zone geometry: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var code string
+		var geometry sql.NullString
+		if err := rows.Scan(&code, &geometry); err != nil {
+			return nil, fmt.Errorf("scanning zone geometry: %w", err)
+		}
+		r, err := parseStoredRing(geometry)
+		if err != nil {
+			debuglog.Printf("Skipping geometry of zone %s, using its center: %v", code, err)
+			continue
+		}
+		d := r.boundaryDistance(lat, lon)
+		if best, ok := distances[code]; !ok || closerPart(d, best) {
+			distances[code] = d
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading zone geometry: %w", err)
+	}
+	return distances, nil
+}
+
+// parseStoredRing decodes a geometry read from the database, which may be NULL
+func parseStoredRing(geometry sql.NullString) (ring, error) {
+	if !geometry.Valid || strings.TrimSpace(geometry.String) == "" {
+		return nil, errors.New("zone geometry is empty")
+	}
+	return parseRing(geometry.String)
+}
+
+// hasGeometry reports whether the marine_zones table has a geometry column; tables
+// provisioned by older versions only stored zone centers
+func hasGeometry(db *sql.DB) (bool, error) {
+	var n int
+	err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('marine_zones') WHERE name = 'geometry'").Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("checking for zone geometry: %w", err)
+	}
+	return n > 0, nil
+}
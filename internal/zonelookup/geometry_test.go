@@ -0,0 +1,246 @@
+package zonelookup
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"log"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ngmaloney/marine-terminal/internal/debuglog"
+)
+
+// square is a 1° x 1° zone with its southwest corner at 41°N, 70°W
+var square = ring{{-70, 41}, {-69, 41}, {-69, 42}, {-70, 42}, {-70, 41}}
+
+func TestPointSegmentDistance(t *testing.T) {
+	tests := []struct {
+		name                   string
+		px, py, ax, ay, bx, by float64
+		want                   float64
+	}{
+		{"perpendicular to middle", 0, 1, -1, 0, 1, 0, 1},
+		{"beyond end", 3, 4, -1, 0, 0, 0, 5},
+		{"on segment", 0.5, 0, 0, 0, 1, 0, 0},
+		{"degenerate segment", 3, 4, 0, 0, 0, 0, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pointSegmentDistance(tt.px, tt.py, tt.ax, tt.ay, tt.bx, tt.by)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("pointSegmentDistance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRing_Contains(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lon float64
+		want     bool
+	}{
+		{"center", 41.5, -69.5, true},
+		{"near corner inside", 41.01, -69.99, true},
+		{"west", 41.5, -70.5, false},
+		{"north", 42.5, -69.5, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := square.contains(tt.lat, tt.lon); got != tt.want {
+				t.Errorf("contains(%v, %v) = %v, want %v", tt.lat, tt.lon, got, tt.want)
+			}
+		})
+	}
+
+	// Concave ring: the notch between the arms is outside
+	u := ring{{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3}}
+	if u.contains(2, 1.5) {
+		t.Error("point in the notch of a U shape should be outside")
+	}
+	if !u.contains(2, 0.5) {
+		t.Error("point in the arm of a U shape should be inside")
+	}
+}
+
+func TestRing_BoundaryDistance(t *testing.T) {
+	// Half a degree of latitude south of the square's southern edge
+	d := square.boundaryDistance(40.5, -69.5)
+	if d.Inside {
+		t.Error("point south of the zone reported inside")
+	}
+	if want := 0.5 * milesPerDegree; math.Abs(d.Miles-want) > 0.01 {
+		t.Errorf("Miles = %.2f, want %.2f", d.Miles, want)
+	}
+
+	// Inside, 0.1° of longitude from the western edge, which is nearer than any other edge
+	d = square.boundaryDistance(41.5, -69.9)
+	if !d.Inside {
+		t.Error("point inside the zone reported outside")
+	}
+	if want := 0.1 * milesPerDegree * math.Cos(41.5*math.Pi/180); math.Abs(d.Miles-want) > 0.01 {
+		t.Errorf("Miles = %.2f, want %.2f", d.Miles, want)
+	}
+}
+
+func TestParseRing(t *testing.T) {
+	if _, err := parseRing(`[[-70,41],[-69,41],[-69,42]]`); err != nil {
+		t.Errorf("parseRing() error = %v", err)
+	}
+	if _, err := parseRing(`[[-70,41],[-69,41]]`); err == nil {
+		t.Error("Expected error for a ring with too few points")
+	}
+	if _, err := parseRing(`not json`); err == nil {
+		t.Error("Expected error for invalid geometry")
+	}
+}
+
+func TestZoneBoundaryDistanceFromDB(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open in-memory database: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE marine_zones (zone_code TEXT, zone_name TEXT, geometry TEXT NOT NULL);
+		INSERT INTO marine_zones VALUES ('ANZ254', 'Square Sound', '[[-70,41],[-69,41],[-69,42],[-70,42],[-70,41]]');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create test data: %v", err)
+	}
+
+	d, err := zoneBoundaryDistanceFromDB(db, "anz254", 41.5, -69.5)
+	if err != nil {
+		t.Fatalf("zoneBoundaryDistanceFromDB() error = %v", err)
+	}
+	if !d.Inside {
+		t.Error("center of the zone reported outside")
+	}
+
+	if _, err := zoneBoundaryDistanceFromDB(db, "ANZ999", 41.5, -69.5); err == nil {
+		t.Error("Expected error for unknown zone")
+	}
+}
+
+func TestZoneBoundaryDistancesFromDB_SkipsBadGeometry(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open in-memory database: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE marine_zones (zone_code TEXT, zone_name TEXT, geometry TEXT);
+		INSERT INTO marine_zones VALUES ('ANZ254', 'Square Sound', '[[-70,41],[-69,41],[-69,42],[-70,42],[-70,41]]');
+		INSERT INTO marine_zones VALUES ('ANZ255', 'Broken Sound', '{"type": "Polygon", "coordinates": [[');
+		INSERT INTO marine_zones VALUES ('ANZ256', 'Empty Sound', '');
+		INSERT INTO marine_zones VALUES ('ANZ257', 'Missing Sound', NULL);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create test data: %v", err)
+	}
+
+	var buf bytes.Buffer
+	orig := log.Writer()
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(orig) })
+	debuglog.SetEnabled(true)
+	t.Cleanup(func() { debuglog.SetEnabled(false) })
+
+	distances, err := zoneBoundaryDistancesFromDB(db, []string{"anz254", "ANZ255", "ANZ256", "ANZ257"}, 41.5, -69.5)
+	if err != nil {
+		t.Fatalf("zoneBoundaryDistancesFromDB() error = %v", err)
+	}
+	if len(distances) != 1 {
+		t.Fatalf("got distances for %d zones, want only ANZ254: %v", len(distances), distances)
+	}
+	if d, ok := distances["ANZ254"]; !ok || !d.Inside {
+		t.Errorf("ANZ254 = %+v, %v; want inside", d, ok)
+	}
+	for _, code := range []string{"ANZ255", "ANZ256", "ANZ257"} {
+		if !strings.Contains(buf.String(), "Skipping geometry of zone "+code) {
+			t.Errorf("debug log doesn't mention skipping %s:\n%s", code, buf.String())
+		}
+	}
+
+	if _, err := zoneBoundaryDistanceFromDB(db, "ANZ255", 41.5, -69.5); !errors.Is(err, ErrNoGeometry) {
+		t.Errorf("zoneBoundaryDistanceFromDB(malformed) error = %v, want ErrNoGeometry", err)
+	}
+	if _, err := zoneBoundaryDistanceFromDB(db, "ANZ257", 41.5, -69.5); !errors.Is(err, ErrNoGeometry) {
+		t.Errorf("zoneBoundaryDistanceFromDB(NULL) error = %v, want ErrNoGeometry", err)
+	}
+}
+
+func TestZoneBoundaryDistancesFromDB_NoGeometryColumn(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open in-memory database: %v", err)
+	}
+	defer db.Close()
+
+	// Databases provisioned by older versions only stored zone centers
+	_, err = db.Exec(`
+		CREATE TABLE marine_zones (zone_code TEXT, zone_name TEXT, center_lat REAL, center_lon REAL);
+		INSERT INTO marine_zones VALUES ('ANZ254', 'Square Sound', 41.5, -69.5);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create test data: %v", err)
+	}
+
+	distances, err := zoneBoundaryDistancesFromDB(db, []string{"ANZ254"}, 41.5, -69.5)
+	if err != nil {
+		t.Fatalf("zoneBoundaryDistancesFromDB() error = %v", err)
+	}
+	if len(distances) != 0 {
+		t.Errorf("got distances %v, want none", distances)
+	}
+	if _, err := zoneBoundaryDistanceFromDB(db, "ANZ254", 41.5, -69.5); !errors.Is(err, ErrNoGeometry) {
+		t.Errorf("zoneBoundaryDistanceFromDB() error = %v, want ErrNoGeometry", err)
+	}
+}
+
+func TestZoneBoundaryDistancesFromDB_MultiPartZone(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open in-memory database: %v", err)
+	}
+	defer db.Close()
+
+	// ANZ254 has two parts; the point is inside the first, and the second is stored last
+	_, err = db.Exec(`
+		CREATE TABLE marine_zones (zone_code TEXT, zone_name TEXT, geometry TEXT);
+		INSERT INTO marine_zones VALUES ('ANZ254', 'Square Sound', '[[-70,41],[-69,41],[-69,42],[-70,42],[-70,41]]');
+		INSERT INTO marine_zones VALUES ('ANZ254', 'Square Sound', '[[-68,43],[-67,43],[-67,44],[-68,44],[-68,43]]');
+		INSERT INTO marine_zones VALUES ('ANZ255', 'Split Sound', '[[-68.9,41],[-68.5,41],[-68.5,42],[-68.9,42],[-68.9,41]]');
+		INSERT INTO marine_zones VALUES ('ANZ255', 'Split Sound', 'not json');
+		INSERT INTO marine_zones VALUES ('ANZ255', 'Split Sound', '[[-66,41],[-65,41],[-65,42],[-66,42],[-66,41]]');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create test data: %v", err)
+	}
+
+	distances, err := zoneBoundaryDistancesFromDB(db, []string{"ANZ254", "ANZ255"}, 41.5, -69.5)
+	if err != nil {
+		t.Fatalf("zoneBoundaryDistancesFromDB() error = %v", err)
+	}
+	if d := distances["ANZ254"]; !d.Inside {
+		t.Errorf("ANZ254 = %+v, want inside its first part", d)
+	}
+	// Outside both of ANZ255's usable parts, measured to the nearer one
+	if d, ok := distances["ANZ255"]; !ok || d.Inside || d.Miles > 40 {
+		t.Errorf("ANZ255 = %+v, %v; want outside, about 30 mi from its nearest part", d, ok)
+	}
+
+	d, err := zoneBoundaryDistanceFromDB(db, "ANZ254", 41.5, -69.5)
+	if err != nil || !d.Inside {
+		t.Errorf("zoneBoundaryDistanceFromDB(ANZ254) = %+v, %v; want inside", d, err)
+	}
+	if d, err := zoneBoundaryDistanceFromDB(db, "ANZ255", 41.5, -69.5); err != nil || d.Miles > 40 {
+		t.Errorf("zoneBoundaryDistanceFromDB(ANZ255) = %+v, %v; want the nearest part", d, err)
+	}
+}
@@ -2,26 +2,78 @@ package zonelookup
 
 import (
 	"archive/zip"
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/jonas-p/go-shp"
+	"github.com/ngmaloney/marine-terminal/internal/provision"
 	_ "modernc.org/sqlite"
 )
 
 const (
-	// NOAA Marine Zones Shapefile URL (updated quarterly)
-	marineZonesURL = "https://www.weather.gov/source/gis/Shapefiles/WSOM/mz18mr25.zip"
-	downloadDir    = "data"
-	shapefileBase  = "mz18mr25"
+	// DefaultShapefileEdition is the NOAA marine zones shapefile edition provisioned by default
+	DefaultShapefileEdition = "mz18mr25"
+
+	downloadDir = "data"
+)
+
+// marineZonesBaseURL is the NOAA marine zones shapefile directory (a new edition is
+// published quarterly)
+var marineZonesBaseURL = "https://www.weather.gov/source/gis/Shapefiles/WSOM/"
+
+// ErrShapefileDownload matches provisioning failures caused by downloading the shapefile,
+// e.g. a timeout: a *ProvisionError in StageDownload. Nothing usable was left on disk, so
+// they can simply be retried. Any other provisioning failure may come from a corrupt
+// download; remove the leftover files with CleanupProvisioningFiles before retrying.
+var ErrShapefileDownload = errors.New("downloading shapefile")
+
+// errTruncatedDownload marks a download that ended before the promised Content-Length
+var errTruncatedDownload = errors.New("download truncated")
+
+// downloadAttempts is how many times a truncated or unreadable shapefile download is
+// tried before giving up, and downloadRetryDelay the pause between attempts
+var (
+	downloadAttempts   = 3
+	downloadRetryDelay = 2 * time.Second
 )
 
+// shapefileBase is the shapefile edition chosen with SetShapefileEdition
+var shapefileBase = DefaultShapefileEdition
+
+// editionPinned is true once an edition has been chosen explicitly, disabling discovery
+var editionPinned bool
+
+var shapefileEditionRE = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// SetShapefileEdition pins the NOAA marine zones shapefile edition (e.g. "mz18mr25")
+// used by subsequent provisioning runs instead of discovering the latest one.
+// A trailing ".zip" is ignored.
+func SetShapefileEdition(name string) error {
+	name = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".zip")
+	if !shapefileEditionRE.MatchString(name) {
+		return fmt.Errorf("invalid shapefile edition %q: expected a name like %q", name, DefaultShapefileEdition)
+	}
+	shapefileBase = name
+	editionPinned = true
+	return nil
+}
+
+// marineZonesURL returns the download URL for a shapefile edition
+func marineZonesURL(edition string) string {
+	return marineZonesBaseURL + edition + ".zip"
+}
+
 // NeedsProvisioning checks if the database needs to be provisioned
 func NeedsProvisioning(dbPath string) (bool, error) {
 	// If file doesn't exist, we need to provision
@@ -47,11 +99,12 @@ func NeedsProvisioning(dbPath string) (bool, error) {
 
 // ProvisionDatabase checks if the marine_zones table exists and creates it if not
 func ProvisionDatabase(dbPath string) error {
-	return ProvisionDatabaseWithProgress(dbPath, nil)
+	return ProvisionDatabaseWithProgress(context.Background(), dbPath, nil)
 }
 
-// ProvisionDatabaseWithProgress provisions the database and reports progress via channel
-func ProvisionDatabaseWithProgress(dbPath string, progressChan chan<- string) error {
+// ProvisionDatabaseWithProgress provisions the database and reports progress via channel.
+// Cancelling ctx stops the shapefile download and removes the partial file.
+func ProvisionDatabaseWithProgress(ctx context.Context, dbPath string, progressChan chan<- provision.Progress) error {
 	needs, err := NeedsProvisioning(dbPath)
 	if err != nil {
 		return err
@@ -59,54 +112,134 @@ func ProvisionDatabaseWithProgress(dbPath string, progressChan chan<- string) er
 	if !needs {
 		return nil
 	}
-	
+
+	provision.Send(progressChan, provision.Status("Marine zones table not found, provisioning..."))
+	return provisionDatabase(ctx, dbPath, progressChan)
+}
+
+// ReprovisionDatabaseWithProgress rebuilds the marine_zones table even if it already exists,
+// e.g. when a previous provisioning run left it empty. Cancelling ctx stops the shapefile
+// download and removes the partial file.
+func ReprovisionDatabaseWithProgress(ctx context.Context, dbPath string, progressChan chan<- provision.Progress) error {
+	provision.Send(progressChan, provision.Status("Re-provisioning marine zones..."))
+	return provisionDatabase(ctx, dbPath, progressChan)
+}
+
+// provisionDatabase downloads the shapefile and (re)builds the marine_zones table
+func provisionDatabase(ctx context.Context, dbPath string, progressChan chan<- provision.Progress) error {
 	sendProgress := func(msg string) {
-		if progressChan != nil {
-			progressChan <- msg
-		} else {
-			log.Println(msg)
-		}
+		provision.Send(progressChan, provision.Status(msg))
 	}
 
-	sendProgress("Marine zones table not found, provisioning...")
-
 	// Create data directory if it doesn't exist
 	dataDir := filepath.Dir(dbPath)
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
-		return fmt.Errorf("creating data directory: %w", err)
+		return &ProvisionError{Stage: StageSetup, Err: err}
+	}
+
+	// Pick the shapefile edition, falling back to the default if discovery fails
+	sendProgress("Checking for the latest NOAA marine zones edition...")
+	editionCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	edition, err := resolveEdition(editionCtx)
+	cancel()
+	if err != nil {
+		sendProgress(fmt.Sprintf("Couldn't determine the latest edition (%v), using %s", err, edition))
 	}
 
 	// Download shapefile
-	zipPath := filepath.Join(dataDir, shapefileBase+".zip")
-	sendProgress(fmt.Sprintf("Downloading NOAA marine zones from %s...", marineZonesURL))
-	if err := downloadFile(zipPath, marineZonesURL); err != nil {
-		return fmt.Errorf("downloading shapefile: %w", err)
+	zipPath := filepath.Join(dataDir, edition+".zip")
+	url := marineZonesURL(edition)
+	sendProgress(fmt.Sprintf("Downloading NOAA marine zones from %s...", url))
+	if err := downloadShapefile(ctx, zipPath, url, edition, sendProgress); err != nil {
+		return &ProvisionError{Stage: StageDownload, Err: err}
 	}
 	defer os.Remove(zipPath) // Clean up zip file after extraction
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	// Extract shapefile
 	sendProgress("Extracting shapefile...")
 	if err := unzipFile(zipPath, dataDir); err != nil {
-		return fmt.Errorf("extracting shapefile: %w", err)
+		return &ProvisionError{Stage: StageExtract, Err: err}
 	}
 
 	// Build database
-	shapefilePath := filepath.Join(dataDir, shapefileBase+".shp")
+	shapefilePath := filepath.Join(dataDir, edition+".shp")
 	sendProgress("Building marine zones database...")
-	if err := buildDatabase(shapefilePath, dbPath, progressChan); err != nil {
-		return fmt.Errorf("building database: %w", err)
+	if err := buildDatabase(shapefilePath, dbPath, edition, progressChan); err != nil {
+		return &ProvisionError{Stage: StageBuild, Err: err}
 	}
 
 	// Clean up shapefile files (keep only the database)
-	cleanupShapefiles(dataDir, shapefileBase)
+	cleanupShapefiles(dataDir, edition)
 
 	sendProgress(fmt.Sprintf("Successfully provisioned marine zones database at %s", dbPath))
 	return nil
 }
 
-// downloadFile downloads a file from a URL to a local path
-func downloadFile(filepath string, url string) error {
-	resp, err := http.Get(url)
+// downloadShapefile downloads an edition's zip to zipPath and checks that it opens and
+// holds the edition's .shp. Truncated or unreadable downloads are deleted and tried
+// again, up to downloadAttempts times. Cancelling ctx stops at once.
+func downloadShapefile(ctx context.Context, zipPath, url, edition string, sendProgress func(string)) error {
+	var err error
+	for attempt := 1; attempt <= downloadAttempts; attempt++ {
+		if attempt > 1 {
+			sendProgress(fmt.Sprintf("Download was incomplete (%v), retrying (attempt %d of %d)...", err, attempt, downloadAttempts))
+			select {
+			case <-time.After(downloadRetryDelay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if err = downloadFile(ctx, zipPath, url); err != nil {
+			if !errors.Is(err, errTruncatedDownload) {
+				return err
+			}
+			continue
+		}
+		var found bool
+		found, err = zipContains(zipPath, edition+".shp")
+		if err != nil {
+			os.Remove(zipPath)
+			err = fmt.Errorf("%w: %s is not a readable zip file: %w", errTruncatedDownload, filepath.Base(zipPath), err)
+			continue
+		}
+		if !found {
+			os.Remove(zipPath)
+			return classifiedError{kind: FailureParse, err: fmt.Errorf("%s does not contain %s.shp; the edition may not be a marine zones shapefile", url, edition)}
+		}
+		return nil
+	}
+	return fmt.Errorf("%w after %d attempts; check the network connection and try again", err, downloadAttempts)
+}
+
+// zipContains reports whether the zip file at path opens and holds a file named name,
+// ignoring case and directories
+func zipContains(path, name string) (bool, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return false, err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if strings.EqualFold(filepath.Base(f.Name), name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// downloadFile downloads a file from a URL to a local path. A partially written
+// file is removed if the download fails or ctx is cancelled; a body shorter than its
+// Content-Length fails with errTruncatedDownload.
+func downloadFile(ctx context.Context, filepath string, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -120,9 +253,17 @@ func downloadFile(filepath string, url string) error {
 	if err != nil {
 		return err
 	}
-	defer out.Close()
 
-	_, err = io.Copy(out, resp.Body)
+	written, err := io.Copy(out, resp.Body)
+	if errors.Is(err, io.ErrUnexpectedEOF) || (err == nil && resp.ContentLength >= 0 && written != resp.ContentLength) {
+		err = fmt.Errorf("%w: received %d of %d bytes", errTruncatedDownload, written, resp.ContentLength)
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(filepath)
+	}
 	return err
 }
 
@@ -174,8 +315,10 @@ func unzipFile(src, dest string) error {
 	return nil
 }
 
-// buildDatabase creates the marine_zones table in the SQLite database from the shapefile
-func buildDatabase(shapefilePath, dbPath string, progressChan chan<- string) error {
+// buildDatabase creates the marine_zones table in the SQLite database from the shapefile.
+// The table is rebuilt in a single transaction, so an existing table is left untouched
+// if the shapefile yields no zones. The edition is recorded in the metadata table.
+func buildDatabase(shapefilePath, dbPath, edition string, progressChan chan<- provision.Progress) error {
 	// Open the shapefile
 	shape, err := shp.Open(shapefilePath)
 	if err != nil {
@@ -186,12 +329,20 @@ func buildDatabase(shapefilePath, dbPath string, progressChan chan<- string) err
 	// Open SQLite database (don't remove - may contain other tables)
 	db, err := sql.Open("sqlite", dbPath)
 	if err != nil {
-		return fmt.Errorf("opening database: %w", err)
+		return diskFailure(fmt.Errorf("opening database: %w", err))
 	}
 	defer db.Close()
 
-	// Create table
-	_, err = db.Exec(`
+	tx, err := db.Begin()
+	if err != nil {
+		return diskFailure(fmt.Errorf("starting transaction: %w", err))
+	}
+	defer tx.Rollback() // Rollback on error
+
+	// Create table (replacing any previous, possibly empty, copy)
+	_, err = tx.Exec(`
+		DROP TABLE IF EXISTS marine_zones;
+
 		CREATE TABLE marine_zones (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			zone_code TEXT NOT NULL,
@@ -213,10 +364,11 @@ func buildDatabase(shapefilePath, dbPath string, progressChan chan<- string) err
 		CREATE INDEX idx_zones_center ON marine_zones(center_lat, center_lon);
 	`)
 	if err != nil {
-		return fmt.Errorf("creating table: %w", err)
+		return diskFailure(fmt.Errorf("creating table: %w", err))
 	}
 
 	// Process each zone
+	total := shape.AttributeCount()
 	count := 0
 	for shape.Next() {
 		n, p := shape.Shape()
@@ -282,7 +434,7 @@ func buildDatabase(shapefilePath, dbPath string, progressChan chan<- string) err
 		}
 
 		// Insert into database
-		_, err = db.Exec(`
+		_, err = tx.Exec(`
 			INSERT INTO marine_zones (
 				zone_code, zone_name, geometry,
 				bbox_min_lat, bbox_max_lat, bbox_min_lon, bbox_max_lon,
@@ -301,30 +453,67 @@ func buildDatabase(shapefilePath, dbPath string, progressChan chan<- string) err
 
 		count++
 		if count%100 == 0 {
-			msg := fmt.Sprintf("Processed %d zones...", count)
-			if progressChan != nil {
-				progressChan <- msg
-			} else {
-				log.Println(msg)
-			}
+			provision.Send(progressChan, provision.Step(fmt.Sprintf("Processed %d of %d zones...", count, total), count, total))
 		}
 	}
 
-	msg := fmt.Sprintf("Successfully created database with %d marine zones", count)
-	if progressChan != nil {
-		progressChan <- msg
-	} else {
-		log.Println(msg)
+	if count == 0 {
+		return fmt.Errorf("no marine zones read from %s", filepath.Base(shapefilePath))
+	}
+
+	if err := recordEdition(tx, edition); err != nil {
+		return diskFailure(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return diskFailure(fmt.Errorf("committing transaction: %w", err))
 	}
+
+	provision.Send(progressChan, provision.Step(fmt.Sprintf("Successfully created database with %d marine zones", count), total, total))
 	return nil
 }
 
+// shapefileExtensions are the files a shapefile consists of
+var shapefileExtensions = []string{".shp", ".shx", ".dbf", ".prj", ".cpg", ".shp.xml"}
+
 // cleanupShapefiles removes the extracted shapefile components
 func cleanupShapefiles(dir, base string) {
-	// Shapefile consists of multiple files with different extensions
-	extensions := []string{".shp", ".shx", ".dbf", ".prj", ".cpg", ".shp.xml"}
-	for _, ext := range extensions {
+	for _, ext := range shapefileExtensions {
 		path := filepath.Join(dir, base+ext)
 		os.Remove(path) // Ignore errors
 	}
 }
+
+// CleanupProvisioningFiles removes shapefile zips and extracted shapefiles of any
+// edition left next to the database at dbPath by a failed provisioning run, so a
+// retry downloads a fresh copy instead of reusing a possibly corrupt one
+func CleanupProvisioningFiles(dbPath string) error {
+	dir := filepath.Dir(dbPath)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading data directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !isProvisioningFile(entry.Name()) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// isProvisioningFile reports whether name is a shapefile zip or component for an edition
+func isProvisioningFile(name string) bool {
+	for _, ext := range append([]string{".zip"}, shapefileExtensions...) {
+		if base, ok := strings.CutSuffix(name, ext); ok && editionRE.MatchString(base) {
+			return true
+		}
+	}
+	return false
+}
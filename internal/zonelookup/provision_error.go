@@ -0,0 +1,86 @@
+package zonelookup
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// ProvisionStage is the step of provisioning the marine zones table a failure happened in
+type ProvisionStage string
+
+const (
+	StageSetup    ProvisionStage = "setup"    // Creating the data directory
+	StageDownload ProvisionStage = "download" // Downloading the shapefile zip
+	StageExtract  ProvisionStage = "extract"  // Unzipping the shapefile
+	StageBuild    ProvisionStage = "build"    // Reading the shapefile into the database
+)
+
+// stageActions describes what each stage was doing, prefixing its errors' messages
+var stageActions = map[ProvisionStage]string{
+	StageSetup:    "creating data directory",
+	StageDownload: "downloading shapefile",
+	StageExtract:  "extracting shapefile",
+	StageBuild:    "building database",
+}
+
+// FailureKind groups provisioning failures by what the user can do about them
+type FailureKind int
+
+const (
+	FailureNetwork FailureKind = iota // The shapefile couldn't be fetched from NOAA
+	FailureParse                      // The download isn't a usable marine zones shapefile
+	FailureDisk                       // Files or the database couldn't be written
+)
+
+// ProvisionError reports which stage of provisioning the marine zones table failed,
+// with the underlying error
+type ProvisionError struct {
+	Stage ProvisionStage
+	Err   error
+}
+
+func (e *ProvisionError) Error() string {
+	return stageActions[e.Stage] + ": " + e.Err.Error()
+}
+
+func (e *ProvisionError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports download failures as ErrShapefileDownload
+func (e *ProvisionError) Is(target error) bool {
+	return target == ErrShapefileDownload && e.Stage == StageDownload
+}
+
+// Kind classifies the failure. Errors from the file system are disk failures
+// whatever the stage; otherwise downloads fail on the network and extracting or
+// reading the shapefile fails parsing it.
+func (e *ProvisionError) Kind() FailureKind {
+	var classified classifiedError
+	if errors.As(e.Err, &classified) {
+		return classified.kind
+	}
+	var pathErr *fs.PathError
+	switch {
+	case e.Stage == StageSetup || errors.As(e.Err, &pathErr):
+		return FailureDisk
+	case e.Stage == StageDownload:
+		return FailureNetwork
+	default:
+		return FailureParse
+	}
+}
+
+// classifiedError overrides the FailureKind its stage implies for err, keeping err's message
+type classifiedError struct {
+	kind FailureKind
+	err  error
+}
+
+func (e classifiedError) Error() string { return e.err.Error() }
+func (e classifiedError) Unwrap() error { return e.err }
+
+// diskFailure marks err, e.g. from writing the database, as a disk failure
+func diskFailure(err error) error {
+	return classifiedError{kind: FailureDisk, err: err}
+}
@@ -0,0 +1,282 @@
+package zonelookup
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// zipBytes builds a zip archive holding empty files with the given names
+func zipBytes(t *testing.T, names ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range names {
+		if _, err := zw.Create(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// emptyDBF is a dBase table with no fields or records
+var emptyDBF = append([]byte{3, 0, 0, 0, 0, 0, 0, 0, 33, 0, 1, 0}, append(make([]byte, 20), 0x0D)...)
+
+// zipContents builds a zip archive holding files with the given names and contents
+func zipContents(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// noRetryDelay removes the pause between download attempts for the test
+func noRetryDelay(t *testing.T) {
+	orig := downloadRetryDelay
+	downloadRetryDelay = 0
+	t.Cleanup(func() { downloadRetryDelay = orig })
+}
+
+func TestCleanupProvisioningFiles(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "marine-terminal.db")
+
+	leftovers := []string{"mz18mr25.zip", "mz18mr25.shp", "mz18mr25.dbf", "mz18mr25.shp.xml", "mz05mr24.zip"}
+	kept := []string{"marine-terminal.db", "notes.zip", "exports.txt"}
+	for _, name := range append(leftovers, kept...) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("partial"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := CleanupProvisioningFiles(dbPath); err != nil {
+		t.Fatalf("CleanupProvisioningFiles() error: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var remaining []string
+	for _, e := range entries {
+		remaining = append(remaining, e.Name())
+	}
+	sort.Strings(kept)
+	if len(remaining) != len(kept) {
+		t.Fatalf("remaining files = %v, want %v", remaining, kept)
+	}
+	for i := range kept {
+		if remaining[i] != kept[i] {
+			t.Errorf("remaining files = %v, want %v", remaining, kept)
+			break
+		}
+	}
+
+	// A data directory that was never created is nothing to clean up
+	if err := CleanupProvisioningFiles(filepath.Join(dir, "missing", "marine-terminal.db")); err != nil {
+		t.Errorf("CleanupProvisioningFiles() on a missing directory error: %v", err)
+	}
+}
+
+func TestDownloadFile_RemovesPartialFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Promise more than is sent, so the body ends early like a dropped connection
+		w.Header().Set("Content-Length", "1000")
+		w.Write([]byte("truncated"))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "mz18mr25.zip")
+	if err := downloadFile(context.Background(), path, server.URL); !errors.Is(err, errTruncatedDownload) {
+		t.Fatalf("downloadFile() error = %v, want a truncated download", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("partial download should be removed, stat error = %v", err)
+	}
+}
+
+func TestDownloadFile_CancelledMidDownload(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1000")
+		w.Write([]byte("PK\x03\x04partial"))
+		w.(http.Flusher).Flush()
+		// Quit while the rest of the file is still on its way
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "mz18mr25.zip")
+	err := downloadShapefile(ctx, path, server.URL, "mz18mr25", func(string) {})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("downloadShapefile() error = %v, want context.Canceled", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("partial download should be removed, stat error = %v", err)
+	}
+}
+
+func TestDownloadShapefile_TruncatedDownload(t *testing.T) {
+	noRetryDelay(t)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Length", "1000")
+		w.Write([]byte("PK\x03\x04truncated"))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "mz18mr25.zip")
+	err := downloadShapefile(context.Background(), path, server.URL, "mz18mr25", func(string) {})
+	if err == nil {
+		t.Fatal("downloadShapefile() should fail when every download is truncated")
+	}
+	if requests != downloadAttempts {
+		t.Errorf("downloaded %d times, want %d", requests, downloadAttempts)
+	}
+	for _, want := range []string{"download truncated", "received 13 of 1000 bytes", "after 3 attempts", "check the network connection"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %q", err, want)
+		}
+	}
+	if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+		t.Errorf("truncated download should be removed, stat error = %v", statErr)
+	}
+}
+
+func TestDownloadShapefile_RetriesThenSucceeds(t *testing.T) {
+	noRetryDelay(t)
+	archive := zipBytes(t, "mz18mr25.shp", "mz18mr25.dbf")
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests {
+		case 1:
+			w.Header().Set("Content-Length", "1000")
+			w.Write(archive[:10])
+		case 2:
+			// Complete as far as HTTP is concerned, but not a zip file
+			w.Write([]byte("<html>Service Unavailable</html>"))
+		default:
+			w.Write(archive)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "mz18mr25.zip")
+	var progress []string
+	if err := downloadShapefile(context.Background(), path, server.URL, "mz18mr25", func(msg string) { progress = append(progress, msg) }); err != nil {
+		t.Fatalf("downloadShapefile() error = %v", err)
+	}
+	if requests != 3 || len(progress) != 2 {
+		t.Errorf("expected 3 downloads and 2 retry messages, got %d and %v", requests, progress)
+	}
+}
+
+func TestDownloadShapefile_MissingShapefile(t *testing.T) {
+	noRetryDelay(t)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(zipBytes(t, "readme.txt"))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "mz18mr25.zip")
+	err := downloadShapefile(context.Background(), path, server.URL, "mz18mr25", func(string) {})
+	if err == nil || !strings.Contains(err.Error(), "does not contain mz18mr25.shp") {
+		t.Fatalf("downloadShapefile() error = %v, want a missing .shp error", err)
+	}
+	if requests != 1 {
+		t.Errorf("a complete archive without the shapefile shouldn't be retried, downloaded %d times", requests)
+	}
+	if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+		t.Errorf("unusable archive should be removed, stat error = %v", statErr)
+	}
+}
+
+func TestProvisionDatabase_FailureStages(t *testing.T) {
+	noRetryDelay(t)
+	origBase, origEdition, origPinned := marineZonesBaseURL, shapefileBase, editionPinned
+	shapefileBase, editionPinned = "mz18mr25", true
+	t.Cleanup(func() { marineZonesBaseURL, shapefileBase, editionPinned = origBase, origEdition, origPinned })
+
+	shapefile := zipContents(t, map[string][]byte{"mz18mr25.shp": nil, "mz18mr25.dbf": emptyDBF})
+	tests := []struct {
+		name    string
+		status  int
+		body    []byte
+		blocked string // Path under the data directory made a file or directory to block a write
+		stage   ProvisionStage
+		kind    FailureKind
+	}{
+		{"data directory", http.StatusOK, nil, ".", StageSetup, FailureDisk},
+		{"download", http.StatusNotFound, nil, "", StageDownload, FailureNetwork},
+		{"not a shapefile", http.StatusOK, zipBytes(t, "readme.txt"), "", StageDownload, FailureParse},
+		{"unzip", http.StatusOK, zipBytes(t, "../mz18mr25.shp"), "", StageExtract, FailureParse},
+		{"no zones", http.StatusOK, shapefile, "", StageBuild, FailureParse},
+		{"database", http.StatusOK, shapefile, "marine-terminal.db", StageBuild, FailureDisk},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write(tt.body)
+			}))
+			defer server.Close()
+			marineZonesBaseURL = server.URL + "/"
+
+			dir := filepath.Join(t.TempDir(), "data")
+			switch tt.blocked {
+			case "":
+			case ".":
+				// A file where the data directory should be
+				if err := os.WriteFile(dir, []byte("not a directory"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			default:
+				// A directory where a file should be
+				if err := os.MkdirAll(filepath.Join(dir, tt.blocked), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := provisionDatabase(context.Background(), filepath.Join(dir, "marine-terminal.db"), nil)
+			var perr *ProvisionError
+			if !errors.As(err, &perr) {
+				t.Fatalf("provisionDatabase() error = %v, want a *ProvisionError", err)
+			}
+			if perr.Stage != tt.stage || perr.Kind() != tt.kind {
+				t.Errorf("stage = %s, kind = %d; want %s, %d (error: %v)", perr.Stage, perr.Kind(), tt.stage, tt.kind, err)
+			}
+			if got := errors.Is(err, ErrShapefileDownload); got != (tt.stage == StageDownload) {
+				t.Errorf("errors.Is(err, ErrShapefileDownload) = %v for stage %s", got, tt.stage)
+			}
+		})
+	}
+}
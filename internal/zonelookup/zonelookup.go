@@ -5,45 +5,104 @@ import (
 	"database/sql"
 	"fmt"
 	"math"
+	"os"
+	"regexp"
 	"sort"
-	"sync"
+	"strings"
 
+	"github.com/ngmaloney/marine-terminal/internal/database"
 	_ "modernc.org/sqlite"
 )
 
-var (
-	db   *sql.DB
-	once sync.Once
-	initErr error
-)
+// conn is the shared connection used by GetDB
+var conn = database.NewConn(func(dbPath string) (*sql.DB, error) {
+	// Provision database if it doesn't exist
+	if err := ProvisionDatabase(dbPath); err != nil {
+		return nil, err
+	}
+	return database.OpenTuned(dbPath)
+})
+
+// zoneCodePattern matches NOAA marine zone codes: two letters, a "Z", then digits (e.g. ANZ254)
+var zoneCodePattern = regexp.MustCompile(`^[A-Z]{2}Z\d+$`)
 
 // ZoneInfo represents a marine zone with its distance from a point
 type ZoneInfo struct {
-	Code     string
-	Name     string
-	Distance float64 // Distance in miles
+	Code      string
+	Name      string
+	Distance  float64 // Distance in miles
+	CenterLat float64
+	CenterLon float64
 }
 
-// GetDB returns the singleton database connection
+// IsValidZoneCode checks whether a string has the format of a marine zone code
+func IsValidZoneCode(code string) bool {
+	return zoneCodePattern.MatchString(strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// GetDB returns the shared database connection for dbPath
 // Automatically provisions the database if it doesn't exist
 func GetDB(dbPath string) (*sql.DB, error) {
-	once.Do(func() {
-		// Provision database if it doesn't exist
-		initErr = ProvisionDatabase(dbPath)
-		if initErr != nil {
-			return
-		}
+	return conn.Get(dbPath)
+}
 
-		db, initErr = sql.Open("sqlite", dbPath)
-		if initErr != nil {
-			return
-		}
-		// Set pragmas for performance
-		db.Exec("PRAGMA journal_mode=WAL")
-		db.Exec("PRAGMA synchronous=NORMAL")
-		db.Exec("PRAGMA cache_size=10000")
-	})
-	return db, initErr
+// ResetDB closes the shared connection so the next GetDB opens the database again
+func ResetDB() error {
+	return conn.Reset()
+}
+
+// SetDB makes GetDB return db for dbPath, e.g. an in-memory database in tests
+func SetDB(dbPath string, db *sql.DB) error {
+	return conn.Set(dbPath, db)
+}
+
+// IsEmpty reports whether the marine_zones table is missing or contains no rows.
+// A provisioned but empty table usually means an earlier provisioning run failed partway.
+func IsEmpty(dbPath string) (bool, error) {
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return true, nil
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return false, fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	return isEmptyDB(db)
+}
+
+// isEmptyDB checks for marine_zones rows using the provided database connection
+func isEmptyDB(db *sql.DB) (bool, error) {
+	var tables int
+	err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name='marine_zones'").Scan(&tables)
+	if err != nil {
+		return false, fmt.Errorf("checking for marine_zones table: %w", err)
+	}
+	if tables == 0 {
+		return true, nil
+	}
+
+	var hasRows bool
+	if err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM marine_zones)").Scan(&hasRows); err != nil {
+		return false, fmt.Errorf("counting marine zones: %w", err)
+	}
+	return !hasRows, nil
+}
+
+// Count returns the number of rows in the marine_zones table
+func Count(dbPath string) (int, error) {
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return 0, fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM marine_zones").Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting marine zones: %w", err)
+	}
+	return count, nil
 }
 
 // haversineDistance calculates distance in miles between two lat/lon points
@@ -96,7 +155,10 @@ func getNearbyMarineZonesFromDB(db *sql.DB, lat, lon float64, maxDistanceMiles f
 	}
 	defer rows.Close()
 
+	// A zone whose parts were stored as separate rows appears once per part; keep the
+	// closest part of each
 	var zones []ZoneInfo
+	seen := make(map[string]int) // Index in zones by zone code
 	for rows.Next() {
 		var code, name string
 		var centerLat, centerLon float64
@@ -109,13 +171,24 @@ func getNearbyMarineZonesFromDB(db *sql.DB, lat, lon float64, maxDistanceMiles f
 		distance := HaversineDistance(lat, lon, centerLat, centerLon)
 
 		// Only include zones within the max distance
-		if distance <= maxDistanceMiles {
-			zones = append(zones, ZoneInfo{
-				Code:     code,
-				Name:     name,
-				Distance: distance,
-			})
+		if distance > maxDistanceMiles {
+			continue
+		}
+		zone := ZoneInfo{
+			Code:      code,
+			Name:      name,
+			Distance:  distance,
+			CenterLat: centerLat,
+			CenterLon: centerLon,
 		}
+		if i, ok := seen[code]; ok {
+			if distance < zones[i].Distance {
+				zones[i] = zone
+			}
+			continue
+		}
+		seen[code] = len(zones)
+		zones = append(zones, zone)
 	}
 
 	// Sort by distance (closest first)
@@ -126,6 +199,20 @@ func getNearbyMarineZonesFromDB(db *sql.DB, lat, lon float64, maxDistanceMiles f
 	return zones, nil
 }
 
+// GetZoneInfoByCode retrieves a single marine zone by its code (e.g. "ANZ254")
+func GetZoneInfoByCode(dbPath, zoneCode string) (*ZoneInfo, error) {
+	zoneCode = strings.ToUpper(strings.TrimSpace(zoneCode))
+	if !IsValidZoneCode(zoneCode) {
+		return nil, fmt.Errorf("invalid zone code %q: expected format like ANZ254", zoneCode)
+	}
+
+	db, err := GetDB(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	return getZoneInfoByCodeFromDB(db, zoneCode)
+}
+
 // getZoneInfoByCodeFromDB retrieves a single marine zone using the provided database connection
 func getZoneInfoByCodeFromDB(db *sql.DB, zoneCode string) (*ZoneInfo, error) {
 	var code, name string
@@ -145,8 +232,10 @@ func getZoneInfoByCodeFromDB(db *sql.DB, zoneCode string) (*ZoneInfo, error) {
 
 	// For direct lookup, distance is not relevant, so set to 0.0
 	return &ZoneInfo{
-		Code:     code,
-		Name:     name,
-		Distance: 0.0,
+		Code:      code,
+		Name:      name,
+		Distance:  0.0,
+		CenterLat: centerLat,
+		CenterLon: centerLon,
 	}, nil
 }
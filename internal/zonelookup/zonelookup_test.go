@@ -2,6 +2,7 @@ package zonelookup
 
 import (
 	"database/sql"
+	"path/filepath"
 	"testing"
 
 	_ "modernc.org/sqlite"
@@ -71,6 +72,56 @@ func TestGetNearbyMarineZonesFromDB(t *testing.T) {
 	}
 }
 
+func TestGetNearbyMarineZonesFromDB_DuplicateCodes(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open in-memory database: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE marine_zones (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			zone_code TEXT NOT NULL,
+			zone_name TEXT,
+			center_lat REAL NOT NULL,
+			center_lon REAL NOT NULL
+		)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	// Z1's parts were stored as separate rows, the farther one first
+	_, err = db.Exec(`
+		INSERT INTO marine_zones (zone_code, zone_name, center_lat, center_lon) VALUES
+		('Z1', 'Split Zone', 40.3, -70.3),
+		('Z2', 'Other Zone', 40.2, -70.2),
+		('Z1', 'Split Zone', 40.1, -70.1),
+		('Z1', 'Split Zone', 40.4, -70.4)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	zones, err := getNearbyMarineZonesFromDB(db, 40.0, -70.0, 50.0)
+	if err != nil {
+		t.Fatalf("getNearbyMarineZonesFromDB() error = %v", err)
+	}
+	if len(zones) != 2 {
+		t.Fatalf("got %d zones, want 2: %+v", len(zones), zones)
+	}
+	if zones[0].Code != "Z1" || zones[0].CenterLat != 40.1 || zones[0].CenterLon != -70.1 {
+		t.Errorf("zones[0] = %+v, want Z1's nearest part at 40.1, -70.1", zones[0])
+	}
+	if want := HaversineDistance(40.0, -70.0, 40.1, -70.1); zones[0].Distance != want {
+		t.Errorf("zones[0].Distance = %v, want %v", zones[0].Distance, want)
+	}
+	if zones[1].Code != "Z2" {
+		t.Errorf("zones[1] = %s, want Z2", zones[1].Code)
+	}
+}
+
 func TestGetZoneInfoByCodeFromDB(t *testing.T) {
 	db, err := sql.Open("sqlite", ":memory:")
 	if err != nil {
@@ -106,6 +157,9 @@ func TestGetZoneInfoByCodeFromDB(t *testing.T) {
 	if zone == nil || zone.Name != "Test Zone" {
 		t.Errorf("getZoneInfoByCodeFromDB('Z1') = %v, want 'Test Zone'", zone)
 	}
+	if zone != nil && (zone.CenterLat != 40.0 || zone.CenterLon != -70.0) {
+		t.Errorf("getZoneInfoByCodeFromDB('Z1') center = %v, %v, want 40.0, -70.0", zone.CenterLat, zone.CenterLon)
+	}
 
 	// Test not found
 	_, err = getZoneInfoByCodeFromDB(db, "Z999")
@@ -113,3 +167,147 @@ func TestGetZoneInfoByCodeFromDB(t *testing.T) {
 		t.Error("getZoneInfoByCodeFromDB('Z999') expected error, got nil")
 	}
 }
+
+func TestIsValidZoneCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"ANZ254", true},
+		{"anz254", true},
+		{" GMZ830 ", true},
+		{"PZZ135", true},
+		{"ANZ", false},
+		{"AN254", false},
+		{"A1Z254", false},
+		{"02633", false},
+		{"Chatham, MA", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := IsValidZoneCode(tt.code); got != tt.want {
+				t.Errorf("IsValidZoneCode(%q) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsEmptyDB(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open in-memory database: %v", err)
+	}
+	defer db.Close()
+
+	empty, err := isEmptyDB(db)
+	if err != nil || !empty {
+		t.Errorf("missing table: isEmptyDB() = %v, %v; want true, nil", empty, err)
+	}
+
+	if _, err := db.Exec(`CREATE TABLE marine_zones (zone_code TEXT NOT NULL, zone_name TEXT, center_lat REAL NOT NULL, center_lon REAL NOT NULL)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	empty, err = isEmptyDB(db)
+	if err != nil || !empty {
+		t.Errorf("empty table: isEmptyDB() = %v, %v; want true, nil", empty, err)
+	}
+
+	if _, err := db.Exec(`INSERT INTO marine_zones VALUES ('Z1', 'Test Zone', 40.0, -70.0)`); err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+	empty, err = isEmptyDB(db)
+	if err != nil || empty {
+		t.Errorf("populated table: isEmptyDB() = %v, %v; want false, nil", empty, err)
+	}
+}
+
+func TestSetShapefileEdition(t *testing.T) {
+	defer func() {
+		shapefileBase = DefaultShapefileEdition
+		editionPinned = false
+	}()
+
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"mz05mr26", "mz05mr26", false},
+		{" MZ05MR26.zip ", "mz05mr26", false},
+		{"", "", true},
+		{"../etc/passwd", "", true},
+		{"mz05mr26.tar.gz", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			shapefileBase = DefaultShapefileEdition
+			err := SetShapefileEdition(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetShapefileEdition(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if shapefileBase != DefaultShapefileEdition {
+					t.Errorf("edition changed to %q on error", shapefileBase)
+				}
+				return
+			}
+			if shapefileBase != tt.want {
+				t.Errorf("edition = %q, want %q", shapefileBase, tt.want)
+			}
+			if !editionPinned {
+				t.Error("edition should be pinned after SetShapefileEdition")
+			}
+		})
+	}
+}
+
+// createZonesDB writes a provisioned database at dbPath holding a single zone
+func createZonesDB(t *testing.T, dbPath, code, name string) {
+	t.Helper()
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	_, err = db.Exec(`
+		CREATE TABLE marine_zones (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			zone_code TEXT NOT NULL,
+			zone_name TEXT,
+			center_lat REAL NOT NULL,
+			center_lon REAL NOT NULL
+		);
+		INSERT INTO marine_zones (zone_code, zone_name, center_lat, center_lon) VALUES (?, ?, 41.5, -70.0);
+	`, code, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetDB_SwitchesPaths(t *testing.T) {
+	t.Cleanup(func() { ResetDB() })
+
+	dir := t.TempDir()
+	pathA, pathB := filepath.Join(dir, "a.db"), filepath.Join(dir, "b.db")
+	createZonesDB(t, pathA, "ANZ254", "Nantucket Sound")
+	createZonesDB(t, pathB, "ANZ254", "Renamed Sound")
+
+	zone, err := GetZoneInfoByCode(pathA, "ANZ254")
+	if err != nil {
+		t.Fatalf("GetZoneInfoByCode(a) error = %v", err)
+	}
+	if zone.Name != "Nantucket Sound" {
+		t.Errorf("zone from a = %q, want Nantucket Sound", zone.Name)
+	}
+
+	// A second path used to keep returning the first database opened
+	zone, err = GetZoneInfoByCode(pathB, "ANZ254")
+	if err != nil {
+		t.Fatalf("GetZoneInfoByCode(b) error = %v", err)
+	}
+	if zone.Name != "Renamed Sound" {
+		t.Errorf("zone from b = %q, want Renamed Sound", zone.Name)
+	}
+}
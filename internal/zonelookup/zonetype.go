@@ -0,0 +1,100 @@
+package zonelookup
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ZoneType distinguishes coastal waters zones from offshore ones, which boaters pick
+// between by how far out their trip goes
+type ZoneType string
+
+const (
+	ZoneTypeCoastal  ZoneType = "coastal"
+	ZoneTypeOffshore ZoneType = "offshore"
+)
+
+// Label names the zone type for display, e.g. "Coastal waters"
+func (t ZoneType) Label() string {
+	if t == ZoneTypeOffshore {
+		return "Offshore waters"
+	}
+	return "Coastal waters"
+}
+
+// ZoneFamily describes the marine zones sharing a three-letter prefix
+type ZoneFamily struct {
+	Dir string // Text product directory, e.g. "an" for coastal/an/anz254.txt
+
+	// Zone numbers from OffshoreFrom to OffshoreTo have offshore forecasts; both are 0
+	// for families without offshore zones
+	OffshoreFrom, OffshoreTo int
+}
+
+// ZoneFamilies maps every NOAA marine zone prefix to where its forecasts are
+// published. The TAFB offshore zones in the Gulf and Caribbean are numbered below
+// 100, the Atlantic and Pacific offshore zones from 800 up.
+var ZoneFamilies = map[string]ZoneFamily{
+	"ANZ": {Dir: "an", OffshoreFrom: 800, OffshoreTo: 999}, // Atlantic
+	"AMZ": {Dir: "am", OffshoreFrom: 1, OffshoreTo: 99},    // Puerto Rico, the U.S. Virgin Islands and the Caribbean
+	"GMZ": {Dir: "gm", OffshoreFrom: 1, OffshoreTo: 99},    // Gulf of America (formerly Gulf of Mexico)
+	"PZZ": {Dir: "pz", OffshoreFrom: 800, OffshoreTo: 999}, // Pacific coast
+	"PKZ": {Dir: "pk"},                                     // Alaska
+	"PHZ": {Dir: "ph", OffshoreFrom: 180, OffshoreTo: 199}, // Hawaii
+	"PMZ": {Dir: "pm"},                                     // Guam and the Northern Marianas
+	"PSZ": {Dir: "ps"},                                     // American Samoa
+	"LSZ": {Dir: "ls"},                                     // Lake Superior
+	"LMZ": {Dir: "lm"},                                     // Lake Michigan
+	"LHZ": {Dir: "lh"},                                     // Lake Huron
+	"LEZ": {Dir: "le"},                                     // Lake Erie
+	"LOZ": {Dir: "lo"},                                     // Lake Ontario
+	"LCZ": {Dir: "lc"},                                     // Lake St. Clair
+	"SLZ": {Dir: "sl"},                                     // St. Lawrence River
+}
+
+// ZoneCodeType classifies a zone by its number within its family, e.g. offshore for
+// GMZ089 or ANZ800, and returns the family. It returns false for codes that aren't a
+// known prefix followed by a three-digit number.
+func ZoneCodeType(code string) (ZoneType, ZoneFamily, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 6 || strings.Trim(code[3:], "0123456789") != "" {
+		return "", ZoneFamily{}, false
+	}
+	family, ok := ZoneFamilies[code[:3]]
+	if !ok {
+		return "", ZoneFamily{}, false
+	}
+	n, _ := strconv.Atoi(code[3:])
+	if family.OffshoreTo > 0 && n >= family.OffshoreFrom && n <= family.OffshoreTo {
+		return ZoneTypeOffshore, family, true
+	}
+	return ZoneTypeCoastal, family, true
+}
+
+// outerWatersPattern matches zone names describing waters that start some distance
+// out, e.g. "from 20 to 40 nm", as opposed to "out 20 nm"
+var outerWatersPattern = regexp.MustCompile(`(?i)\b(\d+)\s*(?:to|-)\s*\d+\s*nm\b`)
+
+// ClassifyZone reports whether a marine zone covers coastal or offshore waters. Zones
+// numbered among their family's offshore forecast zones (see ZoneFamilies) are
+// offshore; otherwise the name decides: zones called offshore, or covering waters
+// beyond a distance from shore, are offshore too.
+func ClassifyZone(code, name string) ZoneType {
+	if t, _, ok := ZoneCodeType(code); ok && t == ZoneTypeOffshore {
+		return ZoneTypeOffshore
+	}
+	lower := strings.ToLower(name)
+	if strings.Contains(lower, "offshore") || strings.Contains(lower, "beyond") {
+		return ZoneTypeOffshore
+	}
+	if m := outerWatersPattern.FindStringSubmatch(name); m != nil && m[1] != "0" {
+		return ZoneTypeOffshore
+	}
+	return ZoneTypeCoastal
+}
+
+// Type classifies the zone as coastal or offshore
+func (z ZoneInfo) Type() ZoneType {
+	return ClassifyZone(z.Code, z.Name)
+}
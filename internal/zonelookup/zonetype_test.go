@@ -0,0 +1,39 @@
+package zonelookup
+
+import "testing"
+
+func TestClassifyZone(t *testing.T) {
+	tests := []struct {
+		code string
+		name string
+		want ZoneType
+	}{
+		{"ANZ254", "Coastal waters from Provincetown MA to Chatham MA to Nantucket MA out 20 nm", ZoneTypeCoastal},
+		{"ANZ232", "Nantucket Sound", ZoneTypeCoastal},
+		{"ANZ250", "Coastal waters east of Ipswich Bay and the Stellwagen Bank National Marine Sanctuary", ZoneTypeCoastal},
+		{"ANZ270", "Waters from Currituck Beach Light NC to Oregon Inlet NC from 20 to 40 nm", ZoneTypeOffshore},
+		{"PZZ670", "Point Arena to Point Reyes 10-60 NM", ZoneTypeOffshore},
+		{"ANZ650", "Coastal waters from Fenwick Island DE to Chincoteague VA out 0 to 20 nm", ZoneTypeCoastal},
+		{"GMZ770", "Waters from Destin to Pensacola FL beyond 20 nm", ZoneTypeOffshore},
+		{"ANZ800", "Gulf of Maine to the Hague Line", ZoneTypeOffshore},
+		{"anz905", "", ZoneTypeOffshore},
+		{"GMZ089", "Gulf of America from 22N to 26N W of 87W", ZoneTypeOffshore},
+		{"AMZ013", "Caribbean N of 18N between 64W and 72W", ZoneTypeOffshore},
+		{"GMZ850", "", ZoneTypeCoastal},
+		{"PHZ180", "Offshore Waters Within 240 nm of Honolulu", ZoneTypeOffshore},
+		{"PHZ110", "Maalaea Bay", ZoneTypeCoastal},
+		{"PKZ850", "", ZoneTypeCoastal},
+		{"ANZ999x", "Nantucket Sound", ZoneTypeCoastal},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyZone(tt.code, tt.name); got != tt.want {
+			t.Errorf("ClassifyZone(%s, %q) = %s, want %s", tt.code, tt.name, got, tt.want)
+		}
+	}
+
+	zone := ZoneInfo{Code: "ANZ270", Name: "Waters from Currituck Beach Light NC to Oregon Inlet NC from 20 to 40 nm"}
+	if zone.Type() != ZoneTypeOffshore || zone.Type().Label() != "Offshore waters" {
+		t.Errorf("Type() = %s (%s), want offshore", zone.Type(), zone.Type().Label())
+	}
+}